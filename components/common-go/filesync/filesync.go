@@ -0,0 +1,566 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+// Package filesync synchronises directory trees using content-addressed chunks.
+// Both sides list their tree, the differences are computed based on the chunk digests
+// and only the chunks which differ are transferred. Interrupted transfers can be resumed
+// by computing the differences again.
+package filesync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultChunkSize is the size of the chunks file content is split into.
+const DefaultChunkSize = 1024 * 1024
+
+// EntryType is the type of an entry of a directory tree.
+type EntryType int
+
+const (
+	// TypeRegular is a regular file
+	TypeRegular EntryType = iota
+	// TypeDirectory is a directory
+	TypeDirectory
+	// TypeSymlink is a symbolic link
+	TypeSymlink
+)
+
+// Entry is a file, directory or symlink of a directory tree.
+type Entry struct {
+	// Path is relative to the root of the tree and uses forward slashes
+	Path string
+	Type EntryType
+	// Mode holds the permission bits
+	Mode       fs.FileMode
+	Size       int64
+	ModTime    time.Time
+	LinkTarget string
+	// Chunks are the digests of the content chunks of a regular file
+	Chunks []string
+}
+
+// Digest returns the content address of a chunk.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Filter selects paths of a directory tree using glob patterns.
+// Patterns without a slash match any path element, e.g. `node_modules` or `*.log`.
+// Patterns with a slash match the path relative to the root, `**` matches any number of path elements.
+// A path is selected if it or one of its parents matches an include pattern and neither it nor one of its parents
+// matches an exclude pattern. If there are no include patterns, all paths are included.
+type Filter struct {
+	Include []string
+	Exclude []string
+}
+
+// Excluded returns true if the path or one of its parents is excluded.
+func (f Filter) Excluded(p string) bool {
+	return matchAny(f.Exclude, p)
+}
+
+// Included returns true if the path or one of its parents is included.
+func (f Filter) Included(p string) bool {
+	return len(f.Include) == 0 || matchAny(f.Include, p)
+}
+
+// Validate returns an error if one of the patterns is malformed.
+func (f Filter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+func matchAny(patterns []string, p string) bool {
+	elements := strings.Split(p, "/")
+	for _, pattern := range patterns {
+		pattern = strings.Trim(pattern, "/")
+		if !strings.Contains(pattern, "/") {
+			for _, element := range elements {
+				if ok, _ := path.Match(pattern, element); ok {
+					return true
+				}
+			}
+			continue
+		}
+		segments := strings.Split(pattern, "/")
+		// a pattern matches a path if it matches the path or one of its parents
+		for i := len(elements); i > 0; i-- {
+			if matchSegments(segments, elements[:i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchSegments(pattern, elements []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(elements); i++ {
+				if matchSegments(pattern[1:], elements[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(elements) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], elements[0]); !ok {
+			return false
+		}
+		pattern, elements = pattern[1:], elements[1:]
+	}
+	return len(elements) == 0
+}
+
+// List lists the selected entries of the directory tree at root, parents first.
+// Directories are listed if they are selected or contain selected entries.
+// A root which does not exist is treated as an empty tree.
+func List(root string, filter Filter, chunkSize int64) ([]*Entry, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	var (
+		res  []*Entry
+		dirs = make(map[string]*Entry)
+	)
+	err := filepath.WalkDir(root, func(fn string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if fn == root && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if fn == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, fn)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if filter.Excluded(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// the file has been removed while we were walking the tree
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		entry := &Entry{
+			Path:    rel,
+			Mode:    info.Mode().Perm(),
+			ModTime: info.ModTime(),
+		}
+		switch {
+		case info.Mode().IsDir():
+			entry.Type = TypeDirectory
+			dirs[rel] = entry
+			if filter.Included(rel) {
+				res = append(res, entry)
+			}
+			return nil
+		case info.Mode()&fs.ModeSymlink != 0:
+			entry.Type = TypeSymlink
+			entry.LinkTarget, err = os.Readlink(fn)
+			if err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			entry.Type = TypeRegular
+			entry.Size = info.Size()
+			entry.Chunks, err = chunkDigests(fn, chunkSize)
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			// devices, sockets and pipes cannot be synchronised
+			return nil
+		}
+		if !filter.Included(rel) {
+			return nil
+		}
+		res = append(res, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list %s: %w", root, err)
+	}
+
+	// add the parents of selected entries which are not selected themselves
+	listed := make(map[string]struct{}, len(res))
+	for _, entry := range res {
+		listed[entry.Path] = struct{}{}
+	}
+	for _, entry := range res {
+		for dir := path.Dir(entry.Path); dir != "."; dir = path.Dir(dir) {
+			if _, ok := listed[dir]; ok {
+				break
+			}
+			listed[dir] = struct{}{}
+			if parent, ok := dirs[dir]; ok {
+				res = append(res, parent)
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Path < res[j].Path })
+	return res, nil
+}
+
+func chunkDigests(fn string, chunkSize int64) ([]string, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		res []string
+		buf = make([]byte, chunkSize)
+	)
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			res = append(res, Digest(buf[:n]))
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Plan describes how to make a destination tree equal to a source tree.
+type Plan struct {
+	// Entries need to be created or updated in the destination, parents first
+	Entries []*Entry
+	// Chunks maps the paths of regular files in Entries to the indexes of the chunks which have to be transferred
+	Chunks map[string][]int
+	// Removals are the paths which only exist in the destination, children first
+	Removals []string
+}
+
+// Bytes returns the number of bytes to transfer for a given chunk size.
+func (p *Plan) Bytes(chunkSize int64) int64 {
+	var res int64
+	for _, entry := range p.Entries {
+		for _, idx := range p.Chunks[entry.Path] {
+			res += ChunkLength(entry, idx, chunkSize)
+		}
+	}
+	return res
+}
+
+// Empty returns true if the trees are equal.
+func (p *Plan) Empty() bool {
+	return len(p.Entries) == 0 && len(p.Removals) == 0
+}
+
+// ChunkLength returns the length of a chunk of a regular file.
+func ChunkLength(entry *Entry, idx int, chunkSize int64) int64 {
+	offset := int64(idx) * chunkSize
+	if offset+chunkSize > entry.Size {
+		return entry.Size - offset
+	}
+	return chunkSize
+}
+
+// Diff computes the changes which make dst equal to src.
+func Diff(src, dst []*Entry) *Plan {
+	var (
+		res      = &Plan{Chunks: make(map[string][]int)}
+		existing = make(map[string]*Entry, len(dst))
+		wanted   = make(map[string]struct{}, len(src))
+	)
+	for _, entry := range dst {
+		existing[entry.Path] = entry
+	}
+	for _, entry := range src {
+		wanted[entry.Path] = struct{}{}
+
+		current, ok := existing[entry.Path]
+		if ok && current.Type != entry.Type {
+			ok = false
+		}
+		var chunks []int
+		if entry.Type == TypeRegular {
+			for idx, digest := range entry.Chunks {
+				if !ok || idx >= len(current.Chunks) || current.Chunks[idx] != digest {
+					chunks = append(chunks, idx)
+				}
+			}
+		}
+		changed := !ok || len(chunks) > 0 ||
+			current.Mode != entry.Mode ||
+			current.Size != entry.Size ||
+			current.LinkTarget != entry.LinkTarget ||
+			(entry.Type == TypeRegular && !sameModTime(current.ModTime, entry.ModTime))
+		if !changed {
+			continue
+		}
+		res.Entries = append(res.Entries, entry)
+		if len(chunks) > 0 {
+			res.Chunks[entry.Path] = chunks
+		}
+	}
+	for i := len(dst) - 1; i >= 0; i-- {
+		if _, ok := wanted[dst[i].Path]; !ok {
+			res.Removals = append(res.Removals, dst[i].Path)
+		}
+	}
+	return res
+}
+
+// sameModTime compares modification times with second precision as not all file systems support sub-second precision.
+func sameModTime(a, b time.Time) bool {
+	return a.Truncate(time.Second).Equal(b.Truncate(time.Second))
+}
+
+// ReadChunk reads a chunk of a regular file of the tree at root.
+func ReadChunk(root, p string, offset, length int64) ([]byte, error) {
+	fn, err := Resolve(root, p)
+	if err != nil {
+		return nil, err
+	}
+	f, err := openRegular(fn, os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, length)
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// Writer applies changes to the directory tree at Root. The metadata of entries is applied
+// on Close, so that writing chunks does not change their modification time.
+type Writer struct {
+	Root string
+	// Chown is called for all created or updated paths on Close if not nil
+	Chown func(fn string) error
+
+	entries []*Entry
+}
+
+// NewWriter creates a new writer for the directory tree at root.
+func NewWriter(root string) *Writer {
+	return &Writer{Root: root}
+}
+
+// Apply creates or updates an entry. Regular files are truncated to their size, their content
+// has to be written using WriteChunk.
+func (w *Writer) Apply(entry *Entry) error {
+	fn, err := Resolve(w.Root, entry.Path)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Lstat(fn); err == nil && entryType(info) != entry.Type {
+		err = os.RemoveAll(fn)
+		if err != nil {
+			return err
+		}
+	}
+	err = os.MkdirAll(filepath.Dir(fn), 0755)
+	if err != nil {
+		return err
+	}
+
+	switch entry.Type {
+	case TypeDirectory:
+		err = os.MkdirAll(fn, 0755)
+	case TypeSymlink:
+		if target, lerr := os.Readlink(fn); lerr == nil && target == entry.LinkTarget {
+			break
+		}
+		_ = os.Remove(fn)
+		err = os.Symlink(entry.LinkTarget, fn)
+	case TypeRegular:
+		var f *os.File
+		f, err = openRegular(fn, os.O_CREATE|os.O_WRONLY)
+		if err != nil {
+			return err
+		}
+		err = f.Truncate(entry.Size)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	default:
+		err = fmt.Errorf("unknown entry type %d", entry.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot apply %s: %w", entry.Path, err)
+	}
+	w.entries = append(w.entries, entry)
+	return nil
+}
+
+// WriteChunk writes a chunk of a regular file which has been applied before.
+func (w *Writer) WriteChunk(p string, offset int64, data []byte, digest string) error {
+	if digest != "" && Digest(data) != digest {
+		return fmt.Errorf("chunk of %s at %d does not match its digest %s", p, offset, digest)
+	}
+	fn, err := Resolve(w.Root, p)
+	if err != nil {
+		return err
+	}
+	f, err := openRegular(fn, os.O_WRONLY)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteAt(data, offset)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Remove removes an entry including its children.
+func (w *Writer) Remove(p string) error {
+	fn, err := Resolve(w.Root, p)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(fn)
+}
+
+// Close applies the mode and modification time of all applied entries, children first.
+func (w *Writer) Close() error {
+	for i := len(w.entries) - 1; i >= 0; i-- {
+		entry := w.entries[i]
+		fn, err := Resolve(w.Root, entry.Path)
+		if err != nil {
+			return err
+		}
+		info, err := os.Lstat(fn)
+		if err != nil {
+			return fmt.Errorf("cannot apply metadata of %s: %w", entry.Path, err)
+		}
+		if entryType(info) != entry.Type {
+			return fmt.Errorf("%s has been replaced while writing", entry.Path)
+		}
+		if entry.Type != TypeSymlink {
+			err = os.Chmod(fn, entry.Mode)
+			if err != nil {
+				return fmt.Errorf("cannot change mode of %s: %w", entry.Path, err)
+			}
+			if !entry.ModTime.IsZero() {
+				err = os.Chtimes(fn, entry.ModTime, entry.ModTime)
+				if err != nil {
+					return fmt.Errorf("cannot change modification time of %s: %w", entry.Path, err)
+				}
+			}
+		}
+		if w.Chown != nil {
+			err = w.Chown(fn)
+			if err != nil {
+				return fmt.Errorf("cannot change owner of %s: %w", entry.Path, err)
+			}
+		}
+	}
+	w.entries = nil
+	return nil
+}
+
+func entryType(info fs.FileInfo) EntryType {
+	switch {
+	case info.IsDir():
+		return TypeDirectory
+	case info.Mode()&fs.ModeSymlink != 0:
+		return TypeSymlink
+	default:
+		return TypeRegular
+	}
+}
+
+// Resolve returns the file name of a path of the tree at root. Absolute paths, paths containing ".." and
+// paths whose parents are symlinks are rejected, so that a path cannot escape the root. The last element of
+// the path may be a symlink, callers must not follow it.
+func Resolve(root, p string) (string, error) {
+	p = filepath.ToSlash(p)
+	if p == "" || path.IsAbs(p) {
+		return "", fmt.Errorf("invalid path %q: must be relative", p)
+	}
+	for _, element := range strings.Split(p, "/") {
+		if element == ".." {
+			return "", fmt.Errorf("invalid path %q: must not contain ..", p)
+		}
+	}
+	clean := path.Clean(p)
+	if clean == "." {
+		return "", fmt.Errorf("invalid path %q", p)
+	}
+
+	// parents which do not exist yet are created as directories by Apply
+	elements := strings.Split(clean, "/")
+	for i := 1; i < len(elements); i++ {
+		parent := path.Join(elements[:i]...)
+		info, err := os.Lstat(filepath.Join(root, filepath.FromSlash(parent)))
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if !info.IsDir() {
+			return "", fmt.Errorf("invalid path %q: %s is not a directory", p, parent)
+		}
+	}
+	return filepath.Join(root, filepath.FromSlash(clean)), nil
+}
+
+// openRegular opens a regular file without following symlinks or blocking on pipes. New files are created with mode 0600,
+// their final mode is applied by Close.
+func openRegular(fn string, flag int) (*os.File, error) {
+	f, err := os.OpenFile(fn, flag|syscall.O_NOFOLLOW|syscall.O_NONBLOCK, 0600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, fmt.Errorf("%s is not a regular file", fn)
+	}
+	return f, nil
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package filesync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		Desc     string
+		Filter   Filter
+		Path     string
+		Selected bool
+	}{
+		{Desc: "no patterns", Path: "src/main.go", Selected: true},
+		{Desc: "excluded element", Filter: Filter{Exclude: []string{"node_modules"}}, Path: "web/node_modules/react/index.js"},
+		{Desc: "excluded extension", Filter: Filter{Exclude: []string{"*.log"}}, Path: "logs/server.log"},
+		{Desc: "excluded path", Filter: Filter{Exclude: []string{"build/out"}}, Path: "build/out/main"},
+		{Desc: "excluded path does not match elsewhere", Filter: Filter{Exclude: []string{"build/out"}}, Path: "src/build/out", Selected: true},
+		{Desc: "double star", Filter: Filter{Exclude: []string{"**/testdata/*.json"}}, Path: "pkg/a/testdata/case.json"},
+		{Desc: "included parent", Filter: Filter{Include: []string{"src"}}, Path: "src/pkg/main.go", Selected: true},
+		{Desc: "not included", Filter: Filter{Include: []string{"src"}}, Path: "docs/README.md"},
+		{Desc: "included and excluded", Filter: Filter{Include: []string{"src"}, Exclude: []string{"*_test.go"}}, Path: "src/main_test.go"},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			selected := test.Filter.Included(test.Path) && !test.Filter.Excluded(test.Path)
+			if selected != test.Selected {
+				t.Errorf("expected selected to be %v", test.Selected)
+			}
+		})
+	}
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		fn := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fn, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// sync makes dst equal to src, returning the plan which has been applied.
+func sync(t *testing.T, src, dst string, filter Filter, chunkSize int64, removals bool) *Plan {
+	srcEntries, err := List(src, filter, chunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dstEntries, err := List(dst, filter, chunkSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := Diff(srcEntries, dstEntries)
+
+	w := NewWriter(dst)
+	if removals {
+		for _, p := range plan.Removals {
+			if err := w.Remove(p); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, entry := range plan.Entries {
+		if err := w.Apply(entry); err != nil {
+			t.Fatal(err)
+		}
+		for _, idx := range plan.Chunks[entry.Path] {
+			offset := int64(idx) * chunkSize
+			data, err := ReadChunk(src, entry.Path, offset, ChunkLength(entry, idx, chunkSize))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WriteChunk(entry.Path, offset, data, entry.Chunks[idx]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return plan
+}
+
+func TestSync(t *testing.T) {
+	const chunkSize = 4
+	var (
+		src    = t.TempDir()
+		dst    = filepath.Join(t.TempDir(), "dst")
+		filter = Filter{Exclude: []string{"*.log"}}
+	)
+	writeFiles(t, src, map[string]string{
+		"README.md":       "hello world",
+		"src/main.go":     "package main",
+		"src/server.log":  "ignored",
+		"empty/.keep":     "",
+		"src/pkg/util.go": "package pkg",
+	})
+	if err := os.Symlink("src/main.go", filepath.Join(src, "main")); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := sync(t, src, dst, filter, chunkSize, true)
+	if plan.Bytes(chunkSize) != int64(len("hello world")+len("package main")+len("package pkg")) {
+		t.Errorf("unexpected number of transferred bytes: %d", plan.Bytes(chunkSize))
+	}
+	if _, err := os.Stat(filepath.Join(dst, "src/server.log")); !os.IsNotExist(err) {
+		t.Errorf("excluded file should not be synchronised")
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "main")); err != nil || target != "src/main.go" {
+		t.Errorf("symlink should be synchronised: %s, %v", target, err)
+	}
+	if plan := sync(t, src, dst, filter, chunkSize, true); !plan.Empty() {
+		t.Errorf("trees should be equal after synchronisation: %+v", plan)
+	}
+
+	// change the second chunk only
+	writeFiles(t, src, map[string]string{"README.md": "hellO world"})
+	if err := os.Remove(filepath.Join(src, "src/pkg/util.go")); err != nil {
+		t.Fatal(err)
+	}
+	plan = sync(t, src, dst, filter, chunkSize, true)
+	if diff := cmp.Diff(map[string][]int{"README.md": {1}}, plan.Chunks); diff != "" {
+		t.Errorf("unexpected chunks (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"src/pkg/util.go"}, plan.Removals); diff != "" {
+		t.Errorf("unexpected removals (-want +got):\n%s", diff)
+	}
+	content, err := os.ReadFile(filepath.Join(dst, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "hellO world" {
+		t.Errorf("unexpected content: %s", content)
+	}
+	if plan := sync(t, src, dst, filter, chunkSize, true); !plan.Empty() {
+		t.Errorf("trees should be equal after synchronisation: %+v", plan)
+	}
+}
+
+func TestResolveCannotEscapeRoot(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeFiles(t, root, map[string]string{"dir/file": "hello"})
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Path  string
+		Valid bool
+	}{
+		{Path: "dir/file", Valid: true},
+		{Path: "dir/new/file", Valid: true},
+		{Path: "link", Valid: true},
+		{Path: "/etc/passwd"},
+		{Path: "../../etc/passwd"},
+		{Path: "dir/../../etc/passwd"},
+		{Path: "."},
+		{Path: ""},
+		{Path: "link/passwd"},
+		{Path: "link/etc/passwd"},
+		{Path: "dir/file/child"},
+	}
+	for _, test := range tests {
+		t.Run(test.Path, func(t *testing.T) {
+			_, err := Resolve(root, test.Path)
+			if valid := err == nil; valid != test.Valid {
+				t.Errorf("expected valid to be %v, got error %v", test.Valid, err)
+			}
+		})
+	}
+}
+
+func TestWriterDoesNotFollowSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeFiles(t, outside, map[string]string{"passwd": "root:x:0:0"})
+
+	w := NewWriter(root)
+	err := w.Apply(&Entry{Path: "a", Type: TypeSymlink, LinkTarget: outside})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Apply(&Entry{Path: "a/passwd", Type: TypeRegular, Mode: 0644, Size: 4}); err == nil {
+		t.Error("expected writing through a symlinked parent to fail")
+	}
+	if err = w.WriteChunk("a/passwd", 0, []byte("evil"), ""); err == nil {
+		t.Error("expected writing a chunk through a symlinked parent to fail")
+	}
+
+	err = os.Symlink(filepath.Join(outside, "passwd"), filepath.Join(root, "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.WriteChunk("b", 0, []byte("evil"), ""); err == nil {
+		t.Error("expected writing a chunk to a symlink to fail")
+	}
+	if _, err = ReadChunk(root, "b", 0, 4); err == nil {
+		t.Error("expected reading a chunk of a symlink to fail")
+	}
+
+	content, err := os.ReadFile(filepath.Join(outside, "passwd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "root:x:0:0" {
+		t.Errorf("file outside of the root has been modified: %s", content)
+	}
+}
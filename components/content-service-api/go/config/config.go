@@ -27,6 +27,12 @@ type StorageConfig struct {
 	// S3Config configures the S3 remote storage
 	S3Config *S3Config `json:"s3,omitempty"`
 
+	// AzureConfig configures the Azure Blob Storage remote storage
+	AzureConfig *AzureConfig `json:"azure,omitempty"`
+
+	// LocalConfig configures the filesystem-local remote storage
+	LocalConfig *LocalConfig `json:"local,omitempty"`
+
 	BlobQuota int64 `json:"blobQuota"`
 }
 
@@ -58,6 +64,13 @@ const (
 	// exist in the environment. See https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/config#LoadDefaultConfig for more details.
 	S3Storage RemoteStorageType = "s3"
 
+	// AzureStorage stores workspaces in Azure Blob Storage containers
+	AzureStorage RemoteStorageType = "azure"
+
+	// LocalStorage stores workspaces in directories on a local path, e.g. an NFS mount shared by all nodes.
+	// Presigned URLs are served by content-service itself.
+	LocalStorage RemoteStorageType = "local"
+
 	// NullStorage does not synchronize workspaces at all
 	NullStorage RemoteStorageType = ""
 )
@@ -116,6 +129,58 @@ type S3Config struct {
 	CredentialsFile string `json:"credentialsFile"`
 }
 
+// AzureConfig configures the Azure Blob Storage remote storage backend
+type AzureConfig struct {
+	AccountName    string `json:"accountName"`
+	AccountKey     string `json:"accountKey"`
+	AccountKeyFile string `json:"accountKeyFile"`
+
+	// Endpoint is the URL of the blob service. Defaults to https://<accountName>.blob.core.windows.net,
+	// use http://127.0.0.1:10000/<accountName> for the Azurite emulator.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Container stores all content in a single container rather than one container per user
+	Container string `json:"container,omitempty"`
+}
+
+// LocalConfig configures the filesystem-local remote storage backend
+type LocalConfig struct {
+	// Path is the directory in which buckets are stored as directories
+	Path string `json:"path"`
+
+	// URL is the base URL under which content-service serves presigned URLs, e.g. http://content-service:8080
+	URL string `json:"url"`
+
+	// Secret is the key presigned URLs are signed with
+	Secret     string `json:"secret,omitempty"`
+	SecretFile string `json:"secretFile,omitempty"`
+}
+
+// EncryptionConfig configures the client-side envelope encryption of workspace backups
+type EncryptionConfig struct {
+	// KMS selects the key management service which wraps the data keys of the backups
+	KMS KMSType `json:"kms"`
+
+	// Keyfile configures the keyfile KMS
+	Keyfile *KeyfileKMSConfig `json:"keyfile,omitempty"`
+}
+
+// KMSType defines the key management service used to wrap data keys
+type KMSType string
+
+const (
+	// KeyfileKMS wraps data keys with keys derived from master keys read from a local file
+	KeyfileKMS KMSType = "keyfile"
+)
+
+// KeyfileKMSConfig configures the keyfile KMS
+type KeyfileKMSConfig struct {
+	// Path points to a JSON file of the form {"primary": "<name>", "keys": {"<name>": "<base64 encoded 32 byte key>"}}.
+	// Data keys are wrapped using the primary key. To rotate keys, add a new key, make it the primary one and
+	// re-wrap existing data keys before removing the old key.
+	Path string `json:"path"`
+}
+
 type PProf struct {
 	Addr string `json:"address"`
 }
@@ -128,6 +193,10 @@ type UsageReportConfig struct {
 type ServiceConfig struct {
 	Service baseserver.ServerConfiguration `json:"service"`
 	Storage StorageConfig                  `json:"storage"`
+	// HTTP serves the presigned URLs of the local storage
+	HTTP *baseserver.ServerConfiguration `json:"http,omitempty"`
+	// Encryption is used to re-wrap the data keys of encrypted backups
+	Encryption *EncryptionConfig `json:"encryption,omitempty"`
 	// Deprecated
 	_ UsageReportConfig `json:"usageReport"`
 }
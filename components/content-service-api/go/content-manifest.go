@@ -13,6 +13,9 @@ const (
 	// ContentTypeManifest manifest is the content type for a JSON serialized WorkspaceContentManifest
 	ContentTypeManifest = "application/vnd.gitpod.ws.manifest.v1+json"
 
+	// ContentTypeChunkIndex is the content type for a JSON serialized WorkspaceChunkIndex
+	ContentTypeChunkIndex = "application/vnd.gitpod.ws.chunks.v1+json"
+
 	// MediaTypeUncompressedLayer is a valid OCIv1 media type for uncompressed layer archives
 	MediaTypeUncompressedLayer = ociv1.MediaTypeImageLayer
 )
@@ -46,3 +49,21 @@ type WorkspaceContentLayer struct {
 	// Workspace instance ID this content layer came from
 	InstanceID string `json:"instanceID"`
 }
+
+// WorkspaceChunkIndex describes a workspace backup which has been split into content-defined chunks.
+// The chunks are stored next to the index and shared between the backups of a workspace, s.t. only
+// chunks which changed since the last backup need to be uploaded.
+type WorkspaceChunkIndex struct {
+	// Digest is the digest of the reassembled archive.
+	Digest digest.Digest `json:"digest"`
+	// Size is the size of the reassembled archive.
+	Size int64 `json:"size"`
+	// Chunks make up the archive in order.
+	Chunks []WorkspaceChunk `json:"chunks"`
+}
+
+// WorkspaceChunk describes a single chunk of a chunked workspace backup.
+type WorkspaceChunk struct {
+	Digest digest.Digest `json:"digest"`
+	Size   int64         `json:"size"`
+}
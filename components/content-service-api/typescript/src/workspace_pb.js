@@ -25,6 +25,8 @@ var global = function () {
 
 goog.exportSymbol("proto.contentservice.DeleteWorkspaceRequest", null, global);
 goog.exportSymbol("proto.contentservice.DeleteWorkspaceResponse", null, global);
+goog.exportSymbol("proto.contentservice.GarbageCollectChunksRequest", null, global);
+goog.exportSymbol("proto.contentservice.GarbageCollectChunksResponse", null, global);
 goog.exportSymbol("proto.contentservice.WorkspaceDownloadURLRequest", null, global);
 goog.exportSymbol("proto.contentservice.WorkspaceDownloadURLResponse", null, global);
 goog.exportSymbol("proto.contentservice.WorkspaceSnapshotExistsRequest", null, global);
@@ -157,6 +159,48 @@ if (goog.DEBUG && !COMPILED) {
     proto.contentservice.WorkspaceSnapshotExistsResponse.displayName =
         "proto.contentservice.WorkspaceSnapshotExistsResponse";
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.contentservice.GarbageCollectChunksRequest = function (opt_data) {
+    jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.contentservice.GarbageCollectChunksRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+    /**
+     * @public
+     * @override
+     */
+    proto.contentservice.GarbageCollectChunksRequest.displayName = "proto.contentservice.GarbageCollectChunksRequest";
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.contentservice.GarbageCollectChunksResponse = function (opt_data) {
+    jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.contentservice.GarbageCollectChunksResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+    /**
+     * @public
+     * @override
+     */
+    proto.contentservice.GarbageCollectChunksResponse.displayName = "proto.contentservice.GarbageCollectChunksResponse";
+}
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
     /**
@@ -959,4 +1003,263 @@ proto.contentservice.WorkspaceSnapshotExistsResponse.prototype.setExists = funct
     return jspb.Message.setProto3BooleanField(this, 1, value);
 };
 
+if (jspb.Message.GENERATE_TO_OBJECT) {
+    /**
+     * Creates an object representation of this proto.
+     * Field names that are reserved in JavaScript and will be renamed to pb_name.
+     * Optional fields that are not set will be set to undefined.
+     * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+     * For the list of reserved names please see:
+     *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+     * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+     *     JSPB instance for transitional soy proto support:
+     *     http://goto/soy-param-migration
+     * @return {!Object}
+     */
+    proto.contentservice.GarbageCollectChunksRequest.prototype.toObject = function (opt_includeInstance) {
+        return proto.contentservice.GarbageCollectChunksRequest.toObject(opt_includeInstance, this);
+    };
+
+    /**
+     * Static version of the {@see toObject} method.
+     * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+     *     the JSPB instance for transitional soy proto support:
+     *     http://goto/soy-param-migration
+     * @param {!proto.contentservice.GarbageCollectChunksRequest} msg The msg instance to transform.
+     * @return {!Object}
+     * @suppress {unusedLocalVariables} f is only used for nested messages
+     */
+    proto.contentservice.GarbageCollectChunksRequest.toObject = function (includeInstance, msg) {
+        var f,
+            obj = {
+                ownerId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+                workspaceId: jspb.Message.getFieldWithDefault(msg, 2, ""),
+            };
+
+        if (includeInstance) {
+            obj.$jspbMessageInstance = msg;
+        }
+        return obj;
+    };
+}
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.contentservice.GarbageCollectChunksRequest}
+ */
+proto.contentservice.GarbageCollectChunksRequest.deserializeBinary = function (bytes) {
+    var reader = new jspb.BinaryReader(bytes);
+    var msg = new proto.contentservice.GarbageCollectChunksRequest();
+    return proto.contentservice.GarbageCollectChunksRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.contentservice.GarbageCollectChunksRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.contentservice.GarbageCollectChunksRequest}
+ */
+proto.contentservice.GarbageCollectChunksRequest.deserializeBinaryFromReader = function (msg, reader) {
+    while (reader.nextField()) {
+        if (reader.isEndGroup()) {
+            break;
+        }
+        var field = reader.getFieldNumber();
+        switch (field) {
+            case 1:
+                var value = /** @type {string} */ (reader.readString());
+                msg.setOwnerId(value);
+                break;
+            case 2:
+                var value = /** @type {string} */ (reader.readString());
+                msg.setWorkspaceId(value);
+                break;
+            default:
+                reader.skipField();
+                break;
+        }
+    }
+    return msg;
+};
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.contentservice.GarbageCollectChunksRequest.prototype.serializeBinary = function () {
+    var writer = new jspb.BinaryWriter();
+    proto.contentservice.GarbageCollectChunksRequest.serializeBinaryToWriter(this, writer);
+    return writer.getResultBuffer();
+};
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.contentservice.GarbageCollectChunksRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.GarbageCollectChunksRequest.serializeBinaryToWriter = function (message, writer) {
+    var f = undefined;
+    f = message.getOwnerId();
+    if (f.length > 0) {
+        writer.writeString(1, f);
+    }
+    f = message.getWorkspaceId();
+    if (f.length > 0) {
+        writer.writeString(2, f);
+    }
+};
+
+/**
+ * optional string owner_id = 1;
+ * @return {string}
+ */
+proto.contentservice.GarbageCollectChunksRequest.prototype.getOwnerId = function () {
+    return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+/**
+ * @param {string} value
+ * @return {!proto.contentservice.GarbageCollectChunksRequest} returns this
+ */
+proto.contentservice.GarbageCollectChunksRequest.prototype.setOwnerId = function (value) {
+    return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+/**
+ * optional string workspace_id = 2;
+ * @return {string}
+ */
+proto.contentservice.GarbageCollectChunksRequest.prototype.getWorkspaceId = function () {
+    return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+/**
+ * @param {string} value
+ * @return {!proto.contentservice.GarbageCollectChunksRequest} returns this
+ */
+proto.contentservice.GarbageCollectChunksRequest.prototype.setWorkspaceId = function (value) {
+    return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+    /**
+     * Creates an object representation of this proto.
+     * Field names that are reserved in JavaScript and will be renamed to pb_name.
+     * Optional fields that are not set will be set to undefined.
+     * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+     * For the list of reserved names please see:
+     *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+     * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+     *     JSPB instance for transitional soy proto support:
+     *     http://goto/soy-param-migration
+     * @return {!Object}
+     */
+    proto.contentservice.GarbageCollectChunksResponse.prototype.toObject = function (opt_includeInstance) {
+        return proto.contentservice.GarbageCollectChunksResponse.toObject(opt_includeInstance, this);
+    };
+
+    /**
+     * Static version of the {@see toObject} method.
+     * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+     *     the JSPB instance for transitional soy proto support:
+     *     http://goto/soy-param-migration
+     * @param {!proto.contentservice.GarbageCollectChunksResponse} msg The msg instance to transform.
+     * @return {!Object}
+     * @suppress {unusedLocalVariables} f is only used for nested messages
+     */
+    proto.contentservice.GarbageCollectChunksResponse.toObject = function (includeInstance, msg) {
+        var f,
+            obj = {
+                deletedChunks: jspb.Message.getFieldWithDefault(msg, 1, 0),
+            };
+
+        if (includeInstance) {
+            obj.$jspbMessageInstance = msg;
+        }
+        return obj;
+    };
+}
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.contentservice.GarbageCollectChunksResponse}
+ */
+proto.contentservice.GarbageCollectChunksResponse.deserializeBinary = function (bytes) {
+    var reader = new jspb.BinaryReader(bytes);
+    var msg = new proto.contentservice.GarbageCollectChunksResponse();
+    return proto.contentservice.GarbageCollectChunksResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.contentservice.GarbageCollectChunksResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.contentservice.GarbageCollectChunksResponse}
+ */
+proto.contentservice.GarbageCollectChunksResponse.deserializeBinaryFromReader = function (msg, reader) {
+    while (reader.nextField()) {
+        if (reader.isEndGroup()) {
+            break;
+        }
+        var field = reader.getFieldNumber();
+        switch (field) {
+            case 1:
+                var value = /** @type {number} */ (reader.readInt64());
+                msg.setDeletedChunks(value);
+                break;
+            default:
+                reader.skipField();
+                break;
+        }
+    }
+    return msg;
+};
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.contentservice.GarbageCollectChunksResponse.prototype.serializeBinary = function () {
+    var writer = new jspb.BinaryWriter();
+    proto.contentservice.GarbageCollectChunksResponse.serializeBinaryToWriter(this, writer);
+    return writer.getResultBuffer();
+};
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.contentservice.GarbageCollectChunksResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.contentservice.GarbageCollectChunksResponse.serializeBinaryToWriter = function (message, writer) {
+    var f = undefined;
+    f = message.getDeletedChunks();
+    if (f !== 0) {
+        writer.writeInt64(1, f);
+    }
+};
+
+/**
+ * optional int64 deleted_chunks = 1;
+ * @return {number}
+ */
+proto.contentservice.GarbageCollectChunksResponse.prototype.getDeletedChunks = function () {
+    return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+/**
+ * @param {number} value
+ * @return {!proto.contentservice.GarbageCollectChunksResponse} returns this
+ */
+proto.contentservice.GarbageCollectChunksResponse.prototype.setDeletedChunks = function (value) {
+    return jspb.Message.setProto3IntField(this, 1, value);
+};
+
 goog.object.extend(exports, proto.contentservice);
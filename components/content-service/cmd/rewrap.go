@@ -0,0 +1,117 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"path"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/content-service/pkg/encryption"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+var rewrapBuckets []string
+
+// rewrapCmd re-wraps the data keys of encrypted backups after a key rotation
+var rewrapCmd = &cobra.Command{
+	Use:   "rewrap [<ownerID> <workspaceID>...]",
+	Short: "Wraps the data keys of encrypted backups and snapshots using the current key of the KMS",
+	Long: `Wraps the data keys of encrypted backups and snapshots using the current key of the KMS.
+
+Either re-wraps the backups of the given workspaces of an owner, or - using --bucket - all backups
+stored in the given buckets.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(rewrapBuckets) > 0 {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := getConfig()
+		if cfg.Encryption == nil {
+			return xerrors.Errorf("no encryption configured")
+		}
+		kms, err := encryption.NewKMS(cfg.Encryption)
+		if err != nil {
+			return err
+		}
+		ps, err := storage.NewPresignedAccess(&cfg.Storage)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		if len(rewrapBuckets) > 0 {
+			for _, bucket := range rewrapBuckets {
+				err = rewrapBucket(ctx, kms, ps, bucket)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		owner := args[0]
+		bucket := ps.Bucket(owner)
+		for _, workspaceID := range args[1:] {
+			prefix := path.Dir(ps.BackupObject(owner, workspaceID, storage.DefaultBackup)) + "/"
+			objs, err := ps.ListObjects(ctx, bucket, prefix)
+			if err != nil {
+				return xerrors.Errorf("cannot list objects of workspace %s: %w", workspaceID, err)
+			}
+
+			for _, obj := range objs {
+				err = rewrap(ctx, kms, ps, owner, bucket, obj)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	},
+}
+
+// rewrapBucket re-wraps the data keys of all backups in a bucket, using the scope of the owner each backup is stored for
+func rewrapBucket(ctx context.Context, kms encryption.KMS, ps storage.PresignedAccess, bucket string) error {
+	objs, err := ps.ListObjects(ctx, bucket, "")
+	if err != nil {
+		return xerrors.Errorf("cannot list objects of bucket %s: %w", bucket, err)
+	}
+
+	for _, obj := range objs {
+		owner, err := storage.BackupOwner(ps, bucket, obj)
+		if err != nil {
+			// only backups are encrypted
+			log.WithField("bucket", bucket).WithField("object", obj).Debug("skipping object which is no backup")
+			continue
+		}
+		err = rewrap(ctx, kms, ps, owner, bucket, obj)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rewrap(ctx context.Context, kms encryption.KMS, ps storage.PresignedAccess, owner, bucket, obj string) error {
+	rewrapped, err := encryption.Rewrap(ctx, kms, ps, owner, bucket, obj)
+	if err != nil {
+		return xerrors.Errorf("cannot re-wrap data key of %s: %w", obj, err)
+	}
+	if rewrapped {
+		log.WithField("bucket", bucket).WithField("object", obj).Info("re-wrapped data key")
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(rewrapCmd)
+	rewrapCmd.Flags().StringSliceVar(&rewrapBuckets, "bucket", nil, "re-wrap all backups stored in this bucket")
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/baseserver"
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/api/config"
 	"github.com/gitpod-io/gitpod/content-service/pkg/service"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -20,14 +22,31 @@ var runCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := getConfig()
 
-		srv, err := baseserver.New("content-service",
+		opts := []baseserver.Option{
 			baseserver.WithGRPC(&cfg.Service),
 			baseserver.WithVersion(Version),
-		)
+		}
+		if cfg.Storage.Kind == config.LocalStorage {
+			// the presigned URLs of the local storage are served by content-service itself
+			if cfg.HTTP == nil {
+				log.Fatal("Local storage requires the HTTP server to be configured.")
+			}
+			opts = append(opts, baseserver.WithHTTP(cfg.HTTP))
+		}
+
+		srv, err := baseserver.New("content-service", opts...)
 		if err != nil {
 			log.WithError(err).Fatal("Failed to create server.")
 		}
 
+		if cfg.Storage.Kind == config.LocalStorage {
+			localStorage, err := storage.NewPresignedLocalAccess(cfg.Storage.LocalConfig)
+			if err != nil {
+				log.WithError(err).Fatal("Cannot create local storage")
+			}
+			srv.HTTPMux().Handle(storage.LocalStoragePath, localStorage)
+		}
+
 		contentService, err := service.NewContentService(cfg.Storage)
 		if err != nil {
 			log.WithError(err).Fatalf("Cannot create content service")
@@ -8,6 +8,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
 )
 
 var options testOpts
@@ -129,10 +132,11 @@ var presignedCmd = &cobra.Command{
 }
 
 var presignedUploadCmd = &cobra.Command{
-	Use:     "upload <key>",
+	Use:     "upload <key> [file]",
 	Short:   "upload",
-	Args:    cobra.ExactArgs(1),
-	Example: "upload gitpod-signed",
+	Long:    "Prints a presigned upload URL for key and uploads file to it if given. Uploads to the URL must set the \"x-ms-blob-type: BlockBlob\" header.",
+	Args:    cobra.RangeArgs(1, 2),
+	Example: "upload gitpod-signed ./content.tar",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		presigned, err := createPresignedAccess()
 		if err != nil {
@@ -145,6 +149,34 @@ var presignedUploadCmd = &cobra.Command{
 		}
 
 		fmt.Printf("%s\n", info.URL)
+		if len(args) < 2 {
+			return nil
+		}
+
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		stat, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequest(http.MethodPut, info.URL, f)
+		if err != nil {
+			return err
+		}
+		req.ContentLength = stat.Size()
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(resp.Body)
+			return xerrors.Errorf("upload failed with status %s: %s", resp.Status, body)
+		}
 		return nil
 	},
 }
@@ -6,6 +6,8 @@ package archive
 
 import (
 	"archive/tar"
+	"bufio"
+	"bytes"
 	"context"
 	"io"
 	"os"
@@ -16,6 +18,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/opentracing/opentracing-go"
 	"golang.org/x/sys/unix"
 	"golang.org/x/xerrors"
@@ -54,7 +57,11 @@ func WithGIDMapping(mappings []IDMapping) TarOption {
 	}
 }
 
-// ExtractTarbal extracts an OCI compatible tar file src to the folder dst, expecting the overlay whiteout format
+// zstdMagic starts every zstd frame
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// ExtractTarbal extracts an OCI compatible tar file src to the folder dst, expecting the overlay whiteout format.
+// src may be compressed using zstd.
 func ExtractTarbal(ctx context.Context, src io.Reader, dst string, opts ...TarOption) (err error) {
 	type Info struct {
 		UID, GID  int
@@ -73,6 +80,18 @@ func ExtractTarbal(ctx context.Context, src io.Reader, dst string, opts ...TarOp
 		opt(&cfg)
 	}
 
+	// Streamed backups are compressed using zstd
+	bsrc := bufio.NewReader(src)
+	src = bsrc
+	if magic, _ := bsrc.Peek(len(zstdMagic)); bytes.Equal(magic, zstdMagic) {
+		dec, err := zstd.NewReader(bsrc)
+		if err != nil {
+			return xerrors.Errorf("cannot decompress tarbal: %w", err)
+		}
+		defer dec.Close()
+		src = dec
+	}
+
 	pipeReader, pipeWriter := io.Pipe()
 	teeReader := io.TeeReader(src, pipeWriter)
 
@@ -13,6 +13,8 @@ import (
 	"path/filepath"
 	"syscall"
 	"testing"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 func TestExtractTarbal(t *testing.T) {
@@ -25,6 +27,7 @@ func TestExtractTarbal(t *testing.T) {
 	tests := []struct {
 		Name  string
 		Files []file
+		Zstd  bool
 	}{
 		{
 			Name: "simple-test",
@@ -37,6 +40,13 @@ func TestExtractTarbal(t *testing.T) {
 			Name:  "empty-tar",
 			Files: []file{},
 		},
+		{
+			Name: "zstd-compressed",
+			Files: []file{
+				{"file.txt", 1024, 33333, 0644},
+			},
+			Zstd: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -66,6 +76,20 @@ func TestExtractTarbal(t *testing.T) {
 			tw.Flush()
 			tw.Close()
 
+			if test.Zstd {
+				var compressed bytes.Buffer
+				enc, err := zstd.NewWriter(&compressed)
+				if err != nil {
+					t.Fatalf("cannot prepare archive: %q", err)
+				}
+				_, err = enc.Write(buf.Bytes())
+				if err != nil {
+					t.Fatalf("cannot prepare archive: %q", err)
+				}
+				enc.Close()
+				buf = &compressed
+			}
+
 			wd, err := os.MkdirTemp("", "")
 			defer os.RemoveAll(wd)
 			if err != nil {
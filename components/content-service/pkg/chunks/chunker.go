@@ -0,0 +1,118 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package chunks
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// gear is the table of the rolling gear hash. It must never change, or the chunk boundaries
+// of existing backups are no longer found and none of their chunks are reused.
+var gear = func() (res [256]uint64) {
+	// splitmix64 with a fixed seed
+	state := uint64(0x6770746368756e6b)
+	for i := range res {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		res[i] = z ^ (z >> 31)
+	}
+	return
+}()
+
+// Split reads r until EOF and calls fn for every chunk. Chunk boundaries are found using FastCDC
+// with normalized chunking: below the average size a boundary is less likely than above it, which
+// narrows the distribution of the chunk sizes. The chunk buffer is reused once fn returns.
+func Split(r io.Reader, opts Options, fn func(chunk []byte) error) error {
+	err := opts.validate()
+	if err != nil {
+		return err
+	}
+
+	var (
+		buf = make([]byte, opts.MaxSize)
+		n   int
+		eof bool
+		// the hash bits which have to be zero for a boundary
+		avgBits   = bits.Len(uint(opts.AvgSize)) - 1
+		maskSmall = mask(avgBits + 1)
+		maskLarge = mask(avgBits - 1)
+	)
+	for {
+		if !eof && n < len(buf) {
+			var read int
+			read, err = io.ReadFull(r, buf[n:])
+			n += read
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				eof = true
+			} else if err != nil {
+				return err
+			}
+		}
+		if n == 0 {
+			return nil
+		}
+
+		cut := boundary(buf[:n], opts, maskSmall, maskLarge)
+		err = fn(buf[:cut])
+		if err != nil {
+			return err
+		}
+		n = copy(buf, buf[cut:n])
+	}
+}
+
+// boundary returns the length of the next chunk of data
+func boundary(data []byte, opts Options, maskSmall, maskLarge uint64) int {
+	if len(data) <= opts.MinSize {
+		return len(data)
+	}
+	var (
+		hash   uint64
+		normal = opts.AvgSize
+		end    = len(data)
+	)
+	if end > opts.MaxSize {
+		end = opts.MaxSize
+	}
+	if normal > end {
+		normal = end
+	}
+	// the hash covers the last 64 bytes, hence the bytes before the minimum size only need to warm it up
+	i := opts.MinSize - 64
+	if i < 0 {
+		i = 0
+	}
+	for ; i < opts.MinSize; i++ {
+		hash = (hash << 1) + gear[data[i]]
+	}
+	for ; i < normal; i++ {
+		hash = (hash << 1) + gear[data[i]]
+		if hash&maskSmall == 0 {
+			return i + 1
+		}
+	}
+	for ; i < end; i++ {
+		hash = (hash << 1) + gear[data[i]]
+		if hash&maskLarge == 0 {
+			return i + 1
+		}
+	}
+	return end
+}
+
+// mask returns a mask of the n most significant bits, which are the best mixed bits of the gear hash
+func mask(n int) uint64 {
+	if n <= 0 {
+		return 0
+	}
+	if n >= 64 {
+		return ^uint64(0)
+	}
+	return ^uint64(0) << (64 - n)
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+// Package chunks splits workspace backups into content-defined chunks and reassembles them.
+//
+// Chunk boundaries depend on the content only, hence an insertion or deletion in a file changes
+// the chunks around it, but not the rest of the archive. Chunks are content addressed, s.t.
+// consecutive backups of a workspace only need to upload the chunks which have changed.
+package chunks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+)
+
+const (
+	// Prefix is the prefix of the chunk objects, relative to the backup location of a workspace.
+	Prefix = "chunks/"
+
+	// DefaultMinSize is the default minimum size of a chunk
+	DefaultMinSize = 512 * 1024
+	// DefaultAvgSize is the default size chunks are aimed at
+	DefaultAvgSize = 2 * 1024 * 1024
+	// DefaultMaxSize is the default maximum size of a chunk
+	DefaultMaxSize = 8 * 1024 * 1024
+)
+
+// ObjectName returns the object name of a chunk relative to the backup location of a workspace.
+func ObjectName(dgst digest.Digest) string {
+	return fmt.Sprintf("%s%s/%s", Prefix, dgst.Algorithm(), dgst.Encoded())
+}
+
+// Options configure the chunk sizes. Changing them changes the chunk boundaries, which prevents
+// the chunks of previous backups from being reused.
+type Options struct {
+	MinSize int
+	AvgSize int
+	MaxSize int
+}
+
+// DefaultOptions are the chunk sizes used for workspace backups
+var DefaultOptions = Options{
+	MinSize: DefaultMinSize,
+	AvgSize: DefaultAvgSize,
+	MaxSize: DefaultMaxSize,
+}
+
+func (o Options) validate() error {
+	if o.MinSize <= 0 || o.MinSize > o.AvgSize || o.AvgSize > o.MaxSize {
+		return xerrors.Errorf("invalid chunk sizes: min %d, avg %d, max %d", o.MinSize, o.AvgSize, o.MaxSize)
+	}
+	return nil
+}
+
+// UploadFunc stores a chunk. It is called concurrently for different chunks.
+type UploadFunc func(ctx context.Context, dgst digest.Digest, chunk []byte) error
+
+// Result describes a chunked archive
+type Result struct {
+	Index *csapi.WorkspaceChunkIndex
+
+	// UploadedChunks and UploadedBytes count the chunks which have been uploaded
+	UploadedChunks int
+	UploadedBytes  int64
+}
+
+// Build splits the archive into chunks and uploads every chunk for which exists returns false, up to
+// concurrency chunks at a time. Each chunk is uploaded at most once, even if it occurs multiple times in the archive.
+func Build(ctx context.Context, archive io.Reader, opts Options, concurrency int, exists func(dgst digest.Digest) bool, upload UploadFunc) (*Result, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(concurrency)
+
+	var (
+		res = &Result{
+			Index: &csapi.WorkspaceChunkIndex{},
+		}
+		archiveDigest = digest.Canonical.Digester()
+		uploaded      = make(map[digest.Digest]struct{})
+	)
+	err := Split(archive, opts, func(chunk []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		_, _ = archiveDigest.Hash().Write(chunk)
+
+		dgst := digest.FromBytes(chunk)
+		res.Index.Size += int64(len(chunk))
+		res.Index.Chunks = append(res.Index.Chunks, csapi.WorkspaceChunk{
+			Digest: dgst,
+			Size:   int64(len(chunk)),
+		})
+		if _, ok := uploaded[dgst]; ok || exists(dgst) {
+			return nil
+		}
+		uploaded[dgst] = struct{}{}
+		res.UploadedChunks++
+		res.UploadedBytes += int64(len(chunk))
+
+		// Split reuses the chunk buffer, Go blocks until an upload slot is free
+		data := append([]byte(nil), chunk...)
+		eg.Go(func() error {
+			err := upload(ctx, dgst, data)
+			if err != nil {
+				return xerrors.Errorf("cannot upload chunk %s: %w", dgst, err)
+			}
+			return nil
+		})
+		return nil
+	})
+	uerr := eg.Wait()
+	if uerr != nil {
+		// a failed upload cancels the context, which is what Split reports then
+		return nil, uerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	res.Index.Digest = archiveDigest.Digest()
+	return res, nil
+}
+
+// FetchFunc downloads a chunk
+type FetchFunc func(ctx context.Context, chunk csapi.WorkspaceChunk) (io.ReadCloser, error)
+
+// Assemble writes the archive described by the index to w. Up to concurrency chunks are downloaded ahead
+// of time. The digests of the chunks and of the archive are verified.
+func Assemble(ctx context.Context, w io.Writer, index *csapi.WorkspaceChunkIndex, concurrency int, fetch FetchFunc) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type fetchResult struct {
+		data []byte
+		err  error
+	}
+	var (
+		results = make([]chan fetchResult, len(index.Chunks))
+		slots   = make(chan struct{}, concurrency)
+	)
+	for i := range results {
+		results[i] = make(chan fetchResult, 1)
+	}
+	go func() {
+		for i, chunk := range index.Chunks {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(i int, chunk csapi.WorkspaceChunk) {
+				data, err := fetchChunk(ctx, chunk, fetch)
+				results[i] <- fetchResult{data, err}
+			}(i, chunk)
+		}
+	}()
+
+	archiveDigest := digest.Canonical.Digester()
+	var size int64
+	for i := range index.Chunks {
+		var res fetchResult
+		select {
+		case res = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		<-slots
+		if res.err != nil {
+			return res.err
+		}
+		_, _ = archiveDigest.Hash().Write(res.data)
+		size += int64(len(res.data))
+		_, err := w.Write(res.data)
+		if err != nil {
+			return err
+		}
+	}
+	if size != index.Size {
+		return xerrors.Errorf("archive has %d bytes, expected %d", size, index.Size)
+	}
+	if index.Digest != "" && archiveDigest.Digest() != index.Digest {
+		return xerrors.Errorf("archive digest %s does not match %s", archiveDigest.Digest(), index.Digest)
+	}
+	return nil
+}
+
+func fetchChunk(ctx context.Context, chunk csapi.WorkspaceChunk, fetch FetchFunc) ([]byte, error) {
+	rc, err := fetch(ctx, chunk)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot download chunk %s: %w", chunk.Digest, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, chunk.Size+1))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot download chunk %s: %w", chunk.Digest, err)
+	}
+	if int64(len(data)) != chunk.Size {
+		return nil, xerrors.Errorf("chunk %s has %d bytes, expected %d", chunk.Digest, len(data), chunk.Size)
+	}
+	if err := chunk.Digest.Validate(); err != nil {
+		return nil, xerrors.Errorf("invalid chunk digest: %w", err)
+	}
+	if actual := chunk.Digest.Algorithm().FromBytes(data); actual != chunk.Digest {
+		return nil, xerrors.Errorf("chunk %s has digest %s", chunk.Digest, actual)
+	}
+	return data, nil
+}
+
+// Unreferenced returns the chunk objects which are not referenced by any of the indices.
+// Objects are full object names below prefix, e.g. as returned by listing the chunks of a workspace.
+func Unreferenced(objects []string, prefix string, indices ...*csapi.WorkspaceChunkIndex) []string {
+	referenced := make(map[string]struct{})
+	for _, index := range indices {
+		if index == nil {
+			continue
+		}
+		for _, chunk := range index.Chunks {
+			referenced[ObjectName(chunk.Digest)] = struct{}{}
+		}
+	}
+
+	var res []string
+	for _, obj := range objects {
+		name := strings.TrimPrefix(obj, prefix)
+		if !strings.HasPrefix(name, Prefix) {
+			continue
+		}
+		if _, ok := referenced[name]; ok {
+			continue
+		}
+		res = append(res, obj)
+	}
+	return res
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Minute}
+
+// Download downloads an object from a presigned URL.
+func Download(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, xerrors.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package chunks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opencontainers/go-digest"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+)
+
+var testOptions = Options{
+	MinSize: 1024,
+	AvgSize: 4096,
+	MaxSize: 16384,
+}
+
+type memoryStore map[digest.Digest][]byte
+
+// memoryStoreMu guards all memory stores as chunks are uploaded concurrently
+var memoryStoreMu sync.Mutex
+
+func (s memoryStore) exists(dgst digest.Digest) bool {
+	memoryStoreMu.Lock()
+	defer memoryStoreMu.Unlock()
+	_, ok := s[dgst]
+	return ok
+}
+
+func (s memoryStore) upload(ctx context.Context, dgst digest.Digest, chunk []byte) error {
+	memoryStoreMu.Lock()
+	defer memoryStoreMu.Unlock()
+	s[dgst] = append([]byte(nil), chunk...)
+	return nil
+}
+
+func (s memoryStore) fetch(ctx context.Context, chunk csapi.WorkspaceChunk) (io.ReadCloser, error) {
+	memoryStoreMu.Lock()
+	defer memoryStoreMu.Unlock()
+	return io.NopCloser(bytes.NewReader(s[chunk.Digest])), nil
+}
+
+func randomData(seed int64, size int) []byte {
+	res := make([]byte, size)
+	_, _ = rand.New(rand.NewSource(seed)).Read(res)
+	return res
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		Name string
+		Data []byte
+	}{
+		{Name: "empty"},
+		{Name: "smaller than min size", Data: randomData(1, 100)},
+		{Name: "random", Data: randomData(2, 1024*1024)},
+		{Name: "zeros", Data: make([]byte, 100*1024)},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				joined []byte
+				count  int
+			)
+			err := Split(bytes.NewReader(test.Data), testOptions, func(chunk []byte) error {
+				count++
+				if len(chunk) > testOptions.MaxSize {
+					t.Errorf("chunk %d has %d bytes, more than the max size", count, len(chunk))
+				}
+				joined = append(joined, chunk...)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(joined, test.Data) {
+				t.Errorf("chunks do not add up to the data")
+			}
+		})
+	}
+}
+
+func TestBuildAndAssemble(t *testing.T) {
+	ctx := context.Background()
+	store := make(memoryStore)
+
+	original := randomData(3, 2*1024*1024)
+	first, err := Build(ctx, bytes.NewReader(original), testOptions, 4, store.exists, store.upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Index.Digest != digest.FromBytes(original) {
+		t.Errorf("unexpected archive digest %s", first.Index.Digest)
+	}
+	if first.UploadedBytes != int64(len(original)) {
+		t.Errorf("expected all %d bytes to be uploaded, got %d", len(original), first.UploadedBytes)
+	}
+
+	// insert some bytes in the middle, which shifts all following content
+	modified := append(append(append([]byte(nil), original[:1024*1024]...), []byte("hello world")...), original[1024*1024:]...)
+	second, err := Build(ctx, bytes.NewReader(modified), testOptions, 4, store.exists, store.upload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.UploadedChunks == 0 || second.UploadedBytes > int64(len(modified)/10) {
+		t.Errorf("expected only the changed chunks to be uploaded, got %d chunks with %d bytes", second.UploadedChunks, second.UploadedBytes)
+	}
+
+	for _, test := range []struct {
+		Name     string
+		Index    *csapi.WorkspaceChunkIndex
+		Expected []byte
+	}{
+		{Name: "original", Index: first.Index, Expected: original},
+		{Name: "modified", Index: second.Index, Expected: modified},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Assemble(ctx, &buf, test.Index, 4, store.fetch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), test.Expected) {
+				t.Errorf("reassembled archive differs")
+			}
+		})
+	}
+
+	t.Run("corrupted chunk", func(t *testing.T) {
+		corrupted := make(memoryStore)
+		for dgst, chunk := range store {
+			corrupted[dgst] = chunk
+		}
+		dgst := first.Index.Chunks[1].Digest
+		chunk := append([]byte(nil), corrupted[dgst]...)
+		chunk[0] ^= 0xff
+		corrupted[dgst] = chunk
+
+		err := Assemble(ctx, io.Discard, first.Index, 4, corrupted.fetch)
+		if err == nil {
+			t.Errorf("expected corrupted chunk to be detected")
+		}
+	})
+}
+
+func TestBuildFailedUpload(t *testing.T) {
+	_, err := Build(context.Background(), bytes.NewReader(randomData(4, 256*1024)), testOptions, 4,
+		func(dgst digest.Digest) bool { return false },
+		func(ctx context.Context, dgst digest.Digest, chunk []byte) error { return io.ErrShortWrite },
+	)
+	if !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("expected the upload error, got %v", err)
+	}
+}
+
+func TestUnreferenced(t *testing.T) {
+	var (
+		a      = digest.FromString("a")
+		b      = digest.FromString("b")
+		c      = digest.FromString("c")
+		prefix = "workspaces/ws1/"
+	)
+	objects := []string{
+		prefix + "full.tar",
+		prefix + ObjectName(a),
+		prefix + ObjectName(b),
+		prefix + ObjectName(c),
+	}
+	act := Unreferenced(objects, prefix,
+		&csapi.WorkspaceChunkIndex{Chunks: []csapi.WorkspaceChunk{{Digest: a}}},
+		&csapi.WorkspaceChunkIndex{Chunks: []csapi.WorkspaceChunk{{Digest: c}, {Digest: a}}},
+		nil,
+	)
+	if diff := cmp.Diff([]string{prefix + ObjectName(b)}, act); diff != "" {
+		t.Errorf("unexpected unreferenced chunks (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package encryption_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/content-service/pkg/encryption"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+const segmentSize = 64 * 1024
+
+func TestStreamRoundtrip(t *testing.T) {
+	tests := []struct {
+		Name string
+		Size int
+	}{
+		{Name: "empty", Size: 0},
+		{Name: "single byte", Size: 1},
+		{Name: "less than a segment", Size: segmentSize - 1},
+		{Name: "exactly one segment", Size: segmentSize},
+		{Name: "more than a segment", Size: segmentSize + 1},
+		{Name: "multiple segments", Size: 3*segmentSize + 42},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			key := newKey(t)
+			content := make([]byte, test.Size)
+			_, _ = rand.Read(content)
+
+			encrypted := encrypt(t, key, content)
+			// short content may well appear in the ciphertext by chance
+			if test.Size >= 16 && bytes.Contains(encrypted, content) {
+				t.Fatal("encrypted content contains the plain content")
+			}
+
+			r, err := encryption.NewReader(bytes.NewReader(encrypted), key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			act, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(act, content) {
+				t.Errorf("decrypted content differs: got %d bytes, expected %d", len(act), len(content))
+			}
+		})
+	}
+}
+
+func TestStreamTampering(t *testing.T) {
+	key := newKey(t)
+	content := make([]byte, 2*segmentSize+100)
+	_, _ = rand.Read(content)
+	encrypted := encrypt(t, key, content)
+	// magic and nonce prefix
+	const headerSize = 6 + 7
+	const sealedSegmentSize = segmentSize + 16
+
+	tests := []struct {
+		Name   string
+		Key    []byte
+		Modify func(c []byte) []byte
+	}{
+		{
+			Name:   "flipped bit",
+			Modify: func(c []byte) []byte { c[headerSize+10] ^= 1; return c },
+		},
+		{
+			Name:   "truncated last segment",
+			Modify: func(c []byte) []byte { return c[:len(c)-10] },
+		},
+		{
+			Name:   "dropped last segment",
+			Modify: func(c []byte) []byte { return c[:headerSize+2*sealedSegmentSize] },
+		},
+		{
+			Name: "reordered segments",
+			Modify: func(c []byte) []byte {
+				res := append([]byte{}, c[:headerSize]...)
+				res = append(res, c[headerSize+sealedSegmentSize:headerSize+2*sealedSegmentSize]...)
+				res = append(res, c[headerSize:headerSize+sealedSegmentSize]...)
+				return append(res, c[headerSize+2*sealedSegmentSize:]...)
+			},
+		},
+		{
+			Name:   "wrong key",
+			Key:    newKey(t),
+			Modify: func(c []byte) []byte { return c },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			k := key
+			if test.Key != nil {
+				k = test.Key
+			}
+			c := test.Modify(append([]byte{}, encrypted...))
+
+			r, err := encryption.NewReader(bytes.NewReader(c), k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = io.ReadAll(r)
+			if err == nil {
+				t.Fatal("expected an error when decrypting modified content")
+			}
+		})
+	}
+
+	_, err := encryption.NewReader(bytes.NewReader(content), key)
+	if err != encryption.ErrNotEncrypted {
+		t.Errorf("expected ErrNotEncrypted for plain content, got %v", err)
+	}
+}
+
+func TestKeyfileKMS(t *testing.T) {
+	ctx := context.Background()
+	v1 := writeKeyfile(t, "v1", map[string][]byte{"v1": newKey(t)})
+	kms, err := encryption.NewKeyfileKMS(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dk, err := encryption.NewDataKey(ctx, kms, "user-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dk.KeyID != "v1/user-a" {
+		t.Errorf("unexpected key ID: %s", dk.KeyID)
+	}
+
+	key, err := kms.UnwrapKey(ctx, "user-a", dk.KeyID, dk.Wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, dk.Key()) {
+		t.Error("unwrapped data key differs")
+	}
+
+	_, err = kms.UnwrapKey(ctx, "user-b", "v1/user-b", dk.Wrapped)
+	if err == nil {
+		t.Error("expected data key of one scope not to unwrap in another")
+	}
+	_, err = kms.UnwrapKey(ctx, "user-b", dk.KeyID, dk.Wrapped)
+	if !errors.Is(err, encryption.ErrScopeMismatch) {
+		t.Errorf("expected data key of one scope not to unwrap when another is expected, got %v", err)
+	}
+	_, err = kms.UnwrapKey(ctx, "user-a", "v0/user-a", dk.Wrapped)
+	if err == nil {
+		t.Error("expected unknown key to fail")
+	}
+
+	for name, keys := range map[string]map[string][]byte{
+		"missing primary key": {"v2": newKey(t)},
+		"short key":           {"v1": []byte("too short")},
+		"invalid name":        {"v1": newKey(t), "v/2": newKey(t)},
+	} {
+		_, err := encryption.NewKeyfileKMS(writeKeyfile(t, "v1", keys))
+		if err == nil {
+			t.Errorf("expected keyfile with %s to be invalid", name)
+		}
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	ctx := context.Background()
+	var (
+		owner  = "user-a"
+		k1, k2 = newKey(t), newKey(t)
+	)
+	kmsV1, err := encryption.NewKeyfileKMS(writeKeyfile(t, "v1", map[string][]byte{"v1": k1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kmsV2, err := encryption.NewKeyfileKMS(writeKeyfile(t, "v2", map[string][]byte{"v1": k1, "v2": k2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.StorageConfig{
+		Stage: config.StageDevStaging,
+		Kind:  config.LocalStorage,
+		LocalConfig: &config.LocalConfig{
+			Path:   t.TempDir(),
+			URL:    "http://localhost:8080",
+			Secret: "test-secret",
+		},
+	}
+	rs, err := storage.NewDirectAccess(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rs.Init(ctx, owner, "workspace", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps, err := storage.NewPresignedAccess(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dk, err := encryption.NewDataKey(ctx, kmsV1, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(t.TempDir(), "backup")
+	err = os.WriteFile(src, encrypt(t, dk.Key(), []byte("hello world")), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations := dk.Annotations()
+	annotations[storage.ObjectAnnotationDigest] = "sha256:foo"
+	bkt, obj, err := rs.Upload(ctx, src, storage.DefaultBackup, storage.WithAnnotations(annotations))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rewrapped, err := encryption.Rewrap(ctx, kmsV1, ps, owner, bkt, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewrapped {
+		t.Error("expected data key wrapped with the primary key not to be re-wrapped")
+	}
+
+	rewrapped, err = encryption.Rewrap(ctx, kmsV2, ps, owner, bkt, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rewrapped {
+		t.Fatal("expected data key to be re-wrapped")
+	}
+
+	nfo, err := ps.SignDownload(ctx, bkt, obj, &storage.SignedURLOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nfo.Meta.EncryptionKeyID != "v2/"+owner {
+		t.Errorf("unexpected key ID after re-wrapping: %s", nfo.Meta.EncryptionKeyID)
+	}
+	if nfo.Meta.Digest != "sha256:foo" {
+		t.Errorf("re-wrapping lost the other annotations: %+v", nfo.Meta)
+	}
+
+	// the old key can be removed once all data keys are re-wrapped
+	kmsV2Only, err := encryption.NewKeyfileKMS(writeKeyfile(t, "v2", map[string][]byte{"v2": k2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := encryption.UnwrapDataKey(ctx, kmsV2Only, owner, &nfo.Meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, dk.Key()) {
+		t.Error("re-wrapped data key differs")
+	}
+}
+
+func TestNewKMS(t *testing.T) {
+	fn := writeKeyfile(t, "v1", map[string][]byte{"v1": newKey(t)})
+	tests := []struct {
+		Name        string
+		Config      *config.EncryptionConfig
+		ExpectError bool
+	}{
+		{Name: "keyfile", Config: &config.EncryptionConfig{KMS: config.KeyfileKMS, Keyfile: &config.KeyfileKMSConfig{Path: fn}}},
+		{Name: "missing config", ExpectError: true},
+		{Name: "missing keyfile config", Config: &config.EncryptionConfig{KMS: config.KeyfileKMS}, ExpectError: true},
+		{Name: "unknown KMS", Config: &config.EncryptionConfig{KMS: "foobar"}, ExpectError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := encryption.NewKMS(test.Config)
+			if (err != nil) != test.ExpectError {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func newKey(t *testing.T) []byte {
+	key := make([]byte, encryption.DataKeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func encrypt(t *testing.T, key, content []byte) []byte {
+	var buf bytes.Buffer
+	w, err := encryption.NewWriter(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// write in odd portions to exercise the segment buffering
+	for c := content; len(c) > 0; {
+		n := 1000
+		if n > len(c) {
+			n = len(c)
+		}
+		_, err = w.Write(c[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		c = c[n:]
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func writeKeyfile(t *testing.T, primary string, keys map[string][]byte) string {
+	kf := struct {
+		Primary string            `json:"primary"`
+		Keys    map[string]string `json:"keys"`
+	}{Primary: primary, Keys: make(map[string]string, len(keys))}
+	for name, k := range keys {
+		kf.Keys[name] = base64.StdEncoding.EncodeToString(k)
+	}
+	fc, err := json.Marshal(kf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := filepath.Join(t.TempDir(), "keyfile.json")
+	err = os.WriteFile(fn, fc, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fn
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+// DataKeySize is the size of data keys in bytes
+const DataKeySize = 32
+
+// ErrRewrapUnsupported is returned by Rewrap if the storage cannot update the annotations of an object
+var ErrRewrapUnsupported = errors.New("storage does not support updating object annotations")
+
+// DataKey is the key a single object is encrypted with
+type DataKey struct {
+	// KeyID identifies the key the data key is wrapped with
+	KeyID string
+	// Wrapped is the data key, encrypted by the KMS
+	Wrapped []byte
+
+	key []byte
+}
+
+// NewDataKey generates a random data key and wraps it using the current key of the scope
+func NewDataKey(ctx context.Context, kms KMS, scope string) (*DataKey, error) {
+	key := make([]byte, DataKeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, wrapped, err := kms.WrapKey(ctx, scope, key)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot wrap data key: %w", err)
+	}
+	return &DataKey{KeyID: keyID, Wrapped: wrapped, key: key}, nil
+}
+
+// Key returns the plain data key
+func (k *DataKey) Key() []byte {
+	return k.key
+}
+
+// Annotations returns the object annotations which record the wrapped data key
+func (k *DataKey) Annotations() map[string]string {
+	return map[string]string{
+		storage.ObjectAnnotationEncryptionKeyID:  k.KeyID,
+		storage.ObjectAnnotationEncryptedDataKey: base64.StdEncoding.EncodeToString(k.Wrapped),
+	}
+}
+
+// IsEncrypted returns true if the object metadata records a data key
+func IsEncrypted(meta *storage.ObjectMeta) bool {
+	return meta != nil && meta.EncryptionKeyID != ""
+}
+
+// UnwrapDataKey returns the plain data key of an encrypted object which belongs to the scope
+func UnwrapDataKey(ctx context.Context, kms KMS, scope string, meta *storage.ObjectMeta) ([]byte, error) {
+	if !IsEncrypted(meta) {
+		return nil, xerrors.Errorf("object is not encrypted")
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(meta.EncryptedDataKey)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decode data key: %w", err)
+	}
+
+	key, err := kms.UnwrapKey(ctx, scope, meta.EncryptionKeyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != DataKeySize {
+		return nil, xerrors.Errorf("data key has %d bytes, expected %d", len(key), DataKeySize)
+	}
+	return key, nil
+}
+
+// Rewrap wraps the data key of an encrypted object using the current key of the scope the object was encrypted
+// for. The content of the object remains untouched. Returns false if the object is not encrypted or its data key
+// is wrapped using the current key already.
+func Rewrap(ctx context.Context, kms KMS, ps storage.PresignedAccess, scope, bucket, obj string) (rewrapped bool, err error) {
+	updater, ok := ps.(storage.AnnotationUpdater)
+	if !ok {
+		return false, ErrRewrapUnsupported
+	}
+
+	info, err := ps.SignDownload(ctx, bucket, obj, &storage.SignedURLOptions{})
+	if err != nil {
+		return false, err
+	}
+	if !IsEncrypted(&info.Meta) {
+		return false, nil
+	}
+
+	key, err := UnwrapDataKey(ctx, kms, scope, &info.Meta)
+	if err != nil {
+		return false, err
+	}
+	keyID, wrapped, err := kms.WrapKey(ctx, scope, key)
+	if err != nil {
+		return false, xerrors.Errorf("cannot wrap data key: %w", err)
+	}
+	if keyID == info.Meta.EncryptionKeyID {
+		return false, nil
+	}
+
+	dk := DataKey{KeyID: keyID, Wrapped: wrapped}
+	err = updater.UpdateAnnotations(ctx, bucket, obj, dk.Annotations())
+	if err != nil {
+		return false, xerrors.Errorf("cannot update data key: %w", err)
+	}
+	return true, nil
+}
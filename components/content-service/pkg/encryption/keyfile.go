@@ -0,0 +1,131 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+var _ KMS = &KeyfileKMS{}
+
+// KeyfileKMS wraps data keys using keys derived from the master keys of a local keyfile.
+// Every scope gets its own key encryption key, s.t. a data key cannot be unwrapped in the
+// context of another scope. Key IDs have the form <master key name>/<scope>.
+type KeyfileKMS struct {
+	primary string
+	keys    map[string][]byte
+}
+
+type keyfile struct {
+	Primary string            `json:"primary"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// NewKeyfileKMS reads a keyfile of the form {"primary": "<name>", "keys": {"<name>": "<base64 encoded 32 byte key>"}}.
+// Data keys are wrapped using the primary key, all other keys are used for unwrapping only.
+func NewKeyfileKMS(fn string) (*KeyfileKMS, error) {
+	fc, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read keyfile: %w", err)
+	}
+	var kf keyfile
+	err = json.Unmarshal(fc, &kf)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse keyfile: %w", err)
+	}
+
+	res := &KeyfileKMS{
+		primary: kf.Primary,
+		keys:    make(map[string][]byte, len(kf.Keys)),
+	}
+	for name, k := range kf.Keys {
+		if name == "" || strings.Contains(name, "/") {
+			return nil, xerrors.Errorf("invalid key name: %q", name)
+		}
+		key, err := base64.StdEncoding.DecodeString(k)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot decode key %s: %w", name, err)
+		}
+		if len(key) != DataKeySize {
+			return nil, xerrors.Errorf("key %s has %d bytes, expected %d", name, len(key), DataKeySize)
+		}
+		res.keys[name] = key
+	}
+	if _, ok := res.keys[res.primary]; !ok {
+		return nil, xerrors.Errorf("primary key %q is not part of the keyfile", res.primary)
+	}
+
+	return res, nil
+}
+
+// WrapKey implements KMS
+func (k *KeyfileKMS) WrapKey(ctx context.Context, scope string, dataKey []byte) (keyID string, wrapped []byte, err error) {
+	keyID = k.primary + "/" + scope
+	aead, err := k.kek(keyID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dataKey)+aead.Overhead())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", nil, err
+	}
+	return keyID, aead.Seal(nonce, nonce, dataKey, []byte(keyID)), nil
+}
+
+// UnwrapKey implements KMS
+func (k *KeyfileKMS) UnwrapKey(ctx context.Context, scope, keyID string, wrapped []byte) (dataKey []byte, err error) {
+	_, keyScope, _ := strings.Cut(keyID, "/")
+	if keyScope != scope {
+		return nil, xerrors.Errorf("%w: key %s, expected scope %s", ErrScopeMismatch, keyID, scope)
+	}
+
+	aead, err := k.kek(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, xerrors.Errorf("wrapped data key is too short")
+	}
+
+	dataKey, err = aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unwrap data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// kek derives the key encryption key of a key ID from its master key
+func (k *KeyfileKMS) kek(keyID string) (cipher.AEAD, error) {
+	name, scope, ok := strings.Cut(keyID, "/")
+	if !ok {
+		return nil, xerrors.Errorf("invalid key ID: %q", keyID)
+	}
+	master, ok := k.keys[name]
+	if !ok {
+		return nil, xerrors.Errorf("%w: %s", ErrUnknownKey, name)
+	}
+
+	mac := hmac.New(sha256.New, master)
+	mac.Write([]byte("gitpod-backup-kek\x00"))
+	mac.Write([]byte(scope))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+// Package encryption implements the client-side envelope encryption of workspace backups.
+//
+// Every backup is encrypted using its own random data key. The data key is wrapped by a key
+// management service (KMS) and stored in the object annotations, next to the ID of the key it
+// is wrapped with. Rotating the keys of the KMS hence only requires re-wrapping the data keys,
+// rather than encrypting the backups again.
+package encryption
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/xerrors"
+
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+)
+
+var (
+	// ErrUnknownKey is returned by a KMS if it does not know the key a data key was wrapped with
+	ErrUnknownKey = errors.New("unknown key")
+
+	// ErrScopeMismatch is returned by a KMS if a data key was wrapped for another scope than the expected one
+	ErrScopeMismatch = errors.New("data key belongs to another scope")
+)
+
+// KMS wraps and unwraps data keys
+type KMS interface {
+	// WrapKey encrypts a data key using the current key of the scope, i.e. the owner of the backup location the
+	// content is stored at. The returned key ID identifies the key which is needed to unwrap the data key again.
+	WrapKey(ctx context.Context, scope string, dataKey []byte) (keyID string, wrapped []byte, err error)
+
+	// UnwrapKey decrypts a data key which was wrapped using the key identified by keyID. Callers pass the scope
+	// they expect the content to belong to; UnwrapKey fails with ErrScopeMismatch if the key ID belongs to another.
+	UnwrapKey(ctx context.Context, scope, keyID string, wrapped []byte) (dataKey []byte, err error)
+}
+
+// NewKMS produces a KMS based on its configuration
+func NewKMS(cfg *config.EncryptionConfig) (KMS, error) {
+	if cfg == nil {
+		return nil, xerrors.Errorf("missing encryption config")
+	}
+
+	switch cfg.KMS {
+	case config.KeyfileKMS:
+		if cfg.Keyfile == nil {
+			return nil, xerrors.Errorf("missing keyfile KMS config")
+		}
+		return NewKeyfileKMS(cfg.Keyfile.Path)
+	default:
+		return nil, xerrors.Errorf("unknown KMS: %s", cfg.KMS)
+	}
+}
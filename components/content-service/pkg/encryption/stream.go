@@ -0,0 +1,224 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"golang.org/x/xerrors"
+)
+
+// Encrypted content starts with the magic and a random nonce prefix, followed by segments of at most
+// segmentSize bytes of content, each sealed using AES-256-GCM. The nonce of a segment consists of the
+// prefix, the segment number and a flag which marks the last segment, s.t. segments can neither be
+// reordered nor dropped without failing authentication (see the STREAM construction by Hoang et al.).
+const (
+	segmentSize     = 64 * 1024
+	noncePrefixSize = 7
+)
+
+var magic = []byte("gpenc\x01")
+
+// ErrNotEncrypted is returned by NewReader if the content does not start with the header of encrypted content
+var ErrNotEncrypted = errors.New("content is not encrypted")
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != DataKeySize {
+		return nil, xerrors.Errorf("data key has %d bytes, expected %d", len(key), DataKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type segmentNonce [12]byte
+
+func (n *segmentNonce) set(counter uint32, last bool) []byte {
+	binary.BigEndian.PutUint32(n[noncePrefixSize:], counter)
+	n[len(n)-1] = 0
+	if last {
+		n[len(n)-1] = 1
+	}
+	return n[:]
+}
+
+// NewWriter returns a writer which encrypts everything written to it using the data key and writes it to dst.
+// Callers must close the writer to write the last segment.
+func NewWriter(dst io.Writer, key []byte) (io.WriteCloser, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &writer{
+		dst:  dst,
+		aead: aead,
+		buf:  make([]byte, 0, segmentSize),
+		out:  make([]byte, 0, segmentSize+aead.Overhead()),
+	}
+	_, err = rand.Read(w.nonce[:noncePrefixSize])
+	if err != nil {
+		return nil, err
+	}
+	_, err = dst.Write(append(append([]byte{}, magic...), w.nonce[:noncePrefixSize]...))
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+type writer struct {
+	dst     io.Writer
+	aead    cipher.AEAD
+	nonce   segmentNonce
+	counter uint32
+	buf     []byte
+	out     []byte
+	err     error
+	closed  bool
+}
+
+// Write implements io.Writer
+func (w *writer) Write(p []byte) (n int, err error) {
+	if w.closed {
+		return 0, xerrors.Errorf("writer is closed")
+	}
+	for len(p) > 0 {
+		if w.err != nil {
+			return n, w.err
+		}
+		// a full segment is sealed only once we know it's not the last one
+		if len(w.buf) == segmentSize {
+			w.seal(false)
+			continue
+		}
+
+		c := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+c]
+		p = p[c:]
+		n += c
+	}
+	return n, w.err
+}
+
+// Close seals the last segment. It does not close the underlying writer.
+func (w *writer) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	if w.err != nil {
+		return w.err
+	}
+	w.seal(true)
+	return w.err
+}
+
+func (w *writer) seal(last bool) {
+	if w.counter == math.MaxUint32 {
+		w.err = xerrors.Errorf("content is too large")
+		return
+	}
+
+	w.out = w.aead.Seal(w.out[:0], w.nonce.set(w.counter, last), w.buf, nil)
+	w.counter++
+	w.buf = w.buf[:0]
+	_, w.err = w.dst.Write(w.out)
+}
+
+// NewReader returns a reader which decrypts content written by a writer produced by NewWriter.
+// Returns ErrNotEncrypted if src does not start with the header of encrypted content.
+func NewReader(src io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reader{
+		src:  bufio.NewReader(src),
+		aead: aead,
+		in:   make([]byte, segmentSize+aead.Overhead()),
+	}
+	hdr := make([]byte, len(magic)+noncePrefixSize)
+	_, err = io.ReadFull(r.src, hdr)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	if err != nil || !bytes.Equal(hdr[:len(magic)], magic) {
+		return nil, ErrNotEncrypted
+	}
+	copy(r.nonce[:noncePrefixSize], hdr[len(magic):])
+
+	return r, nil
+}
+
+type reader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	nonce   segmentNonce
+	counter uint32
+	in      []byte
+	plain   []byte
+	done    bool
+	err     error
+}
+
+// Read implements io.Reader
+func (r *reader) Read(p []byte) (n int, err error) {
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		r.err = r.open()
+	}
+
+	n = copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+func (r *reader) open() error {
+	n, err := io.ReadFull(r.src, r.in)
+	last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !last {
+		return err
+	}
+	if !last {
+		// a full segment is the last one if nothing follows it
+		_, err = r.src.Peek(1)
+		last = errors.Is(err, io.EOF)
+		if err != nil && !last {
+			return err
+		}
+	}
+	if n < r.aead.Overhead() {
+		return xerrors.Errorf("encrypted content is truncated")
+	}
+	if r.counter == math.MaxUint32 {
+		return xerrors.Errorf("content is too large")
+	}
+
+	plain, err := r.aead.Open(r.in[:0], r.nonce.set(r.counter, last), r.in[:n], nil)
+	if err != nil {
+		return xerrors.Errorf("cannot decrypt segment %d, content is corrupted or truncated: %w", r.counter, err)
+	}
+	r.counter++
+	r.plain = plain
+	r.done = last
+	return nil
+}
@@ -22,9 +22,11 @@ type config struct {
 	FromBackup string            `json:"fromBackupURL,omitempty"`
 }
 
-// PrepareFromBackup produces executor config to restore a backup
-func PrepareFromBackup(url string) ([]byte, error) {
+// PrepareFromBackup produces executor config to restore a backup. If the backup is chunked,
+// chunkURLs contains the URLs of its chunks keyed by their chunks.ObjectName.
+func PrepareFromBackup(url string, chunkURLs map[string]string) ([]byte, error) {
 	return json.Marshal(config{
+		URLs:       chunkURLs,
 		FromBackup: url,
 	})
 }
@@ -70,11 +72,12 @@ func Execute(ctx context.Context, destination string, cfgin io.Reader, forceGitU
 			return "", err
 		}
 	} else {
-		rs = &storage.NamedURLDownloader{
-			URLs: map[string]string{
-				storage.DefaultBackup: cfg.FromBackup,
-			},
+		urls := make(map[string]string, len(cfg.URLs)+1)
+		for name, url := range cfg.URLs {
+			urls[name] = url
 		}
+		urls[storage.DefaultBackup] = cfg.FromBackup
+		rs = &storage.NamedURLDownloader{URLs: urls}
 		ilr = &initializer.EmptyInitializer{}
 	}
 
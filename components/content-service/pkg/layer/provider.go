@@ -22,6 +22,7 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/tracing"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/content-service/pkg/encryption"
 	"github.com/gitpod-io/gitpod/content-service/pkg/executor"
 	"github.com/gitpod-io/gitpod/content-service/pkg/initializer"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
@@ -54,6 +55,9 @@ type Provider struct {
 
 var errUnsupportedContentType = xerrors.Errorf("unsupported workspace content type")
 
+// errEncryptedContent is returned for encrypted backups and snapshots, which only ws-daemon can decrypt
+var errEncryptedContent = xerrors.Errorf("workspace content is encrypted and cannot be restored from a content layer")
+
 func (s *Provider) downloadContentManifest(ctx context.Context, bkt, obj string) (manifest *csapi.WorkspaceContentManifest, info *storage.DownloadInfo, err error) {
 	//nolint:ineffassign
 	span, ctx := opentracing.StartSpanFromContext(ctx, "downloadContentManifest")
@@ -117,6 +121,23 @@ func (s *Provider) downloadContentManifest(ctx context.Context, bkt, obj string)
 	return
 }
 
+// signBackupChunks signs the chunks of a chunked backup. Returns nil if the backup is a regular tarball.
+func (s *Provider) signBackupChunks(ctx context.Context, bkt, obj string, info *storage.DownloadInfo) (map[string]string, error) {
+	if !storage.IsChunkedBackup(info) {
+		return nil, nil
+	}
+
+	infos, err := storage.SignChunkedBackup(ctx, s.Storage, bkt, obj, info)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]string, len(infos))
+	for name, info := range infos {
+		res[name] = info.URL
+	}
+	return res, nil
+}
+
 // GetContentLayer provides the content layer for a workspace
 func (s *Provider) GetContentLayer(ctx context.Context, owner, workspaceID string, initializer *csapi.WorkspaceInitializer) (l []Layer, manifest *csapi.WorkspaceContentManifest, err error) {
 	span, ctx := tracing.FromContext(ctx, "GetContentLayer")
@@ -157,8 +178,15 @@ func (s *Provider) GetContentLayer(ctx context.Context, owner, workspaceID strin
 	}
 	if err == nil {
 		span.LogKV("backup found", "legacy workspace backup")
+		if encryption.IsEncrypted(&info.Meta) {
+			return nil, nil, errEncryptedContent
+		}
 
-		cdesc, err := executor.PrepareFromBackup(info.URL)
+		chunkURLs, err := s.signBackupChunks(ctx, bucket, fmt.Sprintf(fmtLegacyBackupName, workspaceID), info)
+		if err != nil {
+			return nil, nil, err
+		}
+		cdesc, err := executor.PrepareFromBackup(info.URL, chunkURLs)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -254,8 +282,15 @@ func (s *Provider) GetContentLayerPVC(ctx context.Context, owner, workspaceID st
 	}
 	if err == nil {
 		span.LogKV("backup found", "legacy workspace backup")
+		if encryption.IsEncrypted(&info.Meta) {
+			return nil, nil, errEncryptedContent
+		}
 
-		cdesc, err := executor.PrepareFromBackup(info.URL)
+		chunkURLs, err := s.signBackupChunks(ctx, bucket, fmt.Sprintf(fmtLegacyBackupName, workspaceID), info)
+		if err != nil {
+			return nil, nil, err
+		}
+		cdesc, err := executor.PrepareFromBackup(info.URL, chunkURLs)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -392,6 +427,9 @@ func (s *Provider) getSnapshotContentLayer(ctx context.Context, sp *csapi.Snapsh
 
 	if manifest == nil {
 		// we've found a legacy snapshot
+		if encryption.IsEncrypted(&info.Meta) {
+			return nil, nil, errEncryptedContent
+		}
 		cdesc, err := executor.Prepare(&csapi.WorkspaceInitializer{Spec: &csapi.WorkspaceInitializer_Snapshot{Snapshot: sp}}, map[string]string{
 			sp.Snapshot: info.URL,
 		})
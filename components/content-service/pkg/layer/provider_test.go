@@ -226,6 +226,10 @@ func (s *testStorage) DiskUsage(ctx context.Context, bucket string, prefix strin
 	return 0, nil
 }
 
+func (s *testStorage) ListObjects(ctx context.Context, bucket string, prefix string) ([]string, error) {
+	return nil, nil
+}
+
 func (s *testStorage) SignDownload(ctx context.Context, bucket, obj string, options *storage.SignedURLOptions) (info *storage.DownloadInfo, err error) {
 	info, ok := s.Objs[obj]
 	if !ok || info == nil {
@@ -7,6 +7,7 @@ package service
 import (
 	"context"
 	"errors"
+	"path"
 	"strings"
 
 	"github.com/opentracing/opentracing-go"
@@ -17,6 +18,7 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/tracing"
 	"github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/content-service/pkg/chunks"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 )
 
@@ -111,6 +113,17 @@ func (cs *WorkspaceService) DeleteWorkspace(ctx context.Context, req *api.Delete
 		return nil, status.Error(codes.Unknown, err.Error())
 	}
 
+	chunksPrefix := path.Dir(blobName) + "/" + chunks.Prefix
+	err = cs.s.DeleteObject(ctx, cs.s.Bucket(req.OwnerId), &storage.DeleteObjectQuery{Prefix: chunksPrefix})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			log.WithError(err).Debug("deleting workspace backup: NotFound, ", chunksPrefix)
+			return &api.DeleteWorkspaceResponse{}, nil
+		}
+		log.WithError(err).Error("error deleting workspace backup: ", chunksPrefix)
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+
 	return &api.DeleteWorkspaceResponse{}, nil
 }
 
@@ -129,3 +142,52 @@ func (cs *WorkspaceService) WorkspaceSnapshotExists(ctx context.Context, req *ap
 		Exists: exists,
 	}, nil
 }
+
+// GarbageCollectChunks deletes the backup chunks of a workspace which are no longer referenced by its backup
+func (cs *WorkspaceService) GarbageCollectChunks(ctx context.Context, req *api.GarbageCollectChunksRequest) (resp *api.GarbageCollectChunksResponse, err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GarbageCollectChunks")
+	span.SetTag("user", req.OwnerId)
+	span.SetTag("workspaceId", req.WorkspaceId)
+	defer tracing.FinishSpan(span, &err)
+
+	var (
+		bucket = cs.s.Bucket(req.OwnerId)
+		backup = cs.s.BackupObject(req.OwnerId, req.WorkspaceId, storage.DefaultBackup)
+		// chunks are stored next to the backup
+		prefix = path.Dir(backup) + "/"
+	)
+	objs, err := cs.s.ListObjects(ctx, bucket, prefix+chunks.Prefix)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	if len(objs) == 0 {
+		return &api.GarbageCollectChunksResponse{}, nil
+	}
+
+	// if the backup is no longer chunked, none of the chunks are referenced anymore
+	var index *api.WorkspaceChunkIndex
+	info, err := cs.s.SignDownload(ctx, bucket, backup, &storage.SignedURLOptions{})
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	if err == nil && storage.IsChunkedBackup(info) {
+		index, err = storage.DownloadChunkIndex(ctx, info.URL)
+		if err != nil {
+			return nil, status.Error(codes.Unknown, err.Error())
+		}
+	}
+
+	unreferenced := chunks.Unreferenced(objs, prefix, index)
+	span.LogKV("chunks", len(objs), "unreferenced", len(unreferenced))
+	for _, obj := range unreferenced {
+		err = cs.s.DeleteObject(ctx, bucket, &storage.DeleteObjectQuery{Name: obj})
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.WithError(err).WithFields(log.OWI(req.OwnerId, req.WorkspaceId, "")).Error("error deleting backup chunk: ", obj)
+			return nil, status.Error(codes.Unknown, err.Error())
+		}
+	}
+
+	return &api.GarbageCollectChunksResponse{
+		DeletedChunks: int64(len(unreferenced)),
+	}, nil
+}
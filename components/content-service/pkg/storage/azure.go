@@ -0,0 +1,764 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+)
+
+var _ DirectAccess = &DirectAzureStorage{}
+var _ BytesUploader = &DirectAzureStorage{}
+var _ MultipartUploader = &DirectAzureStorage{}
+var _ PresignedAccess = &presignedAzureStorage{}
+var _ AnnotationUpdater = &presignedAzureStorage{}
+
+// ValidateAzureConfig checks if the Azure storage config is valid
+func ValidateAzureConfig(c *config.AzureConfig) error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.AccountName, validation.Required),
+		validation.Field(&c.AccountKey, validation.Required),
+	)
+}
+
+// addAzureParamsFromMounts allows for the account key to be read from a file
+func addAzureParamsFromMounts(c *config.AzureConfig) error {
+	if c.AccountKeyFile != "" {
+		value, err := os.ReadFile(c.AccountKeyFile)
+		if err != nil {
+			return err
+		}
+		c.AccountKey = strings.TrimSpace(string(value))
+	}
+	return nil
+}
+
+// NewAzureClient produces a new Azure Blob Storage client based on this configuration
+func NewAzureClient(c *config.AzureConfig) (*azblob.Client, error) {
+	err := addAzureParamsFromMounts(c)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ValidateAzureConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(c.AccountName, c.AccountKey)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net/", c.AccountName)
+	}
+	return azblob.NewClientWithSharedKeyCredential(endpoint, cred, nil)
+}
+
+// newDirectAzureAccess provides direct access to the remote storage system
+func newDirectAzureAccess(cfg *config.AzureConfig) (*DirectAzureStorage, error) {
+	if cfg == nil {
+		return nil, xerrors.Errorf("missing Azure storage config")
+	}
+	c := *cfg
+	err := addAzureParamsFromMounts(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ValidateAzureConfig(&c); err != nil {
+		return nil, err
+	}
+	return &DirectAzureStorage{AzureConfig: c}, nil
+}
+
+// DirectAzureStorage implements Azure Blob Storage as remote storage backend
+type DirectAzureStorage struct {
+	Username      string
+	WorkspaceName string
+	InstanceID    string
+	AzureConfig   config.AzureConfig
+
+	client *azblob.Client
+}
+
+// Validate checks if the Azure storage is configured properly
+func (rs *DirectAzureStorage) Validate() error {
+	err := ValidateAzureConfig(&rs.AzureConfig)
+	if err != nil {
+		return err
+	}
+
+	return validation.ValidateStruct(rs,
+		validation.Field(&rs.Username, validation.Required),
+		validation.Field(&rs.WorkspaceName, validation.Required),
+	)
+}
+
+// Init initializes the remote storage - call this before calling anything else on the interface
+func (rs *DirectAzureStorage) Init(ctx context.Context, owner, workspace, instance string) (err error) {
+	rs.Username = owner
+	rs.WorkspaceName = workspace
+	rs.InstanceID = instance
+
+	err = rs.Validate()
+	if err != nil {
+		return err
+	}
+
+	cl, err := NewAzureClient(&rs.AzureConfig)
+	if err != nil {
+		return err
+	}
+	rs.client = cl
+
+	return nil
+}
+
+// EnsureExists makes sure that the remote storage location exists and can be up- or downloaded from
+func (rs *DirectAzureStorage) EnsureExists(ctx context.Context) (err error) {
+	return azureEnsureExists(ctx, rs.client, rs.bucketName())
+}
+
+func azureEnsureExists(ctx context.Context, client *azblob.Client, bucket string) (err error) {
+	//nolint:staticcheck,ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "DirectEnsureExists")
+	defer tracing.FinishSpan(span, &err)
+
+	if client == nil {
+		return xerrors.Errorf("no Azure client available - did you call Init()?")
+	}
+
+	_, err = client.CreateContainer(ctx, bucket, nil)
+	if bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		// container exists already - we're fine
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot create container: %w", err)
+	}
+	log.WithField("container", bucket).Debug("Created container")
+
+	return nil
+}
+
+func (rs *DirectAzureStorage) download(ctx context.Context, destination string, bkt string, obj string, mappings []archive.IDMapping) (found bool, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "download")
+	span.SetTag("bucket", bkt)
+	span.SetTag("object", obj)
+	defer tracing.FinishSpan(span, &err)
+
+	if rs.client == nil {
+		return false, xerrors.Errorf("no Azure client available - did you call Init()?")
+	}
+
+	resp, err := rs.client.DownloadStream(ctx, bkt, obj, nil)
+	if err != nil {
+		err = translateAzureError(err)
+		if err == ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	err = extractTarbal(ctx, destination, resp.Body, mappings)
+	if err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// Download takes the latest state from the remote storage and downloads it to a local path
+func (rs *DirectAzureStorage) Download(ctx context.Context, destination string, name string, mappings []archive.IDMapping) (bool, error) {
+	return rs.download(ctx, destination, rs.bucketName(), rs.objectName(name), mappings)
+}
+
+// DownloadSnapshot downloads a snapshot. The snapshot name is expected to be one produced by Qualify
+func (rs *DirectAzureStorage) DownloadSnapshot(ctx context.Context, destination string, name string, mappings []archive.IDMapping) (bool, error) {
+	bkt, obj, err := ParseSnapshotName(name)
+	if err != nil {
+		return false, err
+	}
+
+	return rs.download(ctx, destination, bkt, obj, mappings)
+}
+
+// ListObjects returns all objects found with the given prefix. Returns an empty list if the bucket does not exuist (yet).
+func (rs *DirectAzureStorage) ListObjects(ctx context.Context, prefix string) (objects []string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	blobs, err := azureListBlobs(ctx, rs.client, rs.bucketName(), prefix)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list objects: %w", err)
+	}
+	for _, b := range blobs {
+		objects = append(objects, *b.Name)
+	}
+	return objects, nil
+}
+
+// Qualify fully qualifies a snapshot name so that it can be downloaded using DownloadSnapshot
+func (rs *DirectAzureStorage) Qualify(name string) string {
+	return fmt.Sprintf("%s@%s", rs.objectName(name), rs.bucketName())
+}
+
+// UploadInstance takes all files from a local location and uploads it to the per-instance remote storage
+func (rs *DirectAzureStorage) UploadInstance(ctx context.Context, source string, name string, opts ...UploadOption) (bucket, object string, err error) {
+	if rs.InstanceID == "" {
+		return "", "", xerrors.Errorf("instanceID is required to comput object name")
+	}
+	return rs.Upload(ctx, source, InstanceObjectName(rs.InstanceID, name), opts...)
+}
+
+// Upload takes all files from a local location and uploads it to the remote storage
+func (rs *DirectAzureStorage) Upload(ctx context.Context, source string, name string, opts ...UploadOption) (bucket, obj string, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "DirectUpload")
+	defer tracing.FinishSpan(span, &err)
+
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		err = xerrors.Errorf("cannot get options: %w", err)
+		return
+	}
+
+	if rs.client == nil {
+		err = xerrors.Errorf("no Azure client available - did you call Init()?")
+		return
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	bucket = rs.bucketName()
+	obj = rs.objectName(name)
+	span.LogKV("bucket", bucket)
+	span.LogKV("obj", obj)
+	span.LogKV("account", rs.AzureConfig.AccountName)
+	_, err = rs.client.UploadFile(ctx, bucket, obj, f, &azblob.UploadFileOptions{
+		HTTPHeaders: azureHTTPHeaders(options.ContentType),
+		Metadata:    annotationsToAzureMetadata(options.Annotations),
+	})
+	if err != nil {
+		err = translateAzureError(err)
+		return
+	}
+
+	return
+}
+
+// UploadBytes implements BytesUploader
+func (rs *DirectAzureStorage) UploadBytes(ctx context.Context, data []byte, name string, opts ...UploadOption) (bucket, obj string, err error) {
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		return "", "", xerrors.Errorf("cannot get options: %w", err)
+	}
+	if rs.client == nil {
+		return "", "", xerrors.Errorf("no Azure client available - did you call Init()?")
+	}
+
+	bucket = rs.bucketName()
+	obj = rs.objectName(name)
+	_, err = rs.client.UploadBuffer(ctx, bucket, obj, data, &azblob.UploadBufferOptions{
+		HTTPHeaders: azureHTTPHeaders(options.ContentType),
+		Metadata:    annotationsToAzureMetadata(options.Annotations),
+	})
+	if err != nil {
+		return "", "", translateAzureError(err)
+	}
+	return bucket, obj, nil
+}
+
+// NewMultipartUpload implements MultipartUploader by staging the parts as blocks of a block blob
+func (rs *DirectAzureStorage) NewMultipartUpload(ctx context.Context, name string, opts ...UploadOption) (upload MultipartUpload, err error) {
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get options: %w", err)
+	}
+
+	if rs.client == nil {
+		return nil, xerrors.Errorf("no Azure client available - did you call Init()?")
+	}
+
+	res := &azureMultipartUpload{
+		bucket:      rs.bucketName(),
+		obj:         rs.objectName(name),
+		contentType: options.ContentType,
+		metadata:    annotationsToAzureMetadata(options.Annotations),
+		parts:       make(map[int]struct{}),
+	}
+	res.client = rs.client.ServiceClient().NewContainerClient(res.bucket).NewBlockBlobClient(res.obj)
+	return res, nil
+}
+
+type azureMultipartUpload struct {
+	client      *blockblob.Client
+	bucket      string
+	obj         string
+	contentType string
+	metadata    map[string]*string
+
+	mu    sync.Mutex
+	parts map[int]struct{}
+}
+
+// azureBlockID produces the block ID of a part. All block IDs of a blob must have the same length.
+func azureBlockID(number int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", number)))
+}
+
+// UploadPart implements MultipartUpload
+func (u *azureMultipartUpload) UploadPart(ctx context.Context, number int, data []byte) error {
+	if len(data) == 0 {
+		// Azure does not accept empty blocks - an empty block list produces an empty blob though
+		return nil
+	}
+
+	_, err := u.client.StageBlock(ctx, azureBlockID(number), streaming.NopCloser(bytes.NewReader(data)), nil)
+	if err != nil {
+		return translateAzureError(err)
+	}
+
+	u.mu.Lock()
+	u.parts[number] = struct{}{}
+	u.mu.Unlock()
+	return nil
+}
+
+// Complete implements MultipartUpload
+func (u *azureMultipartUpload) Complete(ctx context.Context) (bucket, obj string, err error) {
+	u.mu.Lock()
+	numbers := make([]int, 0, len(u.parts))
+	for n := range u.parts {
+		numbers = append(numbers, n)
+	}
+	u.mu.Unlock()
+	sort.Ints(numbers)
+
+	ids := make([]string, len(numbers))
+	for i, n := range numbers {
+		ids[i] = azureBlockID(n)
+	}
+	_, err = u.client.CommitBlockList(ctx, ids, &blockblob.CommitBlockListOptions{
+		HTTPHeaders: azureHTTPHeaders(u.contentType),
+		Metadata:    u.metadata,
+	})
+	if err != nil {
+		return "", "", translateAzureError(err)
+	}
+	return u.bucket, u.obj, nil
+}
+
+// Abort implements MultipartUpload. Azure discards uncommitted blocks after a week, there is no way to discard them earlier.
+func (u *azureMultipartUpload) Abort(ctx context.Context) error {
+	return nil
+}
+
+func azureBucketName(ownerID, bkt string) string {
+	if bkt != "" {
+		return bkt
+	}
+
+	return fmt.Sprintf("gitpod-user-%s", ownerID)
+}
+
+func azureWorkspaceBackupObjectName(ownerID, workspaceID, name string) string {
+	return filepath.Join(ownerID, "workspaces", workspaceID, name)
+}
+
+// Bucket provides the bucket name for a particular user
+func (rs *DirectAzureStorage) Bucket(ownerID string) string {
+	return azureBucketName(ownerID, rs.AzureConfig.Container)
+}
+
+// BackupObject returns a backup's object name that a direct downloader would download
+func (rs *DirectAzureStorage) BackupObject(name string) string {
+	return rs.objectName(name)
+}
+
+func (rs *DirectAzureStorage) bucketName() string {
+	return azureBucketName(rs.Username, rs.AzureConfig.Container)
+}
+
+func (rs *DirectAzureStorage) objectName(name string) string {
+	var username string
+	if rs.AzureConfig.Container != "" {
+		username = rs.Username
+	}
+	return azureWorkspaceBackupObjectName(username, rs.WorkspaceName, name)
+}
+
+func newPresignedAzureAccess(cfg *config.AzureConfig) (*presignedAzureStorage, error) {
+	if cfg == nil {
+		return nil, xerrors.Errorf("missing Azure storage config")
+	}
+	c := *cfg
+	cl, err := NewAzureClient(&c)
+	if err != nil {
+		return nil, err
+	}
+	return &presignedAzureStorage{client: cl, AzureConfig: c}, nil
+}
+
+type presignedAzureStorage struct {
+	client      *azblob.Client
+	AzureConfig config.AzureConfig
+}
+
+// EnsureExists makes sure that the remote storage location exists and can be up- or downloaded from
+func (s *presignedAzureStorage) EnsureExists(ctx context.Context, bucket string) (err error) {
+	return azureEnsureExists(ctx, s.client, bucket)
+}
+
+func (s *presignedAzureStorage) DiskUsage(ctx context.Context, bucket string, prefix string) (size int64, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "azure.DiskUsage")
+	defer tracing.FinishSpan(span, &err)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	blobs, err := azureListBlobs(ctx, s.client, bucket, prefix)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, b := range blobs {
+		if b.Properties != nil && b.Properties.ContentLength != nil {
+			total += *b.Properties.ContentLength
+		}
+	}
+	return total, nil
+}
+
+func (s *presignedAzureStorage) ListObjects(ctx context.Context, bucket string, prefix string) (objects []string, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "azure.ListObjects")
+	defer tracing.FinishSpan(span, &err)
+
+	blobs, err := azureListBlobs(ctx, s.client, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range blobs {
+		objects = append(objects, *b.Name)
+	}
+	return objects, nil
+}
+
+func (s *presignedAzureStorage) SignDownload(ctx context.Context, bucket, object string, options *SignedURLOptions) (info *DownloadInfo, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "azure.SignDownload")
+	defer func() {
+		if err == ErrNotFound {
+			span.LogKV("found", false)
+			tracing.FinishSpan(span, nil)
+			return
+		}
+
+		tracing.FinishSpan(span, &err)
+	}()
+
+	client := s.client.ServiceClient().NewContainerClient(bucket).NewBlobClient(object)
+	props, err := client.GetProperties(ctx, nil)
+	if err != nil {
+		return nil, translateAzureError(err)
+	}
+	url, err := client.GetSASURL(sas.BlobPermissions{Read: true}, time.Now().Add(30*time.Minute), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		contentType string
+		size        int64
+	)
+	if props.ContentType != nil {
+		contentType = *props.ContentType
+	}
+	if props.ContentLength != nil {
+		size = *props.ContentLength
+	}
+	return &DownloadInfo{
+		Meta: ObjectMeta{
+			ContentType:        contentType,
+			OCIMediaType:       azureMetadataAnnotation(props.Metadata, ObjectAnnotationOCIContentType),
+			Digest:             azureMetadataAnnotation(props.Metadata, ObjectAnnotationDigest),
+			UncompressedDigest: azureMetadataAnnotation(props.Metadata, ObjectAnnotationUncompressedDigest),
+			EncryptionKeyID:    azureMetadataAnnotation(props.Metadata, ObjectAnnotationEncryptionKeyID),
+			EncryptedDataKey:   azureMetadataAnnotation(props.Metadata, ObjectAnnotationEncryptedDataKey),
+		},
+		Size: size,
+		URL:  url,
+	}, nil
+}
+
+// SignUpload describes an object for upload.
+func (s *presignedAzureStorage) SignUpload(ctx context.Context, bucket, obj string, options *SignedURLOptions) (info *UploadInfo, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "azure.SignUpload")
+	defer tracing.FinishSpan(span, &err)
+
+	client := s.client.ServiceClient().NewContainerClient(bucket).NewBlobClient(obj)
+	url, err := client.GetSASURL(sas.BlobPermissions{Create: true, Write: true}, time.Now().Add(30*time.Minute), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadInfo{URL: url}, nil
+}
+
+func (s *presignedAzureStorage) DeleteObject(ctx context.Context, bucket string, query *DeleteObjectQuery) (err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "azure.DeleteObject")
+	defer tracing.FinishSpan(span, &err)
+
+	if query.Name != "" {
+		_, err = s.client.DeleteBlob(ctx, bucket, query.Name, nil)
+		if err != nil {
+			log.WithField("bucket", bucket).WithField("object", query.Name).Error(err)
+			return translateAzureError(err)
+		}
+		return nil
+	}
+	if query.Prefix != "" {
+		blobs, err := azureListBlobs(ctx, s.client, bucket, query.Prefix)
+		if err != nil {
+			return err
+		}
+		for _, b := range blobs {
+			_, err = s.client.DeleteBlob(ctx, bucket, *b.Name, nil)
+			if err != nil && translateAzureError(err) != ErrNotFound {
+				log.WithField("bucket", bucket).WithField("object", *b.Name).Error(err)
+				return translateAzureError(err)
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteBucket deletes a bucket. If all users share a container, only the user's content is deleted.
+func (s *presignedAzureStorage) DeleteBucket(ctx context.Context, userID, bucket string) (err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "azure.DeleteBucket")
+	defer tracing.FinishSpan(span, &err)
+
+	if s.AzureConfig.Container != "" {
+		if userID == "" {
+			return xerrors.Errorf("cannot delete the content of an empty user ID from the shared container")
+		}
+		return s.DeleteObject(ctx, bucket, &DeleteObjectQuery{Prefix: userID + "/"})
+	}
+
+	_, err = s.client.DeleteContainer(ctx, bucket, nil)
+	err = translateAzureError(err)
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// ObjectHash gets a hash value of an object
+func (s *presignedAzureStorage) ObjectHash(ctx context.Context, bucket string, obj string) (hash string, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "azure.ObjectHash")
+	defer tracing.FinishSpan(span, &err)
+
+	props, err := s.client.ServiceClient().NewContainerClient(bucket).NewBlobClient(obj).GetProperties(ctx, nil)
+	if err != nil {
+		return "", translateAzureError(err)
+	}
+	if len(props.ContentMD5) == 0 {
+		// blobs assembled from blocks have no Content-MD5 unless the uploader provided one
+		if props.ETag == nil {
+			return "", xerrors.Errorf("object has neither Content-MD5 nor ETag")
+		}
+		return strings.Trim(string(*props.ETag), `"`), nil
+	}
+	return hex.EncodeToString(props.ContentMD5), nil
+}
+
+func (s *presignedAzureStorage) ObjectExists(ctx context.Context, bucket, obj string) (exists bool, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "azure.ObjectExists")
+	defer tracing.FinishSpan(span, &err)
+
+	_, err = s.client.ServiceClient().NewContainerClient(bucket).NewBlobClient(obj).GetProperties(ctx, nil)
+	if err != nil {
+		e := translateAzureError(err)
+		if e == ErrNotFound {
+			return false, nil
+		}
+		return false, e
+	}
+	return true, nil
+}
+
+// UpdateAnnotations implements AnnotationUpdater
+func (s *presignedAzureStorage) UpdateAnnotations(ctx context.Context, bucket, obj string, annotations map[string]string) (err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "azure.UpdateAnnotations")
+	defer tracing.FinishSpan(span, &err)
+
+	client := s.client.ServiceClient().NewContainerClient(bucket).NewBlobClient(obj)
+	props, err := client.GetProperties(ctx, nil)
+	if err != nil {
+		return translateAzureError(err)
+	}
+
+	// setting the metadata replaces all existing metadata
+	md := make(map[string]*string, len(props.Metadata)+len(annotations))
+	for k, v := range props.Metadata {
+		md[strings.ToLower(k)] = v
+	}
+	for k, v := range annotationsToAzureMetadata(annotations) {
+		md[strings.ToLower(k)] = v
+	}
+	_, err = client.SetMetadata(ctx, md, nil)
+	return translateAzureError(err)
+}
+
+// Bucket provides the bucket name for a particular user
+func (s *presignedAzureStorage) Bucket(ownerID string) string {
+	return azureBucketName(ownerID, s.AzureConfig.Container)
+}
+
+// BlobObject returns a blob's object name
+func (s *presignedAzureStorage) BlobObject(userID, name string) (string, error) {
+	return blobObjectName(name)
+}
+
+// BackupObject returns a backup's object name that a direct downloader would download
+func (s *presignedAzureStorage) BackupObject(ownerID string, workspaceID, name string) string {
+	var username string
+	if s.AzureConfig.Container != "" {
+		username = ownerID
+	}
+	return azureWorkspaceBackupObjectName(username, workspaceID, name)
+}
+
+// InstanceObject returns a instance's object name that a direct downloader would download
+func (s *presignedAzureStorage) InstanceObject(ownerID string, workspaceID string, instanceID string, name string) string {
+	return s.BackupObject(ownerID, workspaceID, InstanceObjectName(instanceID, name))
+}
+
+// azureListBlobs lists all blobs with the given prefix. Returns an empty list if the container does not exist.
+func azureListBlobs(ctx context.Context, client *azblob.Client, bucket, prefix string) ([]*container.BlobItem, error) {
+	if client == nil {
+		return nil, xerrors.Errorf("no Azure client available - did you call Init()?")
+	}
+
+	var (
+		res   []*container.BlobItem
+		pager = client.NewListBlobsFlatPager(bucket, &azblob.ListBlobsFlatOptions{Prefix: &prefix})
+	)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			err = translateAzureError(err)
+			if err == ErrNotFound {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if page.Segment == nil {
+			continue
+		}
+		for _, b := range page.Segment.BlobItems {
+			if b == nil || b.Name == nil {
+				continue
+			}
+			res = append(res, b)
+		}
+	}
+	return res, nil
+}
+
+func azureHTTPHeaders(contentType string) *blob.HTTPHeaders {
+	if contentType == "" {
+		return nil
+	}
+	return &blob.HTTPHeaders{BlobContentType: &contentType}
+}
+
+// annotationToAzureMetadata maps annotations to metadata names, which need to be valid C# identifiers
+func annotationToAzureMetadata(annotation string) string {
+	return strings.ReplaceAll(annotation, "-", "_")
+}
+
+func annotationsToAzureMetadata(annotations map[string]string) map[string]*string {
+	if len(annotations) == 0 {
+		return nil
+	}
+	res := make(map[string]*string, len(annotations))
+	for k, v := range annotations {
+		v := v
+		res[annotationToAzureMetadata(k)] = &v
+	}
+	return res
+}
+
+// azureMetadataAnnotation returns the value of an annotation. Azure does not preserve the case of metadata names.
+func azureMetadataAnnotation(md map[string]*string, annotation string) string {
+	name := annotationToAzureMetadata(annotation)
+	for k, v := range md {
+		if strings.EqualFold(k, name) && v != nil {
+			return *v
+		}
+	}
+	return ""
+}
+
+func translateAzureError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound, bloberror.ResourceNotFound) {
+		return ErrNotFound
+	}
+	var rerr *azcore.ResponseError
+	if errors.As(err, &rerr) && rerr.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	return err
+}
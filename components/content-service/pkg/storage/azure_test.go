@@ -0,0 +1,294 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+const (
+	// azuriteAccountName and azuriteAccountKey are the well-known development credentials of the Azurite emulator
+	azuriteAccountName = "devstoreaccount1"
+	azuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+func TestAzureBucketName(t *testing.T) {
+	tests := []struct {
+		Name            string
+		ContainerConfig string
+		OwnerID         string
+		ExpectedBucket  string
+	}{
+		{
+			Name:            "no dedicated container",
+			ContainerConfig: "",
+			OwnerID:         "fake-owner-id",
+			ExpectedBucket:  "gitpod-user-fake-owner-id",
+		},
+		{
+			Name:            "with dedicated container",
+			ContainerConfig: "root-container",
+			OwnerID:         "fake-owner-id",
+			ExpectedBucket:  "root-container",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := &config.StorageConfig{
+				Stage: config.StageDevStaging,
+				Kind:  config.AzureStorage,
+				AzureConfig: &config.AzureConfig{
+					AccountName: "fake",
+					AccountKey:  azuriteAccountKey,
+					Container:   test.ContainerConfig,
+				},
+			}
+			ps, err := storage.NewPresignedAccess(cfg)
+			if err != nil {
+				t.Fatalf("failed to create presigned azure access: '%v'", err)
+			}
+
+			actualBucketName := ps.Bucket(test.OwnerID)
+			if actualBucketName != test.ExpectedBucket {
+				t.Fatalf("[presigned azure] unexpected bucket name: is '%s' but expected '%s'", actualBucketName, test.ExpectedBucket)
+			}
+		})
+	}
+}
+
+func TestAzureBackupObject(t *testing.T) {
+	tests := []struct {
+		Name                 string
+		ContainerConfig      string
+		Username             string
+		Workspace            string
+		InstanceID           string
+		ObjectName           string
+		ExpectedBackupObject string
+	}{
+		{
+			Name:                 "no dedicated container",
+			ContainerConfig:      "",
+			Username:             "test-user",
+			Workspace:            "gitpodio-gitpod-2cx8z8e643x",
+			InstanceID:           "fa9aa2af-b6de-45fc-8b48-534bb440429f",
+			ObjectName:           "backup.tar",
+			ExpectedBackupObject: "workspaces/gitpodio-gitpod-2cx8z8e643x/backup.tar",
+		},
+		{
+			Name:                 "with dedicated container",
+			ContainerConfig:      "root-container",
+			Username:             "test-user",
+			Workspace:            "gitpodio-gitpod-2cx8z8e643x",
+			InstanceID:           "fa9aa2af-b6de-45fc-8b48-534bb440429f",
+			ObjectName:           "backup.tar",
+			ExpectedBackupObject: "test-user/workspaces/gitpodio-gitpod-2cx8z8e643x/backup.tar",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := &config.StorageConfig{
+				Stage: config.StageDevStaging,
+				Kind:  config.AzureStorage,
+				AzureConfig: &config.AzureConfig{
+					AccountName: "fake",
+					AccountKey:  azuriteAccountKey,
+					Container:   test.ContainerConfig,
+				},
+			}
+			rs, err := storage.NewDirectAccess(cfg)
+			if err != nil {
+				t.Fatalf("failed to create azure access: '%v'", err)
+			}
+			err = rs.Init(context.Background(), test.Username, test.Workspace, test.InstanceID)
+			if err != nil {
+				t.Fatalf("failed to init azure access: '%v'", err)
+			}
+
+			actualBackupObject := rs.BackupObject(test.ObjectName)
+			if actualBackupObject != test.ExpectedBackupObject {
+				t.Fatalf("[azure] unexpected backup object name: is '%s' but expected '%s'", actualBackupObject, test.ExpectedBackupObject)
+			}
+
+			ps, err := storage.NewPresignedAccess(cfg)
+			if err != nil {
+				t.Fatalf("failed to create presigned azure access: '%v'", err)
+			}
+
+			actualBackupObject = ps.BackupObject(test.Username, test.Workspace, test.ObjectName)
+			if actualBackupObject != test.ExpectedBackupObject {
+				t.Fatalf("[presigned azure] unexpected backup object name: is '%s' but expected '%s'", actualBackupObject, test.ExpectedBackupObject)
+			}
+		})
+	}
+}
+
+type testableAzurePresignedAccess struct {
+	storage.PresignedAccess
+
+	Config *config.AzureConfig
+}
+
+func (s testableAzurePresignedAccess) ForTestCreateObj(ctx context.Context, bucket, path, content string) error {
+	err := s.EnsureExists(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	nfo, err := s.SignUpload(ctx, bucket, path, &storage.SignedURLOptions{})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, nfo.URL, strings.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s: %s", resp.Status, msg)
+	}
+	return nil
+}
+
+func (s testableAzurePresignedAccess) ForTestReset(ctx context.Context) error {
+	client, err := storage.NewAzureClient(s.Config)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteContainer(ctx, "test-bucket", nil)
+	if bloberror.HasCode(err, bloberror.ContainerNotFound) {
+		return nil
+	}
+	return err
+}
+
+// newTestAzureConfig returns a config for the Azurite emulator listening on AZURITE_ENDPOINT,
+// e.g. http://127.0.0.1:10000/devstoreaccount1, and skips the test if none is set.
+func newTestAzureConfig(t *testing.T) *config.AzureConfig {
+	endpoint := os.Getenv("AZURITE_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("AZURITE_ENDPOINT is not set")
+	}
+	return &config.AzureConfig{
+		Endpoint:    endpoint,
+		AccountName: azuriteAccountName,
+		AccountKey:  azuriteAccountKey,
+	}
+}
+
+func TestAzurePresignedHappyPath(t *testing.T) {
+	cfg := newTestAzureConfig(t)
+	ps, err := storage.NewPresignedAccess(&config.StorageConfig{
+		Stage:       config.StageDevStaging,
+		Kind:        config.AzureStorage,
+		AzureConfig: cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SuiteTestPresignedAccess(t, testableAzurePresignedAccess{PresignedAccess: ps, Config: cfg})
+}
+
+func TestAzureDirectAccess(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestAzureConfig(t)
+	scfg := &config.StorageConfig{
+		Stage:       config.StageDevStaging,
+		Kind:        config.AzureStorage,
+		AzureConfig: cfg,
+	}
+	ps, err := storage.NewPresignedAccess(scfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := storage.NewDirectAccess(scfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rs.Init(ctx, "owner", "workspace", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rs.EnsureExists(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ps.DeleteBucket(ctx, "owner", ps.Bucket("owner")) })
+
+	mpu, ok := rs.(storage.MultipartUploader)
+	if !ok {
+		t.Fatal("Azure storage does not support multipart uploads")
+	}
+	upload, err := mpu.NewMultipartUpload(ctx, storage.DefaultBackup, storage.WithContentType("text/plain"), storage.WithAnnotations(map[string]string{storage.ObjectAnnotationDigest: "sha256:foo"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	failOnErr(t, upload.UploadPart(ctx, 2, []byte(" world")))
+	failOnErr(t, upload.UploadPart(ctx, 1, []byte("hello")))
+	bkt, obj, err := upload.Complete(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bkt != ps.Bucket("owner") || obj != ps.BackupObject("owner", "workspace", storage.DefaultBackup) {
+		t.Errorf("direct and presigned access disagree on the backup location: %s/%s", bkt, obj)
+	}
+
+	nfo, err := ps.SignDownload(ctx, bkt, obj, &storage.SignedURLOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nfo.Meta.ContentType != "text/plain" || nfo.Meta.Digest != "sha256:foo" || nfo.Size != int64(len("hello world")) {
+		t.Errorf("unexpected download info: %+v", nfo)
+	}
+	resp, err := http.Get(nfo.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "hello world" {
+		t.Errorf("unexpected content: %q", body)
+	}
+
+	objs, err := rs.ListObjects(ctx, rs.BackupObject(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 || objs[0] != obj {
+		t.Errorf("unexpected objects: %v", objs)
+	}
+
+	err = ps.DeleteObject(ctx, bkt, &storage.DeleteObjectQuery{Prefix: rs.BackupObject("")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exists, err := ps.ObjectExists(ctx, bkt, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists {
+		t.Errorf("expected %s to be deleted", obj)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/chunks"
+)
+
+const (
+	// chunkSignConcurrency is the number of chunk downloads signed in parallel
+	chunkSignConcurrency = 16
+
+	// chunkDownloadConcurrency is the number of chunks downloaded ahead of time during restore
+	chunkDownloadConcurrency = 8
+)
+
+// IsChunkedBackup returns true if the object is the chunk index of a chunked backup rather than a tarball
+func IsChunkedBackup(info *DownloadInfo) bool {
+	return info != nil && info.Meta.ContentType == csapi.ContentTypeChunkIndex
+}
+
+// ChunkObject returns the name of the object of a chunk which belongs to the chunked backup with the given object name.
+// Chunks are stored next to the backups which reference them.
+func ChunkObject(backupObject string, chunk csapi.WorkspaceChunk) string {
+	return path.Join(path.Dir(backupObject), chunks.ObjectName(chunk.Digest))
+}
+
+// DownloadChunkIndex downloads the chunk index of a chunked backup.
+func DownloadChunkIndex(ctx context.Context, url string) (*csapi.WorkspaceChunkIndex, error) {
+	rc, err := chunks.Download(ctx, url)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot download chunk index: %w", err)
+	}
+	defer rc.Close()
+
+	return decodeChunkIndex(rc)
+}
+
+func decodeChunkIndex(r io.Reader) (*csapi.WorkspaceChunkIndex, error) {
+	var index csapi.WorkspaceChunkIndex
+	err := json.NewDecoder(r).Decode(&index)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse chunk index: %w", err)
+	}
+	return &index, nil
+}
+
+// SignChunkedBackup signs the downloads of the chunks of a chunked backup. The result is keyed by chunks.ObjectName.
+func SignChunkedBackup(ctx context.Context, ps PresignedAccess, bucket, obj string, info *DownloadInfo) (map[string]DownloadInfo, error) {
+	index, err := DownloadChunkIndex(ctx, info.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu  sync.Mutex
+		res = make(map[string]DownloadInfo, len(index.Chunks))
+	)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(chunkSignConcurrency)
+	for _, chunk := range index.Chunks {
+		name := chunks.ObjectName(chunk.Digest)
+		mu.Lock()
+		_, signed := res[name]
+		res[name] = DownloadInfo{}
+		mu.Unlock()
+		if signed {
+			continue
+		}
+
+		chunk := chunk
+		eg.Go(func() error {
+			info, err := ps.SignDownload(ctx, bucket, ChunkObject(obj, chunk), &SignedURLOptions{})
+			if err != nil {
+				return xerrors.Errorf("cannot sign download of chunk %s: %w", chunk.Digest, err)
+			}
+			mu.Lock()
+			res[name] = *info
+			mu.Unlock()
+			return nil
+		})
+	}
+	err = eg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ExtractChunkedBackup reassembles a chunked backup and extracts it to destination.
+// The chunks are downloaded from urls, which are keyed by chunks.ObjectName.
+func ExtractChunkedBackup(ctx context.Context, destination string, index *csapi.WorkspaceChunkIndex, urls map[string]string, mappings []archive.IDMapping) error {
+	for _, chunk := range index.Chunks {
+		if _, ok := urls[chunks.ObjectName(chunk.Digest)]; !ok {
+			return xerrors.Errorf("no download URL for chunk %s", chunk.Digest)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		err := chunks.Assemble(ctx, pw, index, chunkDownloadConcurrency, func(ctx context.Context, chunk csapi.WorkspaceChunk) (io.ReadCloser, error) {
+			return chunks.Download(ctx, urls[chunks.ObjectName(chunk.Digest)])
+		})
+		pw.CloseWithError(err)
+	}()
+
+	err := extractTarbal(ctx, destination, pr, mappings)
+	// make sure the assembly stops if the extraction failed
+	pr.CloseWithError(xerrors.Errorf("extraction stopped"))
+	return err
+}
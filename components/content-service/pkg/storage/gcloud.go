@@ -15,6 +15,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -35,6 +36,9 @@ import (
 )
 
 var _ DirectAccess = &DirectGCPStorage{}
+var _ BytesUploader = &DirectGCPStorage{}
+var _ MultipartUploader = &DirectGCPStorage{}
+var _ AnnotationUpdater = &PresignedGCPStorage{}
 
 var validateExistsInFilesystem = validation.By(func(o interface{}) error {
 	s, ok := o.(string)
@@ -358,6 +362,12 @@ func (rs *DirectGCPStorage) Upload(ctx context.Context, source string, name stri
 	defer tracing.FinishSpan(span, &err)
 	log := log.WithFields(log.OWI(rs.Username, rs.WorkspaceName, ""))
 
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		err = xerrors.Errorf("cannot get options: %w", err)
+		return
+	}
+
 	if rs.client == nil {
 		err = xerrors.Errorf("no gcloud client available - did you call Init()?")
 		return
@@ -403,11 +413,20 @@ func (rs *DirectGCPStorage) Upload(ctx context.Context, source string, name stri
 			sa = fmt.Sprintf(`-o "Credentials:gs_service_key_file=%v"`, rs.GCPConfig.CredentialsFile)
 		}
 
-		args := fmt.Sprintf(`gsutil -q -m %v\
+		// the content type tells manifests and chunk indices apart from tarballs, hence we must not lose it
+		var headers string
+		if options.ContentType != "" {
+			headers += fmt.Sprintf(` -h "Content-Type:%s"`, options.ContentType)
+		}
+		for k, v := range options.Annotations {
+			headers += fmt.Sprintf(` -h "x-goog-meta-%s:%s"`, k, v)
+		}
+
+		args := fmt.Sprintf(`gsutil -q -m %v%v \
 		  -o "GSUtil:parallel_composite_upload_threshold=150M" \
 		  -o "GSUtil:parallel_process_count=3" \
 		  -o "GSUtil:parallel_thread_count=6" \
-		  cp %s gs://%s`, sa, source, filepath.Join(bucket, object))
+		  cp %s gs://%s`, sa, headers, source, filepath.Join(bucket, object))
 
 		log.WithField("flags", args).Debug("gsutil flags")
 
@@ -429,6 +448,181 @@ func (rs *DirectGCPStorage) Upload(ctx context.Context, source string, name stri
 	return
 }
 
+// UploadBytes implements BytesUploader
+func (rs *DirectGCPStorage) UploadBytes(ctx context.Context, data []byte, name string, opts ...UploadOption) (bucket, obj string, err error) {
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		return "", "", xerrors.Errorf("cannot get options: %w", err)
+	}
+	if rs.client == nil {
+		return "", "", xerrors.Errorf("no gcloud client available - did you call Init()?")
+	}
+
+	bucket = rs.bucketName()
+	obj = rs.objectName(name)
+	w := rs.client.Bucket(bucket).Object(obj).NewWriter(ctx)
+	// the content is in memory already - no need for a resumable upload
+	w.ChunkSize = 0
+	w.ContentType = options.ContentType
+	w.Metadata = options.Annotations
+	_, err = w.Write(data)
+	if err != nil {
+		_ = w.Close()
+		return "", "", err
+	}
+	err = w.Close()
+	if err != nil {
+		return "", "", err
+	}
+	return bucket, obj, nil
+}
+
+// gcpMaxComposeSources is the maximum number of objects GCS can compose into one
+const gcpMaxComposeSources = 32
+
+// NewMultipartUpload implements MultipartUploader. GCS has no multipart uploads, hence we upload the parts
+// as temporary objects and compose them - just like gsutil's parallel composite uploads do.
+func (rs *DirectGCPStorage) NewMultipartUpload(ctx context.Context, name string, opts ...UploadOption) (upload MultipartUpload, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "GCloudBucketRemotegcpStorage.NewMultipartUpload")
+	defer tracing.FinishSpan(span, &err)
+
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get options: %w", err)
+	}
+
+	if rs.client == nil {
+		return nil, xerrors.Errorf("no gcloud client available - did you call Init()?")
+	}
+
+	bucket := rs.bucketName()
+	err = gcpEnsureExists(ctx, rs.client, bucket, rs.GCPConfig)
+	if err != nil {
+		return nil, xerrors.Errorf("unexpected error: %w", err)
+	}
+
+	obj := rs.objectName(name)
+	span.LogKV("bucket", bucket, "obj", obj)
+	return &gcpMultipartUpload{
+		bucketName:  bucket,
+		bucket:      rs.client.Bucket(bucket),
+		obj:         obj,
+		partsPrefix: fmt.Sprintf("%s.parts-%d/", obj, time.Now().UnixNano()),
+		contentType: options.ContentType,
+		metadata:    options.Annotations,
+		parts:       make(map[int]struct{}),
+	}, nil
+}
+
+type gcpMultipartUpload struct {
+	bucketName  string
+	bucket      *gcpstorage.BucketHandle
+	obj         string
+	partsPrefix string
+	contentType string
+	metadata    map[string]string
+
+	mu    sync.Mutex
+	parts map[int]struct{}
+}
+
+func (u *gcpMultipartUpload) partName(number int) string {
+	return fmt.Sprintf("%s%05d", u.partsPrefix, number)
+}
+
+// UploadPart implements MultipartUpload
+func (u *gcpMultipartUpload) UploadPart(ctx context.Context, number int, data []byte) error {
+	w := u.bucket.Object(u.partName(number)).NewWriter(ctx)
+	// the part is in memory already - no need for a resumable upload
+	w.ChunkSize = 0
+	_, err := w.Write(data)
+	if err != nil {
+		_ = w.Close()
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	u.parts[number] = struct{}{}
+	u.mu.Unlock()
+	return nil
+}
+
+// Complete implements MultipartUpload
+func (u *gcpMultipartUpload) Complete(ctx context.Context) (bucket, obj string, err error) {
+	u.mu.Lock()
+	numbers := make([]int, 0, len(u.parts))
+	for n := range u.parts {
+		numbers = append(numbers, n)
+	}
+	u.mu.Unlock()
+	sort.Ints(numbers)
+
+	if len(numbers) == 0 {
+		return "", "", xerrors.Errorf("no parts uploaded")
+	}
+
+	// Compose can only combine a limited number of objects, hence we append the parts in batches. All but the last
+	// batch go into a temporary object s.t. the previous content of the object stays intact until the final compose.
+	var (
+		dst = u.bucket.Object(u.obj)
+		tmp = u.bucket.Object(u.partsPrefix + "composed")
+	)
+	for i := 0; i < len(numbers); {
+		var srcs []*gcpstorage.ObjectHandle
+		if i > 0 {
+			srcs = append(srcs, tmp)
+		}
+		for ; i < len(numbers) && len(srcs) < gcpMaxComposeSources; i++ {
+			srcs = append(srcs, u.bucket.Object(u.partName(numbers[i])))
+		}
+
+		target := tmp
+		if i == len(numbers) {
+			target = dst
+		}
+		composer := target.ComposerFrom(srcs...)
+		composer.ContentType = u.contentType
+		composer.Metadata = u.metadata
+		_, err = composer.Run(ctx)
+		if err != nil {
+			return "", "", xerrors.Errorf("cannot compose parts: %w", err)
+		}
+	}
+
+	err = u.deleteParts(ctx)
+	if err != nil {
+		log.WithError(err).WithField("prefix", u.partsPrefix).Warn("cannot delete parts of multipart upload")
+	}
+	return u.bucketName, u.obj, nil
+}
+
+// Abort implements MultipartUpload
+func (u *gcpMultipartUpload) Abort(ctx context.Context) error {
+	return u.deleteParts(ctx)
+}
+
+func (u *gcpMultipartUpload) deleteParts(ctx context.Context) error {
+	it := u.bucket.Objects(ctx, &gcpstorage.Query{Prefix: u.partsPrefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = u.bucket.Object(attrs.Name).Delete(ctx)
+		if err != nil && !errors.Is(err, gcpstorage.ErrObjectNotExist) {
+			return err
+		}
+	}
+}
+
 func (rs *DirectGCPStorage) bucketName() string {
 	return gcpBucketName(rs.Stage, rs.Username)
 }
@@ -578,6 +772,34 @@ func (p *PresignedGCPStorage) DiskUsage(ctx context.Context, bucket string, pref
 	return total, nil
 }
 
+// ListObjects returns the names of all objects with the given prefix
+func (p *PresignedGCPStorage) ListObjects(ctx context.Context, bucket string, prefix string) (objects []string, err error) {
+	client, err := newGCPClient(ctx, p.config)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:staticcheck
+	defer client.Close()
+
+	it := client.Bucket(bucket).Objects(ctx, &gcpstorage.Query{
+		Prefix: prefix,
+	})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if errors.Is(err, gcpstorage.ErrBucketNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("cannot iterate list objects: %w", err)
+		}
+		objects = append(objects, attrs.Name)
+	}
+	return objects, nil
+}
+
 // SignDownload provides presigned URLs to access remote storage objects
 func (p *PresignedGCPStorage) SignDownload(ctx context.Context, bucket, object string, options *SignedURLOptions) (*DownloadInfo, error) {
 	client, err := newGCPClient(ctx, p.config)
@@ -618,6 +840,8 @@ func (p *PresignedGCPStorage) downloadInfo(ctx context.Context, client *gcpstora
 		OCIMediaType:       obj.Metadata[ObjectAnnotationOCIContentType],
 		Digest:             obj.Metadata[ObjectAnnotationDigest],
 		UncompressedDigest: obj.Metadata[ObjectAnnotationUncompressedDigest],
+		EncryptionKeyID:    obj.Metadata[ObjectAnnotationEncryptionKeyID],
+		EncryptedDataKey:   obj.Metadata[ObjectAnnotationEncryptedDataKey],
 	}
 	url, err := gcpstorage.SignedURL(obj.Bucket, obj.Name, &gcpstorage.SignedURLOptions{
 		Method:         "GET",
@@ -796,6 +1020,23 @@ func (p *PresignedGCPStorage) ObjectExists(ctx context.Context, bucket, obj stri
 	return true, nil
 }
 
+// UpdateAnnotations implements AnnotationUpdater
+func (p *PresignedGCPStorage) UpdateAnnotations(ctx context.Context, bucket, obj string, annotations map[string]string) error {
+	client, err := newGCPClient(ctx, p.config)
+	if err != nil {
+		return err
+	}
+	//nolint:staticcheck
+	defer client.Close()
+
+	// metadata is merged with the existing one
+	_, err = client.Bucket(bucket).Object(obj).Update(ctx, gcpstorage.ObjectAttrsToUpdate{Metadata: annotations})
+	if errors.Is(err, gcpstorage.ErrBucketNotExist) || errors.Is(err, gcpstorage.ErrObjectNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
 // BackupObject returns a backup's object name that a direct downloader would download
 func (p *PresignedGCPStorage) BackupObject(ownerID string, workspaceID string, name string) string {
 	return fmt.Sprintf("workspaces/%s", gcpWorkspaceBackupObjectName(workspaceID, name))
@@ -0,0 +1,849 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+)
+
+var _ DirectAccess = &DirectLocalStorage{}
+var _ BytesUploader = &DirectLocalStorage{}
+var _ PresignedAccess = &PresignedLocalStorage{}
+var _ AnnotationUpdater = &PresignedLocalStorage{}
+
+const (
+	// LocalStoragePath is the HTTP path under which PresignedLocalStorage serves objects
+	LocalStoragePath = "/storage/"
+
+	// localMetaDir holds the content type and annotations of the objects, next to the bucket directories
+	localMetaDir = ".meta"
+
+	// localTmpPrefix marks files which are still being written
+	localTmpPrefix = ".upload-"
+
+	localURLExpiry = 30 * time.Minute
+)
+
+// ValidateLocalConfig checks if the local storage config is valid
+func ValidateLocalConfig(c *config.LocalConfig) error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Path, validation.Required),
+	)
+}
+
+// addLocalParamsFromMounts allows for the secret to be read from a file
+func addLocalParamsFromMounts(c *config.LocalConfig) error {
+	if c.SecretFile != "" {
+		value, err := os.ReadFile(c.SecretFile)
+		if err != nil {
+			return err
+		}
+		c.Secret = strings.TrimSpace(string(value))
+	}
+	return nil
+}
+
+func newDirectLocalAccess(cfg *config.LocalConfig) (*DirectLocalStorage, error) {
+	if cfg == nil {
+		return nil, xerrors.Errorf("missing local storage config")
+	}
+	err := ValidateLocalConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &DirectLocalStorage{LocalConfig: *cfg, store: localStore{Path: cfg.Path}}, nil
+}
+
+// DirectLocalStorage implements a directory on the local filesystem as remote storage backend
+type DirectLocalStorage struct {
+	Username      string
+	WorkspaceName string
+	InstanceID    string
+	LocalConfig   config.LocalConfig
+
+	store localStore
+}
+
+// Validate checks if the local storage is configured properly
+func (rs *DirectLocalStorage) Validate() error {
+	err := ValidateLocalConfig(&rs.LocalConfig)
+	if err != nil {
+		return err
+	}
+
+	return validation.ValidateStruct(rs,
+		validation.Field(&rs.Username, validation.Required),
+		validation.Field(&rs.WorkspaceName, validation.Required),
+	)
+}
+
+// Init initializes the remote storage - call this before calling anything else on the interface
+func (rs *DirectLocalStorage) Init(ctx context.Context, owner, workspace, instance string) (err error) {
+	rs.Username = owner
+	rs.WorkspaceName = workspace
+	rs.InstanceID = instance
+	rs.store = localStore{Path: rs.LocalConfig.Path}
+
+	return rs.Validate()
+}
+
+// EnsureExists makes sure that the remote storage location exists and can be up- or downloaded from
+func (rs *DirectLocalStorage) EnsureExists(ctx context.Context) (err error) {
+	return rs.store.ensureBucket(rs.bucketName())
+}
+
+func (rs *DirectLocalStorage) download(ctx context.Context, destination string, bkt string, obj string, mappings []archive.IDMapping) (found bool, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "download")
+	span.SetTag("bucket", bkt)
+	span.SetTag("object", obj)
+	defer tracing.FinishSpan(span, &err)
+
+	f, _, err := rs.store.open(bkt, obj)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	err = extractTarbal(ctx, destination, f, mappings)
+	if err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// Download takes the latest state from the remote storage and downloads it to a local path
+func (rs *DirectLocalStorage) Download(ctx context.Context, destination string, name string, mappings []archive.IDMapping) (bool, error) {
+	return rs.download(ctx, destination, rs.bucketName(), rs.objectName(name), mappings)
+}
+
+// DownloadSnapshot downloads a snapshot. The snapshot name is expected to be one produced by Qualify
+func (rs *DirectLocalStorage) DownloadSnapshot(ctx context.Context, destination string, name string, mappings []archive.IDMapping) (bool, error) {
+	bkt, obj, err := ParseSnapshotName(name)
+	if err != nil {
+		return false, err
+	}
+
+	return rs.download(ctx, destination, bkt, obj, mappings)
+}
+
+// ListObjects returns all objects found with the given prefix. Returns an empty list if the bucket does not exuist (yet).
+func (rs *DirectLocalStorage) ListObjects(ctx context.Context, prefix string) (objects []string, err error) {
+	objs, err := rs.store.list(rs.bucketName(), prefix)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list objects: %w", err)
+	}
+	for _, obj := range objs {
+		objects = append(objects, obj.Name)
+	}
+	return objects, nil
+}
+
+// Qualify fully qualifies a snapshot name so that it can be downloaded using DownloadSnapshot
+func (rs *DirectLocalStorage) Qualify(name string) string {
+	return fmt.Sprintf("%s@%s", rs.objectName(name), rs.bucketName())
+}
+
+// UploadInstance takes all files from a local location and uploads it to the per-instance remote storage
+func (rs *DirectLocalStorage) UploadInstance(ctx context.Context, source string, name string, opts ...UploadOption) (bucket, object string, err error) {
+	if rs.InstanceID == "" {
+		return "", "", xerrors.Errorf("instanceID is required to comput object name")
+	}
+	return rs.Upload(ctx, source, InstanceObjectName(rs.InstanceID, name), opts...)
+}
+
+// Upload takes all files from a local location and uploads it to the remote storage
+func (rs *DirectLocalStorage) Upload(ctx context.Context, source string, name string, opts ...UploadOption) (bucket, obj string, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "DirectUpload")
+	defer tracing.FinishSpan(span, &err)
+
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		err = xerrors.Errorf("cannot get options: %w", err)
+		return
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	bucket = rs.bucketName()
+	obj = rs.objectName(name)
+	span.LogKV("bucket", bucket)
+	span.LogKV("obj", obj)
+	err = rs.store.put(bucket, obj, f, localObjectMeta{
+		ContentType: options.ContentType,
+		Annotations: options.Annotations,
+	})
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// UploadBytes implements BytesUploader
+func (rs *DirectLocalStorage) UploadBytes(ctx context.Context, data []byte, name string, opts ...UploadOption) (bucket, obj string, err error) {
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		return "", "", xerrors.Errorf("cannot get options: %w", err)
+	}
+
+	bucket = rs.bucketName()
+	obj = rs.objectName(name)
+	err = rs.store.put(bucket, obj, bytes.NewReader(data), localObjectMeta{
+		ContentType: options.ContentType,
+		Annotations: options.Annotations,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return bucket, obj, nil
+}
+
+// Bucket provides the bucket name for a particular user
+func (rs *DirectLocalStorage) Bucket(ownerID string) string {
+	return localBucketName(ownerID)
+}
+
+// BackupObject returns a backup's object name that a direct downloader would download
+func (rs *DirectLocalStorage) BackupObject(name string) string {
+	return rs.objectName(name)
+}
+
+func (rs *DirectLocalStorage) bucketName() string {
+	return localBucketName(rs.Username)
+}
+
+func (rs *DirectLocalStorage) objectName(name string) string {
+	return localWorkspaceBackupObjectName(rs.WorkspaceName, name)
+}
+
+func localBucketName(ownerID string) string {
+	return fmt.Sprintf("gitpod-user-%s", ownerID)
+}
+
+func localWorkspaceBackupObjectName(workspaceID, name string) string {
+	return path.Join("workspaces", workspaceID, name)
+}
+
+// NewPresignedLocalAccess provides presigned URLs for a directory on the local filesystem.
+// The URLs are served by the PresignedLocalStorage itself, see ServeHTTP.
+func NewPresignedLocalAccess(cfg *config.LocalConfig) (*PresignedLocalStorage, error) {
+	if cfg == nil {
+		return nil, xerrors.Errorf("missing local storage config")
+	}
+	c := *cfg
+	err := addLocalParamsFromMounts(&c)
+	if err != nil {
+		return nil, err
+	}
+	err = validation.ValidateStruct(&c,
+		validation.Field(&c.Path, validation.Required),
+		validation.Field(&c.URL, validation.Required),
+		validation.Field(&c.Secret, validation.Required),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PresignedLocalStorage{
+		LocalConfig: c,
+		store:       localStore{Path: c.Path},
+		secret:      []byte(c.Secret),
+	}, nil
+}
+
+// PresignedLocalStorage provides HMAC-signed URLs for a directory on the local filesystem and serves them
+// as http.Handler under LocalStoragePath.
+type PresignedLocalStorage struct {
+	LocalConfig config.LocalConfig
+
+	store  localStore
+	secret []byte
+}
+
+// EnsureExists makes sure that the remote storage location exists and can be up- or downloaded from
+func (s *PresignedLocalStorage) EnsureExists(ctx context.Context, bucket string) (err error) {
+	return s.store.ensureBucket(bucket)
+}
+
+func (s *PresignedLocalStorage) DiskUsage(ctx context.Context, bucket string, prefix string) (size int64, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "local.DiskUsage")
+	defer tracing.FinishSpan(span, &err)
+
+	objs, err := s.store.list(bucket, prefix)
+	if err != nil {
+		return 0, err
+	}
+	for _, obj := range objs {
+		size += obj.Size
+	}
+	return size, nil
+}
+
+func (s *PresignedLocalStorage) ListObjects(ctx context.Context, bucket string, prefix string) (objects []string, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "local.ListObjects")
+	defer tracing.FinishSpan(span, &err)
+
+	objs, err := s.store.list(bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		objects = append(objects, obj.Name)
+	}
+	return objects, nil
+}
+
+func (s *PresignedLocalStorage) SignDownload(ctx context.Context, bucket, obj string, options *SignedURLOptions) (info *DownloadInfo, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "local.SignDownload")
+	defer func() {
+		if err == ErrNotFound {
+			span.LogKV("found", false)
+			tracing.FinishSpan(span, nil)
+			return
+		}
+
+		tracing.FinishSpan(span, &err)
+	}()
+
+	stat, meta, err := s.store.stat(bucket, obj)
+	if err != nil {
+		return nil, err
+	}
+	url, err := s.signURL(http.MethodGet, bucket, obj, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &DownloadInfo{
+		Meta: ObjectMeta{
+			ContentType:        meta.ContentType,
+			OCIMediaType:       meta.Annotations[ObjectAnnotationOCIContentType],
+			Digest:             meta.Annotations[ObjectAnnotationDigest],
+			UncompressedDigest: meta.Annotations[ObjectAnnotationUncompressedDigest],
+			EncryptionKeyID:    meta.Annotations[ObjectAnnotationEncryptionKeyID],
+			EncryptedDataKey:   meta.Annotations[ObjectAnnotationEncryptedDataKey],
+		},
+		Size: stat.Size(),
+		URL:  url,
+	}, nil
+}
+
+// SignUpload describes an object for upload
+func (s *PresignedLocalStorage) SignUpload(ctx context.Context, bucket, obj string, options *SignedURLOptions) (info *UploadInfo, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "local.SignUpload")
+	defer tracing.FinishSpan(span, &err)
+
+	var contentType string
+	if options != nil {
+		contentType = options.ContentType
+	}
+	url, err := s.signURL(http.MethodPut, bucket, obj, contentType)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadInfo{URL: url}, nil
+}
+
+func (s *PresignedLocalStorage) DeleteObject(ctx context.Context, bucket string, query *DeleteObjectQuery) (err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "local.DeleteObject")
+	defer tracing.FinishSpan(span, &err)
+
+	if query.Name != "" {
+		err = s.store.delete(bucket, query.Name)
+		if err != nil {
+			log.WithField("bucket", bucket).WithField("object", query.Name).Error(err)
+			return err
+		}
+		return nil
+	}
+	if query.Prefix != "" {
+		objs, err := s.store.list(bucket, query.Prefix)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			err = s.store.delete(bucket, obj.Name)
+			if err != nil {
+				log.WithField("bucket", bucket).WithField("object", obj.Name).Error(err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteBucket deletes a bucket
+func (s *PresignedLocalStorage) DeleteBucket(ctx context.Context, userID, bucket string) (err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "local.DeleteBucket")
+	defer tracing.FinishSpan(span, &err)
+
+	return s.store.deleteBucket(bucket)
+}
+
+// ObjectHash gets a hash value of an object
+func (s *PresignedLocalStorage) ObjectHash(ctx context.Context, bucket string, obj string) (hash string, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "local.ObjectHash")
+	defer tracing.FinishSpan(span, &err)
+
+	f, _, err := s.store.open(bucket, obj)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (s *PresignedLocalStorage) ObjectExists(ctx context.Context, bucket, obj string) (exists bool, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "local.ObjectExists")
+	defer tracing.FinishSpan(span, &err)
+
+	_, _, err = s.store.stat(bucket, obj)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdateAnnotations implements AnnotationUpdater
+func (s *PresignedLocalStorage) UpdateAnnotations(ctx context.Context, bucket, obj string, annotations map[string]string) error {
+	return s.store.updateAnnotations(bucket, obj, annotations)
+}
+
+// Bucket provides the bucket name for a particular user
+func (s *PresignedLocalStorage) Bucket(ownerID string) string {
+	return localBucketName(ownerID)
+}
+
+// BlobObject returns a blob's object name
+func (s *PresignedLocalStorage) BlobObject(userID, name string) (string, error) {
+	return blobObjectName(name)
+}
+
+// BackupObject returns a backup's object name that a direct downloader would download
+func (s *PresignedLocalStorage) BackupObject(ownerID string, workspaceID, name string) string {
+	return localWorkspaceBackupObjectName(workspaceID, name)
+}
+
+// InstanceObject returns a instance's object name that a direct downloader would download
+func (s *PresignedLocalStorage) InstanceObject(ownerID string, workspaceID string, instanceID string, name string) string {
+	return s.BackupObject(ownerID, workspaceID, InstanceObjectName(instanceID, name))
+}
+
+// signURL produces a URL for method on bucket/obj which expires after localURLExpiry.
+// If contentType is set, uploads must use that content type.
+func (s *PresignedLocalStorage) signURL(method, bucket, obj, contentType string) (string, error) {
+	_, err := s.store.objectPath(bucket, obj)
+	if err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(localURLExpiry).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	if contentType != "" {
+		query.Set("contentType", contentType)
+	}
+	query.Set("signature", s.signature(method, bucket, obj, expires, contentType))
+
+	p := (&url.URL{Path: LocalStoragePath + bucket + "/" + obj}).EscapedPath()
+	return strings.TrimSuffix(s.LocalConfig.URL, "/") + p + "?" + query.Encode(), nil
+}
+
+func (s *PresignedLocalStorage) signature(method, bucket, obj string, expires int64, contentType string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d\n%s", method, bucket, obj, expires, contentType)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP serves the URLs produced by SignDownload and SignUpload
+func (s *PresignedLocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := r.Method
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		method = http.MethodGet
+	case http.MethodPut:
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bucket, obj, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, LocalStoragePath), "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		http.Error(w, "URL has expired", http.StatusForbidden)
+		return
+	}
+	contentType := query.Get("contentType")
+	expected := s.signature(method, bucket, obj, expires, contentType)
+	if !hmac.Equal([]byte(expected), []byte(query.Get("signature"))) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	if method == http.MethodPut {
+		if contentType != "" && r.Header.Get("Content-Type") != contentType {
+			http.Error(w, "unexpected content type", http.StatusForbidden)
+			return
+		}
+
+		err = s.store.put(bucket, obj, r.Body, localObjectMeta{ContentType: r.Header.Get("Content-Type")})
+		if err != nil {
+			log.WithError(err).WithField("bucket", bucket).WithField("object", obj).Error("cannot store object")
+			http.Error(w, "cannot store object", http.StatusInternalServerError)
+			return
+		}
+		return
+	}
+
+	f, meta, err := s.store.open(bucket, obj)
+	if err == ErrNotFound {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.WithError(err).WithField("bucket", bucket).WithField("object", obj).Error("cannot open object")
+		http.Error(w, "cannot open object", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		http.Error(w, "cannot open object", http.StatusInternalServerError)
+		return
+	}
+
+	contentType = meta.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	http.ServeContent(w, r, "", stat.ModTime(), f)
+}
+
+// localStore keeps buckets as directories below Path. Objects are files within their bucket directory,
+// their content type and annotations are kept in localMetaDir. As objects are files, an object cannot be
+// a prefix directory of other objects at the same time.
+type localStore struct {
+	Path string
+}
+
+type localObjectMeta struct {
+	ContentType string            `json:"contentType,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type localObject struct {
+	Name string
+	Size int64
+}
+
+func (s localStore) bucketPath(bucket string) (string, error) {
+	if bucket == "" || strings.ContainsAny(bucket, `/\`) || strings.HasPrefix(bucket, ".") {
+		return "", xerrors.Errorf("invalid bucket name: %s", bucket)
+	}
+	return filepath.Join(s.Path, bucket), nil
+}
+
+func (s localStore) objectPath(bucket, obj string) (string, error) {
+	bkt, err := s.bucketPath(bucket)
+	if err != nil {
+		return "", err
+	}
+	if obj == "" || path.Clean("/"+obj) != "/"+obj {
+		return "", xerrors.Errorf("invalid object name: %s", obj)
+	}
+	if strings.HasPrefix(path.Base(obj), localTmpPrefix) {
+		return "", xerrors.Errorf("invalid object name: %s", obj)
+	}
+	return filepath.Join(bkt, filepath.FromSlash(obj)), nil
+}
+
+func (s localStore) metaPath(bucket, obj string) string {
+	return filepath.Join(s.Path, localMetaDir, bucket, filepath.FromSlash(obj)+".json")
+}
+
+func (s localStore) ensureBucket(bucket string) error {
+	bkt, err := s.bucketPath(bucket)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(bkt, 0755)
+}
+
+// put writes the object atomically, s.t. readers never see partial content
+func (s localStore) put(bucket, obj string, content io.Reader, meta localObjectMeta) error {
+	fn, err := s.objectPath(bucket, obj)
+	if err != nil {
+		return err
+	}
+
+	mfn := s.metaPath(bucket, obj)
+	if meta.ContentType == "" && len(meta.Annotations) == 0 {
+		err = os.Remove(mfn)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	} else {
+		m, err := json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+		err = writeFileAtomically(mfn, bytes.NewReader(m))
+		if err != nil {
+			return xerrors.Errorf("cannot write object metadata: %w", err)
+		}
+	}
+
+	return writeFileAtomically(fn, content)
+}
+
+func (s localStore) open(bucket, obj string) (*os.File, localObjectMeta, error) {
+	var meta localObjectMeta
+	fn, err := s.objectPath(bucket, obj)
+	if err != nil {
+		return nil, meta, err
+	}
+	f, err := os.Open(fn)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, meta, ErrNotFound
+	}
+	if err != nil {
+		return nil, meta, err
+	}
+	meta, err = s.meta(bucket, obj)
+	if err != nil {
+		f.Close()
+		return nil, meta, err
+	}
+	return f, meta, nil
+}
+
+func (s localStore) stat(bucket, obj string) (fs.FileInfo, localObjectMeta, error) {
+	var meta localObjectMeta
+	fn, err := s.objectPath(bucket, obj)
+	if err != nil {
+		return nil, meta, err
+	}
+	stat, err := os.Stat(fn)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, meta, ErrNotFound
+	}
+	if err != nil {
+		return nil, meta, err
+	}
+	if stat.IsDir() {
+		return nil, meta, ErrNotFound
+	}
+	meta, err = s.meta(bucket, obj)
+	if err != nil {
+		return nil, meta, err
+	}
+	return stat, meta, nil
+}
+
+func (s localStore) updateAnnotations(bucket, obj string, annotations map[string]string) error {
+	_, meta, err := s.stat(bucket, obj)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		meta.Annotations[k] = v
+	}
+
+	m, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	err = writeFileAtomically(s.metaPath(bucket, obj), bytes.NewReader(m))
+	if err != nil {
+		return xerrors.Errorf("cannot write object metadata: %w", err)
+	}
+	return nil
+}
+
+func (s localStore) meta(bucket, obj string) (meta localObjectMeta, err error) {
+	m, err := os.ReadFile(s.metaPath(bucket, obj))
+	if errors.Is(err, fs.ErrNotExist) {
+		return meta, nil
+	}
+	if err != nil {
+		return meta, err
+	}
+	err = json.Unmarshal(m, &meta)
+	if err != nil {
+		return meta, xerrors.Errorf("cannot read object metadata: %w", err)
+	}
+	return meta, nil
+}
+
+// list returns all objects with the given prefix. Returns an empty list if the bucket does not exist.
+func (s localStore) list(bucket, prefix string) ([]localObject, error) {
+	bkt, err := s.bucketPath(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []localObject
+	err = filepath.WalkDir(bkt, func(fn string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && fn == bkt {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), localTmpPrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(bkt, fn)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		res = append(res, localObject{Name: name, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s localStore) delete(bucket, obj string) error {
+	fn, err := s.objectPath(bucket, obj)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(fn)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	err = os.Remove(s.metaPath(bucket, obj))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// object stores have no directories - remove the ones which are empty now
+	bkt, _ := s.bucketPath(bucket)
+	for dir := filepath.Dir(fn); dir != bkt && strings.HasPrefix(dir, bkt); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func (s localStore) deleteBucket(bucket string) error {
+	bkt, err := s.bucketPath(bucket)
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(bkt)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(s.Path, localMetaDir, bucket))
+}
+
+func writeFileAtomically(fn string, content io.Reader) (err error) {
+	err = os.MkdirAll(filepath.Dir(fn), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(fn), localTmpPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	_, err = io.Copy(f, content)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(f.Name(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), fn)
+}
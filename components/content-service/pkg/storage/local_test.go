@@ -0,0 +1,255 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package storage_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+type testableLocalPresignedAccess struct {
+	*storage.PresignedLocalStorage
+}
+
+func (s testableLocalPresignedAccess) ForTestCreateObj(ctx context.Context, bucket, path, content string) error {
+	nfo, err := s.SignUpload(ctx, bucket, path, &storage.SignedURLOptions{})
+	if err != nil {
+		return err
+	}
+	return doRequest(http.MethodPut, nfo.URL, "", strings.NewReader(content), nil)
+}
+
+func (s testableLocalPresignedAccess) ForTestReset(ctx context.Context) error {
+	err := os.RemoveAll(s.LocalConfig.Path)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(s.LocalConfig.Path, 0755)
+}
+
+func newTestLocalStorage(t *testing.T) *storage.PresignedLocalStorage {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	ps, err := storage.NewPresignedLocalAccess(&config.LocalConfig{
+		Path:   t.TempDir(),
+		URL:    srv.URL,
+		Secret: "test-secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.Handle(storage.LocalStoragePath, ps)
+	return ps
+}
+
+func TestLocalPresignedHappyPath(t *testing.T) {
+	SuiteTestPresignedAccess(t, testableLocalPresignedAccess{newTestLocalStorage(t)})
+}
+
+func TestLocalSignedURLs(t *testing.T) {
+	const (
+		bucket      = "test-bucket"
+		obj         = "foo/bar.txt"
+		content     = "hello world"
+		contentType = "text/plain"
+	)
+
+	tests := []struct {
+		Name              string
+		UploadContentType string
+		Tamper            func(url string) string
+		ExpectedStatus    int
+	}{
+		{
+			Name:              "happy path",
+			UploadContentType: contentType,
+			ExpectedStatus:    http.StatusOK,
+		},
+		{
+			Name:              "unexpected content type",
+			UploadContentType: "application/octet-stream",
+			ExpectedStatus:    http.StatusForbidden,
+		},
+		{
+			Name:              "tampered object",
+			UploadContentType: contentType,
+			Tamper:            func(url string) string { return strings.Replace(url, "bar.txt", "baz.txt", 1) },
+			ExpectedStatus:    http.StatusForbidden,
+		},
+		{
+			Name:              "tampered expiry",
+			UploadContentType: contentType,
+			Tamper:            func(url string) string { return strings.Replace(url, "expires=", "expires=9", 1) },
+			ExpectedStatus:    http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := context.Background()
+			ps := newTestLocalStorage(t)
+
+			up, err := ps.SignUpload(ctx, bucket, obj, &storage.SignedURLOptions{ContentType: contentType})
+			if err != nil {
+				t.Fatal(err)
+			}
+			url := up.URL
+			if test.Tamper != nil {
+				url = test.Tamper(url)
+			}
+			var status int
+			err = doRequest(http.MethodPut, url, test.UploadContentType, strings.NewReader(content), &status)
+			if status != test.ExpectedStatus {
+				t.Fatalf("unexpected upload status: is %d but expected %d (%v)", status, test.ExpectedStatus, err)
+			}
+			if test.ExpectedStatus != http.StatusOK {
+				exists, err := ps.ObjectExists(ctx, bucket, obj)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if exists {
+					t.Fatalf("object was uploaded despite status %d", status)
+				}
+				return
+			}
+
+			down, err := ps.SignDownload(ctx, bucket, obj, &storage.SignedURLOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if down.Meta.ContentType != contentType {
+				t.Errorf("unexpected content type: is %s but expected %s", down.Meta.ContentType, contentType)
+			}
+			resp, err := http.Get(down.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != content {
+				t.Errorf("unexpected content: is %q but expected %q", body, content)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != contentType {
+				t.Errorf("unexpected content type header: is %s but expected %s", ct, contentType)
+			}
+
+			_, err = ps.SignDownload(ctx, bucket, "does/not/exist", &storage.SignedURLOptions{})
+			if err != storage.ErrNotFound {
+				t.Errorf("expected ErrNotFound for missing object, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLocalDirectAccess(t *testing.T) {
+	ctx := context.Background()
+	ps := newTestLocalStorage(t)
+	cfg := &config.StorageConfig{
+		Stage:       config.StageDevStaging,
+		Kind:        config.LocalStorage,
+		LocalConfig: &ps.LocalConfig,
+	}
+	rs, err := storage.NewDirectAccess(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rs.Init(ctx, "owner", "workspace", "instance")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	_ = tw.WriteHeader(&tar.Header{Name: "file.txt", Size: 5, Mode: 0644, Typeflag: tar.TypeReg, Uid: os.Getuid(), Gid: os.Getgid()})
+	_, _ = tw.Write([]byte("hello"))
+	tw.Close()
+	src := filepath.Join(t.TempDir(), "backup.tar")
+	err = os.WriteFile(src, buf.Bytes(), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bkt, obj, err := rs.Upload(ctx, src, storage.DefaultBackup, storage.WithContentType("application/x-tar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bkt != ps.Bucket("owner") || obj != ps.BackupObject("owner", "workspace", storage.DefaultBackup) {
+		t.Errorf("direct and presigned access disagree on the backup location: %s/%s", bkt, obj)
+	}
+
+	objs, err := rs.ListObjects(ctx, rs.BackupObject(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 || objs[0] != obj {
+		t.Errorf("unexpected objects: %v", objs)
+	}
+
+	nfo, err := ps.SignDownload(ctx, bkt, obj, &storage.SignedURLOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nfo.Meta.ContentType != "application/x-tar" {
+		t.Errorf("unexpected content type: %s", nfo.Meta.ContentType)
+	}
+
+	dst := t.TempDir()
+	found, err := rs.Download(ctx, dst, storage.DefaultBackup, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("backup not found")
+	}
+	content, err := os.ReadFile(filepath.Join(dst, "file.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "hello" {
+		t.Errorf("unexpected content: %q", content)
+	}
+
+	found, err = rs.DownloadSnapshot(ctx, dst, rs.Qualify("does-not-exist.tar"), nil)
+	if err != nil || found {
+		t.Errorf("expected missing snapshot to not be found: found=%v err=%v", found, err)
+	}
+}
+
+func doRequest(method, url, contentType string, body io.Reader, status *int) error {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if status != nil {
+		*status = resp.StatusCode
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s: %s", resp.Status, msg)
+	}
+	return nil
+}
@@ -5,13 +5,16 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
@@ -27,6 +30,9 @@ import (
 )
 
 var _ DirectAccess = &DirectMinIOStorage{}
+var _ BytesUploader = &DirectMinIOStorage{}
+var _ MultipartUploader = &DirectMinIOStorage{}
+var _ AnnotationUpdater = &presignedMinIOStorage{}
 
 // Validate checks if the GCloud storage MinIOconfig is valid
 func ValidateMinIOConfig(c *config.MinIOConfig) error {
@@ -312,6 +318,109 @@ func (rs *DirectMinIOStorage) Upload(ctx context.Context, source string, name st
 	return
 }
 
+// UploadBytes implements BytesUploader
+func (rs *DirectMinIOStorage) UploadBytes(ctx context.Context, data []byte, name string, opts ...UploadOption) (bucket, obj string, err error) {
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		return "", "", xerrors.Errorf("cannot get options: %w", err)
+	}
+	if rs.client == nil {
+		return "", "", xerrors.Errorf("no minio client available - did you call Init()?")
+	}
+
+	bucket = rs.bucketName()
+	obj = rs.objectName(name)
+	_, err = rs.client.PutObject(ctx, bucket, obj, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		UserMetadata: options.Annotations,
+		ContentType:  options.ContentType,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return bucket, obj, nil
+}
+
+// NewMultipartUpload implements MultipartUploader
+func (rs *DirectMinIOStorage) NewMultipartUpload(ctx context.Context, name string, opts ...UploadOption) (upload MultipartUpload, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "minio.NewMultipartUpload")
+	defer tracing.FinishSpan(span, &err)
+
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get options: %w", err)
+	}
+
+	if rs.client == nil {
+		return nil, xerrors.Errorf("no minio client available - did you call Init()?")
+	}
+
+	res := &minioMultipartUpload{
+		core:   minio.Core{Client: rs.client},
+		bucket: rs.bucketName(),
+		obj:    rs.objectName(name),
+		parts:  make(map[int]minio.CompletePart),
+	}
+	span.LogKV("bucket", res.bucket, "obj", res.obj)
+	res.uploadID, err = res.core.NewMultipartUpload(ctx, res.bucket, res.obj, minio.PutObjectOptions{
+		UserMetadata: options.Annotations,
+		ContentType:  options.ContentType,
+	})
+	if err != nil {
+		return nil, translateMinioError(err)
+	}
+
+	return res, nil
+}
+
+type minioMultipartUpload struct {
+	core     minio.Core
+	bucket   string
+	obj      string
+	uploadID string
+
+	mu    sync.Mutex
+	parts map[int]minio.CompletePart
+}
+
+// UploadPart implements MultipartUpload
+func (u *minioMultipartUpload) UploadPart(ctx context.Context, number int, data []byte) error {
+	part, err := u.core.PutObjectPart(ctx, u.bucket, u.obj, u.uploadID, number, bytes.NewReader(data), int64(len(data)), "", "", nil)
+	if err != nil {
+		return translateMinioError(err)
+	}
+
+	u.mu.Lock()
+	u.parts[number] = minio.CompletePart{
+		PartNumber: number,
+		ETag:       part.ETag,
+	}
+	u.mu.Unlock()
+	return nil
+}
+
+// Complete implements MultipartUpload
+func (u *minioMultipartUpload) Complete(ctx context.Context) (bucket, obj string, err error) {
+	u.mu.Lock()
+	parts := make([]minio.CompletePart, 0, len(u.parts))
+	for _, p := range u.parts {
+		parts = append(parts, p)
+	}
+	u.mu.Unlock()
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	_, err = u.core.CompleteMultipartUpload(ctx, u.bucket, u.obj, u.uploadID, parts, minio.PutObjectOptions{})
+	if err != nil {
+		return "", "", translateMinioError(err)
+	}
+	return u.bucket, u.obj, nil
+}
+
+// Abort implements MultipartUpload
+func (u *minioMultipartUpload) Abort(ctx context.Context) error {
+	return translateMinioError(u.core.AbortMultipartUpload(ctx, u.bucket, u.obj, u.uploadID))
+}
+
 func minioBucketName(ownerID, bucketName string) string {
 	if bucketName != "" {
 		return bucketName
@@ -386,6 +495,27 @@ func (s *presignedMinIOStorage) DiskUsage(ctx context.Context, bucket string, pr
 	return total, nil
 }
 
+func (s *presignedMinIOStorage) ListObjects(ctx context.Context, bucket string, prefix string) (objects []string, err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "minio.ListObjects")
+	defer tracing.FinishSpan(span, &err)
+
+	for object := range s.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			err = translateMinioError(object.Err)
+			if err == ErrNotFound {
+				return nil, nil
+			}
+			return nil, err
+		}
+		objects = append(objects, object.Key)
+	}
+	return objects, nil
+}
+
 func (s *presignedMinIOStorage) SignDownload(ctx context.Context, bucket, object string, options *SignedURLOptions) (info *DownloadInfo, err error) {
 	//nolint:ineffassign
 	span, ctx := opentracing.StartSpanFromContext(ctx, "minio.SignDownload")
@@ -418,6 +548,8 @@ func (s *presignedMinIOStorage) SignDownload(ctx context.Context, bucket, object
 			OCIMediaType:       stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationOCIContentType)),
 			Digest:             stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationDigest)),
 			UncompressedDigest: stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationUncompressedDigest)),
+			EncryptionKeyID:    stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationEncryptionKeyID)),
+			EncryptedDataKey:   stat.Metadata.Get(annotationToAmzMetaHeader(ObjectAnnotationEncryptedDataKey)),
 		},
 		Size: stat.Size,
 		URL:  url.String(),
@@ -521,6 +653,32 @@ func (s *presignedMinIOStorage) ObjectExists(ctx context.Context, bucket, obj st
 	return true, nil
 }
 
+// UpdateAnnotations implements AnnotationUpdater by copying the object onto itself. ComposeObject falls back
+// to a multipart copy for objects which are too large for a single copy.
+func (s *presignedMinIOStorage) UpdateAnnotations(ctx context.Context, bucket, obj string, annotations map[string]string) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "minio.UpdateAnnotations")
+	defer tracing.FinishSpan(span, &err)
+
+	stat, err := s.client.StatObject(ctx, bucket, obj, minio.StatObjectOptions{})
+	if err != nil {
+		return translateMinioError(err)
+	}
+
+	// replacing the metadata drops everything we do not set explicitly
+	md := map[string]string{"Content-Type": stat.ContentType}
+	for k, v := range stat.UserMetadata {
+		md[http.CanonicalHeaderKey(k)] = v
+	}
+	for k, v := range annotations {
+		md[http.CanonicalHeaderKey(k)] = v
+	}
+	_, err = s.client.ComposeObject(ctx,
+		minio.CopyDestOptions{Bucket: bucket, Object: obj, UserMetadata: md, ReplaceMetadata: true},
+		minio.CopySrcOptions{Bucket: bucket, Object: obj},
+	)
+	return translateMinioError(err)
+}
+
 func annotationToAmzMetaHeader(annotation string) string {
 	return http.CanonicalHeaderKey(fmt.Sprintf("X-Amz-Meta-%s", annotation))
 }
@@ -0,0 +1,177 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// DefaultPartSize is the size of the parts of a multipart upload. S3 requires at least 5MiB
+	// per part and supports at most 10000 parts.
+	DefaultPartSize = 32 * 1024 * 1024
+)
+
+var (
+	// ErrMultipartUnsupported is returned when a backend cannot upload objects in parts
+	ErrMultipartUnsupported = errors.New("multipart upload not supported")
+)
+
+// MultipartUploader is implemented by DirectAccess backends which can upload an object in parts,
+// s.t. it can be streamed to the remote storage without knowing its size upfront.
+type MultipartUploader interface {
+	// NewMultipartUpload starts the upload of an object in parts. Returns ErrMultipartUnsupported
+	// if the backend cannot upload this object in parts.
+	NewMultipartUpload(ctx context.Context, name string, options ...UploadOption) (MultipartUpload, error)
+}
+
+// MultipartUpload is an upload of an object in parts
+type MultipartUpload interface {
+	// UploadPart uploads a part of the object. Parts are numbered starting from 1. Uploading a part
+	// with the same number again replaces it, hence a failed part can be retried on its own.
+	UploadPart(ctx context.Context, number int, data []byte) error
+
+	// Complete assembles the object from all uploaded parts
+	Complete(ctx context.Context) (bucket, obj string, err error)
+
+	// Abort discards all uploaded parts
+	Abort(ctx context.Context) error
+}
+
+// RetryFunc runs op until it succeeds or the retries are exhausted
+type RetryFunc func(ctx context.Context, op func(ctx context.Context) error) error
+
+// MultipartWriter streams everything written to it to a multipart upload. Failed parts are retried on their own,
+// s.t. a failure resumes from the last part the remote storage has confirmed instead of starting over.
+type MultipartWriter struct {
+	ctx    context.Context
+	upload MultipartUpload
+	retry  RetryFunc
+
+	buf    []byte
+	n      int
+	part   int
+	size   int64
+	err    error
+	closed bool
+}
+
+// NewMultipartWriter creates a writer which uploads parts of partSize. Use retry to retry failed parts.
+func NewMultipartWriter(ctx context.Context, upload MultipartUpload, partSize int, retry RetryFunc) *MultipartWriter {
+	if retry == nil {
+		retry = func(ctx context.Context, op func(ctx context.Context) error) error { return op(ctx) }
+	}
+	return &MultipartWriter{
+		ctx:    ctx,
+		upload: upload,
+		retry:  retry,
+		buf:    make([]byte, partSize),
+	}
+}
+
+// Write implements io.Writer
+func (w *MultipartWriter) Write(p []byte) (n int, err error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.closed {
+		return 0, xerrors.Errorf("multipart writer is closed")
+	}
+
+	for len(p) > 0 {
+		c := copy(w.buf[w.n:], p)
+		w.n += c
+		n += c
+		p = p[c:]
+
+		if w.n == len(w.buf) {
+			err = w.flush()
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (w *MultipartWriter) flush() error {
+	w.part++
+	var (
+		part = w.part
+		data = w.buf[:w.n]
+	)
+	err := w.retry(w.ctx, func(ctx context.Context) error {
+		return w.upload.UploadPart(ctx, part, data)
+	})
+	if err != nil {
+		w.err = xerrors.Errorf("cannot upload part %d: %w", part, err)
+		return w.err
+	}
+	w.size += int64(w.n)
+	w.n = 0
+	return nil
+}
+
+// Size returns the number of bytes which have been uploaded
+func (w *MultipartWriter) Size() int64 {
+	return w.size
+}
+
+// Close uploads the last part and completes the upload. If writing failed or the upload cannot be completed,
+// the upload is aborted.
+func (w *MultipartWriter) Close() (bucket, obj string, err error) {
+	if w.closed {
+		return "", "", xerrors.Errorf("multipart writer is closed")
+	}
+	w.closed = true
+
+	defer func() {
+		if err != nil {
+			w.abort()
+		}
+	}()
+	if w.err != nil {
+		return "", "", w.err
+	}
+
+	// the last part may be smaller than the part size - or even empty if nothing was written at all
+	if w.n > 0 || w.part == 0 {
+		err = w.flush()
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	err = w.retry(w.ctx, func(ctx context.Context) error {
+		bucket, obj, err = w.upload.Complete(ctx)
+		return err
+	})
+	if err != nil {
+		return "", "", xerrors.Errorf("cannot complete multipart upload: %w", err)
+	}
+	return bucket, obj, nil
+}
+
+// Abort discards the upload. Use this if producing the content failed.
+func (w *MultipartWriter) Abort() {
+	if w.closed {
+		return
+	}
+	w.closed = true
+	w.abort()
+}
+
+func (w *MultipartWriter) abort() {
+	// the context might have been canceled already, which is a common reason for aborting
+	err := w.upload.Abort(context.Background())
+	if err != nil {
+		log.WithError(err).Warn("cannot abort multipart upload")
+	}
+}
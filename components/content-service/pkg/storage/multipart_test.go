@@ -0,0 +1,136 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeMultipartUpload struct {
+	Parts     map[int][]byte
+	Failures  map[int]int
+	Completed bool
+	Aborted   bool
+}
+
+func (u *fakeMultipartUpload) UploadPart(ctx context.Context, number int, data []byte) error {
+	if u.Failures[number] > 0 {
+		u.Failures[number]--
+		return errors.New("part failed")
+	}
+	u.Parts[number] = append([]byte(nil), data...)
+	return nil
+}
+
+func (u *fakeMultipartUpload) Complete(ctx context.Context) (bucket, obj string, err error) {
+	u.Completed = true
+	return "bucket", "obj", nil
+}
+
+func (u *fakeMultipartUpload) Abort(ctx context.Context) error {
+	u.Aborted = true
+	return nil
+}
+
+func TestMultipartWriter(t *testing.T) {
+	retryOnce := func(ctx context.Context, op func(ctx context.Context) error) error {
+		err := op(ctx)
+		if err != nil {
+			err = op(ctx)
+		}
+		return err
+	}
+
+	type Expectation struct {
+		Parts     map[int][]byte
+		Completed bool
+		Aborted   bool
+		Error     bool
+	}
+	tests := []struct {
+		Name        string
+		Content     []byte
+		Failures    map[int]int
+		Retry       RetryFunc
+		Expectation Expectation
+	}{
+		{
+			Name:    "split into parts",
+			Content: []byte("0123456789"),
+			Expectation: Expectation{
+				Parts:     map[int][]byte{1: []byte("0123"), 2: []byte("4567"), 3: []byte("89")},
+				Completed: true,
+			},
+		},
+		{
+			Name:    "exact part size",
+			Content: []byte("01234567"),
+			Expectation: Expectation{
+				Parts:     map[int][]byte{1: []byte("0123"), 2: []byte("4567")},
+				Completed: true,
+			},
+		},
+		{
+			Name:    "empty content",
+			Content: nil,
+			Expectation: Expectation{
+				Parts:     map[int][]byte{1: nil},
+				Completed: true,
+			},
+		},
+		{
+			Name:     "retried part",
+			Content:  []byte("0123456789"),
+			Failures: map[int]int{2: 1},
+			Retry:    retryOnce,
+			Expectation: Expectation{
+				Parts:     map[int][]byte{1: []byte("0123"), 2: []byte("4567"), 3: []byte("89")},
+				Completed: true,
+			},
+		},
+		{
+			Name:     "failed part",
+			Content:  []byte("0123456789"),
+			Failures: map[int]int{2: 2},
+			Retry:    retryOnce,
+			Expectation: Expectation{
+				Parts:   map[int][]byte{1: []byte("0123")},
+				Aborted: true,
+				Error:   true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			upload := &fakeMultipartUpload{Parts: make(map[int][]byte), Failures: test.Failures}
+			w := NewMultipartWriter(context.Background(), upload, 4, test.Retry)
+
+			_, err := bytes.NewReader(test.Content).WriteTo(w)
+			_, _, cerr := w.Close()
+			if err == nil {
+				err = cerr
+			}
+
+			act := Expectation{
+				Parts:     upload.Parts,
+				Completed: upload.Completed,
+				Aborted:   upload.Aborted,
+				Error:     err != nil,
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected upload (-want +got):\n%s", diff)
+			}
+			if !test.Expectation.Error && w.Size() != int64(len(test.Content)) {
+				t.Errorf("unexpected size: is %d but expected %d", w.Size(), len(test.Content))
+			}
+		})
+	}
+}
@@ -10,10 +10,12 @@ import (
 
 	"golang.org/x/xerrors"
 
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
 )
 
-// NamedURLDownloader offers downloads from fixed URLs
+// NamedURLDownloader offers downloads from fixed URLs. If a download turns out to be a chunked backup,
+// the URLs of its chunks are expected to be present under their chunks.ObjectName.
 type NamedURLDownloader struct {
 	URLs map[string]string
 }
@@ -42,6 +44,18 @@ func (d *NamedURLDownloader) Download(ctx context.Context, destination string, n
 	}
 	defer resp.Body.Close()
 
+	if resp.Header.Get("Content-Type") == csapi.ContentTypeChunkIndex {
+		index, err := decodeChunkIndex(resp.Body)
+		if err != nil {
+			return true, err
+		}
+		err = ExtractChunkedBackup(ctx, destination, index, d.URLs, mappings)
+		if err != nil {
+			return true, err
+		}
+		return true, nil
+	}
+
 	err = extractTarbal(ctx, destination, resp.Body, mappings)
 	if err != nil {
 		return true, err
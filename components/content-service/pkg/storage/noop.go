@@ -81,6 +81,11 @@ func (*PresignedNoopStorage) DiskUsage(ctx context.Context, bucket string, prefi
 	return 0, nil
 }
 
+// ListObjects returns an empty list
+func (*PresignedNoopStorage) ListObjects(ctx context.Context, bucket string, prefix string) ([]string, error) {
+	return nil, nil
+}
+
 // SignDownload returns ErrNotFound
 func (*PresignedNoopStorage) SignDownload(ctx context.Context, bucket, obj string, options *SignedURLOptions) (info *DownloadInfo, err error) {
 	return nil, ErrNotFound
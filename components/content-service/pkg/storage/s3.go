@@ -5,12 +5,16 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
@@ -24,7 +28,10 @@ import (
 )
 
 var _ DirectAccess = &s3Storage{}
+var _ BytesUploader = &s3Storage{}
+var _ MultipartUploader = &s3Storage{}
 var _ PresignedAccess = &PresignedS3Storage{}
+var _ AnnotationUpdater = &PresignedS3Storage{}
 
 type S3Config struct {
 	Bucket string
@@ -35,6 +42,8 @@ type S3Client interface {
 	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
 	GetObjectAttributes(ctx context.Context, params *s3.GetObjectAttributesInput, optFns ...func(*s3.Options)) (*s3.GetObjectAttributesOutput, error)
 	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
 }
 
 type PresignedS3Client interface {
@@ -151,6 +160,31 @@ func (rs *PresignedS3Storage) DiskUsage(ctx context.Context, bucket string, pref
 	return
 }
 
+// ListObjects implements PresignedAccess
+func (rs *PresignedS3Storage) ListObjects(ctx context.Context, bucket string, prefix string) ([]string, error) {
+	var (
+		objects []string
+		token   *string
+	)
+	for {
+		resp, err := rs.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(rs.Config.Bucket),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range resp.Contents {
+			objects = append(objects, *e.Key)
+		}
+		if !resp.IsTruncated {
+			return objects, nil
+		}
+		token = resp.NextContinuationToken
+	}
+}
+
 // EnsureExists implements PresignedAccess
 func (rs *PresignedS3Storage) EnsureExists(ctx context.Context, bucket string) error {
 	return nil
@@ -201,17 +235,7 @@ func (rs *PresignedS3Storage) ObjectHash(ctx context.Context, bucket string, obj
 
 // SignDownload implements PresignedAccess
 func (rs *PresignedS3Storage) SignDownload(ctx context.Context, bucket string, obj string, options *SignedURLOptions) (info *DownloadInfo, err error) {
-	resp, err := rs.client.GetObjectAttributes(ctx, &s3.GetObjectAttributesInput{
-		Bucket:           &rs.Config.Bucket,
-		Key:              aws.String(obj),
-		ObjectAttributes: []types.ObjectAttributes{types.ObjectAttributesObjectSize},
-	})
-
-	var nsk *types.NoSuchKey
-	if errors.As(err, &nsk) {
-		return nil, ErrNotFound
-	}
-
+	resp, err := rs.headObject(ctx, obj)
 	if err != nil {
 		return nil, err
 	}
@@ -226,13 +250,157 @@ func (rs *PresignedS3Storage) SignDownload(ctx context.Context, bucket string, o
 
 	return &DownloadInfo{
 		Meta: ObjectMeta{
-			// TODO(cw): implement this if we need to support FWB with S3
+			ContentType:        aws.ToString(resp.ContentType),
+			OCIMediaType:       s3Annotation(resp.Metadata, ObjectAnnotationOCIContentType),
+			Digest:             s3Annotation(resp.Metadata, ObjectAnnotationDigest),
+			UncompressedDigest: s3Annotation(resp.Metadata, ObjectAnnotationUncompressedDigest),
+			EncryptionKeyID:    s3Annotation(resp.Metadata, ObjectAnnotationEncryptionKeyID),
+			EncryptedDataKey:   s3Annotation(resp.Metadata, ObjectAnnotationEncryptedDataKey),
 		},
-		Size: resp.ObjectSize,
+		Size: resp.ContentLength,
 		URL:  req.URL,
 	}, nil
 }
 
+// s3MaxCopySize is the largest object S3 can copy in a single request, and the largest part of a multipart copy
+const s3MaxCopySize = 5 * 1024 * 1024 * 1024
+
+// UpdateAnnotations implements AnnotationUpdater by copying the object onto itself
+func (rs *PresignedS3Storage) UpdateAnnotations(ctx context.Context, bucket string, obj string, annotations map[string]string) error {
+	resp, err := rs.headObject(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	// replacing the metadata drops everything we do not set explicitly
+	md := make(map[string]string, len(resp.Metadata)+len(annotations))
+	for k, v := range resp.Metadata {
+		md[strings.ToLower(k)] = v
+	}
+	for k, v := range annotations {
+		md[strings.ToLower(k)] = v
+	}
+	if resp.ContentLength > s3MaxCopySize {
+		return rs.multipartCopy(ctx, obj, resp, md)
+	}
+
+	_, err = rs.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:            aws.String(rs.Config.Bucket),
+		Key:               aws.String(obj),
+		CopySource:        aws.String(url.PathEscape(rs.Config.Bucket + "/" + obj)),
+		ContentType:       resp.ContentType,
+		Metadata:          md,
+		MetadataDirective: types.MetadataDirectiveReplace,
+	})
+	return err
+}
+
+// s3MultipartCopyClient is implemented by S3 clients which can copy objects in parts
+type s3MultipartCopyClient interface {
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPartCopy(ctx context.Context, params *s3.UploadPartCopyInput, optFns ...func(*s3.Options)) (*s3.UploadPartCopyOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// multipartCopy copies an object which is too large for CopyObject onto itself, replacing its metadata
+func (rs *PresignedS3Storage) multipartCopy(ctx context.Context, obj string, head *s3.HeadObjectOutput, md map[string]string) (err error) {
+	client, ok := rs.client.(s3MultipartCopyClient)
+	if !ok {
+		return ErrMultipartUnsupported
+	}
+
+	var (
+		bucket = aws.String(rs.Config.Bucket)
+		key    = aws.String(obj)
+		source = aws.String(url.PathEscape(rs.Config.Bucket + "/" + obj))
+	)
+	upload, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      bucket,
+		Key:         key,
+		ContentType: head.ContentType,
+		Metadata:    md,
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot create multipart upload: %w", err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		_, abortErr := client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   bucket,
+			Key:      key,
+			UploadId: upload.UploadId,
+		})
+		if abortErr != nil {
+			log.WithError(abortErr).WithField("obj", obj).Warn("cannot abort multipart copy")
+		}
+	}()
+
+	var parts []types.CompletedPart
+	for start, number := int64(0), int32(1); start < head.ContentLength; start, number = start+s3MaxCopySize, number+1 {
+		end := start + s3MaxCopySize - 1
+		if end >= head.ContentLength {
+			end = head.ContentLength - 1
+		}
+		resp, err := client.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+			Bucket:     bucket,
+			Key:        key,
+			UploadId:   upload.UploadId,
+			PartNumber: number,
+			CopySource: source,
+			// make sure we copy the object we just looked at
+			CopySourceIfMatch: head.ETag,
+			CopySourceRange:   aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+		})
+		if err != nil {
+			return xerrors.Errorf("cannot copy part %d: %w", number, err)
+		}
+		parts = append(parts, types.CompletedPart{
+			ETag:       resp.CopyPartResult.ETag,
+			PartNumber: number,
+		})
+	}
+
+	_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   bucket,
+		Key:      key,
+		UploadId: upload.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: parts,
+		},
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot complete multipart copy: %w", err)
+	}
+	return nil
+}
+
+func (rs *PresignedS3Storage) headObject(ctx context.Context, obj string) (*s3.HeadObjectOutput, error) {
+	resp, err := rs.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(rs.Config.Bucket),
+		Key:    aws.String(obj),
+	})
+
+	var (
+		nsk *types.NoSuchKey
+		nf  *types.NotFound
+	)
+	if errors.As(err, &nsk) || errors.As(err, &nf) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// s3Annotation returns the value of an annotation. S3 lower-cases metadata keys.
+func s3Annotation(md map[string]string, annotation string) string {
+	return md[strings.ToLower(annotation)]
+}
+
 // SignUpload implements PresignedAccess
 func (rs *PresignedS3Storage) SignUpload(ctx context.Context, bucket string, obj string, options *SignedURLOptions) (info *UploadInfo, err error) {
 	resp, err := rs.PresignedFactory().PresignPutObject(ctx, &s3.PutObjectInput{
@@ -408,6 +576,39 @@ func (s3st *s3Storage) Upload(ctx context.Context, source string, name string, o
 	return
 }
 
+// UploadBytes implements BytesUploader
+func (s3st *s3Storage) UploadBytes(ctx context.Context, data []byte, name string, opts ...UploadOption) (bucket, obj string, err error) {
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		return "", "", xerrors.Errorf("cannot get options: %w", err)
+	}
+	if s3st.client == nil {
+		return "", "", xerrors.Errorf("no s3 client available - did you call Init()?")
+	}
+	client, ok := s3st.client.(s3PutClient)
+	if !ok {
+		return "", "", xerrors.Errorf("Can only upload with actual S3 client")
+	}
+
+	var contentType *string
+	if options.ContentType != "" {
+		contentType = aws.String(options.ContentType)
+	}
+	bucket = s3st.Config.Bucket
+	obj = s3st.objectName(name)
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(obj),
+		Body:        bytes.NewReader(data),
+		Metadata:    options.Annotations,
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return bucket, obj, nil
+}
+
 // UploadInstance implements DirectAccess
 func (s3st *s3Storage) UploadInstance(ctx context.Context, source string, name string, opts ...UploadOption) (bucket string, obj string, err error) {
 	if s3st.InstanceID == "" {
@@ -415,3 +616,123 @@ func (s3st *s3Storage) UploadInstance(ctx context.Context, source string, name s
 	}
 	return s3st.Upload(ctx, source, InstanceObjectName(s3st.InstanceID, name), opts...)
 }
+
+// s3PutClient is implemented by S3 clients which can upload objects in a single request
+type s3PutClient interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// s3MultipartClient is implemented by S3 clients which support multipart uploads
+type s3MultipartClient interface {
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// NewMultipartUpload implements MultipartUploader
+func (s3st *s3Storage) NewMultipartUpload(ctx context.Context, name string, opts ...UploadOption) (MultipartUpload, error) {
+	options, err := GetUploadOptions(opts)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get options: %w", err)
+	}
+
+	if s3st.client == nil {
+		return nil, xerrors.Errorf("no s3 client available - did you call Init()?")
+	}
+	client, ok := s3st.client.(s3MultipartClient)
+	if !ok {
+		return nil, ErrMultipartUnsupported
+	}
+
+	var contentType *string
+	if options.ContentType != "" {
+		contentType = aws.String(options.ContentType)
+	}
+
+	res := &s3MultipartUpload{
+		client: client,
+		bucket: s3st.Config.Bucket,
+		obj:    s3st.objectName(name),
+		parts:  make(map[int32]types.CompletedPart),
+	}
+	resp, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(res.bucket),
+		Key:         aws.String(res.obj),
+		Metadata:    options.Annotations,
+		ContentType: contentType,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create multipart upload: %w", err)
+	}
+	res.uploadID = resp.UploadId
+
+	return res, nil
+}
+
+type s3MultipartUpload struct {
+	client   s3MultipartClient
+	bucket   string
+	obj      string
+	uploadID *string
+
+	mu    sync.Mutex
+	parts map[int32]types.CompletedPart
+}
+
+// UploadPart implements MultipartUpload
+func (u *s3MultipartUpload) UploadPart(ctx context.Context, number int, data []byte) error {
+	resp, err := u.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(u.bucket),
+		Key:           aws.String(u.obj),
+		UploadId:      u.uploadID,
+		PartNumber:    int32(number),
+		Body:          bytes.NewReader(data),
+		ContentLength: int64(len(data)),
+	})
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	u.parts[int32(number)] = types.CompletedPart{
+		ETag:       resp.ETag,
+		PartNumber: int32(number),
+	}
+	u.mu.Unlock()
+	return nil
+}
+
+// Complete implements MultipartUpload
+func (u *s3MultipartUpload) Complete(ctx context.Context) (bucket, obj string, err error) {
+	u.mu.Lock()
+	parts := make([]types.CompletedPart, 0, len(u.parts))
+	for _, p := range u.parts {
+		parts = append(parts, p)
+	}
+	u.mu.Unlock()
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+
+	_, err = u.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(u.bucket),
+		Key:      aws.String(u.obj),
+		UploadId: u.uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: parts,
+		},
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return u.bucket, u.obj, nil
+}
+
+// Abort implements MultipartUpload
+func (u *s3MultipartUpload) Abort(ctx context.Context) error {
+	_, err := u.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(u.bucket),
+		Key:      aws.String(u.obj),
+		UploadId: u.uploadID,
+	})
+	return err
+}
@@ -6,6 +6,7 @@ package storage_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -15,6 +16,7 @@ import (
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage/mock"
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 )
 
 type mockedS3PresignedAccess struct {
@@ -38,6 +40,9 @@ func TestS3PresignedHappyPath(t *testing.T) {
 		ETag:       aws.String("foobar"),
 		ObjectSize: 100,
 	}, nil).AnyTimes()
+	s3c.EXPECT().HeadObject(gomock.Any(), gomock.Any()).Return(&s3.HeadObjectOutput{
+		ContentLength: 100,
+	}, nil).AnyTimes()
 	s3c.EXPECT().ListObjectsV2(gomock.Any(), gomock.Any()).Return(&s3.ListObjectsV2Output{
 		Contents: []types.Object{
 			{Size: 100},
@@ -61,3 +66,65 @@ func TestS3PresignedHappyPath(t *testing.T) {
 
 	SuiteTestPresignedAccess(t, ps)
 }
+
+type fakeS3MultipartCopyClient struct {
+	storage.S3Client
+
+	Size   int64
+	Ranges []string
+	Parts  []types.CompletedPart
+	Meta   map[string]string
+}
+
+func (c *fakeS3MultipartCopyClient) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	return &s3.HeadObjectOutput{
+		ContentLength: c.Size,
+		ETag:          aws.String("etag"),
+		Metadata:      map[string]string{"gitpod-digest": "digest"},
+	}, nil
+}
+
+func (c *fakeS3MultipartCopyClient) CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	c.Meta = params.Metadata
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil
+}
+
+func (c *fakeS3MultipartCopyClient) UploadPartCopy(ctx context.Context, params *s3.UploadPartCopyInput, optFns ...func(*s3.Options)) (*s3.UploadPartCopyOutput, error) {
+	c.Ranges = append(c.Ranges, *params.CopySourceRange)
+	return &s3.UploadPartCopyOutput{CopyPartResult: &types.CopyPartResult{ETag: params.CopySourceRange}}, nil
+}
+
+func (c *fakeS3MultipartCopyClient) CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	c.Parts = params.MultipartUpload.Parts
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (c *fakeS3MultipartCopyClient) AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	return nil, fmt.Errorf("unexpected abort")
+}
+
+func TestS3UpdateAnnotationsLargeObject(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+	client := &fakeS3MultipartCopyClient{Size: 12 * gib}
+	dut := storage.NewPresignedS3Access(client, storage.S3Config{Bucket: "test-bucket"})
+
+	err := dut.UpdateAnnotations(context.Background(), "test-bucket", "backup.tar", map[string]string{"gitpod-encryption-keyId": "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRanges := []string{
+		fmt.Sprintf("bytes=0-%d", 5*gib-1),
+		fmt.Sprintf("bytes=%d-%d", 5*gib, 10*gib-1),
+		fmt.Sprintf("bytes=%d-%d", 10*gib, 12*gib-1),
+	}
+	if diff := cmp.Diff(expectedRanges, client.Ranges); diff != "" {
+		t.Errorf("unexpected copied ranges (-want +got):\n%s", diff)
+	}
+	if len(client.Parts) != len(expectedRanges) {
+		t.Errorf("expected %d completed parts, got %d", len(expectedRanges), len(client.Parts))
+	}
+	if diff := cmp.Diff(map[string]string{"gitpod-digest": "digest", "gitpod-encryption-keyid": "key"}, client.Meta); diff != "" {
+		t.Errorf("unexpected metadata (-want +got):\n%s", diff)
+	}
+}
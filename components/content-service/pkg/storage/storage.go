@@ -10,7 +10,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path"
 	"regexp"
+	"strings"
 
 	"golang.org/x/xerrors"
 
@@ -72,10 +74,14 @@ type PresignedAccess interface {
 	// DiskUsage gives the total objects size of objects that have the given prefix
 	DiskUsage(ctx context.Context, bucket string, prefix string) (size int64, err error)
 
+	// ListObjects returns the names of all objects with the given prefix. Returns an empty list if the bucket does not exist.
+	ListObjects(ctx context.Context, bucket string, prefix string) ([]string, error)
+
 	// SignDownload describes an object for download - if the object is not found, ErrNotFound is returned
 	SignDownload(ctx context.Context, bucket, obj string, options *SignedURLOptions) (info *DownloadInfo, err error)
 
-	// SignUpload describes an object for upload
+	// SignUpload describes an object for upload. Uploads using the URL must set the "x-ms-blob-type: BlockBlob" header,
+	// which Azure requires and the other storage backends ignore.
 	SignUpload(ctx context.Context, bucket, obj string, options *SignedURLOptions) (info *UploadInfo, err error)
 
 	// DeleteObject deletes objects in the given bucket specified by the given query
@@ -103,6 +109,8 @@ type ObjectMeta struct {
 	OCIMediaType       string
 	Digest             string
 	UncompressedDigest string
+	EncryptionKeyID    string
+	EncryptedDataKey   string
 }
 
 // DownloadInfo describes an object for download
@@ -165,6 +173,13 @@ type DirectAccess interface {
 	UploadInstance(ctx context.Context, source string, name string, options ...UploadOption) (bucket, obj string, err error)
 }
 
+// BytesUploader is implemented by DirectAccess backends which can upload content held in memory without
+// writing it to a local file first. Callers must call EnsureExists before uploading.
+type BytesUploader interface {
+	// UploadBytes uploads data to the remote storage
+	UploadBytes(ctx context.Context, data []byte, name string, options ...UploadOption) (bucket, obj string, err error)
+}
+
 // UploadOptions configure remote storage upload
 type UploadOptions struct {
 	// Annotations are generic metadata atteched to a storage object
@@ -213,8 +228,21 @@ const (
 
 	// ObjectAnnotationOCIContentType is the OCI media type of the object
 	ObjectAnnotationOCIContentType = "gitpod-oci-contentType"
+
+	// ObjectAnnotationEncryptionKeyID identifies the key the data key of an encrypted object is wrapped with
+	ObjectAnnotationEncryptionKeyID = "gitpod-encryption-keyId"
+
+	// ObjectAnnotationEncryptedDataKey is the base64 encoded, wrapped data key of an encrypted object
+	ObjectAnnotationEncryptedDataKey = "gitpod-encryption-dataKey"
 )
 
+// AnnotationUpdater is implemented by presigned access to storage which can change the annotations of
+// an existing object without uploading it again
+type AnnotationUpdater interface {
+	// UpdateAnnotations sets the given annotations of an object and retains all others
+	UpdateAnnotations(ctx context.Context, bucket, obj string, annotations map[string]string) error
+}
+
 // NewDirectAccess provides direct access to a storage system
 func NewDirectAccess(c *config.StorageConfig) (DirectAccess, error) {
 	stage := c.GetStage()
@@ -237,6 +265,10 @@ func NewDirectAccess(c *config.StorageConfig) (DirectAccess, error) {
 		return newDirectS3Access(s3.NewFromConfig(cfg), S3Config{
 			Bucket: c.S3Config.Bucket,
 		}), nil
+	case config.LocalStorage:
+		return newDirectLocalAccess(c.LocalConfig)
+	case config.AzureStorage:
+		return newDirectAzureAccess(c.AzureConfig)
 	default:
 		return &DirectNoopStorage{}, nil
 	}
@@ -264,12 +296,34 @@ func NewPresignedAccess(c *config.StorageConfig) (PresignedAccess, error) {
 		return NewPresignedS3Access(s3.NewFromConfig(cfg), S3Config{
 			Bucket: c.S3Config.Bucket,
 		}), nil
+	case config.LocalStorage:
+		return NewPresignedLocalAccess(c.LocalConfig)
+	case config.AzureStorage:
+		return newPresignedAzureAccess(c.AzureConfig)
 	default:
 		log.Warnf("falling back to noop presigned storage access. Is this intentional? (storage kind: %s)", c.Kind)
 		return &PresignedNoopStorage{}, nil
 	}
 }
 
+// BackupOwner returns the owner of the backup location an object is stored at, i.e. the owner in whose bucket
+// ps places a backup with the object's name. Fails if the object is not stored at a backup location.
+func BackupOwner(ps PresignedAccess, bucket, obj string) (string, error) {
+	var owner string
+	if ps.Bucket("a") == ps.Bucket("b") {
+		// all owners share a bucket, hence the owner is part of the object name
+		owner, _, _ = strings.Cut(obj, "/")
+	} else {
+		owner = strings.TrimPrefix(bucket, ps.Bucket(""))
+	}
+
+	workspaceID, name := path.Base(path.Dir(obj)), path.Base(obj)
+	if owner == "" || ps.Bucket(owner) != bucket || ps.BackupObject(owner, workspaceID, name) != obj {
+		return "", xerrors.Errorf("%s in bucket %s is not stored at a backup location", obj, bucket)
+	}
+	return owner, nil
+}
+
 func extractTarbal(ctx context.Context, dest string, src io.Reader, mappings []archive.IDMapping) error {
 	err := archive.ExtractTarbal(ctx, src, dest, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
 	if err != nil {
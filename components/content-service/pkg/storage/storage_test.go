@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"golang.org/x/xerrors"
+
+	config "github.com/gitpod-io/gitpod/content-service/api/config"
 )
 
 func TestBlobObjectName(t *testing.T) {
@@ -72,3 +74,69 @@ func equivalentError(e1 error, e2 error) bool {
 	}
 	return false
 }
+
+func TestBackupOwner(t *testing.T) {
+	local, err := NewPresignedLocalAccess(&config.LocalConfig{Path: t.TempDir(), URL: "http://localhost", Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared := NewPresignedS3Access(nil, S3Config{Bucket: "shared"})
+
+	tests := []struct {
+		Name          string
+		Storage       PresignedAccess
+		Bucket        string
+		Object        string
+		ExpectedOwner string
+	}{
+		{
+			Name:          "bucket per owner",
+			Storage:       local,
+			Bucket:        local.Bucket("owner"),
+			Object:        local.BackupObject("owner", "workspace", DefaultBackup),
+			ExpectedOwner: "owner",
+		},
+		{
+			Name:          "shared bucket",
+			Storage:       shared,
+			Bucket:        "shared",
+			Object:        shared.BackupObject("owner", "workspace", "snapshot-1.tar"),
+			ExpectedOwner: "owner",
+		},
+		{
+			Name:    "foreign bucket",
+			Storage: local,
+			Bucket:  "some-bucket",
+			Object:  local.BackupObject("owner", "workspace", DefaultBackup),
+		},
+		{
+			Name:    "no backup",
+			Storage: local,
+			Bucket:  local.Bucket("owner"),
+			Object:  "blobs/some-blob",
+		},
+		{
+			Name:    "shared bucket without owner",
+			Storage: shared,
+			Bucket:  "shared",
+			Object:  "workspaces/workspace/" + DefaultBackup,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			owner, err := BackupOwner(test.Storage, test.Bucket, test.Object)
+			if test.ExpectedOwner == "" {
+				if err == nil {
+					t.Errorf("expected an error, got owner %q", owner)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if owner != test.ExpectedOwner {
+				t.Errorf("unexpected owner: is %q but expected %q", owner, test.ExpectedOwner)
+			}
+		})
+	}
+}
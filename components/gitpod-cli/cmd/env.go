@@ -13,19 +13,25 @@ import (
 	"sync"
 	"time"
 
+	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/gitpod-io/gitpod/common-go/util"
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
 	serverapi "github.com/gitpod-io/gitpod/gitpod-protocol"
 	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
 )
 
 var exportEnvs = false
 var unsetEnvs = false
+var resolvedEnvs = false
+var resolvedEnvsTask = ""
 
 // envCmd represents the env command
 var envCmd = &cobra.Command{
@@ -49,6 +55,12 @@ To delete a persistent environment variable use:
 
 Note that you can delete/unset variables if their repository pattern matches the repository of this workspace exactly. I.e. you cannot
 delete environment variables with a repository pattern of */foo, foo/* or */*.
+
+To print the effective environment of the IDE, tasks and SSH sessions along with the source of each variable, use:
+	gp env --resolved
+
+Besides the workspace and your persistent environment variables, it includes the variables loaded from the envFiles
+configured in .gitpod.yml. Use --task to include the env and envFiles of a task.
 `,
 	Args: cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -59,6 +71,14 @@ delete environment variables with a repository pattern of */foo, foo/* or */*.
 			log.SetOutput(f)
 		}
 
+		if resolvedEnvs {
+			if len(args) > 0 {
+				fail("--resolved does not accept arguments")
+			}
+			getResolvedEnvs()
+			return
+		}
+
 		if len(args) > 0 {
 			if unsetEnvs {
 				deleteEnvs(args)
@@ -138,6 +158,55 @@ func getEnvs() {
 	}
 }
 
+func getResolvedEnvs() {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	defer cancel()
+	conn, err := supervisor_helper.Dial(ctx)
+	if err != nil {
+		fail(err.Error())
+	}
+	defer conn.Close()
+
+	taskID := resolvedEnvsTask
+	if taskID != "" {
+		tasks, err := supervisor_helper.GetTasksList(ctx)
+		if err != nil {
+			fail("cannot get task list: " + err.Error())
+		}
+		if task := supervisor_helper.FindTask(tasks, taskID); task != nil {
+			taskID = task.Id
+		}
+	}
+	resp, err := supervisor.NewInfoServiceClient(conn).Environment(ctx, &supervisor.EnvironmentRequest{TaskId: taskID})
+	if status.Code(err) == codes.NotFound {
+		fail(fmt.Sprintf("task %s does not exist. Use 'gp tasks list' to obtain the task ID", resolvedEnvsTask))
+	}
+	if err != nil {
+		fail("failed to fetch the environment from supervisor: " + err.Error())
+	}
+
+	if exportEnvs {
+		for _, v := range resp.Variables {
+			printVar(&serverapi.UserEnvVarValue{Name: v.Name, Value: v.Value}, true)
+		}
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Value", "Source"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	for _, v := range resp.Variables {
+		source := v.Source
+		if len(v.Secrets) > 0 {
+			source += " (secret: " + strings.Join(v.Secrets, ", ") + ")"
+		}
+		table.Append([]string{v.Name, v.Value, source})
+	}
+	table.Render()
+}
+
 func setEnvs(args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 	defer cancel()
@@ -251,4 +320,6 @@ func init() {
 
 	envCmd.Flags().BoolVarP(&exportEnvs, "export", "e", false, "produce a script that can be eval'ed in Bash")
 	envCmd.Flags().BoolVarP(&unsetEnvs, "unset", "u", false, "deletes/unsets persisted environment variables")
+	envCmd.Flags().BoolVar(&resolvedEnvs, "resolved", false, "prints the effective environment of the workspace along with the source of each variable")
+	envCmd.Flags().StringVar(&resolvedEnvsTask, "task", "", "includes the environment of the given task with --resolved")
 }
@@ -0,0 +1,46 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gitpod-io/gitpod/common-go/filesync"
+)
+
+// diffFilesCmd represents the files diff command
+var diffFilesCmd = &cobra.Command{
+	Use:   "diff <local-dir> [workspace-dir]",
+	Short: "Show how a local directory differs from a workspace directory",
+	Long: `Show how a local directory differs from a workspace directory.
+
+Lists the changes gp files push --delete would apply to the workspace: + marks entries which only
+exist locally, ~ marks entries which differ and - marks entries which only exist in the workspace.
+Exits with status 1 if the directories differ.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		files, err := listFiles(context.Background(), args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		plan := filesync.Diff(files.local, files.remote)
+		if plan.Empty() {
+			return
+		}
+		printPlan(plan, files.remote, true)
+		fmt.Printf("%d bytes differ\n", plan.Bytes(files.chunkSize))
+		os.Exit(1)
+	},
+}
+
+func init() {
+	filesCmd.AddCommand(diffFilesCmd)
+}
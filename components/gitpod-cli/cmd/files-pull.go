@@ -0,0 +1,51 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gitpod-io/gitpod/common-go/filesync"
+)
+
+var pullFilesCmdOpts struct {
+	Delete bool
+}
+
+// pullFilesCmd represents the files pull command
+var pullFilesCmd = &cobra.Command{
+	Use:   "pull <local-dir> [workspace-dir]",
+	Short: "Copy the content of a workspace directory into a local directory",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		files, err := listFiles(ctx, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		plan := filesync.Diff(files.remote, files.local)
+		if plan.Empty() || (!pullFilesCmdOpts.Delete && len(plan.Entries) == 0) {
+			fmt.Println("Local files are up to date")
+			return
+		}
+		printPlan(plan, files.local, pullFilesCmdOpts.Delete)
+
+		written, err := files.client.Pull(ctx, plan, files.chunkSize, filesync.NewWriter(files.localDir), pullFilesCmdOpts.Delete)
+		if err != nil {
+			log.Fatalf("cannot pull files: %s", err)
+		}
+		fmt.Printf("Pulled %d entries, transferred %d bytes\n", len(plan.Entries), written)
+	},
+}
+
+func init() {
+	pullFilesCmd.Flags().BoolVar(&pullFilesCmdOpts.Delete, "delete", false, "remove local files which do not exist in the workspace directory")
+	filesCmd.AddCommand(pullFilesCmd)
+}
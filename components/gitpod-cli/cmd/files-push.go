@@ -0,0 +1,51 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gitpod-io/gitpod/common-go/filesync"
+)
+
+var pushFilesCmdOpts struct {
+	Delete bool
+}
+
+// pushFilesCmd represents the files push command
+var pushFilesCmd = &cobra.Command{
+	Use:   "push <local-dir> [workspace-dir]",
+	Short: "Copy the content of a local directory into the workspace",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		files, err := listFiles(ctx, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		plan := filesync.Diff(files.local, files.remote)
+		if plan.Empty() || (!pushFilesCmdOpts.Delete && len(plan.Entries) == 0) {
+			fmt.Println("Workspace files are up to date")
+			return
+		}
+		printPlan(plan, files.remote, pushFilesCmdOpts.Delete)
+
+		written, err := files.client.Push(ctx, plan, files.chunkSize, files.localDir, pushFilesCmdOpts.Delete)
+		if err != nil {
+			log.Fatalf("cannot push files: %s", err)
+		}
+		fmt.Printf("Pushed %d entries, transferred %d bytes\n", len(plan.Entries), written)
+	},
+}
+
+func init() {
+	pushFilesCmd.Flags().BoolVar(&pushFilesCmdOpts.Delete, "delete", false, "remove workspace files which do not exist in the local directory")
+	filesCmd.AddCommand(pushFilesCmd)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/filesync"
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+)
+
+var filesCmdOpts struct {
+	Include []string
+	Exclude []string
+}
+
+// filesCmd represents the files command
+var filesCmd = &cobra.Command{
+	Use:   "files",
+	Short: "Sync and diff workspace content with a local directory",
+	Long: `Sync and diff workspace content with a local directory.
+
+Files are compared in content-addressed chunks, so that only the chunks which differ are
+transferred. An interrupted transfer is resumed by running the same command again.
+
+Workspace paths are relative to the repository root. To sync from your own machine, run gp
+through the supervisor tunnel of the local companion app, e.g.
+
+  SUPERVISOR_ADDR=localhost:<tunnel port> gp files pull ./backup
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			_ = cmd.Help()
+		}
+	},
+}
+
+// filesSync holds the state of the local and the workspace directory tree.
+type filesSync struct {
+	client    *supervisor_helper.FilesClient
+	localDir  string
+	chunkSize int64
+	local     []*filesync.Entry
+	remote    []*filesync.Entry
+}
+
+// listFiles lists the local directory and the workspace directory given by args.
+func listFiles(ctx context.Context, args []string) (*filesSync, error) {
+	filter := filesync.Filter{Include: filesCmdOpts.Include, Exclude: filesCmdOpts.Exclude}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	remoteDir := ""
+	if len(args) > 1 {
+		remoteDir = args[1]
+	}
+	client, err := supervisor_helper.GetFilesClient(ctx, remoteDir)
+	if err != nil {
+		return nil, err
+	}
+	remote, chunkSize, err := client.List(ctx, filter)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list workspace files: %w", err)
+	}
+	local, err := filesync.List(args[0], filter, chunkSize)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list local files: %w", err)
+	}
+	return &filesSync{
+		client:    client,
+		localDir:  args[0],
+		chunkSize: chunkSize,
+		local:     local,
+		remote:    remote,
+	}, nil
+}
+
+// printPlan prints the changes of a plan, marking entries which do not exist in dst yet with +,
+// changed entries with ~ and removed entries with -.
+func printPlan(plan *filesync.Plan, dst []*filesync.Entry, remove bool) {
+	existing := make(map[string]struct{}, len(dst))
+	for _, entry := range dst {
+		existing[entry.Path] = struct{}{}
+	}
+	for _, entry := range plan.Entries {
+		mark := "+"
+		if _, ok := existing[entry.Path]; ok {
+			mark = "~"
+		}
+		fmt.Printf("%s %s\n", mark, entry.Path)
+	}
+	if !remove {
+		return
+	}
+	for _, p := range plan.Removals {
+		fmt.Printf("- %s\n", p)
+	}
+}
+
+func init() {
+	filesCmd.PersistentFlags().StringArrayVar(&filesCmdOpts.Include, "include", nil, "only sync paths matching the glob pattern, e.g. 'src/**'")
+	filesCmd.PersistentFlags().StringArrayVar(&filesCmdOpts.Exclude, "exclude", nil, "do not sync paths matching the glob pattern, e.g. 'node_modules'")
+	rootCmd.AddCommand(filesCmd)
+}
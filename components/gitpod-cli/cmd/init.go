@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 
@@ -17,10 +18,12 @@ import (
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/gitpod-io/gitpod/gitpod-cli/pkg/gitpodlib"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
 )
 
 var (
-	interactive = false
+	interactive      = false
+	fromDevcontainer = false
 )
 
 // initCmd initializes the workspace's .gitpod.yml file
@@ -32,6 +35,16 @@ Create a Gitpod configuration for this project.
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := gitpodlib.GitpodFile{}
+		if fromDevcontainer {
+			d, err := convertDevcontainer()
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("%s", d)
+			writeGitpodFile(d)
+			openCmd.Run(cmd, []string{".gitpod.yml"})
+			return
+		}
 		if interactive {
 			if err := askForDockerImage(&cfg); err != nil {
 				log.Fatal(err)
@@ -66,21 +79,7 @@ ports:
 			fmt.Printf("\n\n---\n%s", d)
 		}
 
-		if _, err := os.Stat(".gitpod.yml"); err == nil {
-			prompt := promptui.Prompt{
-				IsConfirm: true,
-				Label:     ".gitpod.yml file already exists, overwrite?",
-			}
-			if _, err := prompt.Run(); err != nil {
-				fmt.Printf("Not overwriting .gitpod.yml file. Aborting.\n")
-				os.Exit(1)
-				return
-			}
-		}
-
-		if err := os.WriteFile(".gitpod.yml", d, 0644); err != nil {
-			log.Fatal(err)
-		}
+		writeGitpodFile(d)
 
 		// open .gitpod.yml and Dockerfile
 		if v, ok := cfg.Image.(gitpodlib.GitpodImage); ok {
@@ -108,6 +107,52 @@ USER gitpod
 	},
 }
 
+func writeGitpodFile(content []byte) {
+	if _, err := os.Stat(".gitpod.yml"); err == nil {
+		prompt := promptui.Prompt{
+			IsConfirm: true,
+			Label:     ".gitpod.yml file already exists, overwrite?",
+		}
+		if _, err := prompt.Run(); err != nil {
+			fmt.Printf("Not overwriting .gitpod.yml file. Aborting.\n")
+			os.Exit(1)
+			return
+		}
+	}
+
+	if err := os.WriteFile(".gitpod.yml", content, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// convertDevcontainer converts the devcontainer.json of this project to the content of a .gitpod.yml.
+// Parts of the devcontainer.json which have no Gitpod equivalent are reported as warnings.
+func convertDevcontainer() ([]byte, error) {
+	for _, location := range gitpod.DevcontainerLocations {
+		content, err := os.ReadFile(location)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		devcontainer, err := gitpod.ParseDevcontainer(content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", location, err)
+		}
+		config, warnings := devcontainer.ToGitpodConfig(path.Dir(location))
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "warning: %s: %s\n", location, warning)
+		}
+		d, err := yaml.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(fmt.Sprintf("# Converted from %s. Learn more https://www.gitpod.io/docs/config-gitpod-file\n", location)), d...), nil
+	}
+	return nil, fmt.Errorf("no devcontainer.json found, looked for %s", strings.Join(gitpod.DevcontainerLocations, " and "))
+}
+
 func isRequired(input string) error {
 	if input == "" {
 		return errors.New("Cannot be empty")
@@ -222,4 +267,5 @@ func askForTask(cfg *gitpodlib.GitpodFile) error {
 func init() {
 	rootCmd.AddCommand(initCmd)
 	initCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "walk me through an interactive setup.")
+	initCmd.Flags().BoolVar(&fromDevcontainer, "from-devcontainer", false, "convert the devcontainer.json of this project.")
 }
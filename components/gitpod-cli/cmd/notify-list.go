@@ -0,0 +1,114 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+var listNotificationsCmdOpts struct {
+	Unread   bool
+	MarkRead bool
+	Level    string
+	Json     bool
+}
+
+// listNotificationsCmd represents the notify list command
+var listNotificationsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the notifications of the workspace",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var minLevel supervisor.NotifyRequest_Level
+		if listNotificationsCmdOpts.Level != "" {
+			var err error
+			minLevel, err = parseNotificationLevel(listNotificationsCmdOpts.Level)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		client, err := supervisor_helper.GetNotificationServiceClient(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		resp, err := client.ListNotifications(ctx, &supervisor.ListNotificationsRequest{
+			Unread:   listNotificationsCmdOpts.Unread,
+			MarkRead: listNotificationsCmdOpts.MarkRead,
+		})
+		if err != nil {
+			log.Fatalf("cannot list notifications: %s", err)
+		}
+
+		var notifications []*notificationData
+		for _, n := range resp.Notifications {
+			// levels are ordered by severity, most severe first
+			if listNotificationsCmdOpts.Level != "" && n.Request.GetLevel() > minLevel {
+				continue
+			}
+			notifications = append(notifications, newNotificationData(n))
+		}
+
+		if listNotificationsCmdOpts.Json {
+			for _, n := range notifications {
+				fmt.Println(n.JSON())
+			}
+			return
+		}
+		if len(notifications) == 0 {
+			fmt.Println("No notifications")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Time", "Level", "Message", "Status"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		for _, n := range notifications {
+			table.Append([]string{
+				fmt.Sprint(n.ID),
+				n.Timestamp.Format(time.Stamp),
+				n.Level,
+				n.Message,
+				notificationStatus(n),
+			})
+		}
+		table.Render()
+	},
+}
+
+func notificationStatus(n *notificationData) string {
+	switch {
+	case n.Pending:
+		return "awaiting response: " + strings.Join(n.Actions, ", ")
+	case n.Action != "":
+		return "responded: " + n.Action
+	case n.Read:
+		return "read"
+	default:
+		return "unread"
+	}
+}
+
+func init() {
+	listNotificationsCmd.Flags().BoolVarP(&listNotificationsCmdOpts.Unread, "unread", "u", false, "only list notifications which have not been read")
+	listNotificationsCmd.Flags().BoolVar(&listNotificationsCmdOpts.MarkRead, "mark-read", false, "mark the listed notifications as read")
+	listNotificationsCmd.Flags().StringVarP(&listNotificationsCmdOpts.Level, "level", "l", "", "only list notifications of the given severity or more severe ones - one of "+strings.Join(notificationLevels(), ", "))
+	listNotificationsCmd.Flags().BoolVarP(&listNotificationsCmdOpts.Json, "json", "j", false, "Output one JSON object per notification")
+	notifyCmd.AddCommand(listNotificationsCmd)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+var sendNotificationCmdOpts struct {
+	Level   string
+	Actions []string
+	Timeout time.Duration
+}
+
+// sendNotificationCmd represents the notify send command
+var sendNotificationCmd = &cobra.Command{
+	Use:   "send <message>",
+	Short: "Show a notification in the IDE",
+	Long: `Show a notification in the IDE.
+
+Notifications are kept in the notification log of the workspace, so that they are shown once an
+IDE attaches even if none is connected yet. If actions are given, the command waits until the
+user has chosen one and prints it. An empty line is printed if the user dismissed the notification.`,
+	Example: `  gp notify send --level warning --action Retry --action Ignore "Database migration failed"`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		level, err := parseNotificationLevel(sendNotificationCmdOpts.Level)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ctx := context.Background()
+		if sendNotificationCmdOpts.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, sendNotificationCmdOpts.Timeout)
+			defer cancel()
+		}
+		client, err := supervisor_helper.GetNotificationServiceClient(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		resp, err := client.Notify(ctx, &supervisor.NotifyRequest{
+			Level:   level,
+			Message: args[0],
+			Actions: sendNotificationCmdOpts.Actions,
+		})
+		if err != nil {
+			log.Fatalf("cannot send notification: %s", err)
+		}
+		if len(sendNotificationCmdOpts.Actions) > 0 {
+			fmt.Println(resp.Action)
+		}
+	},
+}
+
+func init() {
+	sendNotificationCmd.Flags().StringVarP(&sendNotificationCmdOpts.Level, "level", "l", "info", "notification severity - must be one of "+strings.Join(notificationLevels(), ", "))
+	sendNotificationCmd.Flags().StringArrayVarP(&sendNotificationCmdOpts.Actions, "action", "a", nil, "action to offer to the user, can be repeated")
+	sendNotificationCmd.Flags().DurationVarP(&sendNotificationCmdOpts.Timeout, "timeout", "t", 0, "time to wait for the user to choose an action, e.g. 5m (default: wait indefinitely)")
+	notifyCmd.AddCommand(sendNotificationCmd)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+var watchNotificationsCmdOpts struct {
+	Json bool
+}
+
+// watchNotificationsCmd represents the notify watch command
+var watchNotificationsCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print notifications as they are sent",
+	Long: `Print notifications as they are sent.
+
+Watching does not affect the notifications shown in the IDE: they are neither marked as read nor
+responded to.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		client, err := supervisor_helper.GetNotificationServiceClient(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sub, err := client.Subscribe(ctx, &supervisor.SubscribeRequest{NonInteractive: true})
+		if err != nil {
+			log.Fatalf("cannot watch notifications: %s", err)
+		}
+		for {
+			resp, err := sub.Recv()
+			if err == io.EOF || ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Fatalf("cannot watch notifications: %s", err)
+			}
+
+			n := newNotificationData(&supervisor.Notification{
+				RequestId: resp.RequestId,
+				Request:   resp.Request,
+				Timestamp: timestamppb.Now(),
+				Pending:   len(resp.Request.GetActions()) > 0,
+			})
+			if watchNotificationsCmdOpts.Json {
+				fmt.Println(n.JSON())
+				continue
+			}
+			line := fmt.Sprintf("%s [%s] %s", n.Timestamp.Format(time.Stamp), strings.ToUpper(n.Level), n.Message)
+			if len(n.Actions) > 0 {
+				line += " (" + strings.Join(n.Actions, " | ") + ")"
+			}
+			fmt.Println(line)
+		}
+	},
+}
+
+func init() {
+	watchNotificationsCmd.Flags().BoolVarP(&watchNotificationsCmdOpts.Json, "json", "j", false, "Output one JSON object per notification")
+	notifyCmd.AddCommand(watchNotificationsCmd)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// notifyCmd represents the notify command
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Send and inspect notifications shown in the IDE",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			_ = cmd.Help()
+		}
+	},
+}
+
+type notificationData struct {
+	ID        uint64    `json:"id"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	Actions   []string  `json:"actions,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Read      bool      `json:"read"`
+	Pending   bool      `json:"pending"`
+	Action    string    `json:"action,omitempty"`
+}
+
+func newNotificationData(n *supervisor.Notification) *notificationData {
+	res := &notificationData{
+		ID:      n.RequestId,
+		Read:    n.Read,
+		Pending: n.Pending,
+		Action:  n.Action,
+	}
+	if n.Request != nil {
+		res.Level = strings.ToLower(n.Request.Level.String())
+		res.Message = n.Request.Message
+		res.Actions = n.Request.Actions
+	}
+	if n.Timestamp != nil {
+		res.Timestamp = n.Timestamp.AsTime().Local()
+	}
+	return res
+}
+
+func (n *notificationData) JSON() string {
+	content, _ := json.Marshal(n)
+	return string(content)
+}
+
+// notificationLevels lists the notification levels, most severe first.
+func notificationLevels() []string {
+	var res []string
+	for i := 0; i < len(supervisor.NotifyRequest_Level_name); i++ {
+		res = append(res, strings.ToLower(supervisor.NotifyRequest_Level_name[int32(i)]))
+	}
+	return res
+}
+
+func parseNotificationLevel(level string) (supervisor.NotifyRequest_Level, error) {
+	l, ok := supervisor.NotifyRequest_Level_value[strings.ToUpper(level)]
+	if !ok {
+		return 0, xerrors.Errorf("invalid level %s, must be one of %s", level, strings.Join(notificationLevels(), ", "))
+	}
+	return supervisor.NotifyRequest_Level(l), nil
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+}
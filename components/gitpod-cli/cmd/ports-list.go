@@ -8,6 +8,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
@@ -18,6 +20,10 @@ import (
 	"github.com/olekukonko/tablewriter"
 )
 
+var listPortsOpts struct {
+	Group bool
+}
+
 var listPortsCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Lists the workspace ports and their states.",
@@ -37,10 +43,26 @@ var listPortsCmd = &cobra.Command{
 			return
 		}
 
+		header := []string{"Port", "Status", "URL", "Name & Description"}
+		if listPortsOpts.Group {
+			// ports without a group are listed last
+			sort.SliceStable(ports, func(i, j int) bool {
+				gi, gj := ports[i].Group, ports[j].Group
+				if gi == "" || gj == "" {
+					return gi != "" && gj == ""
+				}
+				return gi < gj
+			})
+			header = append([]string{"Group"}, header...)
+		}
+
 		table := tablewriter.NewWriter(os.Stdout)
-		table.SetHeader([]string{"Port", "Status", "URL", "Name & Description"})
+		table.SetHeader(header)
 		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 		table.SetCenterSeparator("|")
+		if listPortsOpts.Group {
+			table.SetAutoMergeCellsByColumnIndex([]int{0})
+		}
 
 		for _, port := range ports {
 			status := ""
@@ -71,6 +93,10 @@ var listPortsCmd = &cobra.Command{
 					status = "open (private)"
 					statusColor = tablewriter.FgHiCyanColor
 				}
+				if port.Exposed.Visibility == supervisor.PortVisibility_protected {
+					status = "open (protected)"
+					statusColor = tablewriter.FgHiBlueColor
+				}
 			} else if port.Tunneled != nil {
 				if port.Tunneled.Visibility == supervisor.TunnelVisiblity(supervisor.TunnelVisiblity_value["network"]) {
 					status = "open on all interfaces"
@@ -91,15 +117,27 @@ var listPortsCmd = &cobra.Command{
 				}
 			}
 
+			if port.Protocol != supervisor.PortProtocol_http {
+				nameAndDescription = strings.TrimSpace(fmt.Sprintf("%s (%s)", nameAndDescription, port.Protocol))
+			}
+
+			row := []string{fmt.Sprint(port.LocalPort), status, exposedUrl, nameAndDescription}
 			colors := []tablewriter.Colors{}
 			if !noColor && utils.ColorsEnabled() {
 				colors = []tablewriter.Colors{{}, {statusColor}, {}, {}}
 			}
+			if listPortsOpts.Group {
+				group := port.Group
+				if group == "" {
+					group = "-"
+				}
+				row = append([]string{group}, row...)
+				if len(colors) > 0 {
+					colors = append([]tablewriter.Colors{{}}, colors...)
+				}
+			}
 
-			table.Rich(
-				[]string{fmt.Sprint(port.LocalPort), status, exposedUrl, nameAndDescription},
-				colors,
-			)
+			table.Rich(row, colors)
 		}
 
 		table.Render()
@@ -108,5 +146,6 @@ var listPortsCmd = &cobra.Command{
 
 func init() {
 	listPortsCmd.Flags().BoolVarP(&noColor, "no-color", "", false, "Disable output colorization")
+	listPortsCmd.Flags().BoolVar(&listPortsOpts.Group, "group", false, "Group the ports by their port group")
 	portsCmd.AddCommand(listPortsCmd)
 }
@@ -8,37 +8,68 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"strconv"
 	"strings"
 	"time"
 
 	gitpod "github.com/gitpod-io/gitpod/gitpod-cli/pkg/gitpod"
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
 	serverapi "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/bcrypt"
 )
 
+var portsVisibilityOpts struct {
+	User        string
+	Password    string
+	AllowedCIDR []string
+}
+
 // portsVisibilityCmd change visibility of port
 var portsVisibilityCmd = &cobra.Command{
-	Use:   "visibility <port:{private|public}>",
-	Short: "Make a port public or private",
-	Args:  cobra.ExactArgs(1),
+	Use:   "visibility <port|group:{private|public|protected}>",
+	Short: "Make a port or port group public, protected or private",
+	Long: `Make a port or all ports of a port group public, protected or private.
+
+Protected ports are public behind basic auth (--user) and/or an IP allowlist (--allow-cidr).
+If both are given, clients must meet both. The workspace owner can always access the port.`,
+	Example: `  gp ports visibility 3000:public
+  gp ports visibility storybook:public
+  gp ports visibility 3000:protected --user customer
+  gp ports visibility 3000:protected --allow-cidr 203.0.113.0/24`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		portVisibility := args[0]
-		s := strings.Split(portVisibility, ":")
-		if len(s) != 2 {
-			log.Fatal("cannot parse args, should be something like `3000:public` or `3000:private`")
+		sep := strings.LastIndex(portVisibility, ":")
+		if sep <= 0 {
+			log.Fatal("cannot parse args, should be something like `3000:public`, `3000:protected` or `3000:private`")
 		}
-		port, err := strconv.Atoi(s[0])
-		if err != nil {
-			log.Fatal("port should be integer")
+		target, visibility := portVisibility[:sep], portVisibility[sep+1:]
+		if visibility != serverapi.PortVisibilityPublic && visibility != serverapi.PortVisibilityPrivate && visibility != serverapi.PortVisibilityProtected {
+			log.Fatalf("visibility should be `%s`, `%s` or `%s`", serverapi.PortVisibilityPublic, serverapi.PortVisibilityProtected, serverapi.PortVisibilityPrivate)
 		}
-		visibility := s[1]
-		if visibility != serverapi.PortVisibilityPublic && visibility != serverapi.PortVisibilityPrivate {
-			log.Fatalf("visibility should be `%s` or `%s`", serverapi.PortVisibilityPublic, serverapi.PortVisibilityPrivate)
+		var (
+			accessPolicy *serverapi.PortAccessPolicy
+			err          error
+		)
+		if visibility == serverapi.PortVisibilityProtected {
+			accessPolicy, err = getPortAccessPolicy()
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else if portsVisibilityOpts.User != "" || len(portsVisibilityOpts.AllowedCIDR) > 0 {
+			log.Fatal("--user and --allow-cidr can only be used with protected ports")
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
+		ports, err := getVisibilityTargetPorts(ctx, target)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		wsInfo, err := gitpod.GetWSInfo(ctx)
 		if err != nil {
 			log.Fatalf("cannot get workspace info, %s", err.Error())
@@ -50,16 +81,84 @@ var portsVisibilityCmd = &cobra.Command{
 		if err != nil {
 			log.Fatalf("cannot connect to server, %s", err.Error())
 		}
-		if _, err := client.OpenPort(ctx, wsInfo.WorkspaceId, &serverapi.WorkspaceInstancePort{
-			Port:       float64(port),
-			Visibility: visibility,
-		}); err != nil {
-			log.Fatalf("failed to change port visibility: %s", err.Error())
+		for _, port := range ports {
+			if _, err := client.OpenPort(ctx, wsInfo.WorkspaceId, &serverapi.WorkspaceInstancePort{
+				Port:         float64(port),
+				Visibility:   visibility,
+				AccessPolicy: accessPolicy,
+			}); err != nil {
+				log.Fatalf("failed to change port visibility: %s", err.Error())
+			}
+			fmt.Printf("port %v is now %s\n", port, visibility)
 		}
-		fmt.Printf("port %v is now %s\n", port, visibility)
 	},
 }
 
+// getVisibilityTargetPorts returns the port or the ports of the port group the visibility should be changed for
+func getVisibilityTargetPorts(ctx context.Context, target string) ([]uint32, error) {
+	if port, err := strconv.ParseUint(target, 10, 16); err == nil {
+		return []uint32{uint32(port)}, nil
+	}
+	status, err := supervisor_helper.GetPortsList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get ports of group %s: %w", target, err)
+	}
+	var ports []uint32
+	for _, port := range status {
+		if port.Group == target {
+			ports = append(ports, port.LocalPort)
+		}
+	}
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("%s is neither a port nor a port group with detected ports", target)
+	}
+	return ports, nil
+}
+
+func getPortAccessPolicy() (*serverapi.PortAccessPolicy, error) {
+	if portsVisibilityOpts.User == "" && len(portsVisibilityOpts.AllowedCIDR) == 0 {
+		return nil, fmt.Errorf("protected ports require --user and/or --allow-cidr")
+	}
+	// an empty list removes the basic auth of the port
+	policy := &serverapi.PortAccessPolicy{BasicAuth: []string{}}
+	for _, cidr := range portsVisibilityOpts.AllowedCIDR {
+		if _, _, err := net.ParseCIDR(cidr); err != nil && net.ParseIP(cidr) == nil {
+			return nil, fmt.Errorf("invalid CIDR %s", cidr)
+		}
+		policy.AllowedCIDRs = append(policy.AllowedCIDRs, cidr)
+	}
+	if portsVisibilityOpts.User == "" {
+		return policy, nil
+	}
+	if strings.Contains(portsVisibilityOpts.User, ":") {
+		return nil, fmt.Errorf("user must not contain a colon")
+	}
+
+	password := portsVisibilityOpts.Password
+	if password == "" {
+		prompt := promptui.Prompt{
+			Label:    fmt.Sprintf("Password for %s", portsVisibilityOpts.User),
+			Mask:     '*',
+			Validate: isRequired,
+		}
+		var err error
+		password, err = prompt.Run()
+		if err != nil {
+			return nil, err
+		}
+	}
+	// only the hash leaves the workspace
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("cannot hash password: %w", err)
+	}
+	policy.BasicAuth = []string{portsVisibilityOpts.User + ":" + string(hash)}
+	return policy, nil
+}
+
 func init() {
 	portsCmd.AddCommand(portsVisibilityCmd)
+	portsVisibilityCmd.Flags().StringVar(&portsVisibilityOpts.User, "user", "", "require basic auth with this user to access a protected port")
+	portsVisibilityCmd.Flags().StringVar(&portsVisibilityOpts.Password, "password", "", "password of the basic auth user (prompted for if not given)")
+	portsVisibilityCmd.Flags().StringSliceVar(&portsVisibilityOpts.AllowedCIDR, "allow-cidr", nil, "only allow clients from this IP range to access a protected port (can be repeated)")
 }
@@ -0,0 +1,159 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// sshKeysCmd represents the ssh keys command
+var sshKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage the SSH keys authorized to access the workspace",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			_ = cmd.Help()
+		}
+	},
+}
+
+// listSSHKeysCmd represents the ssh keys list command
+var listSSHKeysCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the authorized SSH keys",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		client, err := supervisor_helper.GetSSHServiceClient(ctx)
+		if err != nil {
+			log.Fatalf("cannot get SSH service: %s", err)
+		}
+		resp, err := client.ListKeys(ctx, &supervisor.ListSSHKeysRequest{})
+		if err != nil {
+			log.Fatalf("cannot list SSH keys: %s", err)
+		}
+		if len(resp.Keys) == 0 {
+			fmt.Println("No SSH keys authorized")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Fingerprint", "Type", "Comment", "Expires"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		for _, key := range resp.Keys {
+			expires := "never"
+			if key.ExpiryTime != nil {
+				expires = key.ExpiryTime.AsTime().Local().Format(time.RFC3339)
+				if key.Expired {
+					expires += " (expired)"
+				}
+			}
+			table.Append([]string{key.Fingerprint, key.Type, key.Comment, expires})
+		}
+		table.Render()
+	},
+}
+
+var addSSHKeyCmdOpts struct {
+	TTL time.Duration
+}
+
+// addSSHKeyCmd represents the ssh keys add command
+var addSSHKeyCmd = &cobra.Command{
+	Use:   "add <public key or file>",
+	Short: "Authorizes an SSH key to access the workspace",
+	Long: `Authorizes an SSH key to access the workspace.
+
+The key is given either in the authorized_keys format, e.g. 'ssh-ed25519 AAAA... me@laptop', or as the path of a public key file.
+With --ttl the key is authorized only for the given duration.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		publicKey := args[0]
+		if content, err := os.ReadFile(publicKey); err == nil {
+			publicKey = string(content)
+		}
+		req := &supervisor.AddSSHKeyRequest{PublicKey: strings.TrimSpace(publicKey)}
+		if addSSHKeyCmdOpts.TTL < 0 {
+			log.Fatal("ttl must be positive")
+		}
+		if addSSHKeyCmdOpts.TTL > 0 {
+			req.Ttl = durationpb.New(addSSHKeyCmdOpts.TTL)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		client, err := supervisor_helper.GetSSHServiceClient(ctx)
+		if err != nil {
+			log.Fatalf("cannot get SSH service: %s", err)
+		}
+		resp, err := client.AddKey(ctx, req)
+		if status.Code(err) == codes.InvalidArgument {
+			log.Fatal(status.Convert(err).Message())
+		}
+		if err != nil {
+			log.Fatalf("cannot add SSH key: %s", err)
+		}
+		if resp.Key.ExpiryTime != nil {
+			fmt.Printf("SSH key %s is authorized until %s\n", resp.Key.Fingerprint, resp.Key.ExpiryTime.AsTime().Local().Format(time.RFC3339))
+			return
+		}
+		fmt.Printf("SSH key %s is authorized\n", resp.Key.Fingerprint)
+	},
+}
+
+// revokeSSHKeyCmd represents the ssh keys revoke command
+var revokeSSHKeyCmd = &cobra.Command{
+	Use:   "revoke <fingerprint>",
+	Short: "Revokes an authorized SSH key",
+	Long: `Revokes an authorized SSH key.
+
+The key is referenced by its fingerprint as shown by 'gp ssh keys list'.
+Established SSH sessions are not affected, use 'gp ssh sessions kill' to close them.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		client, err := supervisor_helper.GetSSHServiceClient(ctx)
+		if err != nil {
+			log.Fatalf("cannot get SSH service: %s", err)
+		}
+		_, err = client.RevokeKey(ctx, &supervisor.RevokeSSHKeyRequest{Fingerprint: args[0]})
+		if status.Code(err) == codes.NotFound {
+			log.Fatalf("SSH key %s is not authorized. Use 'gp ssh keys list' to obtain the key fingerprint", args[0])
+		}
+		if err != nil {
+			log.Fatalf("cannot revoke SSH key: %s", err)
+		}
+		fmt.Printf("SSH key %s has been revoked\n", args[0])
+	},
+}
+
+func init() {
+	sshCmd.AddCommand(sshKeysCmd)
+	sshKeysCmd.AddCommand(listSSHKeysCmd)
+	sshKeysCmd.AddCommand(addSSHKeyCmd)
+	sshKeysCmd.AddCommand(revokeSSHKeyCmd)
+
+	addSSHKeyCmd.Flags().DurationVar(&addSSHKeyCmdOpts.TTL, "ttl", 0, "duration after which the key expires, e.g. 2h; the key does not expire if not set")
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sshSessionsCmd represents the ssh sessions command
+var sshSessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Manage the SSH sessions established to the workspace",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			_ = cmd.Help()
+		}
+	},
+}
+
+// listSSHSessionsCmd represents the ssh sessions list command
+var listSSHSessionsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the established SSH sessions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		client, err := supervisor_helper.GetSSHServiceClient(ctx)
+		if err != nil {
+			log.Fatalf("cannot get SSH service: %s", err)
+		}
+		resp, err := client.ListSessions(ctx, &supervisor.ListSSHSessionsRequest{})
+		if err != nil {
+			log.Fatalf("cannot list SSH sessions: %s", err)
+		}
+		if len(resp.Sessions) == 0 {
+			fmt.Println("No SSH sessions established")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Remote Address", "User", "Key", "Started", "Terminals"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		for _, session := range resp.Sessions {
+			started := ""
+			if session.StartTime != nil {
+				started = time.Since(session.StartTime.AsTime()).Truncate(time.Second).String() + " ago"
+			}
+			table.Append([]string{session.Id, session.RemoteAddr, session.User, session.KeyFingerprint, started, strings.Join(session.Terminals, ", ")})
+		}
+		table.Render()
+	},
+}
+
+// killSSHSessionCmd represents the ssh sessions kill command
+var killSSHSessionCmd = &cobra.Command{
+	Use:   "kill <id>",
+	Short: "Closes an established SSH session",
+	Long: `Closes an established SSH session and all its terminals.
+
+The session is referenced by its ID as shown by 'gp ssh sessions list'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		client, err := supervisor_helper.GetSSHServiceClient(ctx)
+		if err != nil {
+			log.Fatalf("cannot get SSH service: %s", err)
+		}
+		_, err = client.KillSession(ctx, &supervisor.KillSSHSessionRequest{Id: args[0]})
+		if status.Code(err) == codes.NotFound {
+			log.Fatalf("SSH session %s does not exist. Use 'gp ssh sessions list' to obtain the session ID", args[0])
+		}
+		if err != nil {
+			log.Fatalf("cannot kill SSH session: %s", err)
+		}
+		fmt.Printf("SSH session %s has been closed\n", args[0])
+	},
+}
+
+func init() {
+	sshCmd.AddCommand(sshSessionsCmd)
+	sshSessionsCmd.AddCommand(listSSHSessionsCmd)
+	sshSessionsCmd.AddCommand(killSSHSessionCmd)
+}
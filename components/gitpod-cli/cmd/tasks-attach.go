@@ -38,7 +38,7 @@ var attachTaskCmd = &cobra.Command{
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
-			tasks, err := supervisor_helper.GetTasksListByState(ctx, supervisor.TaskState_running)
+			tasks, err := supervisor_helper.GetTasksListByState(ctx, supervisor_helper.RunningTaskStates...)
 			if err != nil {
 				log.Fatalf("cannot get task list: %s", err)
 			}
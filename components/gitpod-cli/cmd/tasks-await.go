@@ -0,0 +1,73 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/spf13/cobra"
+)
+
+// awaitTaskCmd represents the tasks await command
+var awaitTaskCmd = &cobra.Command{
+	Use:   "await <id>",
+	Short: "Waits for a workspace task to become ready",
+	Long: `Waits for a workspace task to become ready.
+
+The task is referenced by its terminal ID or its name as shown by 'gp tasks list'.
+Tasks without a readiness probe are considered ready once they are running.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		conn, err := supervisor_helper.Dial(ctx)
+		if err != nil {
+			log.Fatalf("cannot connect to supervisor: %s", err)
+		}
+		client := supervisor.NewStatusServiceClient(conn)
+		tasksStatus, err := client.TasksStatus(ctx, &supervisor.TasksStatusRequest{Observe: true})
+		if err != nil {
+			log.Fatalf("cannot get task status: %s", err)
+		}
+
+		fmt.Printf("Awaiting task %s... ", args[0])
+		for {
+			resp, err := tasksStatus.Recv()
+			if err != nil {
+				fmt.Println()
+				log.Fatalf("cannot receive task status: %s", err)
+			}
+
+			task := supervisor_helper.FindTask(resp.GetTasks(), args[0])
+			if task == nil {
+				fmt.Println()
+				log.Fatalf("task %s not found. Use 'gp tasks list' to obtain the task ID", args[0])
+			}
+
+			switch task.State {
+			case supervisor.TaskState_ready:
+				fmt.Println("ok")
+				return
+			case supervisor.TaskState_running:
+				if !task.HasReadinessProbe {
+					fmt.Println("ok")
+					return
+				}
+			case supervisor.TaskState_closed:
+				fmt.Println()
+				log.Fatalf("task %s has been closed before it became ready", args[0])
+			}
+		}
+	},
+}
+
+func init() {
+	tasksCmd.AddCommand(awaitTaskCmd)
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
@@ -39,7 +40,7 @@ var listTasksCmd = &cobra.Command{
 		}
 
 		table := tablewriter.NewWriter(os.Stdout)
-		table.SetHeader([]string{"Terminal ID", "Name", "State"})
+		table.SetHeader([]string{"Terminal ID", "Name", "State", "Restarts", "Exit Code"})
 		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 		table.SetCenterSeparator("|")
 
@@ -47,6 +48,9 @@ var listTasksCmd = &cobra.Command{
 			0: tablewriter.FgHiGreenColor,
 			1: tablewriter.FgHiGreenColor,
 			2: tablewriter.FgHiBlackColor,
+			3: tablewriter.FgHiYellowColor,
+			4: tablewriter.FgHiGreenColor,
+			5: tablewriter.FgHiRedColor,
 		}
 
 		mapCurrentToColor := map[bool]int{
@@ -61,7 +65,7 @@ var listTasksCmd = &cobra.Command{
 
 			isCurrent := false
 
-			if task.State == api.TaskState_running {
+			if supervisor_helper.IsRunningTaskState(task.State) {
 				terminalClient, err := supervisor_helper.GetTerminalServiceClient(context.Background())
 				if err != nil {
 					log.Fatalf("cannot get terminal service: %s", err)
@@ -78,10 +82,15 @@ var listTasksCmd = &cobra.Command{
 			}
 
 			if !noColor && utils.ColorsEnabled() {
-				colors = []tablewriter.Colors{{mapCurrentToColor[isCurrent]}, {}, {mapStatusToColor[task.State]}}
+				colors = []tablewriter.Colors{{mapCurrentToColor[isCurrent]}, {}, {mapStatusToColor[task.State]}, {}, {}}
 			}
 
-			table.Rich([]string{task.Terminal, task.Presentation.Name, task.State.String()}, colors)
+			exitCode := ""
+			if task.LastExitCode != nil {
+				exitCode = strconv.Itoa(int(*task.LastExitCode))
+			}
+
+			table.Rich([]string{task.Terminal, task.Presentation.Name, task.State.String(), strconv.Itoa(int(task.RestartCount)), exitCode}, colors)
 		}
 
 		table.Render()
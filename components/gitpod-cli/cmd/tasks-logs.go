@@ -0,0 +1,115 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var logsTaskCmdOpts struct {
+	Since  string
+	Grep   string
+	Follow bool
+}
+
+// logsTaskCmd represents the tasks logs command
+var logsTaskCmd = &cobra.Command{
+	Use:   "logs <id>",
+	Short: "Prints the output of a workspace task",
+	Long: `Prints the output of a workspace task, including output which has been written before the terminal was last attached.
+
+The task is referenced by its terminal ID or its name as shown by 'gp tasks list'.
+The output is only available if the terminal history is enabled for the workspace.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-sigs
+			cancel()
+		}()
+
+		req := &supervisor.ReadTerminalHistoryRequest{
+			Alias:  args[0],
+			Grep:   logsTaskCmdOpts.Grep,
+			Follow: logsTaskCmdOpts.Follow,
+		}
+		if logsTaskCmdOpts.Since != "" {
+			since, err := parseSince(logsTaskCmdOpts.Since)
+			if err != nil {
+				log.Fatalf("invalid --since value: %s", err)
+			}
+			req.Since = timestamppb.New(since)
+		}
+
+		listCtx, listCancel := context.WithTimeout(ctx, 5*time.Second)
+		tasks, err := supervisor_helper.GetTasksList(listCtx)
+		listCancel()
+		if err != nil {
+			log.Fatalf("cannot get task list: %s", err)
+		}
+		if task := supervisor_helper.FindTask(tasks, args[0]); task != nil {
+			req.Alias = task.Terminal
+		}
+
+		terminalClient, err := supervisor_helper.GetTerminalServiceClient(ctx)
+		if err != nil {
+			log.Fatalf("cannot get terminal service: %s", err)
+		}
+		history, err := terminalClient.ReadHistory(ctx, req)
+		if err != nil {
+			log.Fatalf("cannot read task output: %s", err)
+		}
+		for {
+			resp, err := history.Recv()
+			if err == io.EOF || errors.Is(ctx.Err(), context.Canceled) {
+				return
+			}
+			if status.Code(err) == codes.NotFound {
+				log.Fatalf("no output found for task %s. Use 'gp tasks list' to obtain the task ID", args[0])
+			}
+			if err != nil {
+				log.Fatalf("cannot read task output: %s", err)
+			}
+			_, err = os.Stdout.Write(resp.Data)
+			if err != nil {
+				log.Fatalf("cannot write task output: %s", err)
+			}
+		}
+	},
+}
+
+// parseSince parses either a duration relative to now, e.g. 10m, or a RFC3339 timestamp.
+func parseSince(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func init() {
+	tasksCmd.AddCommand(logsTaskCmd)
+
+	logsTaskCmd.Flags().StringVar(&logsTaskCmdOpts.Since, "since", "", "only print output written since a relative duration like 10m, or a RFC3339 timestamp")
+	logsTaskCmd.Flags().StringVar(&logsTaskCmdOpts.Grep, "grep", "", "only print lines containing this substring")
+	logsTaskCmd.Flags().BoolVarP(&logsTaskCmdOpts.Follow, "follow", "f", false, "keep printing new output until the task's terminal is closed")
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var recordTaskCmdOpts struct {
+	Output string
+}
+
+// recordTaskCmd represents the tasks record command
+var recordTaskCmd = &cobra.Command{
+	Use:   "record <id>",
+	Short: "Records the terminal session of a workspace task",
+	Long: `Records the terminal session of a workspace task in the asciicast v2 format until interrupted.
+
+The task is referenced by its terminal ID or its name as shown by 'gp tasks list'.
+Recordings can be replayed with standard players, e.g. 'asciinema play <file>'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		tasks, err := supervisor_helper.GetTasksList(ctx)
+		if err != nil {
+			log.Fatalf("cannot get task list: %s", err)
+		}
+		alias := args[0]
+		if task := supervisor_helper.FindTask(tasks, args[0]); task != nil {
+			alias = task.Terminal
+		}
+
+		terminalClient, err := supervisor_helper.GetTerminalServiceClient(ctx)
+		if err != nil {
+			log.Fatalf("cannot get terminal service: %s", err)
+		}
+		started, err := terminalClient.StartRecording(ctx, &supervisor.StartTerminalRecordingRequest{Alias: alias, Path: recordTaskCmdOpts.Output})
+		if status.Code(err) == codes.NotFound {
+			log.Fatalf("task %s is not running. Use 'gp tasks list' to obtain the task ID", args[0])
+		}
+		if err != nil {
+			log.Fatalf("cannot start recording: %s", err)
+		}
+		fmt.Printf("Recording task %s to %s. Press Ctrl+C to stop.\n", args[0], started.Path)
+
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+		<-sigs
+
+		stopCtx, stopCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer stopCancel()
+		_, err = terminalClient.StopRecording(stopCtx, &supervisor.StopTerminalRecordingRequest{Alias: alias})
+		if err != nil && status.Code(err) != codes.NotFound && status.Code(err) != codes.FailedPrecondition {
+			log.Fatalf("cannot stop recording: %s", err)
+		}
+		// the recording ends with the terminal, hence there is nothing to stop if it has been closed already
+		fmt.Printf("Recording saved to %s\n", started.Path)
+	},
+}
+
+func init() {
+	tasksCmd.AddCommand(recordTaskCmd)
+
+	recordTaskCmd.Flags().StringVarP(&recordTaskCmdOpts.Output, "output", "o", "", "name of the file in /workspace/.gitpod/recordings to write the recording to, existing files are not overwritten")
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+var reloadTasksCmdOpts struct {
+	DryRun bool
+}
+
+// reloadTasksCmd represents the reload tasks command
+var reloadTasksCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Apply changes of the tasks in .gitpod.yml",
+	Long: `Apply changes of the tasks in .gitpod.yml to the running workspace.
+
+Tasks which have been added are started, tasks which have been removed are stopped and
+tasks whose commands or environment changed are restarted. Other tasks keep running.
+Tasks are identified by their name, tasks without a name by their order.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		client, err := supervisor_helper.GetControlServiceClient(ctx)
+		if err != nil {
+			log.Fatalf("cannot get control service: %s", err)
+		}
+		resp, err := client.ReloadTasks(ctx, &api.ReloadTasksRequest{DryRun: reloadTasksCmdOpts.DryRun})
+		if err != nil {
+			if s, ok := status.FromError(err); ok {
+				log.Fatalf("cannot reload tasks: %s", s.Message())
+			}
+			log.Fatalf("cannot reload tasks: %s", err)
+		}
+		if len(resp.Added) == 0 && len(resp.Removed) == 0 && len(resp.Changed) == 0 {
+			fmt.Println("The tasks are up to date")
+			return
+		}
+
+		verbs := []string{"Started", "Stopped", "Restarted"}
+		if reloadTasksCmdOpts.DryRun {
+			verbs = []string{"Would start", "Would stop", "Would restart"}
+		}
+		for i, tasks := range [][]string{resp.Added, resp.Removed, resp.Changed} {
+			if len(tasks) > 0 {
+				fmt.Printf("%s: %s\n", verbs[i], strings.Join(tasks, ", "))
+			}
+		}
+	},
+}
+
+func init() {
+	tasksCmd.AddCommand(reloadTasksCmd)
+
+	reloadTasksCmd.Flags().BoolVar(&reloadTasksCmdOpts.DryRun, "dry-run", false, "print the changes without applying them")
+}
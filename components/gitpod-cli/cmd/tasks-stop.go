@@ -49,7 +49,7 @@ var stopTaskCmd = &cobra.Command{
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
-			tasks, err := supervisor_helper.GetTasksListByState(ctx, supervisor.TaskState_running)
+			tasks, err := supervisor_helper.GetTasksListByState(ctx, supervisor_helper.RunningTaskStates...)
 
 			if err != nil {
 				log.Fatalf("cannot get task list: %s", err)
@@ -76,7 +76,7 @@ var stopTaskCmd = &cobra.Command{
 
 			terminalAliases = append(terminalAliases, args[0])
 		} else {
-			tasks, err := supervisor_helper.GetTasksListByState(ctx, supervisor.TaskState_running)
+			tasks, err := supervisor_helper.GetTasksListByState(ctx, supervisor_helper.RunningTaskStates...)
 			if err != nil {
 				log.Fatalf("cannot get task list: %s", err)
 			}
@@ -7,10 +7,16 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	supervisor_helper "github.com/gitpod-io/gitpod/gitpod-cli/pkg/supervisor-helper"
@@ -25,9 +31,14 @@ import (
 )
 
 var topCmdOpts struct {
-	Json bool
+	Json   bool
+	Watch  bool
+	ByTask bool
 }
 
+// topHistorySize is the number of samples shown in the CPU and memory trend of the watch view.
+const topHistorySize = 60
+
 type topData struct {
 	Resources      *supervisor.ResourcesStatusResponse              `json:"resources"`
 	WorkspaceClass *supervisor.WorkspaceInfoResponse_WorkspaceClass `json:"workspace_class"`
@@ -36,7 +47,17 @@ type topData struct {
 var topCmd = &cobra.Command{
 	Use:   "top",
 	Short: "Display usage of workspace resources (CPU and memory)",
+	Long: `Display usage of workspace resources (CPU, memory, disk I/O and processes).
+
+With --watch the view is updated continuously and shows the recent CPU and memory trend
+together with the usage of each process tree. With --by-task the usage is grouped
+by the workspace task the processes have been started by.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if topCmdOpts.Watch {
+			watchTop()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
@@ -76,9 +97,191 @@ var topCmd = &cobra.Command{
 			return
 		}
 		outputTable(data.Resources, data.WorkspaceClass)
+		if topCmdOpts.ByTask {
+			fmt.Println()
+			outputProcessesTable(data.Resources.Processes, true)
+		}
 	},
 }
 
+func watchTop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		cancel()
+	}()
+
+	conn, err := supervisor_helper.Dial(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	var workspaceClass *supervisor.WorkspaceInfoResponse_WorkspaceClass
+	infoCtx, infoCancel := context.WithTimeout(ctx, 5*time.Second)
+	if wsInfo, err := supervisor.NewInfoServiceClient(conn).WorkspaceInfo(infoCtx, &supervisor.WorkspaceInfoRequest{}); err == nil {
+		workspaceClass = wsInfo.WorkspaceClass
+	}
+	infoCancel()
+
+	stream, err := supervisor_helper.ObserveWorkspaceResources(ctx, conn, !topCmdOpts.Json)
+	if err != nil {
+		log.Fatalf("cannot observe workspace resources: %s", err)
+	}
+
+	var history []*supervisor.ResourcesStatusResponse
+	for {
+		resources, err := stream.Recv()
+		if err == io.EOF || errors.Is(ctx.Err(), context.Canceled) {
+			return
+		}
+		if err != nil {
+			log.Fatalf("cannot observe workspace resources: %s", err)
+		}
+
+		if topCmdOpts.Json {
+			content, _ := json.Marshal(&topData{Resources: resources, WorkspaceClass: workspaceClass})
+			fmt.Println(string(content))
+			continue
+		}
+
+		history = append(history, resources)
+		if len(history) > topHistorySize {
+			history = history[len(history)-topHistorySize:]
+		}
+		// the stream starts with the samples of the history, we only render once we've caught up
+		if time.Since(time.UnixMilli(resources.Timestamp)) > 5*time.Second {
+			continue
+		}
+
+		// clear the screen and move the cursor to the top left corner
+		fmt.Print("\033[H\033[2J")
+		outputTable(resources, workspaceClass)
+		fmt.Println()
+		fmt.Printf("CPU    %s\n", sparkline(history, func(r *supervisor.ResourcesStatusResponse) *supervisor.ResourceStatus { return r.Cpu }))
+		fmt.Printf("Memory %s\n", sparkline(history, func(r *supervisor.ResourcesStatusResponse) *supervisor.ResourceStatus { return r.Memory }))
+		fmt.Println()
+		outputProcessesTable(resources.Processes, topCmdOpts.ByTask)
+	}
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the usage relative to the limit of a resource over time.
+func sparkline(history []*supervisor.ResourcesStatusResponse, resource func(*supervisor.ResourcesStatusResponse) *supervisor.ResourceStatus) string {
+	var res strings.Builder
+	for _, sample := range history {
+		status := resource(sample)
+		if status == nil || status.Limit <= 0 {
+			res.WriteRune(sparks[0])
+			continue
+		}
+		idx := int(float64(status.Used) / float64(status.Limit) * float64(len(sparks)-1))
+		if idx < 0 {
+			idx = 0
+		} else if idx >= len(sparks) {
+			idx = len(sparks) - 1
+		}
+		res.WriteRune(sparks[idx])
+	}
+	return res.String()
+}
+
+type processesUsage struct {
+	Name   string
+	Pids   int64
+	Cpu    int64
+	Memory int64
+	Read   int64
+	Write  int64
+}
+
+// groupProcesses sums up the usage of the process trees. If byTask is true, the process trees
+// are grouped by the task they have been started by, all other process trees are listed separately.
+func groupProcesses(processes []*supervisor.ProcessResourcesStatus, byTask bool) []*processesUsage {
+	var (
+		res    []*processesUsage
+		groups = make(map[string]*processesUsage)
+	)
+	for _, p := range processes {
+		var (
+			name  = p.Command
+			group string
+		)
+		if byTask && p.TaskId != "" {
+			group = p.TaskId
+			name = "task: " + p.TaskName
+			if p.TaskName == "" {
+				name = "task: " + p.TaskId
+			}
+		} else if p.Terminal != "" && p.TaskId != "" {
+			name = fmt.Sprintf("[%s] %s", p.TaskName, p.Command)
+		}
+
+		usage, ok := groups[group]
+		if group == "" || !ok {
+			usage = &processesUsage{Name: name}
+			res = append(res, usage)
+			if group != "" {
+				groups[group] = usage
+			}
+		}
+		usage.Pids += p.Pids
+		usage.Cpu += p.Cpu
+		usage.Memory += p.Memory
+		if p.DiskIo != nil {
+			usage.Read += p.DiskIo.Read
+			usage.Write += p.DiskIo.Write
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Cpu > res[j].Cpu
+	})
+	return res
+}
+
+func outputProcessesTable(processes []*supervisor.ProcessResourcesStatus, byTask bool) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetAutoWrapText(false)
+	table.SetColWidth(50)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"Process", "PIDs", "CPU (millicores)", "Memory", "Disk read", "Disk write"})
+
+	for _, usage := range groupProcesses(processes, byTask) {
+		name := usage.Name
+		if len(name) > 50 {
+			name = name[:47] + "..."
+		}
+		table.Append([]string{
+			name,
+			fmt.Sprint(usage.Pids),
+			fmt.Sprintf("%dm", usage.Cpu),
+			fmt.Sprintf("%dMi", usage.Memory/(1024*1024)),
+			formatBytesPerSecond(usage.Read),
+			formatBytesPerSecond(usage.Write),
+		})
+	}
+
+	table.Render()
+}
+
+func formatBytesPerSecond(value int64) string {
+	switch {
+	case value >= 1024*1024:
+		return fmt.Sprintf("%.1fMi/s", float64(value)/(1024*1024))
+	case value >= 1024:
+		return fmt.Sprintf("%.1fKi/s", float64(value)/1024)
+	default:
+		return fmt.Sprintf("%dB/s", value)
+	}
+}
+
 func formatWorkspaceClass(workspaceClass *supervisor.WorkspaceInfoResponse_WorkspaceClass) string {
 	if workspaceClass == nil || workspaceClass.DisplayName == "" {
 		return ""
@@ -106,6 +309,20 @@ func outputTable(workspaceResources *supervisor.ResourcesStatusResponse, workspa
 	table.Append([]string{"Workspace class", formatWorkspaceClass(workspaceClass)})
 	table.Rich([]string{"CPU (millicores)", cpu}, cpuColors)
 	table.Rich([]string{"Memory (bytes)", memory}, memoryColors)
+	if workspaceResources.DiskIo != nil {
+		table.Append([]string{"Disk I/O", fmt.Sprintf("%s read, %s written", formatBytesPerSecond(workspaceResources.DiskIo.Read), formatBytesPerSecond(workspaceResources.DiskIo.Write))})
+	}
+	if pids := workspaceResources.Pids; pids != nil {
+		var pidsColors []tablewriter.Colors
+		if !noColor && utils.ColorsEnabled() {
+			pidsColors = []tablewriter.Colors{nil, {getColor(pids.Severity)}}
+		}
+		value := fmt.Sprint(pids.Used)
+		if pids.Limit > 0 {
+			value = fmt.Sprintf("%d/%d", pids.Used, pids.Limit)
+		}
+		table.Rich([]string{"Processes", value}, pidsColors)
+	}
 
 	table.Render()
 }
@@ -124,5 +341,7 @@ func getColor(severity api.ResourceStatusSeverity) int {
 func init() {
 	topCmd.Flags().BoolVarP(&noColor, "no-color", "", false, "Disable output colorization")
 	topCmd.Flags().BoolVarP(&topCmdOpts.Json, "json", "j", false, "Output in JSON format")
+	topCmd.Flags().BoolVarP(&topCmdOpts.Watch, "watch", "w", false, "Continuously update the resource usage")
+	topCmd.Flags().BoolVar(&topCmdOpts.ByTask, "by-task", false, "Group the process usage by workspace task")
 	rootCmd.AddCommand(topCmd)
 }
@@ -0,0 +1,118 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/gitpod-protocol/validate"
+)
+
+var validateCmdOpts struct {
+	ShowCommands bool
+}
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validates the Gitpod configuration of this project",
+	Long: `Validates the Gitpod configuration of this project.
+
+Reports syntax errors, unknown properties and invalid values with the line and column they occur at.
+If the configuration is valid, the commands of each task are composed the way they are run on
+workspace start and during prebuilds, and checked for shell syntax errors.
+
+Without an argument, the .gitpod.yml in the root of the repository is validated.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		location := ".gitpod.yml"
+		if repoRoot := os.Getenv("GITPOD_REPO_ROOT"); repoRoot != "" {
+			location = filepath.Join(repoRoot, location)
+		}
+		if len(args) > 0 {
+			location = args[0]
+		}
+		content, err := os.ReadFile(location)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		diagnostics := validate.Validate(content)
+		for _, d := range diagnostics {
+			if d.Line == 0 {
+				fmt.Printf("%s: %s\n", location, d)
+			} else {
+				fmt.Printf("%s:%s\n", location, d)
+			}
+		}
+		if validate.HasErrors(diagnostics) {
+			os.Exit(1)
+		}
+
+		var config gitpod.GitpodConfig
+		err = yaml.Unmarshal(content, &config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		valid := true
+		for i, task := range config.Tasks {
+			if task == nil {
+				continue
+			}
+			name := task.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i)
+			}
+			// the commands are composed the same way supervisor composes them
+			commands := gitpod.TaskCommands{Before: &task.Before, Init: &task.Init, Prebuild: &task.Prebuild, Command: &task.Command}
+			for _, phase := range []gitpod.TaskPhase{gitpod.TaskPhasePrebuild, gitpod.TaskPhaseStart, gitpod.TaskPhaseStartFromPrebuild} {
+				command := commands.Compose(phase, gitpod.TaskCommandOptions{Exit: phase == gitpod.TaskPhasePrebuild})
+				if command == "" {
+					continue
+				}
+				if validateCmdOpts.ShowCommands {
+					fmt.Printf("task %s (%s):\n%s\n\n", name, phase, command)
+				}
+				err := checkShellSyntax(command)
+				if err != nil {
+					valid = false
+					fmt.Printf("%s: error: task %s (%s): %s\n", location, name, phase, err)
+				}
+			}
+		}
+		if !valid {
+			os.Exit(1)
+		}
+		fmt.Printf("%s is valid\n", location)
+	},
+}
+
+// checkShellSyntax checks the command for syntax errors without running it.
+func checkShellSyntax(command string) error {
+	var stderr bytes.Buffer
+	check := exec.Command("bash", "-n", "-c", command)
+	check.Stderr = &stderr
+	err := check.Run()
+	if _, ok := err.(*exec.ExitError); ok {
+		msg := strings.TrimSpace(strings.ReplaceAll(stderr.String(), "bash: -c: ", ""))
+		return fmt.Errorf("%s", strings.ReplaceAll(msg, "\n", "; "))
+	}
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().BoolVar(&validateCmdOpts.ShowCommands, "show-commands", false, "print the composed commands of each task")
+}
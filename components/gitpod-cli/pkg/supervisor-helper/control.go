@@ -0,0 +1,19 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor_helper
+
+import (
+	"context"
+
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func GetControlServiceClient(ctx context.Context) (supervisor.ControlServiceClient, error) {
+	conn, err := Dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return supervisor.NewControlServiceClient(conn), nil
+}
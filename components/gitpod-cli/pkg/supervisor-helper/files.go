@@ -0,0 +1,215 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor_helper
+
+import (
+	"context"
+	"io"
+	"io/fs"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/gitpod-io/gitpod/common-go/filesync"
+	supervisor "github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// readChunksBatchSize is the number of chunks requested per ReadChunks call.
+const readChunksBatchSize = 64
+
+// FilesClient synchronises a directory tree of the workspace.
+type FilesClient struct {
+	Client supervisor.FileServiceClient
+	// Path of the directory tree in the workspace, relative paths are resolved against the repository root
+	Path string
+}
+
+func GetFilesClient(ctx context.Context, path string) (*FilesClient, error) {
+	conn, err := Dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &FilesClient{Client: supervisor.NewFileServiceClient(conn), Path: path}, nil
+}
+
+// List lists the entries of the directory tree in the workspace and the chunk size they have been computed with.
+func (c *FilesClient) List(ctx context.Context, filter filesync.Filter) (entries []*filesync.Entry, chunkSize int64, err error) {
+	resp, err := c.Client.ListFiles(ctx, &supervisor.ListFilesRequest{
+		Path:    c.Path,
+		Include: filter.Include,
+		Exclude: filter.Exclude,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	for {
+		msg, err := resp.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		chunkSize = msg.ChunkSize
+		for _, entry := range msg.Entries {
+			entries = append(entries, FromFileEntry(entry))
+		}
+	}
+	return entries, chunkSize, nil
+}
+
+// Pull applies a plan computed against the workspace tree to the local writer.
+func (c *FilesClient) Pull(ctx context.Context, plan *filesync.Plan, chunkSize int64, w *filesync.Writer, remove bool) (written int64, err error) {
+	var chunks []*supervisor.FileChunk
+	for _, entry := range plan.Entries {
+		err = w.Apply(entry)
+		if err != nil {
+			return written, err
+		}
+		for _, idx := range plan.Chunks[entry.Path] {
+			chunks = append(chunks, &supervisor.FileChunk{
+				Path:   entry.Path,
+				Offset: int64(idx) * chunkSize,
+				Length: filesync.ChunkLength(entry, idx, chunkSize),
+			})
+		}
+	}
+
+	for len(chunks) > 0 {
+		batch := chunks
+		if len(batch) > readChunksBatchSize {
+			batch = batch[:readChunksBatchSize]
+		}
+		chunks = chunks[len(batch):]
+
+		resp, err := c.Client.ReadChunks(ctx, &supervisor.ReadChunksRequest{Path: c.Path, Chunks: batch})
+		if err != nil {
+			return written, err
+		}
+		for {
+			msg, err := resp.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return written, err
+			}
+			err = w.WriteChunk(msg.Chunk.Path, msg.Chunk.Offset, msg.Chunk.Data, msg.Chunk.Digest)
+			if err != nil {
+				return written, err
+			}
+			written += int64(len(msg.Chunk.Data))
+		}
+	}
+
+	if remove {
+		for _, p := range plan.Removals {
+			err = w.Remove(p)
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, w.Close()
+}
+
+// Push applies a plan computed against the workspace tree using the content of the local tree at root.
+func (c *FilesClient) Push(ctx context.Context, plan *filesync.Plan, chunkSize int64, root string, remove bool) (written int64, err error) {
+	stream, err := c.Client.WriteFiles(ctx)
+	if err != nil {
+		return 0, err
+	}
+	send := func(req *supervisor.WriteFilesRequest) error {
+		req.Path = c.Path
+		err := stream.Send(req)
+		if err == io.EOF {
+			// the actual error is returned by CloseAndRecv
+			_, err = stream.CloseAndRecv()
+		}
+		return err
+	}
+
+	for _, entry := range plan.Entries {
+		err = send(&supervisor.WriteFilesRequest{Change: &supervisor.WriteFilesRequest_Entry{Entry: ToFileEntry(entry)}})
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, entry := range plan.Entries {
+		for _, idx := range plan.Chunks[entry.Path] {
+			offset := int64(idx) * chunkSize
+			data, err := filesync.ReadChunk(root, entry.Path, offset, filesync.ChunkLength(entry, idx, chunkSize))
+			if err != nil {
+				return 0, xerrors.Errorf("cannot read %s: %w", entry.Path, err)
+			}
+			err = send(&supervisor.WriteFilesRequest{Change: &supervisor.WriteFilesRequest_Chunk{Chunk: &supervisor.FileChunk{
+				Path:   entry.Path,
+				Offset: offset,
+				Length: int64(len(data)),
+				Digest: filesync.Digest(data),
+				Data:   data,
+			}}})
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	if remove {
+		for _, p := range plan.Removals {
+			err = send(&supervisor.WriteFilesRequest{Change: &supervisor.WriteFilesRequest_Remove{Remove: p}})
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return 0, err
+	}
+	return resp.Written, nil
+}
+
+func ToFileEntry(entry *filesync.Entry) *supervisor.FileEntry {
+	res := &supervisor.FileEntry{
+		Path:       entry.Path,
+		Mode:       uint32(entry.Mode),
+		Size:       entry.Size,
+		Modified:   timestamppb.New(entry.ModTime),
+		LinkTarget: entry.LinkTarget,
+		Chunks:     entry.Chunks,
+	}
+	switch entry.Type {
+	case filesync.TypeDirectory:
+		res.Type = supervisor.FileType_directory
+	case filesync.TypeSymlink:
+		res.Type = supervisor.FileType_symlink
+	default:
+		res.Type = supervisor.FileType_regular
+	}
+	return res
+}
+
+func FromFileEntry(entry *supervisor.FileEntry) *filesync.Entry {
+	res := &filesync.Entry{
+		Path:       entry.Path,
+		Mode:       fs.FileMode(entry.Mode).Perm(),
+		Size:       entry.Size,
+		LinkTarget: entry.LinkTarget,
+		Chunks:     entry.Chunks,
+	}
+	if entry.Modified != nil {
+		res.ModTime = entry.Modified.AsTime()
+	}
+	switch entry.Type {
+	case supervisor.FileType_directory:
+		res.Type = filesync.TypeDirectory
+	case supervisor.FileType_symlink:
+		res.Type = filesync.TypeSymlink
+	default:
+		res.Type = filesync.TypeRegular
+	}
+	return res
+}
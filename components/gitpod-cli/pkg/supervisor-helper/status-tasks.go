@@ -28,15 +28,45 @@ func GetTasksList(ctx context.Context) ([]*supervisor.TaskStatus, error) {
 	return resp.GetTasks(), nil
 }
 
-func GetTasksListByState(ctx context.Context, filterState supervisor.TaskState) ([]*supervisor.TaskStatus, error) {
+// RunningTaskStates are the states of tasks which have an active terminal.
+var RunningTaskStates = []supervisor.TaskState{
+	supervisor.TaskState_running,
+	supervisor.TaskState_ready,
+	supervisor.TaskState_unhealthy,
+}
+
+// IsRunningTaskState returns true if tasks in the given state have an active terminal.
+func IsRunningTaskState(state supervisor.TaskState) bool {
+	for _, s := range RunningTaskStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// FindTask returns the task referenced by its terminal ID or its name, or nil if there is none.
+func FindTask(tasks []*supervisor.TaskStatus, id string) *supervisor.TaskStatus {
+	for _, task := range tasks {
+		if task.Terminal == id || task.GetPresentation().GetName() == id {
+			return task
+		}
+	}
+	return nil
+}
+
+func GetTasksListByState(ctx context.Context, filterStates ...supervisor.TaskState) ([]*supervisor.TaskStatus, error) {
 	tasks, err := GetTasksList(ctx)
 	if err != nil {
 		return nil, err
 	}
 	var filteredTasks []*supervisor.TaskStatus
 	for _, task := range tasks {
-		if task.State == filterState {
-			filteredTasks = append(filteredTasks, task)
+		for _, filterState := range filterStates {
+			if task.State == filterState {
+				filteredTasks = append(filteredTasks, task)
+				break
+			}
 		}
 	}
 	return filteredTasks, nil
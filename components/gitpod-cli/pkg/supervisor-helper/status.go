@@ -21,3 +21,8 @@ func GetWorkspaceResources(ctx context.Context, conn *grpc.ClientConn) (*supervi
 
 	return workspaceResources, nil
 }
+
+func ObserveWorkspaceResources(ctx context.Context, conn *grpc.ClientConn, history bool) (supervisor.StatusService_ObserveResourcesStatusClient, error) {
+	client := supervisor.NewStatusServiceClient(conn)
+	return client.ObserveResourcesStatus(ctx, &supervisor.ObserveResourcesStatusRequest{History: history})
+}
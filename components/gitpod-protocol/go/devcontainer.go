@@ -0,0 +1,353 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// DevcontainerLocations are the locations of devcontainer.json relative to the repository root, in order of precedence.
+var DevcontainerLocations = []string{".devcontainer/devcontainer.json", ".devcontainer.json"}
+
+// Devcontainer is the part of a devcontainer.json (https://containers.dev/implementors/json_reference/)
+// which has a Gitpod equivalent.
+type Devcontainer struct {
+	Image string `json:"image,omitempty"`
+	Build *struct {
+		Dockerfile string `json:"dockerfile,omitempty"`
+		Context    string `json:"context,omitempty"`
+	} `json:"build,omitempty"`
+	// DockerFile and Context are the deprecated top-level variants of build.dockerfile and build.context
+	DockerFile string `json:"dockerFile,omitempty"`
+	Context    string `json:"context,omitempty"`
+
+	ForwardPorts    []interface{}                           `json:"forwardPorts,omitempty"`
+	PortsAttributes map[string]*DevcontainerPortsAttributes `json:"portsAttributes,omitempty"`
+
+	OnCreateCommand      interface{} `json:"onCreateCommand,omitempty"`
+	UpdateContentCommand interface{} `json:"updateContentCommand,omitempty"`
+	PostCreateCommand    interface{} `json:"postCreateCommand,omitempty"`
+	PostStartCommand     interface{} `json:"postStartCommand,omitempty"`
+
+	ContainerEnv map[string]string `json:"containerEnv,omitempty"`
+	RemoteEnv    map[string]string `json:"remoteEnv,omitempty"`
+
+	Customizations *struct {
+		Vscode *struct {
+			Extensions []string `json:"extensions,omitempty"`
+		} `json:"vscode,omitempty"`
+	} `json:"customizations,omitempty"`
+	// Extensions is the deprecated top-level variant of customizations.vscode.extensions
+	Extensions []string `json:"extensions,omitempty"`
+
+	// unsupported lists the properties which have no Gitpod equivalent
+	unsupported []string
+}
+
+// DevcontainerPortsAttributes configures a forwarded port.
+type DevcontainerPortsAttributes struct {
+	Label         string `json:"label,omitempty"`
+	OnAutoForward string `json:"onAutoForward,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// devcontainerProperties are the properties of devcontainer.json which are either converted or safe to ignore.
+var devcontainerProperties = map[string]struct{}{
+	"$schema": {}, "name": {}, "image": {}, "build": {}, "dockerFile": {}, "context": {},
+	"forwardPorts": {}, "portsAttributes": {},
+	"onCreateCommand": {}, "updateContentCommand": {}, "postCreateCommand": {}, "postStartCommand": {},
+	"containerEnv": {}, "remoteEnv": {}, "customizations": {}, "extensions": {}, "settings": {},
+}
+
+// ParseDevcontainer parses the content of a devcontainer.json file, which may contain comments and trailing commas.
+func ParseDevcontainer(content []byte) (*Devcontainer, error) {
+	content = StripJSONComments(content)
+
+	var res Devcontainer
+	err := json.Unmarshal(content, &res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse devcontainer.json: %w", err)
+	}
+	var properties map[string]json.RawMessage
+	err = json.Unmarshal(content, &properties)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse devcontainer.json: %w", err)
+	}
+	for name := range properties {
+		if _, supported := devcontainerProperties[name]; !supported {
+			res.unsupported = append(res.unsupported, name)
+		}
+	}
+	sort.Strings(res.unsupported)
+	return &res, nil
+}
+
+var devcontainerOnAutoForward = map[string]string{
+	"notify":      "notify",
+	"openBrowser": "open-browser",
+	"openPreview": "open-preview",
+	"silent":      "ignore",
+	"ignore":      "ignore",
+}
+
+var devcontainerPortRangeRegexp = regexp.MustCompile(`^\d+-\d+$`)
+
+// ToGitpodConfig converts the devcontainer configuration to its Gitpod equivalent. Paths in a devcontainer.json
+// are relative to the directory it is located in, which is given relative to the repository root by dir.
+// The returned warnings describe the parts of the configuration which could not be converted.
+func (d *Devcontainer) ToGitpodConfig(dir string) (config *GitpodConfig, warnings []string) {
+	config = &GitpodConfig{}
+	for _, name := range d.unsupported {
+		warnings = append(warnings, fmt.Sprintf("%s is not supported", name))
+	}
+
+	dockerfile, context := d.DockerFile, d.Context
+	if d.Build != nil {
+		if d.Build.Dockerfile != "" {
+			dockerfile = d.Build.Dockerfile
+		}
+		if d.Build.Context != "" {
+			context = d.Build.Context
+		}
+	}
+	if dockerfile != "" {
+		image := Image_object{File: path.Join(dir, dockerfile)}
+		if context = path.Join(dir, context); context != "." {
+			image.Context = context
+		}
+		config.Image = image
+		if d.Image != "" {
+			warnings = append(warnings, "image is ignored in favour of the Dockerfile")
+		}
+	} else if d.Image != "" {
+		config.Image = d.Image
+	}
+
+	ports := make(map[string]*PortsItems)
+	addPort := func(port string) *PortsItems {
+		if p, exists := ports[port]; exists {
+			return p
+		}
+		p := &PortsItems{Port: port}
+		if n, err := strconv.Atoi(port); err == nil {
+			p.Port = n
+		}
+		ports[port] = p
+		config.Ports = append(config.Ports, p)
+		return p
+	}
+	for _, port := range d.ForwardPorts {
+		switch p := port.(type) {
+		case float64:
+			addPort(strconv.Itoa(int(p)))
+		case string:
+			host, port, found := strings.Cut(p, ":")
+			if _, err := strconv.Atoi(port); !found || err != nil || (host != "localhost" && host != "127.0.0.1") {
+				warnings = append(warnings, fmt.Sprintf("forwardPorts: %s is not supported, only ports of the workspace can be forwarded", p))
+				continue
+			}
+			addPort(port)
+		}
+	}
+	attributes := make([]string, 0, len(d.PortsAttributes))
+	for port := range d.PortsAttributes {
+		attributes = append(attributes, port)
+	}
+	sort.Strings(attributes)
+	for _, port := range attributes {
+		attrs := d.PortsAttributes[port]
+		if _, err := strconv.Atoi(port); err != nil && !devcontainerPortRangeRegexp.MatchString(port) {
+			warnings = append(warnings, fmt.Sprintf("portsAttributes: %s is not supported, only ports and port ranges are", port))
+			continue
+		}
+		p := addPort(port)
+		if attrs == nil {
+			continue
+		}
+		p.Name = attrs.Label
+		if attrs.OnAutoForward != "" {
+			onOpen, ok := devcontainerOnAutoForward[attrs.OnAutoForward]
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("portsAttributes: onAutoForward %s of port %s is not supported", attrs.OnAutoForward, port))
+			}
+			p.OnOpen = onOpen
+		}
+		if attrs.Protocol == "http" || attrs.Protocol == "https" {
+			p.Protocol = attrs.Protocol
+		}
+	}
+
+	var task TasksItems
+	var init []string
+	for _, command := range []struct {
+		Name  string
+		Value interface{}
+	}{
+		{"onCreateCommand", d.OnCreateCommand},
+		{"updateContentCommand", d.UpdateContentCommand},
+		{"postCreateCommand", d.PostCreateCommand},
+	} {
+		cmd, err := devcontainerCommand(command.Value)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", command.Name, err))
+		}
+		if cmd != "" {
+			init = append(init, cmd)
+		}
+	}
+	task.Init = strings.Join(init, "\n")
+	cmd, err := devcontainerCommand(d.PostStartCommand)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("postStartCommand: %v", err))
+	}
+	task.Command = cmd
+
+	env := make(map[string]string, len(d.ContainerEnv)+len(d.RemoteEnv))
+	for name, value := range d.ContainerEnv {
+		env[name] = value
+	}
+	for name, value := range d.RemoteEnv {
+		env[name] = value
+	}
+	if len(env) > 0 {
+		task.Env = make(map[string]interface{}, len(env))
+		for name, value := range env {
+			if strings.Contains(value, "${") {
+				warnings = append(warnings, fmt.Sprintf("variables in the value of environment variable %s are not supported", name))
+			}
+			task.Env[name] = value
+		}
+	}
+	if task.Init != "" || task.Command != "" || len(task.Env) > 0 {
+		task.Name = "Dev Container"
+		config.Tasks = append(config.Tasks, &task)
+	}
+
+	extensions := d.Extensions
+	if d.Customizations != nil && d.Customizations.Vscode != nil && len(d.Customizations.Vscode.Extensions) > 0 {
+		extensions = d.Customizations.Vscode.Extensions
+	}
+	if len(extensions) > 0 {
+		config.Vscode = &Vscode{Extensions: extensions}
+	}
+
+	sort.Strings(warnings)
+	return config, warnings
+}
+
+// devcontainerCommand converts a lifecycle command of devcontainer.json to a shell command. Commands can be a string,
+// an array of arguments or an object of commands which are run in parallel.
+func devcontainerCommand(command interface{}) (string, error) {
+	switch c := command.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return c, nil
+	case []interface{}:
+		args := make([]string, 0, len(c))
+		for _, arg := range c {
+			s, ok := arg.(string)
+			if !ok {
+				return "", xerrors.Errorf("arguments must be strings")
+			}
+			args = append(args, shellQuote(s))
+		}
+		return strings.Join(args, " "), nil
+	case map[string]interface{}:
+		names := make([]string, 0, len(c))
+		for name := range c {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var commands []string
+		for _, name := range names {
+			cmd, err := devcontainerCommand(c[name])
+			if err != nil {
+				return "", err
+			}
+			if _, nested := c[name].(map[string]interface{}); nested {
+				return "", xerrors.Errorf("nested commands are not supported")
+			}
+			commands = append(commands, "("+cmd+") &")
+		}
+		if len(commands) == 0 {
+			return "", nil
+		}
+		return strings.Join(append(commands, "wait"), "\n"), nil
+	default:
+		return "", xerrors.Errorf("unsupported command %v", command)
+	}
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@%+,", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// StripJSONComments removes comments and trailing commas from JSON with comments (JSONC), as used by devcontainer.json.
+func StripJSONComments(content []byte) []byte {
+	var (
+		res      = make([]byte, 0, len(content))
+		inString bool
+		// comma is the position of a pending comma in res which is dropped if followed by a closing bracket
+		comma = -1
+	)
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if inString {
+			res = append(res, c)
+			if c == '\\' && i+1 < len(content) {
+				i++
+				res = append(res, content[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			if i < len(content) {
+				res = append(res, '\n')
+			}
+			continue
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			i += 2
+			for i+1 < len(content) && !(content[i] == '*' && content[i+1] == '/') {
+				i++
+			}
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			res = append(res, c)
+			continue
+		case (c == '}' || c == ']') && comma >= 0:
+			res[comma] = ' '
+		}
+		comma = -1
+		if c == ',' {
+			comma = len(res)
+		} else if c == '"' {
+			inString = true
+		}
+		res = append(res, c)
+	}
+	return res
+}
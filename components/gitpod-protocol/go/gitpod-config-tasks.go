@@ -0,0 +1,187 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package protocol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// ResolveTaskDependencies resolves the dependsOn references of the given tasks by name.
+// The result holds the indices of the tasks each task depends on.
+// An error is returned if a dependency refers to an unknown or ambiguous task name
+// or if the dependencies form a cycle.
+func ResolveTaskDependencies(tasks []TasksItems) ([][]int, error) {
+	byName := make(map[string]int, len(tasks))
+	ambiguous := make(map[string]struct{})
+	for i, task := range tasks {
+		if task.Name == "" {
+			continue
+		}
+		if _, exists := byName[task.Name]; exists {
+			ambiguous[task.Name] = struct{}{}
+		}
+		byName[task.Name] = i
+	}
+
+	deps := make([][]int, len(tasks))
+	for i, task := range tasks {
+		for _, name := range task.DependsOn {
+			if _, ok := ambiguous[name]; ok {
+				return nil, xerrors.Errorf("task %s depends on %s, but there are multiple tasks with that name", taskName(tasks, i), name)
+			}
+			dep, ok := byName[name]
+			if !ok {
+				return nil, xerrors.Errorf("task %s depends on unknown task %s", taskName(tasks, i), name)
+			}
+			deps[i] = append(deps[i], dep)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	var (
+		state = make([]int, len(tasks))
+		path  []int
+		visit func(i int) error
+	)
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			var cycle []string
+			for j := len(path) - 1; j >= 0; j-- {
+				cycle = append([]string{taskName(tasks, path[j])}, cycle...)
+				if path[j] == i {
+					break
+				}
+			}
+			cycle = append(cycle, taskName(tasks, i))
+			return xerrors.Errorf("task dependencies form a cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[i] = visiting
+		path = append(path, i)
+		for _, dep := range deps[i] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		return nil
+	}
+	for i := range tasks {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
+}
+
+func taskName(tasks []TasksItems, i int) string {
+	if tasks[i].Name != "" {
+		return tasks[i].Name
+	}
+	return "#" + strconv.Itoa(i)
+}
+
+// ComposeCommandOptions configures how ComposeCommand combines commands.
+type ComposeCommandOptions struct {
+	Commands []*string
+	// Format is applied to each command
+	Format string
+	// Sep separates the formatted commands
+	Sep string
+}
+
+// ComposeCommand combines the non-empty commands to a single command, as done for the
+// before, init, prebuild and command of a task.
+func ComposeCommand(options ComposeCommandOptions) string {
+	var commands []string
+	for _, command := range options.Commands {
+		if command != nil && strings.TrimSpace(*command) != "" {
+			commands = append(commands, fmt.Sprintf(options.Format, *command))
+		}
+	}
+	return strings.Join(commands, options.Sep)
+}
+
+// TaskPhase is a phase of the workspace lifecycle in which a task runs its commands.
+type TaskPhase string
+
+const (
+	// TaskPhasePrebuild runs before, init and prebuild in a prebuild.
+	TaskPhasePrebuild TaskPhase = "prebuild"
+	// TaskPhaseStart runs before, init and command when a workspace starts without a prebuild.
+	TaskPhaseStart TaskPhase = "start"
+	// TaskPhaseStartFromPrebuild runs before, the prebuild log and command when a workspace starts from a prebuild.
+	TaskPhaseStartFromPrebuild TaskPhase = "start from prebuild"
+	// TaskPhaseRestart runs before and command when a workspace or a task is restarted.
+	TaskPhaseRestart TaskPhase = "restart"
+)
+
+// TaskCommands are the commands of a task.
+type TaskCommands struct {
+	Before   *string
+	Init     *string
+	Prebuild *string
+	Command  *string
+	// PrebuildLog prints the output of the prebuild when a workspace starts from a prebuild
+	PrebuildLog *string
+}
+
+// TaskCommandOptions configures how TaskCommands.Compose composes the command of a task.
+type TaskCommandOptions struct {
+	// ExitCodeFile is the file the exit code of the commands is written to, s.t. dependent tasks
+	// can wait for them even though the terminal of the task stays open.
+	ExitCodeFile string
+	// Exit closes the terminal once the commands have finished, e.g. in prebuilds or for tasks which are restarted.
+	Exit bool
+}
+
+// Phase returns the commands a task runs in a phase, including empty ones.
+func (c TaskCommands) Phase(phase TaskPhase) []*string {
+	switch phase {
+	case TaskPhasePrebuild:
+		return []*string{c.Before, c.Init, c.Prebuild}
+	case TaskPhaseStartFromPrebuild:
+		return []*string{c.Before, c.PrebuildLog, c.Command}
+	case TaskPhaseRestart:
+		return []*string{c.Before, c.Command}
+	default:
+		return []*string{c.Before, c.Init, c.Command}
+	}
+}
+
+// Compose composes the command a task runs in a phase. The result is empty if the task has no commands
+// in that phase and does not write an exit code.
+func (c TaskCommands) Compose(phase TaskPhase, options TaskCommandOptions) string {
+	command := ComposeCommand(ComposeCommandOptions{
+		Commands: c.Phase(phase),
+		Format:   "{\n%s\n}",
+		Sep:      " && ",
+	})
+	if options.ExitCodeFile != "" {
+		// the space at the beginning prevents the command from appearing in the bash history.
+		exitCodeCommand := " echo $? > " + options.ExitCodeFile
+		if strings.TrimSpace(command) == "" {
+			command = exitCodeCommand
+		} else {
+			command += ";" + exitCodeCommand
+		}
+	}
+	if options.Exit && strings.TrimSpace(command) != "" {
+		command += "; exit"
+	}
+	return command
+}
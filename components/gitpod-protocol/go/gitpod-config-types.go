@@ -27,10 +27,6 @@ type CoreDump struct {
 	SoftLimit float64 `yaml:"softLimit,omitempty" json:"softLimit,omitempty"`
 }
 
-// Env Environment variables to set.
-type Env struct {
-}
-
 // Github Configures Gitpod's GitHub app
 type Github struct {
 
@@ -50,6 +46,9 @@ type GitpodConfig struct {
 	// Configure the default action of certain signals is to cause a process to terminate and produce a core dump file, a file containing an image of the process's memory at the time of termination. Disabled by default.
 	CoreDump *CoreDump `yaml:"coreDump,omitempty" json:"coreDump,omitempty"`
 
+	// Paths of dotenv files relative to the repository root to load environment variables from for the IDE, tasks and SSH sessions. Later files take precedence. Values may reference user environment variables as `${secret:NAME}`.
+	EnvFile []string `yaml:"envFile,omitempty" json:"envFile,omitempty"`
+
 	// Experimental network configuration in workspaces (deprecated). Enabled by default
 	ExperimentalNetwork bool `yaml:"experimentalNetwork,omitempty" json:"experimentalNetwork,omitempty"`
 
@@ -65,12 +64,21 @@ type GitpodConfig struct {
 	// Configure JetBrains integration
 	Jetbrains *Jetbrains `yaml:"jetbrains,omitempty" json:"jetbrains,omitempty"`
 
+	// Commands to run when the workspace reaches a lifecycle event. Hooks run as the gitpod user in the repository root, their results are recorded in the supervisor logs and, in prebuilds, in the headless log.
+	Lifecycle *Lifecycle `yaml:"lifecycle,omitempty" json:"lifecycle,omitempty"`
+
 	// The main repository, containing the dev environment configuration.
 	MainConfiguration string `yaml:"mainConfiguration,omitempty" json:"mainConfiguration,omitempty"`
 
 	// List of exposed ports.
 	Ports []*PortsItems `yaml:"ports,omitempty" json:"ports,omitempty"`
 
+	// Configure when you are notified about resource pressure in the workspace. Thresholds are percentages, alerts are raised once a threshold has been exceeded for 30 seconds.
+	ResourceAlerts *ResourceAlerts `yaml:"resourceAlerts,omitempty" json:"resourceAlerts,omitempty"`
+
+	// Service containers, e.g. databases, to run next to the workspace. Services are started with Docker once the workspace content is ready, restarted when they exit or become unhealthy, and their ports are exposed under the name of the service.
+	Services []*ServicesItems `yaml:"services,omitempty" json:"services,omitempty"`
+
 	// List of tasks to run on start. Each task will open a terminal in the IDE.
 	Tasks []*TasksItems `yaml:"tasks,omitempty" json:"tasks,omitempty"`
 
@@ -81,6 +89,32 @@ type GitpodConfig struct {
 	WorkspaceLocation string `yaml:"workspaceLocation,omitempty" json:"workspaceLocation,omitempty"`
 }
 
+// Healthcheck A command run in the container to determine whether the service is healthy. Unhealthy services are restarted.
+type Healthcheck struct {
+
+	// The shell command to run in the container, e.g. `pg_isready -U postgres`.
+	Command string `yaml:"command" json:"command"`
+
+	// Duration between two checks, e.g. `10s`. Defaults to 10s.
+	Interval string `yaml:"interval,omitempty" json:"interval,omitempty"`
+
+	// Number of consecutive failed checks after which the service is unhealthy. Defaults to 3.
+	Retries float64 `yaml:"retries,omitempty" json:"retries,omitempty"`
+
+	// Duration after which a check is considered failed. Defaults to 5s.
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// Http The task is ready once a GET request to this port and path responds with a 2xx status code.
+type Http struct {
+
+	// The path to request. Default is '/'.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// The port to send the request to.
+	Port float64 `yaml:"port" json:"port"`
+}
+
 // Image_object The Docker image to run your workspace in.
 type Image_object struct {
 
@@ -135,13 +169,45 @@ type JetbrainsProduct struct {
 	Vmoptions string `yaml:"vmoptions,omitempty" json:"vmoptions,omitempty"`
 }
 
+// Lifecycle Commands to run when the workspace reaches a lifecycle event. Hooks run as the gitpod user in the repository root, their results are recorded in the supervisor logs and, in prebuilds, in the headless log.
+type Lifecycle struct {
+
+	// Duration without activity after which `onIdle` runs, e.g. `30m`. Defaults to 15m.
+	IdleAfter string `yaml:"idleAfter,omitempty" json:"idleAfter,omitempty"`
+
+	// Runs once the workspace content has been initialized.
+	OnContentReady *LifecycleHook `yaml:"onContentReady,omitempty" json:"onContentReady,omitempty"`
+
+	// Runs once there was neither terminal input nor an SSH connection for the time given by `idleAfter`. Runs again after the workspace has been used in between.
+	OnIdle *LifecycleHook `yaml:"onIdle,omitempty" json:"onIdle,omitempty"`
+
+	// Runs once the IDE is ready.
+	PostStart *LifecycleHook `yaml:"postStart,omitempty" json:"postStart,omitempty"`
+
+	// Runs when the workspace is stopping, before the terminals are closed and the workspace content is backed up, e.g. to flush a local database to disk. Defaults to a timeout of 30s, the timeout is capped at half of the termination grace period of the workspace.
+	PreStop *LifecycleHook `yaml:"preStop,omitempty" json:"preStop,omitempty"`
+}
+
+// LifecycleHook
+type LifecycleHook struct {
+
+	// The shell command to run.
+	Command string `yaml:"command" json:"command"`
+
+	// Duration after which the command is killed, e.g. `2m`. Defaults to 5m, respectively 30s for `preStop`.
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
 // PortsItems
 type PortsItems struct {
 
+	// The IP ranges (e.g. 203.0.113.0/24) clients must connect from to access a protected port.
+	AllowedCIDRs []string `yaml:"allowedCIDRs,omitempty" json:"allowedCIDRs,omitempty"`
+
 	// A description to identify what is this port used for.
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 
-	// Port name.
+	// Port name. Naming a port range makes it a port group, e.g. 'storybook' for 6006-6010.
 	Name string `yaml:"name,omitempty" json:"name,omitempty"`
 
 	// What to do when a service on this port was detected. 'notify' (default) will show a notification asking the user what to do. 'open-browser' will open a new browser tab. 'open-preview' will open in the preview on the right of the IDE. 'ignore' will do nothing.
@@ -150,10 +216,10 @@ type PortsItems struct {
 	// The port number (e.g. 1337) or range (e.g. 3000-3999) to expose.
 	Port interface{} `yaml:"port" json:"port"`
 
-	// The protocol to be used. (deprecated)
+	// The protocol of the service served on this port. 'http' (default), 'https' for services terminating TLS themselves or 'tcp' for services not speaking HTTP.
 	Protocol string `yaml:"protocol,omitempty" json:"protocol,omitempty"`
 
-	// Whether the port visibility should be private or public. 'private' (default) will only allow users with workspace access to access the port. 'public' will allow everyone with the port URL to access the port.
+	// Whether the port visibility should be private, public or protected. 'private' (default) will only allow users with workspace access to access the port. 'public' will allow everyone with the port URL to access the port. 'protected' will allow everyone meeting the port's access policy, e.g. connecting from one of the allowedCIDRs.
 	Visibility string `yaml:"visibility,omitempty" json:"visibility,omitempty"`
 }
 
@@ -189,6 +255,70 @@ type Prebuilds_object struct {
 	PullRequestsFromForks bool `yaml:"pullRequestsFromForks,omitempty" json:"pullRequestsFromForks,omitempty"`
 }
 
+// Readiness A probe which determines when the task is ready. Tasks which depend on this task are started once it is ready. If multiple checks are configured, all of them have to succeed.
+type Readiness struct {
+
+	// The task is ready once a GET request to this port and path responds with a 2xx status code.
+	Http *Http `yaml:"http,omitempty" json:"http,omitempty"`
+
+	// The task is ready once its terminal output matches this regular expression.
+	LogPattern string `yaml:"logPattern,omitempty" json:"logPattern,omitempty"`
+
+	// The task is ready once this port accepts TCP connections.
+	Port float64 `yaml:"port,omitempty" json:"port,omitempty"`
+
+	// Seconds after which a task which has not become ready is considered unhealthy. By default the task is awaited indefinitely.
+	Timeout float64 `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+}
+
+// ResourceAlerts Configure when you are notified about resource pressure in the workspace. Thresholds are percentages, alerts are raised once a threshold has been exceeded for 30 seconds.
+type ResourceAlerts struct {
+
+	// Thresholds for the CPU usage in percent of the workspace limit. Defaults to 80 (warning) and 95 (danger).
+	Cpu *ResourceThresholds `yaml:"cpu,omitempty" json:"cpu,omitempty"`
+
+	// Set to true to disable resource alerts.
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+
+	// Thresholds for the memory usage in percent of the workspace limit. Defaults to 80 (warning) and 95 (danger).
+	Memory *ResourceThresholds `yaml:"memory,omitempty" json:"memory,omitempty"`
+
+	// Thresholds for the pressure stall information (PSI) of CPU, memory and I/O, i.e. the percentage of time some processes were stalled over the last 10 seconds. Alerts on pressure are disabled unless configured, defaults to 10 (warning) and 40 (danger).
+	Pressure *ResourceThresholds `yaml:"pressure,omitempty" json:"pressure,omitempty"`
+}
+
+// ResourceThresholds
+type ResourceThresholds struct {
+
+	// Usage in percent at which an error is raised.
+	Danger float64 `yaml:"danger,omitempty" json:"danger,omitempty"`
+
+	// Usage in percent at which a warning is raised.
+	Warning float64 `yaml:"warning,omitempty" json:"warning,omitempty"`
+}
+
+// ServicesItems
+type ServicesItems struct {
+
+	// Environment variables to set in the container.
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+
+	// A command run in the container to determine whether the service is healthy. Unhealthy services are restarted.
+	Healthcheck *Healthcheck `yaml:"healthcheck,omitempty" json:"healthcheck,omitempty"`
+
+	// The Docker image to run, e.g. `postgres:14`.
+	Image string `yaml:"image" json:"image"`
+
+	// Name of the service. Used to name its container and ports.
+	Name string `yaml:"name" json:"name"`
+
+	// Ports of the container to publish on the same port of the workspace.
+	Ports []float64 `yaml:"ports,omitempty" json:"ports,omitempty"`
+
+	// Volumes to mount in the form `source:target[:options]`. Sources starting with `./` are relative to the repository root, e.g. `./.pgdata:/var/lib/postgresql/data`.
+	Volumes []string `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+}
+
 // TasksItems
 type TasksItems struct {
 
@@ -198,12 +328,21 @@ type TasksItems struct {
 	// The main shell command to run after `before` and `init`. This command is executed last on every start and doesn't have to terminate.
 	Command string `yaml:"command,omitempty" json:"command,omitempty"`
 
+	// Names of the tasks which have to complete before this task is started.
+	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+
 	// Environment variables to set.
-	Env *Env `yaml:"env,omitempty" json:"env,omitempty"`
+	Env map[string]interface{} `yaml:"env,omitempty" json:"env,omitempty"`
+
+	// Paths of dotenv files relative to the repository root to load environment variables from. Later files take precedence, `env` takes precedence over all files. Values may reference user environment variables as `${secret:NAME}`.
+	EnvFile []string `yaml:"envFile,omitempty" json:"envFile,omitempty"`
 
 	// A shell command to run between `before` and the main `command`. This command is executed only on after initializing a workspace with a fresh clone, but not on restarts and snapshots. This command is expected to terminate. If it fails, the `command` property will not be executed.
 	Init string `yaml:"init,omitempty" json:"init,omitempty"`
 
+	// The maximum number of restarts of the task terminal. Default is 10.
+	MaxRestarts float64 `yaml:"maxRestarts,omitempty" json:"maxRestarts,omitempty"`
+
 	// Name of the task. Shown on the tab of the opened terminal.
 	Name string `yaml:"name,omitempty" json:"name,omitempty"`
 
@@ -215,6 +354,12 @@ type TasksItems struct {
 
 	// A shell command to run after `before`. This command is executed only on during workspace prebuilds. This command is expected to terminate. If it fails, the workspace build fails.
 	Prebuild string `yaml:"prebuild,omitempty" json:"prebuild,omitempty"`
+
+	// A probe which determines when the task is ready. Tasks which depend on this task are started once it is ready. If multiple checks are configured, all of them have to succeed.
+	Readiness *Readiness `yaml:"readiness,omitempty" json:"readiness,omitempty"`
+
+	// Whether the task terminal should be restarted once the task's commands have exited. 'on-failure' restarts it if the commands exited with a non-zero exit code, 'always' restarts it regardless of the exit code. Restarts are delayed with an exponential backoff. Default is 'never'.
+	Restart string `yaml:"restart,omitempty" json:"restart,omitempty"`
 }
 
 // Vscode Configure VS Code integration
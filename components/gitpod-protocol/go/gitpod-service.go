@@ -1913,16 +1913,27 @@ type GetWorkspaceTimeoutResult struct {
 
 // WorkspaceInstancePort is the WorkspaceInstancePort message type
 type WorkspaceInstancePort struct {
-	Port       float64 `json:"port,omitempty"`
-	URL        string  `json:"url,omitempty"`
-	Visibility string  `json:"visibility,omitempty"`
+	Port         float64           `json:"port,omitempty"`
+	URL          string            `json:"url,omitempty"`
+	Visibility   string            `json:"visibility,omitempty"`
+	AccessPolicy *PortAccessPolicy `json:"accessPolicy,omitempty"`
 }
 
 const (
-	PortVisibilityPublic  = "public"
-	PortVisibilityPrivate = "private"
+	PortVisibilityPublic    = "public"
+	PortVisibilityPrivate   = "private"
+	PortVisibilityProtected = "protected"
 )
 
+// PortAccessPolicy restricts access to protected ports. If multiple requirements are configured, all must be met.
+type PortAccessPolicy struct {
+	// BasicAuth are htpasswd entries with bcrypt hashed passwords, e.g. user:$2y$...
+	// If nil, a protected port keeps its current basic auth, an empty list removes it.
+	BasicAuth []string `json:"basicAuth"`
+	// AllowedCIDRs are the IP ranges clients must connect from
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
 // GithubAppConfig is the GithubAppConfig message type
 type GithubAppConfig struct {
 	Prebuilds *GithubAppPrebuildConfig `json:"prebuilds,omitempty"`
@@ -1948,13 +1959,21 @@ type ImageConfigFile struct {
 
 // PortConfig is the PortConfig message type
 type PortConfig struct {
-	OnOpen      string  `json:"onOpen,omitempty"`
-	Port        float64 `json:"port,omitempty"`
-	Visibility  string  `json:"visibility,omitempty"`
-	Description string  `json:"description,omitempty"`
-	Name        string  `json:"name,omitempty"`
+	OnOpen       string   `json:"onOpen,omitempty"`
+	Port         float64  `json:"port,omitempty"`
+	Visibility   string   `json:"visibility,omitempty"`
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+	Protocol     string   `json:"protocol,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Name         string   `json:"name,omitempty"`
 }
 
+const (
+	PortProtocolHTTP  = "http"
+	PortProtocolHTTPS = "https"
+	PortProtocolTCP   = "tcp"
+)
+
 // TaskConfig is the TaskConfig message type
 type TaskConfig struct {
 	Before   string                 `json:"before,omitempty"`
@@ -32,6 +32,9 @@ sed -i '5,10d' "$GITPOD_CONFIG_TYPE_PATH" #imports
 sed -i -E 's/(json:)(".*")/yaml:\2 \1\2/g' "$GITPOD_CONFIG_TYPE_PATH"
 gofmt -w "$GITPOD_CONFIG_TYPE_PATH"
 
+# the validator embeds the schema
+cp "$CONFIG_PATH" "$COMPONENT_PATH/validate/gitpod-schema.json"
+
 if [ "${LEEWAY_BUILD-}" == "true" ]; then
     ./_deps/dev-addlicense--app/addlicense "$GITPOD_CONFIG_TYPE_PATH"
 else
@@ -0,0 +1,322 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package validate
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// gitpodSchemaJSON is a copy of components/gitpod-protocol/data/gitpod-schema.json, kept in sync by scripts/generate-config.sh.
+//
+//go:embed gitpod-schema.json
+var gitpodSchemaJSON []byte
+
+var gitpodSchema = mustParseSchema(gitpodSchemaJSON)
+
+// schema is the subset of JSON schema (draft 7) used by the Gitpod config schema.
+type schema struct {
+	Ref                  string                `json:"$ref"`
+	Type                 schemaTypes           `json:"type"`
+	Enum                 []interface{}         `json:"enum"`
+	Pattern              string                `json:"pattern"`
+	Minimum              *float64              `json:"minimum"`
+	Maximum              *float64              `json:"maximum"`
+	Properties           map[string]*schema    `json:"properties"`
+	Required             []string              `json:"required"`
+	AdditionalProperties *additionalProperties `json:"additionalProperties"`
+	Items                *schema               `json:"items"`
+	DeprecationMessage   string                `json:"deprecationMessage"`
+	Definitions          map[string]*schema    `json:"definitions"`
+
+	pattern *regexp.Regexp
+}
+
+// schemaTypes is the type of a schema, which is either a single type or a list of types.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var multiple []string
+	err := json.Unmarshal(data, &multiple)
+	*t = multiple
+	return err
+}
+
+// additionalProperties is either a boolean or a schema for the values of additional properties.
+type additionalProperties struct {
+	Allowed bool
+	Schema  *schema
+}
+
+func (a *additionalProperties) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+func mustParseSchema(data []byte) *schema {
+	var res schema
+	err := json.Unmarshal(data, &res)
+	if err != nil {
+		panic(fmt.Sprintf("cannot parse gitpod config schema: %v", err))
+	}
+	res.compile()
+	return &res
+}
+
+// compile compiles the patterns of the schema and its subschemas. Patterns which are no valid Go
+// regular expressions are ignored.
+func (s *schema) compile() {
+	if s == nil {
+		return
+	}
+	if s.Pattern != "" {
+		s.pattern, _ = regexp.Compile(s.Pattern)
+	}
+	for _, p := range s.Properties {
+		p.compile()
+	}
+	for _, d := range s.Definitions {
+		d.compile()
+	}
+	if s.AdditionalProperties != nil {
+		s.AdditionalProperties.Schema.compile()
+	}
+	s.Items.compile()
+}
+
+type validator struct {
+	schema      *schema
+	diagnostics []Diagnostic
+}
+
+func (v *validator) report(node *yaml.Node, path string, format string, args ...interface{}) {
+	v.add(node, path, SeverityError, format, args...)
+}
+
+func (v *validator) warn(node *yaml.Node, path string, format string, args ...interface{}) {
+	v.add(node, path, SeverityWarning, format, args...)
+}
+
+func (v *validator) add(node *yaml.Node, path string, severity Severity, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Line:     node.Line,
+		Column:   node.Column,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+		Severity: severity,
+	})
+}
+
+// resolve follows references to the definitions of the root schema.
+func (v *validator) resolve(s *schema) *schema {
+	for s != nil && s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/definitions/")
+		s = v.schema.Definitions[name]
+	}
+	return s
+}
+
+func (v *validator) validate(node *yaml.Node, s *schema, path string) {
+	s = v.resolve(s)
+	if s == nil {
+		return
+	}
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if s.DeprecationMessage != "" {
+		v.warn(node, path, "%s", s.DeprecationMessage)
+	}
+
+	actual := nodeType(node)
+	if len(s.Type) > 0 && !s.Type.allows(actual) {
+		v.report(node, path, "expected %s, got %s", strings.Join(s.Type, " or "), actual)
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		v.validateObject(node, s, path)
+	case yaml.SequenceNode:
+		if s.Items == nil {
+			return
+		}
+		for i, item := range node.Content {
+			v.validate(item, s.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case yaml.ScalarNode:
+		v.validateScalar(node, s, path, actual)
+	}
+}
+
+func (v *validator) validateObject(node *yaml.Node, s *schema, path string) {
+	seen := make(map[string]struct{}, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		seen[key.Value] = struct{}{}
+		propPath := key.Value
+		if path != "" {
+			propPath = path + "." + key.Value
+		}
+		if prop, ok := s.Properties[key.Value]; ok {
+			v.validate(value, prop, propPath)
+			continue
+		}
+		if s.AdditionalProperties == nil {
+			continue
+		}
+		if !s.AdditionalProperties.Allowed {
+			msg := fmt.Sprintf("unknown property %q", key.Value)
+			if suggestion := suggest(key.Value, s.Properties); suggestion != "" {
+				msg += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			v.report(key, path, "%s", msg)
+			continue
+		}
+		v.validate(value, s.AdditionalProperties.Schema, propPath)
+	}
+	for _, name := range s.Required {
+		if _, ok := seen[name]; !ok {
+			v.report(node, path, "missing required property %q", name)
+		}
+	}
+}
+
+func (v *validator) validateScalar(node *yaml.Node, s *schema, path string, actual string) {
+	if len(s.Enum) > 0 {
+		var allowed []string
+		matched := false
+		for _, e := range s.Enum {
+			allowed = append(allowed, fmt.Sprintf("%q", fmt.Sprint(e)))
+			if fmt.Sprint(e) == node.Value {
+				matched = true
+			}
+		}
+		if !matched {
+			v.report(node, path, "%q is not allowed, must be one of %s", node.Value, strings.Join(allowed, ", "))
+		}
+	}
+	switch actual {
+	case "string":
+		if s.pattern != nil && !s.pattern.MatchString(node.Value) {
+			v.report(node, path, "%q does not match the pattern %s", node.Value, s.Pattern)
+		}
+	case "integer", "number":
+		var value float64
+		if err := node.Decode(&value); err != nil {
+			return
+		}
+		if s.Minimum != nil && value < *s.Minimum {
+			v.report(node, path, "%v is less than the minimum of %v", node.Value, *s.Minimum)
+		}
+		if s.Maximum != nil && value > *s.Maximum {
+			v.report(node, path, "%v is greater than the maximum of %v", node.Value, *s.Maximum)
+		}
+	}
+}
+
+// nodeType returns the JSON schema type of a YAML node.
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch node.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+func (t schemaTypes) allows(actual string) bool {
+	for _, allowed := range t {
+		if allowed == actual || (allowed == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns the known property closest to the given unknown one, if there is a close enough one.
+func suggest(name string, properties map[string]*schema) string {
+	var (
+		res  string
+		best = 3
+	)
+	for prop := range properties {
+		if strings.EqualFold(prop, name) {
+			return prop
+		}
+		if d := levenshtein(strings.ToLower(name), strings.ToLower(prop)); d < best || (d == best && prop < res) {
+			best, res = d, prop
+		}
+	}
+	return res
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min(values ...int) int {
+	res := values[0]
+	for _, v := range values[1:] {
+		if v < res {
+			res = v
+		}
+	}
+	return res
+}
+
+// lookup returns the value of the given key of a mapping node.
+func lookup(node *yaml.Node, key string) (*yaml.Node, bool) {
+	if node.Kind != yaml.MappingNode {
+		return nil, false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+// Package validate checks .gitpod.yml files against the Gitpod config JSON schema and reports
+// problems with the line and column they occur at.
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	protocol "github.com/gitpod-io/gitpod/gitpod-protocol"
+)
+
+// Severity is the severity of a diagnostic.
+type Severity int
+
+const (
+	// SeverityError marks a problem which makes the config invalid.
+	SeverityError Severity = iota
+	// SeverityWarning marks a problem which doesn't prevent the config from being used, e.g. a deprecated property.
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Diagnostic describes a problem of a config file.
+type Diagnostic struct {
+	// Line and Column are 1-based, they are 0 if the position is unknown
+	Line   int
+	Column int
+	// Path is the path of the offending value, e.g. tasks[0].init
+	Path     string
+	Message  string
+	Severity Severity
+}
+
+func (d Diagnostic) String() string {
+	msg := d.Message
+	if d.Path != "" {
+		msg = d.Path + ": " + msg
+	}
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.Severity, msg)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Column, d.Severity, msg)
+}
+
+// HasErrors returns true if any of the diagnostics is an error.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+var yamlErrorLineRegexp = regexp.MustCompile(`^yaml: (?:line (\d+): )?(.*)$`)
+
+// Validate checks the content of a .gitpod.yml file. It reports syntax errors, type errors, unknown keys,
+// values which don't match the schema and invalid task dependencies. An empty result means the config is valid.
+func Validate(content []byte) []Diagnostic {
+	var doc yaml.Node
+	err := yaml.Unmarshal(content, &doc)
+	if err != nil {
+		d := Diagnostic{Message: err.Error(), Severity: SeverityError}
+		if m := yamlErrorLineRegexp.FindStringSubmatch(err.Error()); m != nil {
+			d.Line, _ = strconv.Atoi(m[1])
+			if d.Line > 0 {
+				d.Column = 1
+			}
+			d.Message = m[2]
+		}
+		return []Diagnostic{d}
+	}
+	if len(doc.Content) == 0 {
+		// an empty file is a valid config
+		return nil
+	}
+	root := doc.Content[0]
+
+	v := &validator{schema: gitpodSchema}
+	v.validate(root, gitpodSchema, "")
+	if !HasErrors(v.diagnostics) {
+		v.validateTasks(root)
+	}
+	sort.SliceStable(v.diagnostics, func(i, j int) bool {
+		if v.diagnostics[i].Line != v.diagnostics[j].Line {
+			return v.diagnostics[i].Line < v.diagnostics[j].Line
+		}
+		return v.diagnostics[i].Column < v.diagnostics[j].Column
+	})
+	return v.diagnostics
+}
+
+// validateTasks checks that the dependencies between tasks can be resolved.
+func (v *validator) validateTasks(root *yaml.Node) {
+	var config protocol.GitpodConfig
+	err := root.Decode(&config)
+	if err != nil {
+		v.report(root, "", "%v", err)
+		return
+	}
+	tasks := make([]protocol.TasksItems, 0, len(config.Tasks))
+	for _, task := range config.Tasks {
+		if task == nil {
+			task = &protocol.TasksItems{}
+		}
+		tasks = append(tasks, *task)
+	}
+	_, err = protocol.ResolveTaskDependencies(tasks)
+	if err != nil {
+		node, _ := lookup(root, "tasks")
+		if node == nil {
+			node = root
+		}
+		v.report(node, "tasks", "%v", err)
+	}
+}
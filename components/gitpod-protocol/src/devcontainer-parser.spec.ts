@@ -0,0 +1,123 @@
+/**
+ * Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+ * Licensed under the GNU Affero General Public License (AGPL).
+ * See License.AGPL.txt in the project root for license information.
+ */
+
+import { suite, test } from "mocha-typescript";
+import * as chai from "chai";
+
+import { DevcontainerParser, stripJSONComments } from "./devcontainer-parser";
+
+const expect = chai.expect;
+
+@suite
+class TestDevcontainerParser {
+    protected parser: DevcontainerParser;
+
+    public before() {
+        this.parser = new DevcontainerParser();
+    }
+
+    @test public testStripJSONComments() {
+        const content = `{
+            // a comment
+            "url": "http://example.com", /* another comment */
+            "list": [1, 2,],
+        }`;
+        expect(JSON.parse(stripJSONComments(content))).to.deep.equal({
+            url: "http://example.com",
+            list: [1, 2],
+        });
+    }
+
+    @test public testImageAndExtensions() {
+        const result = this.parser.parse(
+            `{
+                "name": "Go",
+                "image": "mcr.microsoft.com/devcontainers/go:1",
+                "customizations": { "vscode": { "extensions": ["golang.go"] } }
+            }`,
+            ".devcontainer.json",
+        );
+        expect(result).to.deep.equal({
+            config: {
+                image: "mcr.microsoft.com/devcontainers/go:1",
+                vscode: { extensions: ["golang.go"] },
+            },
+            warnings: [],
+        });
+    }
+
+    @test public testDockerfile() {
+        const result = this.parser.parse(
+            `{ "build": { "dockerfile": "Dockerfile", "context": ".." } }`,
+            ".devcontainer/devcontainer.json",
+        );
+        expect(result.config).to.deep.equal({
+            image: { file: ".devcontainer/Dockerfile" },
+        });
+    }
+
+    @test public testPorts() {
+        const result = this.parser.parse(
+            `{
+                "forwardPorts": [3000, "localhost:5432", "db:5432"],
+                "portsAttributes": {
+                    "3000": { "label": "Application", "onAutoForward": "openPreview", "protocol": "https" },
+                    "9000-9100": { "onAutoForward": "silent" },
+                    "[0-9]+": { "label": "regex" },
+                },
+            }`,
+            ".devcontainer/devcontainer.json",
+        );
+        expect(result).to.deep.equal({
+            config: {
+                ports: [
+                    { port: 3000, name: "Application", onOpen: "open-preview", protocol: "https" },
+                    { port: 5432 },
+                    { port: "9000-9100", onOpen: "ignore" },
+                ],
+            },
+            warnings: [
+                "forwardPorts: db:5432 is not supported, only ports of the workspace can be forwarded",
+                "portsAttributes: [0-9]+ is not supported, only ports and port ranges are",
+            ],
+        });
+    }
+
+    @test public testLifecycleCommandsAndEnv() {
+        const result = this.parser.parse(
+            `{
+                "onCreateCommand": ["npm", "install", "--prefix", "my dir"],
+                "postCreateCommand": { "build": "npm run build", "lint": "npm run lint" },
+                "postStartCommand": "npm start",
+                "postAttachCommand": "echo attached",
+                "containerEnv": { "NODE_ENV": "development", "PORT": "3000" },
+                "remoteEnv": { "PORT": "8080", "PATH": "\${containerEnv:PATH}:/opt/bin" },
+            }`,
+            ".devcontainer/devcontainer.json",
+        );
+        expect(result).to.deep.equal({
+            config: {
+                tasks: [
+                    {
+                        name: "Dev Container",
+                        init: "npm install --prefix 'my dir'\n(npm run build) &\n(npm run lint) &\nwait",
+                        command: "npm start",
+                        env: {
+                            NODE_ENV: "development",
+                            PORT: "8080",
+                            PATH: "\${containerEnv:PATH}:/opt/bin",
+                        },
+                    },
+                ],
+            },
+            warnings: [
+                "postAttachCommand is not supported",
+                "variables in the value of environment variable PATH are not supported",
+            ],
+        });
+    }
+}
+module.exports = new TestDevcontainerParser();
@@ -0,0 +1,278 @@
+/**
+ * Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+ * Licensed under the GNU Affero General Public License (AGPL).
+ * See License.AGPL.txt in the project root for license information.
+ */
+
+import { injectable } from "inversify";
+import * as path from "path";
+import { WorkspaceConfig, ImageConfigFile, PortConfig, PortRangeConfig, PortOnOpen, TaskConfig } from "./protocol";
+
+/**
+ * The locations of devcontainer.json relative to the repository root, in order of precedence.
+ */
+export const DEVCONTAINER_LOCATIONS = [".devcontainer/devcontainer.json", ".devcontainer.json"];
+
+export interface DevcontainerParseResult {
+    config: WorkspaceConfig;
+    /** describe the parts of the devcontainer.json which could not be converted */
+    warnings: string[];
+}
+
+// properties which are either converted or safe to ignore
+const supportedProperties = new Set([
+    "$schema",
+    "name",
+    "image",
+    "build",
+    "dockerFile",
+    "context",
+    "forwardPorts",
+    "portsAttributes",
+    "onCreateCommand",
+    "updateContentCommand",
+    "postCreateCommand",
+    "postStartCommand",
+    "containerEnv",
+    "remoteEnv",
+    "customizations",
+    "extensions",
+    "settings",
+]);
+
+const onAutoForward: { [key: string]: PortOnOpen } = {
+    notify: "notify",
+    openBrowser: "open-browser",
+    openPreview: "open-preview",
+    silent: "ignore",
+    ignore: "ignore",
+};
+
+/**
+ * Converts a devcontainer.json (https://containers.dev/implementors/json_reference/) to its Gitpod equivalent.
+ * This mirrors the conversion of supervisor, see components/gitpod-protocol/go/devcontainer.go.
+ */
+@injectable()
+export class DevcontainerParser {
+    /**
+     * @param content the content of the devcontainer.json, which may contain comments and trailing commas
+     * @param location the path of the devcontainer.json relative to the repository root
+     */
+    public parse(content: string, location: string): DevcontainerParseResult {
+        const devcontainer = JSON.parse(stripJSONComments(content));
+        if (!devcontainer || typeof devcontainer !== "object" || Array.isArray(devcontainer)) {
+            throw new Error("devcontainer.json must contain an object");
+        }
+        const dir = path.posix.dirname(location);
+        const config: WorkspaceConfig = {};
+        const warnings: string[] = [];
+        for (const name of Object.keys(devcontainer)) {
+            if (!supportedProperties.has(name)) {
+                warnings.push(`${name} is not supported`);
+            }
+        }
+
+        const dockerfile: string | undefined = devcontainer.build?.dockerfile || devcontainer.dockerFile;
+        if (dockerfile) {
+            const context = path.posix.join(dir, devcontainer.build?.context || devcontainer.context || "");
+            const image: ImageConfigFile = { file: path.posix.join(dir, dockerfile) };
+            if (context !== ".") {
+                image.context = context;
+            }
+            config.image = image;
+            if (devcontainer.image) {
+                warnings.push("image is ignored in favour of the Dockerfile");
+            }
+        } else if (typeof devcontainer.image === "string") {
+            config.image = devcontainer.image;
+        }
+
+        const ports = new Map<string, PortConfig | PortRangeConfig>();
+        const addPort = (port: string) => {
+            let config = ports.get(port);
+            if (!config) {
+                config = /^\d+$/.test(port) ? { port: parseInt(port, 10) } : { port };
+                ports.set(port, config);
+            }
+            return config;
+        };
+        for (const port of devcontainer.forwardPorts || []) {
+            if (typeof port === "number") {
+                addPort(String(port));
+                continue;
+            }
+            const match = typeof port === "string" && /^(localhost|127\.0\.0\.1):(\d+)$/.exec(port);
+            if (!match) {
+                warnings.push(`forwardPorts: ${port} is not supported, only ports of the workspace can be forwarded`);
+                continue;
+            }
+            addPort(match[2]);
+        }
+        const attributes = devcontainer.portsAttributes || {};
+        for (const port of Object.keys(attributes).sort()) {
+            if (!/^\d+(-\d+)?$/.test(port)) {
+                warnings.push(`portsAttributes: ${port} is not supported, only ports and port ranges are`);
+                continue;
+            }
+            const portConfig = addPort(port);
+            const attrs = attributes[port];
+            if (!attrs) {
+                continue;
+            }
+            if (attrs.label) {
+                portConfig.name = attrs.label;
+            }
+            if (attrs.onAutoForward) {
+                const onOpen = onAutoForward[attrs.onAutoForward];
+                if (onOpen) {
+                    portConfig.onOpen = onOpen;
+                } else {
+                    warnings.push(`portsAttributes: onAutoForward ${attrs.onAutoForward} of port ${port} is not supported`);
+                }
+            }
+            if (attrs.protocol === "http" || attrs.protocol === "https") {
+                portConfig.protocol = attrs.protocol;
+            }
+        }
+        if (ports.size > 0) {
+            config.ports = Array.from(ports.values());
+        }
+
+        const task: TaskConfig = {};
+        const init: string[] = [];
+        for (const name of ["onCreateCommand", "updateContentCommand", "postCreateCommand"]) {
+            try {
+                const command = toShellCommand(devcontainer[name]);
+                if (command) {
+                    init.push(command);
+                }
+            } catch (err) {
+                warnings.push(`${name}: ${err.message}`);
+            }
+        }
+        if (init.length > 0) {
+            task.init = init.join("\n");
+        }
+        try {
+            const command = toShellCommand(devcontainer.postStartCommand);
+            if (command) {
+                task.command = command;
+            }
+        } catch (err) {
+            warnings.push(`postStartCommand: ${err.message}`);
+        }
+        const env: { [name: string]: string } = { ...devcontainer.containerEnv, ...devcontainer.remoteEnv };
+        for (const name of Object.keys(env)) {
+            if (String(env[name]).includes("${")) {
+                warnings.push(`variables in the value of environment variable ${name} are not supported`);
+            }
+        }
+        if (Object.keys(env).length > 0) {
+            task.env = env;
+        }
+        if (task.init || task.command || task.env) {
+            config.tasks = [{ name: "Dev Container", ...task }];
+        }
+
+        const extensions: string[] | undefined =
+            devcontainer.customizations?.vscode?.extensions?.length > 0
+                ? devcontainer.customizations.vscode.extensions
+                : devcontainer.extensions;
+        if (extensions && extensions.length > 0) {
+            config.vscode = { extensions };
+        }
+
+        return { config, warnings: warnings.sort() };
+    }
+}
+
+/**
+ * Converts a lifecycle command of devcontainer.json to a shell command. Commands can be a string,
+ * an array of arguments or an object of commands which are run in parallel.
+ */
+function toShellCommand(command: any): string | undefined {
+    if (command === undefined || command === null) {
+        return undefined;
+    }
+    if (typeof command === "string") {
+        return command;
+    }
+    if (Array.isArray(command)) {
+        if (command.some((arg) => typeof arg !== "string")) {
+            throw new Error("arguments must be strings");
+        }
+        return command.map(shellQuote).join(" ");
+    }
+    if (typeof command === "object") {
+        const commands: string[] = [];
+        for (const name of Object.keys(command).sort()) {
+            if (command[name] && typeof command[name] === "object" && !Array.isArray(command[name])) {
+                throw new Error("nested commands are not supported");
+            }
+            commands.push(`(${toShellCommand(command[name]) || ""}) &`);
+        }
+        return commands.length > 0 ? [...commands, "wait"].join("\n") : undefined;
+    }
+    throw new Error(`unsupported command ${command}`);
+}
+
+function shellQuote(arg: string): string {
+    if (/^[a-zA-Z0-9\-_./=:@%+,]+$/.test(arg)) {
+        return arg;
+    }
+    return "'" + arg.replace(/'/g, "'\\''") + "'";
+}
+
+/**
+ * Removes comments and trailing commas from JSON with comments (JSONC), as used by devcontainer.json.
+ */
+export function stripJSONComments(content: string): string {
+    let result = "";
+    let inString = false;
+    // the position of a pending comma in result which is dropped if followed by a closing bracket
+    let comma = -1;
+    for (let i = 0; i < content.length; i++) {
+        const c = content[i];
+        if (inString) {
+            result += c;
+            if (c === "\\" && i + 1 < content.length) {
+                result += content[++i];
+            } else if (c === '"') {
+                inString = false;
+            }
+            continue;
+        }
+        if (c === "/" && content[i + 1] === "/") {
+            while (i < content.length && content[i] !== "\n") {
+                i++;
+            }
+            if (i < content.length) {
+                result += "\n";
+            }
+            continue;
+        }
+        if (c === "/" && content[i + 1] === "*") {
+            i += 2;
+            while (i + 1 < content.length && !(content[i] === "*" && content[i + 1] === "/")) {
+                i++;
+            }
+            i++;
+            continue;
+        }
+        if (/\s/.test(c)) {
+            result += c;
+            continue;
+        }
+        if ((c === "}" || c === "]") && comma >= 0) {
+            result = result.substring(0, comma) + " " + result.substring(comma + 1);
+        }
+        comma = -1;
+        if (c === ",") {
+            comma = result.length;
+        } else if (c === '"') {
+            inString = true;
+        }
+        result += c;
+    }
+    return result;
+}
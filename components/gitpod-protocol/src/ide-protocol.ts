@@ -136,4 +136,15 @@ export interface IDEOption {
      * LatestImageVersion the semantic version of the latest IDE image.
      */
     latestImageVersion?: string;
+
+    /**
+     * The image ref to an image which places the IDE backend in `/ide-additional/<name>/`,
+     * s.t. it can run next to the IDE the user has chosen.
+     */
+    additionalImage?: string;
+
+    /**
+     * The latest image ref of the additional image.
+     */
+    latestAdditionalImage?: string;
 }
@@ -252,6 +252,8 @@ export type IDESettings = {
     // DEPRECATED: Same with useDesktopIde.
     defaultDesktopIde?: string;
     useLatestVersion?: boolean;
+    // IDEs whose backends run next to the default IDE, see IDEOption.additionalImage.
+    additionalIdes?: string[];
 };
 
 export interface WorkspaceClasses {
@@ -807,6 +809,47 @@ export interface CoreDumpConfig {
     hardLimit?: number;
 }
 
+export interface ResourceThresholds {
+    warning?: number;
+    danger?: number;
+}
+
+export interface ResourceAlertsConfig {
+    cpu?: ResourceThresholds;
+    memory?: ResourceThresholds;
+    pressure?: ResourceThresholds;
+    disabled?: boolean;
+}
+
+export interface LifecycleHook {
+    command: string;
+    timeout?: string;
+}
+
+export interface LifecycleConfig {
+    onContentReady?: LifecycleHook;
+    postStart?: LifecycleHook;
+    preStop?: LifecycleHook;
+    onIdle?: LifecycleHook;
+    idleAfter?: string;
+}
+
+export interface ServiceHealthcheck {
+    command: string;
+    interval?: string;
+    timeout?: string;
+    retries?: number;
+}
+
+export interface ServiceConfig {
+    name: string;
+    image: string;
+    env?: { [name: string]: string };
+    ports?: number[];
+    volumes?: string[];
+    healthcheck?: ServiceHealthcheck;
+}
+
 export interface WorkspaceConfig {
     mainConfiguration?: string;
     additionalRepositories?: RepositoryCloneInformation[];
@@ -816,10 +859,14 @@ export interface WorkspaceConfig {
     checkoutLocation?: string;
     workspaceLocation?: string;
     gitConfig?: { [config: string]: string };
+    envFile?: string[];
     github?: GithubAppConfig;
     vscode?: VSCodeConfig;
     jetbrains?: JetBrainsConfig;
     coreDump?: CoreDumpConfig;
+    resourceAlerts?: ResourceAlertsConfig;
+    lifecycle?: LifecycleConfig;
+    services?: ServiceConfig[];
 
     /** deprecated. Enabled by default **/
     experimentalNetwork?: boolean;
@@ -950,10 +997,13 @@ export interface WhitelistedRepository {
 
 export type PortOnOpen = "open-browser" | "open-preview" | "notify" | "ignore";
 
+export type PortProtocol = "http" | "https" | "tcp";
 export interface PortConfig {
     port: number;
     onOpen?: PortOnOpen;
     visibility?: PortVisibility;
+    allowedCIDRs?: string[];
+    protocol?: PortProtocol;
     description?: string;
     name?: string;
 }
@@ -966,6 +1016,12 @@ export namespace PortConfig {
 export interface PortRangeConfig {
     port: string;
     onOpen?: PortOnOpen;
+    visibility?: PortVisibility;
+    allowedCIDRs?: string[];
+    protocol?: PortProtocol;
+    description?: string;
+    // naming a port range makes it a port group
+    name?: string;
 }
 export namespace PortRangeConfig {
     export function is(config: any): config is PortRangeConfig {
@@ -980,10 +1036,22 @@ export interface TaskConfig {
     prebuild?: string;
     command?: string;
     env?: { [env: string]: any };
+    envFile?: string[];
+    dependsOn?: string[];
+    readiness?: TaskReadinessConfig;
+    restart?: "never" | "on-failure" | "always";
+    maxRestarts?: number;
     openIn?: "bottom" | "main" | "left" | "right";
     openMode?: "split-top" | "split-left" | "split-right" | "split-bottom" | "tab-before" | "tab-after";
 }
 
+export interface TaskReadinessConfig {
+    port?: number;
+    http?: { port: number; path?: string };
+    logPattern?: string;
+    timeout?: number;
+}
+
 export namespace TaskConfig {
     export function is(config: any): config is TaskConfig {
         return config && ("command" in config || "init" in config || "before" in config);
@@ -184,7 +184,17 @@ export interface WorkspaceInstanceConditions {
 export type AdmissionLevel = "owner_only" | "everyone";
 
 // PortVisibility describes how a port can be accessed
-export type PortVisibility = "public" | "private";
+export type PortVisibility = "public" | "private" | "protected";
+
+// PortAccessPolicy restricts access to protected ports. If multiple requirements are configured, all must be met.
+export interface PortAccessPolicy {
+    // htpasswd entries with bcrypt hashed passwords. When opening a protected port without them (undefined or null),
+    // the port keeps its current basic auth, an empty list removes it. They are never returned by the server.
+    basicAuth?: string[] | null;
+
+    // IP ranges clients must connect from
+    allowedCIDRs?: string[];
+}
 
 // WorkspaceInstancePort describes a port exposed on a workspace instance
 export interface WorkspaceInstancePort {
@@ -194,6 +204,9 @@ export interface WorkspaceInstancePort {
     // The visiblity of this port. Optional for backwards compatibility.
     visibility?: PortVisibility;
 
+    // The access policy of a protected port.
+    accessPolicy?: PortAccessPolicy;
+
     // Public, outward-facing URL where the port can be accessed on.
     url?: string;
 }
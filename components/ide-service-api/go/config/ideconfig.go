@@ -65,6 +65,11 @@ type IDEOption struct {
 	ImageLayers []string `json:"imageLayers,omitempty"`
 	// LatestImageLayers for latest additional ide layers and dependencies
 	LatestImageLayers []string `json:"latestImageLayers,omitempty"`
+	// AdditionalImage ref to an image which places the IDE backend and its supervisor-ide-config.json in
+	// /ide-additional/<name>/, s.t. it can run next to the IDE the user has chosen.
+	AdditionalImage string `json:"additionalImage,omitempty"`
+	// LatestAdditionalImage ref to the latest additional image of the IDE.
+	LatestAdditionalImage string `json:"latestAdditionalImage,omitempty"`
 }
 
 type IDEClient struct {
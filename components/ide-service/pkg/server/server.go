@@ -179,8 +179,9 @@ func grpcProbe(cfg baseserver.ServerConfiguration) func() error {
 }
 
 type IDESettings struct {
-	DefaultIde       string `json:"defaultIde,omitempty"`
-	UseLatestVersion bool   `json:"useLatestVersion,omitempty"`
+	DefaultIde       string   `json:"defaultIde,omitempty"`
+	UseLatestVersion bool     `json:"useLatestVersion,omitempty"`
+	AdditionalIdes   []string `json:"additionalIdes,omitempty"`
 }
 
 type WorkspaceContext struct {
@@ -338,6 +339,32 @@ func (s *IDEServiceServer) ResolveWorkspaceConfig(ctx context.Context, req *api.
 			resp.IdeImageLayers = append(resp.IdeImageLayers, desktopImageLayer)
 			resp.IdeImageLayers = append(resp.IdeImageLayers, userImageLayers...)
 		}
+
+		// additional IDE backends run next to the chosen IDE, s.t. several editors can attach to the workspace
+		if ideSettings != nil {
+			imageLayers := make(map[string]struct{}, len(resp.IdeImageLayers))
+			for _, layer := range resp.IdeImageLayers {
+				imageLayers[layer] = struct{}{}
+			}
+			for _, name := range ideSettings.AdditionalIdes {
+				ide, ok := ideConfig.IdeOptions.Options[name]
+				if !ok || ide.AdditionalImage == "" {
+					log.WithField("ide", name).Warn("IDE cannot run as additional IDE")
+					continue
+				}
+				additionalImage := ide.AdditionalImage
+				if useLatest && ide.LatestAdditionalImage != "" {
+					additionalImage = ide.LatestAdditionalImage
+				}
+				for _, layer := range append(getUserImageLayers(&ide), additionalImage) {
+					if _, ok := imageLayers[layer]; ok {
+						continue
+					}
+					imageLayers[layer] = struct{}{}
+					resp.IdeImageLayers = append(resp.IdeImageLayers, layer)
+				}
+			}
+		}
 	}
 
 	jbGW, ok := ideConfig.IdeOptions.Clients["jetbrains-gateway"]
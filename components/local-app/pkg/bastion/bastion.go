@@ -352,6 +352,8 @@ func (b *Bastion) handleUpdate(ur *WorkspaceUpdateRequest) {
 			ws.supervisorListener, err = b.establishTunnel(ws.ctx, ws, "supervisor", 22999, 0, supervisor.TunnelVisiblity_host)
 			if err != nil {
 				logrus.WithError(err).WithField("workspace", ws.WorkspaceID).Error("cannot establish supervisor tunnel")
+			} else {
+				logrus.WithField("workspace", ws.WorkspaceID).Infof("supervisor: sync files with SUPERVISOR_ADDR=%s gp files push|pull|diff", ws.supervisorListener.LocalAddr)
 			}
 		}
 
@@ -482,9 +482,11 @@ export class CodeSyncService {
                     headers: {
                         "content-length": req.headers["content-length"] || String(content.length),
                         "content-type": contentType,
+                        // required by Azure Blob Storage, ignored by other storage backends
+                        "x-ms-blob-type": "BlockBlob",
                     },
                 });
-                if (response.status !== 200) {
+                if (!response.ok) {
                     throw new Error(
                         `code sync: blob service: upload failed with ${response.status} ${response.statusText}`,
                     );
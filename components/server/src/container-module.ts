@@ -10,6 +10,7 @@ import { Server } from "./server";
 import { Authenticator } from "./auth/authenticator";
 import { SessionHandlerProvider } from "./session-handler";
 import { GitpodFileParser } from "@gitpod/gitpod-protocol/lib/gitpod-file-parser";
+import { DevcontainerParser } from "@gitpod/gitpod-protocol/lib/devcontainer-parser";
 import { WorkspaceFactory } from "./workspace/workspace-factory";
 import { UserController } from "./user/user-controller";
 import { InstallationAdminController } from "./installation-admin/installation-admin-controller";
@@ -138,6 +139,7 @@ export const productionContainerModule = new ContainerModule((bind, unbind, isBo
     bind(DebugApp).toSelf().inSingletonScope();
 
     bind(GitpodFileParser).toSelf().inSingletonScope();
+    bind(DevcontainerParser).toSelf().inSingletonScope();
 
     bind(ConfigProvider).toSelf().inSingletonScope();
     bind(ConfigurationService).toSelf().inSingletonScope();
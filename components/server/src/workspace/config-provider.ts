@@ -7,6 +7,7 @@
 import { inject, injectable } from "inversify";
 import fetch from "node-fetch";
 import * as path from "path";
+import * as yaml from "js-yaml";
 
 import { log, LogContext } from "@gitpod/gitpod-protocol/lib/util/logging";
 import {
@@ -24,6 +25,7 @@ import {
     ProjectConfig,
 } from "@gitpod/gitpod-protocol";
 import { GitpodFileParser } from "@gitpod/gitpod-protocol/lib/gitpod-file-parser";
+import { DevcontainerParser, DEVCONTAINER_LOCATIONS } from "@gitpod/gitpod-protocol/lib/devcontainer-parser";
 
 import { MaybeContent } from "../repohost/file-provider";
 import { ConfigurationService } from "../config/configuration-service";
@@ -46,6 +48,7 @@ export class ConfigProvider {
     };
 
     @inject(GitpodFileParser) protected readonly gitpodParser: GitpodFileParser;
+    @inject(DevcontainerParser) protected readonly devcontainerParser: DevcontainerParser;
     @inject(HostContextProvider) protected readonly hostContextProvider: HostContextProvider;
     @inject(AuthorizationService) protected readonly authService: AuthorizationService;
     @inject(Config) protected readonly config: Config;
@@ -182,6 +185,11 @@ export class ConfigProvider {
                 customConfigString = await contextRepoConfig;
                 let origin: WorkspaceConfig["_origin"] = "repo";
 
+                if (!customConfigString) {
+                    // a devcontainer.json serves as an alternative to .gitpod.yml
+                    customConfigString = await this.fetchDevcontainerConfig({ span }, user, commit);
+                }
+
                 if (!customConfigString) {
                     /* We haven't found a Gitpod configuration file in the context repo - check definitely-gp.
                      *
@@ -238,6 +246,50 @@ export class ConfigProvider {
         }
     }
 
+    /**
+     * Converts the devcontainer.json of the context repo to the content of an equivalent .gitpod.yml.
+     */
+    protected async fetchDevcontainerConfig(
+        ctx: TraceContext,
+        user: User,
+        commit: CommitContext,
+    ): Promise<string | undefined> {
+        const span = TraceContext.startSpan("fetchDevcontainerConfig", ctx);
+        const logContext: LogContext = { userId: user.id };
+        try {
+            const hostContext = this.hostContextProvider.get(commit.repository.host);
+            if (!hostContext || !hostContext.services) {
+                return undefined;
+            }
+            for (const location of DEVCONTAINER_LOCATIONS) {
+                const content = await hostContext.services.fileProvider.getFileContent(commit, user, location);
+                if (!content) {
+                    continue;
+                }
+                let result;
+                try {
+                    result = this.devcontainerParser.parse(content, location);
+                } catch (err) {
+                    throw new InvalidGitpodYMLError([`Unparsable ${location}: ${err}`]);
+                }
+                if (result.warnings.length > 0) {
+                    log.info(logContext, `Parts of ${location} are not supported`, {
+                        repoCloneUrl: commit.repository.cloneUrl,
+                        revision: commit.revision,
+                        warnings: result.warnings,
+                    });
+                }
+                return yaml.dump(result.config);
+            }
+            return undefined;
+        } catch (e) {
+            TraceContext.setError({ span }, e);
+            throw e;
+        } finally {
+            span.finish();
+        }
+    }
+
     public defaultConfig(): WorkspaceConfig {
         return {
             ports: [],
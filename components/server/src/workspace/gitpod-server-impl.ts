@@ -37,6 +37,7 @@ import {
     GitpodToken,
     GitpodTokenType,
     PermissionName,
+    PortAccessPolicy,
     PortVisibility,
     PrebuiltWorkspace,
     PrebuiltWorkspaceContext,
@@ -121,10 +122,12 @@ import {
 import { SupportedWorkspaceClass } from "@gitpod/gitpod-protocol/lib/workspace-class";
 import { ImageBuilderClientProvider } from "@gitpod/image-builder/lib";
 import { WorkspaceManagerClientProvider } from "@gitpod/ws-manager/lib/client-provider";
+import { PromisifiedWorkspaceManagerClient } from "@gitpod/ws-manager/lib";
 import {
     ControlPortRequest,
     DescribeWorkspaceRequest,
     MarkActiveRequest,
+    PortAccessPolicy as ProtoPortAccessPolicy,
     PortSpec,
     PortVisibility as ProtoPortVisibility,
     StopWorkspacePolicy,
@@ -1511,6 +1514,7 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
                         port: p.getPort(),
                         url: p.getUrl(),
                         visibility: this.portVisibilityFromProto(p.getVisibility()),
+                        accessPolicy: this.portAccessPolicyFromProto(p.getAccessPolicy()),
                     },
             );
 
@@ -1551,12 +1555,42 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
                 runningInstance.region,
                 this.config.installationShortname,
             );
+            if (port.visibility === "protected") {
+                let accessPolicy = port.accessPolicy;
+                if (accessPolicy?.basicAuth == null) {
+                    // Requests without basic auth, e.g. the supervisor re-exposing a port, keep the basic auth of the exposed port,
+                    // as its hashes are only known to the server. Clients clear it with an empty list.
+                    const basicAuth = await this.getExposedPortBasicAuth(ctx, client, runningInstance.id, port.port);
+                    accessPolicy = { ...accessPolicy, basicAuth };
+                }
+                spec.setAccessPolicy(this.portAccessPolicyToProto(accessPolicy));
+            }
             await client.controlPort(ctx, req);
         } catch (e) {
             throw this.mapGrpcError(e);
         }
     }
 
+    protected async getExposedPortBasicAuth(
+        ctx: TraceContext,
+        client: PromisifiedWorkspaceManagerClient,
+        instanceId: string,
+        port: number,
+    ): Promise<string[]> {
+        const req = new DescribeWorkspaceRequest();
+        req.setId(instanceId);
+        const desc = await client.describeWorkspace(ctx, req);
+        const exposed = desc
+            .getStatus()
+            ?.getSpec()
+            ?.getExposedPortsList()
+            .find((p) => p.getPort() === port);
+        if (exposed?.getVisibility() !== ProtoPortVisibility.PORT_VISIBILITY_PROTECTED) {
+            return [];
+        }
+        return exposed.getAccessPolicy()?.getBasicAuthList() || [];
+    }
+
     protected portVisibilityFromProto(visibility: ProtoPortVisibility): PortVisibility {
         switch (visibility) {
             default: // the default in the protobuf def is: private
@@ -1564,6 +1598,8 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
                 return "private";
             case ProtoPortVisibility.PORT_VISIBILITY_PUBLIC:
                 return "public";
+            case ProtoPortVisibility.PORT_VISIBILITY_PROTECTED:
+                return "protected";
         }
     }
 
@@ -1574,9 +1610,28 @@ export class GitpodServerImpl implements GitpodServerWithTracing, Disposable {
                 return ProtoPortVisibility.PORT_VISIBILITY_PRIVATE;
             case "public":
                 return ProtoPortVisibility.PORT_VISIBILITY_PUBLIC;
+            case "protected":
+                return ProtoPortVisibility.PORT_VISIBILITY_PROTECTED;
         }
     }
 
+    protected portAccessPolicyFromProto(policy: ProtoPortAccessPolicy | undefined): PortAccessPolicy | undefined {
+        if (!policy) {
+            return undefined;
+        }
+        // the password hashes of basic auth never leave the server
+        return {
+            allowedCIDRs: policy.getAllowedCidrsList(),
+        };
+    }
+
+    protected portAccessPolicyToProto(policy: PortAccessPolicy | undefined): ProtoPortAccessPolicy {
+        const result = new ProtoPortAccessPolicy();
+        result.setBasicAuthList(policy?.basicAuth || []);
+        result.setAllowedCidrsList(policy?.allowedCIDRs || []);
+        return result;
+    }
+
     public async closePort(ctx: TraceContext, workspaceId: string, port: number) {
         traceAPIParams(ctx, { workspaceId, port });
         traceWI(ctx, { workspaceId });
@@ -46,6 +46,7 @@ local_java_protoc() {
     # replace Java reserved keywords
     sed -i 's/private = 0;/private_visibility = 0;/g' status.proto
     sed -i 's/public = 1;/public_visibility = 1;/g' status.proto
+    sed -i 's/protected = 2;/protected_visibility = 2;/g' status.proto
     protoc \
         -I /usr/lib/protoc/include -I"$COMPONENTS_DIR" -I. -I"$THIRD_PARTY_INCLUDES" \
         --plugin=protoc-gen-grpc-java=/tmp/protoc-gen-grpc-java \
@@ -57,6 +58,7 @@ local_java_protoc() {
     # revert Java reserved keywords
     sed -i 's/private_visibility = 0;/private = 0;/g' status.proto
     sed -i 's/public_visibility = 1;/public = 1;/g' status.proto
+    sed -i 's/protected_visibility = 2;/protected = 2;/g' status.proto
 }
 
 install_dependencies
@@ -30,6 +30,12 @@ type ControlServiceClient interface {
 	ExposePort(ctx context.Context, in *ExposePortRequest, opts ...grpc.CallOption) (*ExposePortResponse, error)
 	// CreateSSHKeyPair Create a pair of SSH Keys and put them in ~/.ssh/authorized_keys, this will only be generated once in the entire workspace lifecycle
 	CreateSSHKeyPair(ctx context.Context, in *CreateSSHKeyPairRequest, opts ...grpc.CallOption) (*CreateSSHKeyPairResponse, error)
+	// ReloadTasks applies changes of the tasks in .gitpod.yml to the running workspace: added tasks are started,
+	// removed tasks are stopped and tasks whose commands or environment changed are restarted.
+	ReloadTasks(ctx context.Context, in *ReloadTasksRequest, opts ...grpc.CallOption) (*ReloadTasksResponse, error)
+	// MarkActive marks the workspace as used, IDEs call it alongside their heartbeats s.t. the onIdle lifecycle hook
+	// does not run while the workspace is used through an IDE.
+	MarkActive(ctx context.Context, in *MarkActiveRequest, opts ...grpc.CallOption) (*MarkActiveResponse, error)
 }
 
 type controlServiceClient struct {
@@ -58,6 +64,24 @@ func (c *controlServiceClient) CreateSSHKeyPair(ctx context.Context, in *CreateS
 	return out, nil
 }
 
+func (c *controlServiceClient) ReloadTasks(ctx context.Context, in *ReloadTasksRequest, opts ...grpc.CallOption) (*ReloadTasksResponse, error) {
+	out := new(ReloadTasksResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.ControlService/ReloadTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) MarkActive(ctx context.Context, in *MarkActiveRequest, opts ...grpc.CallOption) (*MarkActiveResponse, error) {
+	out := new(MarkActiveResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.ControlService/MarkActive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ControlServiceServer is the server API for ControlService service.
 // All implementations must embed UnimplementedControlServiceServer
 // for forward compatibility
@@ -66,6 +90,12 @@ type ControlServiceServer interface {
 	ExposePort(context.Context, *ExposePortRequest) (*ExposePortResponse, error)
 	// CreateSSHKeyPair Create a pair of SSH Keys and put them in ~/.ssh/authorized_keys, this will only be generated once in the entire workspace lifecycle
 	CreateSSHKeyPair(context.Context, *CreateSSHKeyPairRequest) (*CreateSSHKeyPairResponse, error)
+	// ReloadTasks applies changes of the tasks in .gitpod.yml to the running workspace: added tasks are started,
+	// removed tasks are stopped and tasks whose commands or environment changed are restarted.
+	ReloadTasks(context.Context, *ReloadTasksRequest) (*ReloadTasksResponse, error)
+	// MarkActive marks the workspace as used, IDEs call it alongside their heartbeats s.t. the onIdle lifecycle hook
+	// does not run while the workspace is used through an IDE.
+	MarkActive(context.Context, *MarkActiveRequest) (*MarkActiveResponse, error)
 	mustEmbedUnimplementedControlServiceServer()
 }
 
@@ -79,6 +109,12 @@ func (UnimplementedControlServiceServer) ExposePort(context.Context, *ExposePort
 func (UnimplementedControlServiceServer) CreateSSHKeyPair(context.Context, *CreateSSHKeyPairRequest) (*CreateSSHKeyPairResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateSSHKeyPair not implemented")
 }
+func (UnimplementedControlServiceServer) ReloadTasks(context.Context, *ReloadTasksRequest) (*ReloadTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadTasks not implemented")
+}
+func (UnimplementedControlServiceServer) MarkActive(context.Context, *MarkActiveRequest) (*MarkActiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkActive not implemented")
+}
 func (UnimplementedControlServiceServer) mustEmbedUnimplementedControlServiceServer() {}
 
 // UnsafeControlServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -128,6 +164,42 @@ func _ControlService_CreateSSHKeyPair_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ControlService_ReloadTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ReloadTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.ControlService/ReloadTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ReloadTasks(ctx, req.(*ReloadTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_MarkActive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkActiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).MarkActive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.ControlService/MarkActive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).MarkActive(ctx, req.(*MarkActiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ControlService_ServiceDesc is the grpc.ServiceDesc for ControlService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -143,6 +215,14 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateSSHKeyPair",
 			Handler:    _ControlService_CreateSSHKeyPair_Handler,
 		},
+		{
+			MethodName: "ReloadTasks",
+			Handler:    _ControlService_ReloadTasks_Handler,
+		},
+		{
+			MethodName: "MarkActive",
+			Handler:    _ControlService_MarkActive_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "control.proto",
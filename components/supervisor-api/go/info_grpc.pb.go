@@ -27,6 +27,9 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type InfoServiceClient interface {
 	WorkspaceInfo(ctx context.Context, in *WorkspaceInfoRequest, opts ...grpc.CallOption) (*WorkspaceInfoResponse, error)
+	// Environment returns the effective environment of the processes started by supervisor,
+	// i.e. IDEs, tasks and SSH sessions, along with the source of each variable.
+	Environment(ctx context.Context, in *EnvironmentRequest, opts ...grpc.CallOption) (*EnvironmentResponse, error)
 }
 
 type infoServiceClient struct {
@@ -46,11 +49,23 @@ func (c *infoServiceClient) WorkspaceInfo(ctx context.Context, in *WorkspaceInfo
 	return out, nil
 }
 
+func (c *infoServiceClient) Environment(ctx context.Context, in *EnvironmentRequest, opts ...grpc.CallOption) (*EnvironmentResponse, error) {
+	out := new(EnvironmentResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.InfoService/Environment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InfoServiceServer is the server API for InfoService service.
 // All implementations must embed UnimplementedInfoServiceServer
 // for forward compatibility
 type InfoServiceServer interface {
 	WorkspaceInfo(context.Context, *WorkspaceInfoRequest) (*WorkspaceInfoResponse, error)
+	// Environment returns the effective environment of the processes started by supervisor,
+	// i.e. IDEs, tasks and SSH sessions, along with the source of each variable.
+	Environment(context.Context, *EnvironmentRequest) (*EnvironmentResponse, error)
 	mustEmbedUnimplementedInfoServiceServer()
 }
 
@@ -61,6 +76,9 @@ type UnimplementedInfoServiceServer struct {
 func (UnimplementedInfoServiceServer) WorkspaceInfo(context.Context, *WorkspaceInfoRequest) (*WorkspaceInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WorkspaceInfo not implemented")
 }
+func (UnimplementedInfoServiceServer) Environment(context.Context, *EnvironmentRequest) (*EnvironmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Environment not implemented")
+}
 func (UnimplementedInfoServiceServer) mustEmbedUnimplementedInfoServiceServer() {}
 
 // UnsafeInfoServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -92,6 +110,24 @@ func _InfoService_WorkspaceInfo_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InfoService_Environment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnvironmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServiceServer).Environment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.InfoService/Environment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServiceServer).Environment(ctx, req.(*EnvironmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InfoService_ServiceDesc is the grpc.ServiceDesc for InfoService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -103,6 +139,10 @@ var InfoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "WorkspaceInfo",
 			Handler:    _InfoService_WorkspaceInfo_Handler,
 		},
+		{
+			MethodName: "Environment",
+			Handler:    _InfoService_Environment_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "info.proto",
@@ -30,11 +30,15 @@ type NotificationServiceClient interface {
 	// process. If the list of actions is empty this service returns immediately,
 	// otherwise it blocks until the user has made their choice.
 	Notify(ctx context.Context, in *NotifyRequest, opts ...grpc.CallOption) (*NotifyResponse, error)
-	// Subscribe to notifications. Typically called by the IDE.
+	// Subscribe to notifications. Typically called by the IDE. Non-interactive
+	// subscribers, e.g. gp notify watch, only observe new notifications.
 	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (NotificationService_SubscribeClient, error)
 	// Report a user's choice as a response to a notification. Typically called by
 	// the IDE.
 	Respond(ctx context.Context, in *RespondRequest, opts ...grpc.CallOption) (*RespondResponse, error)
+	// Lists the notifications of the workspace, newest last. The log is bounded,
+	// older notifications are dropped first. It is kept across workspace restarts.
+	ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error)
 	// Called by the IDE to inform supervisor about which is the latest client
 	// actively used by the user. We consider active the last IDE with focus.
 	// Only 1 stream is kept open at any given time. A new subscription
@@ -108,6 +112,15 @@ func (c *notificationServiceClient) Respond(ctx context.Context, in *RespondRequ
 	return out, nil
 }
 
+func (c *notificationServiceClient) ListNotifications(ctx context.Context, in *ListNotificationsRequest, opts ...grpc.CallOption) (*ListNotificationsResponse, error) {
+	out := new(ListNotificationsResponse)
+	err := c.cc.Invoke(ctx, "/supervisor.NotificationService/ListNotifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *notificationServiceClient) SubscribeActive(ctx context.Context, in *SubscribeActiveRequest, opts ...grpc.CallOption) (NotificationService_SubscribeActiveClient, error) {
 	stream, err := c.cc.NewStream(ctx, &NotificationService_ServiceDesc.Streams[1], "/supervisor.NotificationService/SubscribeActive", opts...)
 	if err != nil {
@@ -166,11 +179,15 @@ type NotificationServiceServer interface {
 	// process. If the list of actions is empty this service returns immediately,
 	// otherwise it blocks until the user has made their choice.
 	Notify(context.Context, *NotifyRequest) (*NotifyResponse, error)
-	// Subscribe to notifications. Typically called by the IDE.
+	// Subscribe to notifications. Typically called by the IDE. Non-interactive
+	// subscribers, e.g. gp notify watch, only observe new notifications.
 	Subscribe(*SubscribeRequest, NotificationService_SubscribeServer) error
 	// Report a user's choice as a response to a notification. Typically called by
 	// the IDE.
 	Respond(context.Context, *RespondRequest) (*RespondResponse, error)
+	// Lists the notifications of the workspace, newest last. The log is bounded,
+	// older notifications are dropped first. It is kept across workspace restarts.
+	ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error)
 	// Called by the IDE to inform supervisor about which is the latest client
 	// actively used by the user. We consider active the last IDE with focus.
 	// Only 1 stream is kept open at any given time. A new subscription
@@ -200,6 +217,9 @@ func (UnimplementedNotificationServiceServer) Subscribe(*SubscribeRequest, Notif
 func (UnimplementedNotificationServiceServer) Respond(context.Context, *RespondRequest) (*RespondResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Respond not implemented")
 }
+func (UnimplementedNotificationServiceServer) ListNotifications(context.Context, *ListNotificationsRequest) (*ListNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNotifications not implemented")
+}
 func (UnimplementedNotificationServiceServer) SubscribeActive(*SubscribeActiveRequest, NotificationService_SubscribeActiveServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeActive not implemented")
 }
@@ -279,6 +299,24 @@ func _NotificationService_Respond_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NotificationService_ListNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).ListNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/supervisor.NotificationService/ListNotifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).ListNotifications(ctx, req.(*ListNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NotificationService_SubscribeActive_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(SubscribeActiveRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -351,6 +389,10 @@ var NotificationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Respond",
 			Handler:    _NotificationService_Respond_Handler,
 		},
+		{
+			MethodName: "ListNotifications",
+			Handler:    _NotificationService_ListNotifications_Handler,
+		},
 		{
 			MethodName: "NotifyActive",
 			Handler:    _NotificationService_NotifyActive_Handler,
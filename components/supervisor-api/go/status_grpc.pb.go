@@ -41,8 +41,13 @@ type StatusServiceClient interface {
 	PortsStatus(ctx context.Context, in *PortsStatusRequest, opts ...grpc.CallOption) (StatusService_PortsStatusClient, error)
 	// TasksStatus provides tasks status information.
 	TasksStatus(ctx context.Context, in *TasksStatusRequest, opts ...grpc.CallOption) (StatusService_TasksStatusClient, error)
+	// ServicesStatus provides the status of the service containers configured in .gitpod.yml.
+	ServicesStatus(ctx context.Context, in *ServicesStatusRequest, opts ...grpc.CallOption) (StatusService_ServicesStatusClient, error)
 	// ResourcesStatus provides workspace resources status information.
 	ResourcesStatus(ctx context.Context, in *ResourcesStatuRequest, opts ...grpc.CallOption) (*ResourcesStatusResponse, error)
+	// ObserveResourcesStatus streams workspace resources status information including a
+	// per process tree breakdown. If requested, the rolling history is sent first.
+	ObserveResourcesStatus(ctx context.Context, in *ObserveResourcesStatusRequest, opts ...grpc.CallOption) (StatusService_ObserveResourcesStatusClient, error)
 }
 
 type statusServiceClient struct {
@@ -153,6 +158,38 @@ func (x *statusServiceTasksStatusClient) Recv() (*TasksStatusResponse, error) {
 	return m, nil
 }
 
+func (c *statusServiceClient) ServicesStatus(ctx context.Context, in *ServicesStatusRequest, opts ...grpc.CallOption) (StatusService_ServicesStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StatusService_ServiceDesc.Streams[2], "/supervisor.StatusService/ServicesStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &statusServiceServicesStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StatusService_ServicesStatusClient interface {
+	Recv() (*ServicesStatusResponse, error)
+	grpc.ClientStream
+}
+
+type statusServiceServicesStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *statusServiceServicesStatusClient) Recv() (*ServicesStatusResponse, error) {
+	m := new(ServicesStatusResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *statusServiceClient) ResourcesStatus(ctx context.Context, in *ResourcesStatuRequest, opts ...grpc.CallOption) (*ResourcesStatusResponse, error) {
 	out := new(ResourcesStatusResponse)
 	err := c.cc.Invoke(ctx, "/supervisor.StatusService/ResourcesStatus", in, out, opts...)
@@ -162,6 +199,38 @@ func (c *statusServiceClient) ResourcesStatus(ctx context.Context, in *Resources
 	return out, nil
 }
 
+func (c *statusServiceClient) ObserveResourcesStatus(ctx context.Context, in *ObserveResourcesStatusRequest, opts ...grpc.CallOption) (StatusService_ObserveResourcesStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StatusService_ServiceDesc.Streams[3], "/supervisor.StatusService/ObserveResourcesStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &statusServiceObserveResourcesStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StatusService_ObserveResourcesStatusClient interface {
+	Recv() (*ResourcesStatusResponse, error)
+	grpc.ClientStream
+}
+
+type statusServiceObserveResourcesStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *statusServiceObserveResourcesStatusClient) Recv() (*ResourcesStatusResponse, error) {
+	m := new(ResourcesStatusResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // StatusServiceServer is the server API for StatusService service.
 // All implementations must embed UnimplementedStatusServiceServer
 // for forward compatibility
@@ -181,8 +250,13 @@ type StatusServiceServer interface {
 	PortsStatus(*PortsStatusRequest, StatusService_PortsStatusServer) error
 	// TasksStatus provides tasks status information.
 	TasksStatus(*TasksStatusRequest, StatusService_TasksStatusServer) error
+	// ServicesStatus provides the status of the service containers configured in .gitpod.yml.
+	ServicesStatus(*ServicesStatusRequest, StatusService_ServicesStatusServer) error
 	// ResourcesStatus provides workspace resources status information.
 	ResourcesStatus(context.Context, *ResourcesStatuRequest) (*ResourcesStatusResponse, error)
+	// ObserveResourcesStatus streams workspace resources status information including a
+	// per process tree breakdown. If requested, the rolling history is sent first.
+	ObserveResourcesStatus(*ObserveResourcesStatusRequest, StatusService_ObserveResourcesStatusServer) error
 	mustEmbedUnimplementedStatusServiceServer()
 }
 
@@ -208,9 +282,15 @@ func (UnimplementedStatusServiceServer) PortsStatus(*PortsStatusRequest, StatusS
 func (UnimplementedStatusServiceServer) TasksStatus(*TasksStatusRequest, StatusService_TasksStatusServer) error {
 	return status.Errorf(codes.Unimplemented, "method TasksStatus not implemented")
 }
+func (UnimplementedStatusServiceServer) ServicesStatus(*ServicesStatusRequest, StatusService_ServicesStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method ServicesStatus not implemented")
+}
 func (UnimplementedStatusServiceServer) ResourcesStatus(context.Context, *ResourcesStatuRequest) (*ResourcesStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ResourcesStatus not implemented")
 }
+func (UnimplementedStatusServiceServer) ObserveResourcesStatus(*ObserveResourcesStatusRequest, StatusService_ObserveResourcesStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method ObserveResourcesStatus not implemented")
+}
 func (UnimplementedStatusServiceServer) mustEmbedUnimplementedStatusServiceServer() {}
 
 // UnsafeStatusServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -338,6 +418,27 @@ func (x *statusServiceTasksStatusServer) Send(m *TasksStatusResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _StatusService_ServicesStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ServicesStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StatusServiceServer).ServicesStatus(m, &statusServiceServicesStatusServer{stream})
+}
+
+type StatusService_ServicesStatusServer interface {
+	Send(*ServicesStatusResponse) error
+	grpc.ServerStream
+}
+
+type statusServiceServicesStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *statusServiceServicesStatusServer) Send(m *ServicesStatusResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _StatusService_ResourcesStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ResourcesStatuRequest)
 	if err := dec(in); err != nil {
@@ -356,6 +457,27 @@ func _StatusService_ResourcesStatus_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StatusService_ObserveResourcesStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ObserveResourcesStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StatusServiceServer).ObserveResourcesStatus(m, &statusServiceObserveResourcesStatusServer{stream})
+}
+
+type StatusService_ObserveResourcesStatusServer interface {
+	Send(*ResourcesStatusResponse) error
+	grpc.ServerStream
+}
+
+type statusServiceObserveResourcesStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *statusServiceObserveResourcesStatusServer) Send(m *ResourcesStatusResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // StatusService_ServiceDesc is the grpc.ServiceDesc for StatusService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -395,6 +517,16 @@ var StatusService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _StatusService_TasksStatus_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ServicesStatus",
+			Handler:       _StatusService_ServicesStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ObserveResourcesStatus",
+			Handler:       _StatusService_ObserveResourcesStatus_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "status.proto",
 }
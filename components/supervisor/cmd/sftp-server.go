@@ -0,0 +1,40 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/sftp"
+	"github.com/spf13/cobra"
+)
+
+var sftpServerCmd = &cobra.Command{
+	Use:    "sftp-server",
+	Short:  "serves SFTP on stdin/stdout for the SSH server",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		server, err := sftp.NewServer(struct {
+			io.Reader
+			io.WriteCloser
+		}{os.Stdin, os.Stdout})
+		// stdout is the SFTP connection, hence errors must go to stderr only
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot start SFTP server: %v\n", err)
+			os.Exit(1)
+		}
+		err = server.Serve()
+		if err != nil && err != io.EOF {
+			fmt.Fprintf(os.Stderr, "SFTP server failed: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sftpServerCmd)
+}
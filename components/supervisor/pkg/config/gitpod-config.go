@@ -7,6 +7,8 @@ package config
 import (
 	"context"
 	"os"
+	"path/filepath"
+	"reflect"
 	"sync"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/gitpod-protocol/validate"
 )
 
 // ConfigInterface provides access to the gitpod config file.
@@ -25,9 +28,17 @@ type ConfigInterface interface {
 	Observe(ctx context.Context) <-chan *gitpod.GitpodConfig
 }
 
+// InvalidConfig describes the problems of an invalid config file.
+type InvalidConfig struct {
+	Location    string
+	Diagnostics []validate.Diagnostic
+}
+
 // ConfigService provides access to the gitpod config file.
+// If there is no gitpod config file, a devcontainer.json next to it is used instead.
 type ConfigService struct {
 	location      string
+	fallbacks     []string
 	locationReady <-chan struct{}
 
 	cond   *sync.Cond
@@ -41,17 +52,66 @@ type ConfigService struct {
 	readyOnce sync.Once
 
 	debounceDuration time.Duration
+
+	invalidMu        sync.Mutex
+	invalidListeners map[chan *InvalidConfig]struct{}
+	diagnostics      []validate.Diagnostic
 }
 
 // NewConfigService creates a new instance of ConfigService.
 func NewConfigService(configLocation string, locationReady <-chan struct{}, log *logrus.Entry) *ConfigService {
+	var fallbacks []string
+	for _, location := range gitpod.DevcontainerLocations {
+		fallbacks = append(fallbacks, filepath.Join(filepath.Dir(configLocation), location))
+	}
 	return &ConfigService{
 		location:         configLocation,
+		fallbacks:        fallbacks,
 		locationReady:    locationReady,
 		cond:             sync.NewCond(&sync.Mutex{}),
 		log:              log.WithField("location", configLocation),
 		ready:            make(chan struct{}),
 		debounceDuration: 100 * time.Millisecond,
+		invalidListeners: make(map[chan *InvalidConfig]struct{}),
+	}
+}
+
+// ObserveInvalid provides a channel triggered whenever the config file is changed and it is invalid.
+// If the config file cannot be parsed, the previous config stays in effect.
+func (service *ConfigService) ObserveInvalid(ctx context.Context) <-chan *InvalidConfig {
+	invalid := make(chan *InvalidConfig, 1)
+	service.invalidMu.Lock()
+	service.invalidListeners[invalid] = struct{}{}
+	service.invalidMu.Unlock()
+	go func() {
+		<-ctx.Done()
+		service.invalidMu.Lock()
+		delete(service.invalidListeners, invalid)
+		service.invalidMu.Unlock()
+		close(invalid)
+	}()
+	return invalid
+}
+
+// reportDiagnostics notifies listeners about the problems of the config file unless they have been reported already.
+func (service *ConfigService) reportDiagnostics(location string, diagnostics []validate.Diagnostic) {
+	service.invalidMu.Lock()
+	defer service.invalidMu.Unlock()
+	if reflect.DeepEqual(service.diagnostics, diagnostics) {
+		return
+	}
+	service.diagnostics = diagnostics
+	if !validate.HasErrors(diagnostics) {
+		return
+	}
+	service.log.WithField("diagnostics", diagnostics).Warn("gitpod config watcher: invalid config")
+	invalid := &InvalidConfig{Location: location, Diagnostics: diagnostics}
+	for listener := range service.invalidListeners {
+		select {
+		case listener <- invalid:
+		default:
+			// the listener still has to process a previous report
+		}
 	}
 }
 
@@ -87,13 +147,22 @@ func (service *ConfigService) Watch(ctx context.Context) {
 		return
 	}
 
-	_, err := os.Stat(service.location)
-	if os.IsNotExist(err) {
+	if service.resolveLocation() == "" {
 		service.poll(ctx)
 	}
 	service.watch(ctx)
 }
 
+// resolveLocation returns the location of the config file in use, or an empty string if there is none.
+func (service *ConfigService) resolveLocation() string {
+	for _, location := range append([]string{service.location}, service.fallbacks...) {
+		if _, err := os.Stat(location); !os.IsNotExist(err) {
+			return location
+		}
+	}
+	return ""
+}
+
 func (service *ConfigService) markReady() {
 	service.readyOnce.Do(func() {
 		close(service.ready)
@@ -114,7 +183,15 @@ func (service *ConfigService) watch(ctx context.Context) {
 		return
 	}
 
-	err = watcher.Add(service.location)
+	location := service.resolveLocation()
+	if location == "" {
+		location = service.location
+	}
+	err = watcher.Add(location)
+	if err == nil && location != service.location {
+		// watch for the gitpod config file to be created, it takes precedence over a devcontainer.json
+		err = watcher.Add(filepath.Dir(service.location))
+	}
 	if err != nil {
 		watcher.Close()
 		return
@@ -125,7 +202,7 @@ func (service *ConfigService) watch(ctx context.Context) {
 		defer watcher.Close()
 
 		polling := make(chan struct{}, 1)
-		service.scheduleUpdateConfig(ctx, polling)
+		service.scheduleUpdateConfig(ctx, location, polling)
 		for {
 			select {
 			case <-polling:
@@ -134,21 +211,29 @@ func (service *ConfigService) watch(ctx context.Context) {
 				return
 			case err := <-watcher.Errors:
 				service.log.WithError(err).Error("gitpod config watcher: failed to watch")
-			case <-watcher.Events:
-				service.scheduleUpdateConfig(ctx, polling)
+			case event := <-watcher.Events:
+				if event.Name != location && event.Name != service.location {
+					continue
+				}
+				service.scheduleUpdateConfig(ctx, location, polling)
 			}
 		}
 	}()
 }
 
-func (service *ConfigService) scheduleUpdateConfig(ctx context.Context, polling chan<- struct{}) {
+func (service *ConfigService) scheduleUpdateConfig(ctx context.Context, location string, polling chan<- struct{}) {
 	service.cond.L.Lock()
 	defer service.cond.L.Unlock()
 	if service.pollTimer != nil {
 		service.pollTimer.Stop()
 	}
 	service.pollTimer = time.AfterFunc(service.debounceDuration, func() {
-		err := service.updateConfig()
+		if current := service.resolveLocation(); current != "" && current != location {
+			polling <- struct{}{}
+			service.watch(ctx)
+			return
+		}
+		err := service.updateConfig(location)
 		if os.IsNotExist(err) {
 			polling <- struct{}{}
 			go service.poll(ctx)
@@ -171,18 +256,18 @@ func (service *ConfigService) poll(ctx context.Context) {
 		case <-timer.C:
 		}
 
-		if _, err := os.Stat(service.location); !os.IsNotExist(err) {
+		if service.resolveLocation() != "" {
 			service.watch(ctx)
 			return
 		}
 	}
 }
 
-func (service *ConfigService) updateConfig() error {
+func (service *ConfigService) updateConfig(location string) error {
 	service.cond.L.Lock()
 	defer service.cond.L.Unlock()
 
-	config, err := service.parse()
+	config, err := service.parse(location)
 	if err == nil || os.IsNotExist(err) {
 		service.config = config
 		service.markReady()
@@ -194,12 +279,32 @@ func (service *ConfigService) updateConfig() error {
 	return err
 }
 
-func (service *ConfigService) parse() (*gitpod.GitpodConfig, error) {
-	data, err := os.ReadFile(service.location)
+func (service *ConfigService) parse(location string) (*gitpod.GitpodConfig, error) {
+	data, err := os.ReadFile(location)
 	if err != nil {
 		return nil, err
 	}
+	if location != service.location {
+		return service.parseDevcontainer(location, data)
+	}
+	service.reportDiagnostics(location, validate.Validate(data))
 	var config *gitpod.GitpodConfig
 	err = yaml.Unmarshal(data, &config)
 	return config, err
 }
+
+func (service *ConfigService) parseDevcontainer(location string, data []byte) (*gitpod.GitpodConfig, error) {
+	devcontainer, err := gitpod.ParseDevcontainer(data)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := filepath.Rel(filepath.Dir(service.location), filepath.Dir(location))
+	if err != nil {
+		return nil, err
+	}
+	config, warnings := devcontainer.ToGitpodConfig(filepath.ToSlash(dir))
+	for _, warning := range warnings {
+		service.log.WithField("devcontainer", location).Warnf("gitpod config watcher: %s", warning)
+	}
+	return config, nil
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/gitpod-protocol/validate"
 )
 
 var log = logrus.NewEntry(logrus.StandardLogger())
@@ -229,3 +231,288 @@ ports:
 		t.Errorf("unexpected output (-want +got):\n%s", diff)
 	}
 }
+
+func TestDevcontainerFallback(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "test-gitpod-config-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	err = os.MkdirAll(filepath.Join(tempDir, ".devcontainer"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(tempDir, ".devcontainer", "devcontainer.json"), []byte(`{
+	// comments are allowed
+	"build": { "dockerfile": "Dockerfile" },
+	"forwardPorts": [3000],
+	"postStartCommand": "yarn start",
+	"features": {},
+}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locationReady := make(chan struct{})
+	configService := NewConfigService(tempDir+"/.gitpod.yml", locationReady, log)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	close(locationReady)
+
+	go configService.Watch(ctx)
+
+	listener := configService.Observe(ctx)
+
+	config := <-listener
+	if diff := cmp.Diff(&gitpod.GitpodConfig{
+		Image: gitpod.Image_object{File: ".devcontainer/Dockerfile", Context: ".devcontainer"},
+		Ports: []*gitpod.PortsItems{{Port: 3000}},
+		Tasks: []*gitpod.TasksItems{{Name: "Dev Container", Command: "yarn start"}},
+	}, config); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+
+	// the gitpod config file takes precedence
+	err = os.WriteFile(configService.location, []byte(`
+ports:
+  - port: 8080
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config = <-listener
+	if diff := cmp.Diff(&gitpod.GitpodConfig{
+		Ports: []*gitpod.PortsItems{{Port: 8080}},
+	}, config); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+
+	err = os.Remove(configService.location)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config = <-listener
+	if diff := cmp.Diff([]*gitpod.PortsItems{{Port: 3000}}, config.Ports); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestDevcontainerToGitpodConfig(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Dir         string
+		Content     string
+		Expectation *gitpod.GitpodConfig
+		Warnings    []string
+	}{
+		{
+			Desc: "image and extensions",
+			Dir:  ".",
+			Content: `{
+				"name": "Go",
+				"image": "mcr.microsoft.com/devcontainers/go:1", /* an inline comment */
+				"customizations": { "vscode": { "extensions": ["golang.go"] } }
+			}`,
+			Expectation: &gitpod.GitpodConfig{
+				Image:  "mcr.microsoft.com/devcontainers/go:1",
+				Vscode: &gitpod.Vscode{Extensions: []string{"golang.go"}},
+			},
+		},
+		{
+			Desc: "ports",
+			Dir:  ".devcontainer",
+			Content: `{
+				"forwardPorts": [3000, "localhost:5432", "db:5432"],
+				"portsAttributes": {
+					"3000": { "label": "Application", "onAutoForward": "openPreview", "protocol": "https" },
+					"9000-9100": { "onAutoForward": "silent" },
+					"[0-9]+": { "label": "regex" },
+				},
+			}`,
+			Expectation: &gitpod.GitpodConfig{
+				Ports: []*gitpod.PortsItems{
+					{Port: 3000, Name: "Application", OnOpen: "open-preview", Protocol: "https"},
+					{Port: 5432},
+					{Port: "9000-9100", OnOpen: "ignore"},
+				},
+			},
+			Warnings: []string{
+				"forwardPorts: db:5432 is not supported, only ports of the workspace can be forwarded",
+				"portsAttributes: [0-9]+ is not supported, only ports and port ranges are",
+			},
+		},
+		{
+			Desc: "lifecycle commands and env",
+			Dir:  ".devcontainer",
+			Content: `{
+				"onCreateCommand": ["npm", "install", "--prefix", "my dir"],
+				"postCreateCommand": { "build": "npm run build", "lint": "npm run lint" },
+				"postStartCommand": "npm start",
+				"postAttachCommand": "echo attached",
+				"containerEnv": { "NODE_ENV": "development", "PORT": "3000" },
+				"remoteEnv": { "PORT": "8080", "PATH": "${containerEnv:PATH}:/opt/bin" },
+			}`,
+			Expectation: &gitpod.GitpodConfig{
+				Tasks: []*gitpod.TasksItems{{
+					Name:    "Dev Container",
+					Init:    "npm install --prefix 'my dir'\n(npm run build) &\n(npm run lint) &\nwait",
+					Command: "npm start",
+					Env: map[string]interface{}{
+						"NODE_ENV": "development",
+						"PORT":     "8080",
+						"PATH":     "${containerEnv:PATH}:/opt/bin",
+					},
+				}},
+			},
+			Warnings: []string{
+				"postAttachCommand is not supported",
+				"variables in the value of environment variable PATH are not supported",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			devcontainer, err := gitpod.ParseDevcontainer([]byte(test.Content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, warnings := devcontainer.ToGitpodConfig(test.Dir)
+			if diff := cmp.Diff(test.Expectation, config); diff != "" {
+				t.Errorf("unexpected config (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.Warnings, warnings); diff != "" {
+				t.Errorf("unexpected warnings (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Content     string
+		Expectation []string
+	}{
+		{
+			Desc: "valid",
+			Content: `
+image:
+  file: .gitpod.Dockerfile
+ports:
+  - port: 3000
+  - port: 4000-4999
+tasks:
+  - name: server
+    command: yarn start
+    env:
+      PORT: 3000
+  - dependsOn: [server]
+    command: yarn test
+github:
+  prebuilds:
+    master: true
+`,
+		},
+		{
+			Desc:        "syntax error",
+			Content:     "tasks:\n\t- init: echo\n",
+			Expectation: []string{"2:1: error: found character that cannot start any token"},
+		},
+		{
+			Desc: "type errors and unknown keys",
+			Content: `
+image:
+  dockerfile: .gitpod.Dockerfile
+ports:
+  - port: true
+    onOpen: open-window
+  - port: 3000-
+tasks:
+  - before:
+    comand: yarn start
+github:
+  prebuilds: yes
+`,
+			Expectation: []string{
+				"3:3: error: image: unknown property \"dockerfile\"",
+				"3:3: error: image: missing required property \"file\"",
+				"5:11: error: ports[0].port: expected number or string, got boolean",
+				"6:13: error: ports[0].onOpen: \"open-window\" is not allowed, must be one of \"open-browser\", \"open-preview\", \"notify\", \"ignore\"",
+				"7:11: error: ports[1].port: \"3000-\" does not match the pattern ^\\d+[:-]\\d+$",
+				"9:12: error: tasks[0].before: expected string, got null",
+				"10:5: error: tasks[0]: unknown property \"comand\", did you mean \"command\"?",
+				"12:14: error: github.prebuilds: expected boolean or object, got string",
+			},
+		},
+		{
+			Desc: "task dependencies",
+			Content: `
+tasks:
+  - name: a
+    dependsOn: [b]
+  - name: b
+    dependsOn: [a]
+`,
+			Expectation: []string{"3:3: error: tasks: task dependencies form a cycle: a -> b -> a"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var act []string
+			for _, d := range validate.Validate([]byte(test.Content)) {
+				act = append(act, d.String())
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected diagnostics (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveInvalid(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "test-gitpod-config-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	locationReady := make(chan struct{})
+	configService := NewConfigService(tempDir+"/.gitpod.yml", locationReady, log)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	close(locationReady)
+
+	invalid := configService.ObserveInvalid(ctx)
+	go configService.Watch(ctx)
+	listener := configService.Observe(ctx)
+	<-listener
+
+	err = os.WriteFile(configService.location, []byte("ports:\n  - port: 8080\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-listener
+
+	err = os.WriteFile(configService.location, []byte("ports:\n  - port: 8080\n  - prot: 8081\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case cfg := <-invalid:
+		var act []string
+		for _, d := range cfg.Diagnostics {
+			act = append(act, d.String())
+		}
+		if diff := cmp.Diff([]string{
+			"3:5: error: ports[1]: unknown property \"prot\", did you mean \"port\"?",
+			"3:5: error: ports[1]: missing required property \"port\"",
+		}, act); diff != "" {
+			t.Errorf("unexpected diagnostics (-want +got):\n%s", diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the invalid config to be reported")
+	}
+}
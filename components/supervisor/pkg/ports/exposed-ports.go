@@ -21,6 +21,26 @@ type ExposedPort struct {
 	LocalPort uint32
 	URL       string
 	Public    bool
+	// Protected ports are public behind an access policy
+	Protected bool
+}
+
+// ExposeOptions determine who can access an exposed port
+type ExposeOptions struct {
+	// Public ports are accessible by everyone with the port URL
+	Public bool
+	// AccessPolicy protects a non-public port, i.e. it is accessible by everyone meeting the policy
+	AccessPolicy *gitpod.PortAccessPolicy
+}
+
+func (o ExposeOptions) visibility() string {
+	if o.Public {
+		return gitpod.PortVisibilityPublic
+	}
+	if o.AccessPolicy != nil {
+		return gitpod.PortVisibilityProtected
+	}
+	return gitpod.PortVisibilityPrivate
 }
 
 // ExposedPortsInterface provides access to port exposure
@@ -36,7 +56,7 @@ type ExposedPortsInterface interface {
 	Run(ctx context.Context)
 
 	// Expose exposes a port to the internet. Upon successful execution any Observer will be updated.
-	Expose(ctx context.Context, port uint32, public bool) <-chan error
+	Expose(ctx context.Context, port uint32, opts ExposeOptions) <-chan error
 }
 
 // NoopExposedPorts implements ExposedPortsInterface but does nothing
@@ -51,7 +71,7 @@ func (*NoopExposedPorts) Observe(ctx context.Context) (<-chan []ExposedPort, <-c
 func (*NoopExposedPorts) Run(ctx context.Context) {}
 
 // Expose exposes a port to the internet. Upon successful execution any Observer will be updated.
-func (*NoopExposedPorts) Expose(ctx context.Context, local uint32, public bool) <-chan error {
+func (*NoopExposedPorts) Expose(ctx context.Context, local uint32, opts ExposeOptions) <-chan error {
 	done := make(chan error)
 	close(done)
 	return done
@@ -139,7 +159,8 @@ func (g *GitpodExposedPorts) Observe(ctx context.Context) (<-chan []ExposedPort,
 			for _, p := range serverExposePort {
 				res[uint32(p.Port)] = ExposedPort{
 					LocalPort: uint32(p.Port),
-					Public:    p.Visibility == "public",
+					Public:    p.Visibility == gitpod.PortVisibilityPublic,
+					Protected: p.Visibility == gitpod.PortVisibilityProtected,
 					URL:       p.URL,
 				}
 			}
@@ -226,8 +247,9 @@ func (g *GitpodExposedPorts) doExpose(req *exposePortRequest) {
 }
 
 // Expose exposes a port to the internet. Upon successful execution any Observer will be updated.
-func (g *GitpodExposedPorts) Expose(ctx context.Context, local uint32, public bool) <-chan error {
-	if !public {
+func (g *GitpodExposedPorts) Expose(ctx context.Context, local uint32, opts ExposeOptions) <-chan error {
+	visibility := opts.visibility()
+	if visibility == gitpod.PortVisibilityPrivate {
 		if !g.existInLocalExposed(local) {
 			g.localExposedPort = append(g.localExposedPort, local)
 			g.localExposedNotice <- struct{}{}
@@ -238,8 +260,9 @@ func (g *GitpodExposedPorts) Expose(ctx context.Context, local uint32, public bo
 	}
 	req := &exposePortRequest{
 		port: &gitpod.WorkspaceInstancePort{
-			Port:       float64(local),
-			Visibility: "public",
+			Port:         float64(local),
+			Visibility:   visibility,
+			AccessPolicy: opts.AccessPolicy,
 		},
 		ctx:  ctx,
 		done: make(chan error),
@@ -17,7 +17,7 @@ import (
 
 const NON_CONFIGED_BASIC_SCORE = 100000
 
-// RangeConfig is a port range config.
+// RangeConfig is a port range config. A named port range is a port group.
 type RangeConfig struct {
 	gitpod.PortsItems
 	Start uint32
@@ -29,6 +29,8 @@ type RangeConfig struct {
 type SortConfig struct {
 	gitpod.PortConfig
 	Sort uint32
+	// Group is the name of the port group the port belongs to
+	Group string
 }
 
 // Configs provides access to port configurations.
@@ -43,12 +45,13 @@ func (configs *Configs) ForEach(callback func(port uint32, config *SortConfig))
 		return
 	}
 	visited := make(map[uint32]struct{})
-	for port, config := range configs.instancePortConfigs {
+	for port := range configs.instancePortConfigs {
 		_, exists := visited[port]
 		if exists {
 			continue
 		}
 		visited[port] = struct{}{}
+		config, _, _ := configs.Get(port)
 		callback(port, config)
 	}
 }
@@ -64,29 +67,68 @@ var (
 )
 
 // Get returns the config for the give port.
+// A port config takes precedence over range configs. Ports configured within a port group
+// belong to the group and inherit the settings they don't specify from it.
 func (configs *Configs) Get(port uint32) (*SortConfig, ConfigKind, bool) {
 	if configs == nil {
 		return nil, PortConfigKind, false
 	}
+	rangeConfig := configs.getRange(port)
 	config, exists := configs.instancePortConfigs[port]
 	if exists {
-		return config, PortConfigKind, true
+		if rangeConfig == nil || rangeConfig.Name == "" {
+			return config, PortConfigKind, true
+		}
+		grouped := *config
+		grouped.Group = rangeConfig.Name
+		if grouped.OnOpen == "" {
+			grouped.OnOpen = rangeConfig.OnOpen
+		}
+		if grouped.Visibility == "" {
+			grouped.Visibility = rangeConfig.Visibility
+		}
+		if len(grouped.AllowedCIDRs) == 0 {
+			grouped.AllowedCIDRs = rangeConfig.AllowedCIDRs
+		}
+		if grouped.Protocol == "" {
+			grouped.Protocol = rangeConfig.Protocol
+		}
+		if grouped.Description == "" {
+			grouped.Description = rangeConfig.Description
+		}
+		return &grouped, PortConfigKind, true
+	}
+	if rangeConfig != nil {
+		return &SortConfig{
+			PortConfig: gitpod.PortConfig{
+				Port:         float64(port),
+				OnOpen:       rangeConfig.OnOpen,
+				Visibility:   rangeConfig.Visibility,
+				AllowedCIDRs: rangeConfig.AllowedCIDRs,
+				Protocol:     rangeConfig.Protocol,
+				Description:  rangeConfig.Description,
+				Name:         rangeConfig.Name,
+			},
+			Sort:  rangeConfig.Sort,
+			Group: rangeConfig.Name,
+		}, RangeConfigKind, true
 	}
+	return nil, PortConfigKind, false
+}
+
+// getRange returns the range config of the given port. If ranges overlap, the narrowest one wins.
+// Ranges of the same size take precedence in the order they are configured.
+func (configs *Configs) getRange(port uint32) *RangeConfig {
+	var res *RangeConfig
 	for _, rangeConfig := range configs.instanceRangeConfigs {
-		if rangeConfig.Start <= port && port <= rangeConfig.End {
-			return &SortConfig{
-				PortConfig: gitpod.PortConfig{
-					Port:        float64(port),
-					OnOpen:      rangeConfig.OnOpen,
-					Visibility:  rangeConfig.Visibility,
-					Description: rangeConfig.Description,
-					Name:        rangeConfig.Name,
-				},
-				Sort: rangeConfig.Sort,
-			}, RangeConfigKind, true
+		if port < rangeConfig.Start || rangeConfig.End < port {
+			continue
+		}
+		if res == nil || rangeConfig.End-rangeConfig.Start < res.End-res.Start {
+			res = rangeConfig
 		}
 	}
-	return nil, PortConfigKind, false
+	return res
 }
 
 // ConfigInterace allows to watch port configurations.
@@ -148,7 +190,8 @@ func (service *ConfigService) update(config *gitpod.GitpodConfig, current *Confi
 	currentPortConfigs, currentRangeConfigs := current.instancePortConfigs, current.instanceRangeConfigs
 	var ports []*gitpod.PortsItems
 	if config != nil {
-		ports = config.Ports
+		ports = append(ports, config.Ports...)
+		ports = append(ports, servicePortsConfigs(config.Services)...)
 	}
 	portConfigs, rangeConfigs := parseInstanceConfigs(ports)
 	current.instancePortConfigs = portConfigs
@@ -156,6 +199,26 @@ func (service *ConfigService) update(config *gitpod.GitpodConfig, current *Confi
 	return !reflect.DeepEqual(currentPortConfigs, portConfigs) || !reflect.DeepEqual(currentRangeConfigs, rangeConfigs)
 }
 
+// servicePortsConfigs returns port configurations for the ports of service containers, named after their service.
+// Since the first configuration of a port wins, ports configured explicitly take precedence.
+func servicePortsConfigs(services []*gitpod.ServicesItems) []*gitpod.PortsItems {
+	var res []*gitpod.PortsItems
+	for _, service := range services {
+		if service == nil {
+			continue
+		}
+		for _, port := range service.Ports {
+			res = append(res, &gitpod.PortsItems{
+				Port:        port,
+				Name:        service.Name,
+				Description: fmt.Sprintf("Service %s (%s)", service.Name, service.Image),
+				OnOpen:      "ignore",
+			})
+		}
+	}
+	return res
+}
+
 var portRangeRegexp = regexp.MustCompile(`^(\d+)[-:](\d+)$`)
 
 func parseInstanceConfigs(ports []*gitpod.PortsItems) (portConfigs map[uint32]*SortConfig, rangeConfigs []*RangeConfig) {
@@ -175,11 +238,13 @@ func parseInstanceConfigs(ports []*gitpod.PortsItems) (portConfigs map[uint32]*S
 			if !exists {
 				portConfigs[port] = &SortConfig{
 					PortConfig: gitpod.PortConfig{
-						OnOpen:      config.OnOpen,
-						Port:        float64(Port),
-						Visibility:  config.Visibility,
-						Description: config.Description,
-						Name:        config.Name,
+						OnOpen:       config.OnOpen,
+						Port:         float64(Port),
+						Visibility:   config.Visibility,
+						AllowedCIDRs: config.AllowedCIDRs,
+						Protocol:     config.Protocol,
+						Description:  config.Description,
+						Name:         config.Name,
 					},
 					Sort: uint32(index),
 				}
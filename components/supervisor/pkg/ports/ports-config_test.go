@@ -78,6 +78,56 @@ func TestPortsConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			Desc: "service port config",
+			GitpodConfig: &gitpod.GitpodConfig{
+				Services: []*gitpod.ServicesItems{
+					{
+						Name:  "postgres",
+						Image: "postgres:14",
+						Ports: []float64{5432},
+					},
+				},
+			},
+			Expectation: &PortConfigTestExpectations{
+				InstancePortConfigs: []*gitpod.PortConfig{
+					{
+						Port:        5432,
+						OnOpen:      "ignore",
+						Name:        "postgres",
+						Description: "Service postgres (postgres:14)",
+					},
+				},
+			},
+		},
+		{
+			Desc: "instance port config takes precedence over service port config",
+			GitpodConfig: &gitpod.GitpodConfig{
+				Ports: []*gitpod.PortsItems{
+					{
+						Port:       5432,
+						Visibility: "private",
+						Name:       "Database",
+					},
+				},
+				Services: []*gitpod.ServicesItems{
+					{
+						Name:  "postgres",
+						Image: "postgres:14",
+						Ports: []float64{5432},
+					},
+				},
+			},
+			Expectation: &PortConfigTestExpectations{
+				InstancePortConfigs: []*gitpod.PortConfig{
+					{
+						Port:       5432,
+						Visibility: "private",
+						Name:       "Database",
+					},
+				},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.Desc, func(t *testing.T) {
@@ -136,3 +186,119 @@ func (service *testGitpodConfigService) Watch(ctx context.Context) {
 func (service *testGitpodConfigService) Observe(ctx context.Context) <-chan *gitpod.GitpodConfig {
 	return service.configs
 }
+
+func TestPortsConfigGet(t *testing.T) {
+	portConfigs, rangeConfigs := parseInstanceConfigs([]*gitpod.PortsItems{
+		{Port: "6000-7000", OnOpen: "ignore"},
+		{Port: 6006, OnOpen: "open-browser"},
+		{Port: "6006-6010", Name: "storybook", Description: "Storybook", Protocol: "https", Visibility: "public", OnOpen: "notify"},
+		{Port: "6009-6013", Name: "docs", Protocol: "http"},
+		{Port: "6011-6015", Name: "api", Protocol: "tcp"},
+		{Port: 3000, Name: "web"},
+	})
+	configs := &Configs{
+		instancePortConfigs:  portConfigs,
+		instanceRangeConfigs: rangeConfigs,
+	}
+
+	type expectation struct {
+		Config *SortConfig
+		Kind   ConfigKind
+		Exists bool
+	}
+	tests := []struct {
+		Desc        string
+		Port        uint32
+		Expectation expectation
+	}{
+		{
+			Desc:        "not configured",
+			Port:        8080,
+			Expectation: expectation{Kind: PortConfigKind},
+		},
+		{
+			Desc: "port config outside of groups",
+			Port: 3000,
+			Expectation: expectation{
+				Config: &SortConfig{PortConfig: gitpod.PortConfig{Port: 3000, Name: "web"}, Sort: 5},
+				Kind:   PortConfigKind,
+				Exists: true,
+			},
+		},
+		{
+			Desc: "port of a group",
+			Port: 6007,
+			Expectation: expectation{
+				Config: &SortConfig{
+					PortConfig: gitpod.PortConfig{Port: 6007, Name: "storybook", Description: "Storybook", Protocol: "https", Visibility: "public", OnOpen: "notify"},
+					Sort:       2,
+					Group:      "storybook",
+				},
+				Kind:   RangeConfigKind,
+				Exists: true,
+			},
+		},
+		{
+			Desc: "port config takes precedence over the group and inherits unspecified settings",
+			Port: 6006,
+			Expectation: expectation{
+				Config: &SortConfig{
+					PortConfig: gitpod.PortConfig{Port: 6006, Description: "Storybook", Protocol: "https", Visibility: "public", OnOpen: "open-browser"},
+					Sort:       1,
+					Group:      "storybook",
+				},
+				Kind:   PortConfigKind,
+				Exists: true,
+			},
+		},
+		{
+			Desc: "wider anonymous range",
+			Port: 6500,
+			Expectation: expectation{
+				Config: &SortConfig{PortConfig: gitpod.PortConfig{Port: 6500, OnOpen: "ignore"}},
+				Kind:   RangeConfigKind,
+				Exists: true,
+			},
+		},
+		{
+			Desc: "overlapping groups of the same size are taken in order",
+			Port: 6010,
+			Expectation: expectation{
+				Config: &SortConfig{
+					PortConfig: gitpod.PortConfig{Port: 6010, Name: "storybook", Description: "Storybook", Protocol: "https", Visibility: "public", OnOpen: "notify"},
+					Sort:       2,
+					Group:      "storybook",
+				},
+				Kind:   RangeConfigKind,
+				Exists: true,
+			},
+		},
+		{
+			Desc: "overlapping groups",
+			Port: 6012,
+			Expectation: expectation{
+				Config: &SortConfig{PortConfig: gitpod.PortConfig{Port: 6012, Name: "docs", Protocol: "http"}, Sort: 3, Group: "docs"},
+				Kind:   RangeConfigKind,
+				Exists: true,
+			},
+		},
+		{
+			Desc: "narrower group within a wider range",
+			Port: 6014,
+			Expectation: expectation{
+				Config: &SortConfig{PortConfig: gitpod.PortConfig{Port: 6014, Name: "api", Protocol: "tcp"}, Sort: 4, Group: "api"},
+				Kind:   RangeConfigKind,
+				Exists: true,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var act expectation
+			act.Config, act.Kind, act.Exists = configs.Get(test.Port)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected Get (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package ports
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -15,6 +16,7 @@ import (
 	"net/url"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -65,12 +67,13 @@ func NewManager(exposed ExposedPortsInterface, served ServedPortsObserver, confi
 type localhostProxy struct {
 	io.Closer
 	proxyPort uint32
+	protocol  api.PortProtocol
 }
 
 type autoExposure struct {
-	state  api.PortAutoExposure
-	ctx    context.Context
-	public bool
+	state api.PortAutoExposure
+	ctx   context.Context
+	opts  ExposeOptions
 }
 
 // Manager brings together served and exposed ports. It keeps track of which port is exposed, which one is served,
@@ -85,7 +88,7 @@ type Manager struct {
 
 	internal     map[uint32]struct{}
 	proxies      map[uint32]*localhostProxy
-	proxyStarter func(port uint32) (proxy io.Closer, err error)
+	proxyStarter func(port uint32, protocol api.PortProtocol) (proxy io.Closer, err error)
 	autoExposed  map[uint32]*autoExposure
 
 	autoTunneled      map[uint32]struct{}
@@ -109,6 +112,8 @@ type managedPort struct {
 	Visibility   api.PortVisibility
 	Description  string
 	Name         string
+	Group        string
+	Protocol     api.PortProtocol
 	URL          string
 	OnExposed    api.OnPortExposedAction // deprecated
 	OnOpen       api.PortsStatus_OnOpenAction
@@ -285,6 +290,8 @@ func (pm *Manager) updateState(ctx context.Context, exposed []ExposedPort, serve
 
 	if configured != nil {
 		pm.configs = configured
+		// the protocol of served ports might have changed
+		pm.updateProxies()
 	}
 
 	newState := pm.nextState(ctx)
@@ -327,6 +334,8 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 		if exists {
 			mp.Name = config.Name
 			mp.Description = config.Description
+			mp.Group = config.Group
+			mp.Protocol = getPortProtocol(config)
 		}
 		state[port] = mp
 		return mp
@@ -341,6 +350,8 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 		Visibility := api.PortVisibility_private
 		if exposed.Public {
 			Visibility = api.PortVisibility_public
+		} else if exposed.Protected {
+			Visibility = api.PortVisibility_protected
 		}
 		mp := genManagedPort(port)
 		mp.Exposed = true
@@ -375,12 +386,8 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 				return
 			}
 
-			mp.Visibility = api.PortVisibility_private
-			if config.Visibility == "public" {
-				mp.Visibility = api.PortVisibility_public
-			}
-			public := mp.Visibility == api.PortVisibility_public
-			mp.AutoExposure = pm.autoExpose(ctx, mp.LocalhostPort, public).state
+			mp.Visibility = getPortVisibility(config)
+			mp.AutoExposure = pm.autoExpose(ctx, mp.LocalhostPort, getExposeOptions(mp.Visibility, config)).state
 		})
 	}
 
@@ -401,21 +408,21 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 			continue
 		}
 
-		var public bool
+		var visibility api.PortVisibility
 		config, kind, exists := pm.configs.Get(mp.LocalhostPort)
 
 		configured := exists && kind == PortConfigKind
 		if mp.Exposed || configured {
-			public = mp.Visibility == api.PortVisibility_public
+			visibility = mp.Visibility
 		} else {
-			public = exists && config.Visibility == "public"
+			visibility = getPortVisibility(config)
 		}
 
-		if mp.Exposed && ((mp.Visibility == api.PortVisibility_public && public) || (mp.Visibility == api.PortVisibility_private && !public)) {
+		if mp.Exposed && mp.Visibility == visibility {
 			continue
 		}
 
-		mp.AutoExposure = pm.autoExpose(ctx, mp.LocalhostPort, public).state
+		mp.AutoExposure = pm.autoExpose(ctx, mp.LocalhostPort, getExposeOptions(visibility, config)).state
 	}
 
 	var ports []uint32
@@ -436,12 +443,12 @@ func (pm *Manager) nextState(ctx context.Context) map[uint32]*managedPort {
 }
 
 // clients should guard a call with check whether such port is already exposed or auto exposed
-func (pm *Manager) autoExpose(ctx context.Context, localPort uint32, public bool) *autoExposure {
-	exposing := pm.E.Expose(ctx, localPort, public)
+func (pm *Manager) autoExpose(ctx context.Context, localPort uint32, opts ExposeOptions) *autoExposure {
+	exposing := pm.E.Expose(ctx, localPort, opts)
 	autoExpose := &autoExposure{
-		state:  api.PortAutoExposure_trying,
-		ctx:    ctx,
-		public: public,
+		state: api.PortAutoExposure_trying,
+		ctx:   ctx,
+		opts:  opts,
 	}
 	go func() {
 		err := <-exposing
@@ -468,7 +475,7 @@ func (pm *Manager) RetryAutoExpose(ctx context.Context, localPort uint32) {
 	if !autoExposed || autoExpose.state != api.PortAutoExposure_failed || autoExpose.ctx.Err() != nil {
 		return
 	}
-	pm.autoExpose(autoExpose.ctx, localPort, autoExpose.public)
+	pm.autoExpose(autoExpose.ctx, localPort, autoExpose.opts)
 	pm.forceUpdate()
 }
 
@@ -525,7 +532,7 @@ func (pm *Manager) updateProxies() {
 	}
 
 	for port, proxy := range pm.proxies {
-		if boundToLocalhost, exists := servedPortMap[port]; !exists || !boundToLocalhost {
+		if boundToLocalhost, exists := servedPortMap[port]; !exists || !boundToLocalhost || proxy.protocol != pm.getProtocol(port) {
 			delete(pm.proxies, port)
 			err := proxy.Close()
 			if err != nil {
@@ -543,16 +550,18 @@ func (pm *Manager) updateProxies() {
 			continue
 		}
 
-		proxy, err := pm.proxyStarter(localPort)
+		protocol := pm.getProtocol(localPort)
+		proxy, err := pm.proxyStarter(localPort, protocol)
 		if err != nil {
 			log.WithError(err).WithField("localPort", localPort).Warn("cannot start localhost proxy")
 			continue
 		}
-		log.WithField("localPort", localPort).Info("localhost proxy has been started")
+		log.WithField("localPort", localPort).WithField("protocol", protocol.String()).Info("localhost proxy has been started")
 
 		pm.proxies[localPort] = &localhostProxy{
 			Closer:    proxy,
 			proxyPort: localPort,
+			protocol:  protocol,
 		}
 	}
 }
@@ -580,6 +589,59 @@ func getOnExposedAction(config *gitpod.PortConfig, port uint32) api.OnPortExpose
 	return api.OnPortExposedAction_notify
 }
 
+func (pm *Manager) getProtocol(port uint32) api.PortProtocol {
+	config, _, exists := pm.configs.Get(port)
+	if !exists {
+		return api.PortProtocol_http
+	}
+	return getPortProtocol(config)
+}
+
+// getPortProtocol returns the configured protocol of a port, the default is http.
+func getPortProtocol(config *SortConfig) api.PortProtocol {
+	switch strings.ToLower(config.Protocol) {
+	case gitpod.PortProtocolHTTPS:
+		return api.PortProtocol_https
+	case gitpod.PortProtocolTCP:
+		return api.PortProtocol_tcp
+	default:
+		return api.PortProtocol_http
+	}
+}
+
+// getPortVisibility returns the configured visibility of a port, ports without config are private.
+func getPortVisibility(config *SortConfig) api.PortVisibility {
+	if config == nil {
+		return api.PortVisibility_private
+	}
+	switch config.Visibility {
+	case gitpod.PortVisibilityPublic:
+		return api.PortVisibility_public
+	case gitpod.PortVisibilityProtected:
+		return api.PortVisibility_protected
+	default:
+		return api.PortVisibility_private
+	}
+}
+
+// getExposeOptions returns how to expose a port with the given visibility.
+// Protected ports are accessible from the allowed CIDRs of the port config. Their basic auth is left unset, s.t.
+// re-exposing a port keeps the basic auth set by gp ports visibility.
+func getExposeOptions(visibility api.PortVisibility, config *SortConfig) ExposeOptions {
+	switch visibility {
+	case api.PortVisibility_public:
+		return ExposeOptions{Public: true}
+	case api.PortVisibility_protected:
+		policy := &gitpod.PortAccessPolicy{}
+		if config != nil {
+			policy.AllowedCIDRs = config.AllowedCIDRs
+		}
+		return ExposeOptions{AccessPolicy: policy}
+	default:
+		return ExposeOptions{}
+	}
+}
+
 func getOnOpenAction(config *gitpod.PortConfig, port uint32) api.PortsStatus_OnOpenAction {
 	if config == nil {
 		// anything above 32767 seems odd (e.g. used by language servers)
@@ -638,8 +700,11 @@ func (pm *Manager) Expose(ctx context.Context, port uint32) error {
 	pm.mu.RUnlock()
 	unlock = false
 
-	public := exists && config.Visibility != "private"
-	err := <-pm.E.Expose(ctx, port, public)
+	opts := ExposeOptions{Public: exists && config.Visibility != gitpod.PortVisibilityPrivate}
+	if exists && config.Visibility == gitpod.PortVisibilityProtected {
+		opts = getExposeOptions(api.PortVisibility_protected, config)
+	}
+	err := <-pm.E.Expose(ctx, port, opts)
 	if err != nil && err != context.Canceled {
 		log.WithError(err).WithField("port", port).Error("cannot expose port")
 	}
@@ -773,6 +838,8 @@ func (pm *Manager) getPortStatus(port uint32) *api.PortsStatus {
 		Description: mp.Description,
 		Name:        mp.Name,
 		OnOpen:      mp.OnOpen,
+		Group:       mp.Group,
+		Protocol:    mp.Protocol,
 	}
 	if mp.Exposed && mp.URL != "" {
 		ps.Exposed = &api.ExposedPortInfo{
@@ -792,15 +859,28 @@ func (pm *Manager) getPortStatus(port uint32) *api.PortsStatus {
 	return ps
 }
 
-func startLocalhostProxy(port uint32) (io.Closer, error) {
+func startLocalhostProxy(port uint32, protocol api.PortProtocol) (io.Closer, error) {
 	host := fmt.Sprintf("localhost:%d", port)
+	if protocol == api.PortProtocol_tcp {
+		return startLocalhostTCPProxy(port, host)
+	}
 
-	dsturl, err := url.Parse("http://" + host)
+	scheme := "http"
+	if protocol == api.PortProtocol_https {
+		scheme = "https"
+	}
+	dsturl, err := url.Parse(scheme + "://" + host)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot produce proxy destination URL: %w", err)
 	}
 
 	proxy := httputil.NewSingleHostReverseProxy(dsturl)
+	if protocol == api.PortProtocol_https {
+		// services in the workspace terminating TLS themselves usually use self-signed certificates
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		proxy.Transport = transport
+	}
 	originalDirector := proxy.Director
 	proxy.Director = func(req *http.Request) {
 		req.Host = host
@@ -854,6 +934,54 @@ func startLocalhostProxy(port uint32) (io.Closer, error) {
 	return srv, nil
 }
 
+// startLocalhostTCPProxy forwards plain TCP connections for services not speaking HTTP.
+func startLocalhostTCPProxy(port uint32, host string) (io.Closer, error) {
+	proxyAddr := fmt.Sprintf("%v:%d", workspaceIPAdress, port)
+	lis, err := net.Listen("tcp", proxyAddr)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot listen on proxy port %d: %w", port, err)
+	}
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			if err != nil {
+				log.WithError(err).WithField("local-port", port).Error("localhost proxy failed")
+				return
+			}
+			go func() {
+				defer conn.Close()
+
+				dst, err := net.Dial("tcp", host)
+				if err != nil {
+					if !errors.Is(err, syscall.ECONNREFUSED) {
+						log.WithError(err).WithField("local-port", port).Warn("localhost proxy connection failed")
+					}
+					return
+				}
+				defer dst.Close()
+
+				done := make(chan struct{}, 2)
+				go func() {
+					_, _ = io.Copy(dst, conn)
+					done <- struct{}{}
+				}()
+				go func() {
+					_, _ = io.Copy(conn, dst)
+					done <- struct{}{}
+				}()
+				// closing both connections once either direction is done stops the other one
+				<-done
+			}()
+		}
+	}()
+
+	return lis, nil
+}
+
 func defaultRoutableIP() string {
 	iface, err := nettest.RoutedInterface("ip", net.FlagUp|net.FlagBroadcast)
 	if err != nil {
@@ -136,6 +136,31 @@ func TestPortsUpdateState(t *testing.T) {
 				},
 			},
 		},
+		{
+			Desc: "auto expose configured protected ports",
+			Changes: []Change{
+				{
+					Config: &ConfigChange{instance: []*gitpod.PortsItems{
+						{Port: 8080, Visibility: "protected", AllowedCIDRs: []string{"203.0.113.0/24"}},
+					}},
+				},
+				{
+					Exposed: []ExposedPort{{LocalPort: 8080, Protected: true, URL: "foobar"}},
+				},
+				{
+					Served: []ServedPort{{net.IPv4zero, 8080, false}},
+				},
+			},
+			ExpectedExposure: []ExposedPort{
+				{LocalPort: 8080, Protected: true},
+			},
+			ExpectedUpdates: UpdateExpectation{
+				{},
+				[]*api.PortsStatus{{LocalPort: 8080, OnOpen: api.PortsStatus_notify}},
+				[]*api.PortsStatus{{LocalPort: 8080, OnOpen: api.PortsStatus_notify, Exposed: &api.ExposedPortInfo{Visibility: api.PortVisibility_protected, OnExposed: api.OnPortExposedAction_notify, Url: "foobar"}}},
+				[]*api.PortsStatus{{LocalPort: 8080, Served: true, OnOpen: api.PortsStatus_notify, Exposed: &api.ExposedPortInfo{Visibility: api.PortVisibility_protected, OnExposed: api.OnPortExposedAction_notify, Url: "foobar"}}},
+			},
+		},
 		{
 			Desc: "auto expose configured ports",
 			Changes: []Change{
@@ -507,13 +532,13 @@ func TestPortsUpdateState(t *testing.T) {
 					{LocalPort: 3000, Name: "react", OnOpen: api.PortsStatus_notify},
 				},
 				{
-					{LocalPort: 5002, Name: "react", Served: true, OnOpen: api.PortsStatus_notify},
+					{LocalPort: 5002, Name: "react", Group: "react", Served: true, OnOpen: api.PortsStatus_notify},
 					{LocalPort: 3001, Name: "react", OnOpen: api.PortsStatus_notify},
 					{LocalPort: 3000, Name: "react", OnOpen: api.PortsStatus_notify},
 				},
 				{
-					{LocalPort: 5001, Name: "react", Served: true, OnOpen: api.PortsStatus_notify},
-					{LocalPort: 5002, Name: "react", Served: true, OnOpen: api.PortsStatus_notify},
+					{LocalPort: 5001, Name: "react", Group: "react", Served: true, OnOpen: api.PortsStatus_notify},
+					{LocalPort: 5002, Name: "react", Group: "react", Served: true, OnOpen: api.PortsStatus_notify},
 					{LocalPort: 3001, Name: "react", OnOpen: api.PortsStatus_notify},
 					{LocalPort: 3000, Name: "react", OnOpen: api.PortsStatus_notify},
 				},
@@ -593,19 +618,19 @@ func TestPortsUpdateState(t *testing.T) {
 					{LocalPort: 3000, Name: "react", OnOpen: api.PortsStatus_notify},
 				},
 				{
-					{LocalPort: 3003, Name: "react", OnOpen: api.PortsStatus_notify},
-					{LocalPort: 3001, Name: "react", OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3003, Name: "react", Group: "react", OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3001, Name: "react", Group: "react", OnOpen: api.PortsStatus_notify},
 					{LocalPort: 3000, Name: "react", OnOpen: api.PortsStatus_notify},
 				},
 				{
-					{LocalPort: 3003, Name: "react", OnOpen: api.PortsStatus_notify},
-					{LocalPort: 3001, Name: "react", OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3003, Name: "react", Group: "react", OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3001, Name: "react", Group: "react", OnOpen: api.PortsStatus_notify},
 					{LocalPort: 3000, Served: true, Name: "react", OnOpen: api.PortsStatus_notify},
 				},
 				{
-					{LocalPort: 3003, Name: "react", OnOpen: api.PortsStatus_notify},
-					{LocalPort: 3001, Served: true, Name: "react", OnOpen: api.PortsStatus_notify},
-					{LocalPort: 3002, Served: true, Name: "react", OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3003, Name: "react", Group: "react", OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3001, Served: true, Name: "react", Group: "react", OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3002, Served: true, Name: "react", Group: "react", OnOpen: api.PortsStatus_notify},
 					{LocalPort: 3000, Served: true, Name: "react", OnOpen: api.PortsStatus_notify},
 				},
 				{
@@ -615,9 +640,9 @@ func TestPortsUpdateState(t *testing.T) {
 					{LocalPort: 3002, Served: true, OnOpen: api.PortsStatus_notify_private},
 				},
 				{
-					{LocalPort: 3001, Name: "react", Served: true, OnOpen: api.PortsStatus_notify},
-					{LocalPort: 3002, Name: "react", Served: true, OnOpen: api.PortsStatus_notify},
-					{LocalPort: 3003, Name: "react", OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3001, Name: "react", Group: "react", Served: true, OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3002, Name: "react", Group: "react", Served: true, OnOpen: api.PortsStatus_notify},
+					{LocalPort: 3003, Name: "react", Group: "react", OnOpen: api.PortsStatus_notify},
 					{LocalPort: 3000, Served: true, Name: "react", OnOpen: api.PortsStatus_notify},
 				},
 			},
@@ -669,7 +694,7 @@ func TestPortsUpdateState(t *testing.T) {
 				pm    = NewManager(exposed, served, config, tunneled, test.InternalPorts...)
 				updts [][]*api.PortsStatus
 			)
-			pm.proxyStarter = func(port uint32) (io.Closer, error) {
+			pm.proxyStarter = func(port uint32, protocol api.PortProtocol) (io.Closer, error) {
 				return io.NopCloser(nil), nil
 			}
 
@@ -788,13 +813,14 @@ func (tep *testExposedPorts) Observe(ctx context.Context) (<-chan []ExposedPort,
 func (tep *testExposedPorts) Run(ctx context.Context) {
 }
 
-func (tep *testExposedPorts) Expose(ctx context.Context, local uint32, public bool) <-chan error {
+func (tep *testExposedPorts) Expose(ctx context.Context, local uint32, opts ExposeOptions) <-chan error {
 	tep.mu.Lock()
 	defer tep.mu.Unlock()
 
 	tep.Exposures = append(tep.Exposures, ExposedPort{
 		LocalPort: local,
-		Public:    public,
+		Public:    opts.Public,
+		Protected: !opts.Public && opts.AccessPolicy != nil,
 	})
 	return nil
 }
@@ -831,7 +857,7 @@ func TestPortsConcurrentSubscribe(t *testing.T) {
 		}
 		pm = NewManager(exposed, served, config, tunneled)
 	)
-	pm.proxyStarter = func(local uint32) (io.Closer, error) {
+	pm.proxyStarter = func(local uint32, protocol api.PortProtocol) (io.Closer, error) {
 		return io.NopCloser(nil), nil
 	}
 
@@ -31,6 +31,7 @@ type APIInterface interface {
 	GetToken(ctx context.Context, query *gitpod.GetTokenSearchOptions) (res *gitpod.Token, err error)
 	OpenPort(ctx context.Context, workspaceID string, port *gitpod.WorkspaceInstancePort) (res *gitpod.WorkspaceInstancePort, err error)
 	InstanceUpdates(ctx context.Context, instanceID string, workspaceID string) (<-chan *gitpod.WorkspaceInstance, error)
+	GetEnvVars(ctx context.Context) (res []*gitpod.UserEnvVarValue, err error)
 
 	// Remove this and use segment client directly
 	TrackEvent(ctx context.Context, event *gitpod.RemoteTrackMessage) (err error)
@@ -83,6 +84,7 @@ func NewServerApiService(ctx context.Context, cfg *ServiceConfig, tknsrv api.Tok
 			"function:openPort",
 			"function:trackEvent",
 			"function:getWorkspace",
+			"function:getEnvVars",
 		},
 	})
 	if err != nil {
@@ -210,7 +212,8 @@ func (s *Service) OpenPort(ctx context.Context, workspaceID string, port *gitpod
 	if s == nil {
 		return nil, errNotConnected
 	}
-	if !s.usePublicAPI(ctx) {
+	// the public API does not support access policies of protected ports yet
+	if !s.usePublicAPI(ctx) || port.Visibility == gitpod.PortVisibilityProtected {
 		return s.gitpodService.OpenPort(ctx, workspaceID, port)
 	}
 	service := v1.NewWorkspacesServiceClient(s.publicAPIConn)
@@ -362,6 +365,14 @@ func (s *Service) GetOwnerID(ctx context.Context, workspaceID string) (ownerID s
 	return resp.Result.OwnerId, nil
 }
 
+// GetEnvVars implements APIInterface
+func (s *Service) GetEnvVars(ctx context.Context) (res []*gitpod.UserEnvVarValue, err error) {
+	if s == nil {
+		return nil, errNotConnected
+	}
+	return s.gitpodService.GetEnvVars(ctx)
+}
+
 func (s *Service) TrackEvent(ctx context.Context, event *gitpod.RemoteTrackMessage) (err error) {
 	if s == nil {
 		return errNotConnected
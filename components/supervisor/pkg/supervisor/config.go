@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"time"
 
 	env "github.com/Netflix/go-env"
@@ -24,12 +25,16 @@ import (
 
 const supervisorConfigFile = "supervisor-config.json"
 
+// additionalIDENameRegexp restricts the names of additional IDE backends s.t. they can be used in URL paths.
+var additionalIDENameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
 // Supervisor's configuration is dictated by three different lifecycles/sources:
 //   1. supervisor (static):
 //                  there's some configuration that lives with supervisor and its "installation",
 //                  For example the IDE config location depends on if supervisor is served via registry-facade.
 //   2. IDE: Gitpod supports different IDEs, all of which have different configuration needs.
 //   3. DesktopIDE: Gitpod supports to connect external IDEs (like desktop IDEs).
+//      Further IDE backends can run next to them, see AdditionalIDEs.
 //   4. Workspace: which depends on the individual workspace, its content and configuration.
 
 // Config configures supervisor.
@@ -37,6 +42,8 @@ type Config struct {
 	StaticConfig
 	IDE        IDEConfig
 	DesktopIDE *IDEConfig
+	// AdditionalIDEs are IDE backends which run next to the IDE and desktop IDE
+	AdditionalIDEs []*IDEConfig
 	WorkspaceConfig
 }
 
@@ -53,6 +60,29 @@ func (c Config) Validate() error {
 			return xerrors.Errorf("Desktop IDE config is invalid: %w", err)
 		}
 	}
+	var (
+		names = make(map[string]struct{})
+		ports = map[int]struct{}{c.IDEPort: {}, c.APIEndpointPort: {}, c.SSHPort: {}, desktopIDEPort: {}}
+	)
+	for _, ide := range c.AdditionalIDEs {
+		if err := ide.Validate(); err != nil {
+			return xerrors.Errorf("IDE config %s is invalid: %w", ide.Name, err)
+		}
+		if !additionalIDENameRegexp.MatchString(ide.Name) {
+			return xerrors.Errorf("IDE config %s is invalid: name must match %s", ide.Name, additionalIDENameRegexp.String())
+		}
+		if _, exists := names[ide.Name]; exists {
+			return xerrors.Errorf("IDE config %s is invalid: name is not unique", ide.Name)
+		}
+		names[ide.Name] = struct{}{}
+		if !(0 < ide.Port && ide.Port <= math.MaxUint16) {
+			return xerrors.Errorf("IDE config %s is invalid: port must be between 0 and %d", ide.Name, math.MaxUint16)
+		}
+		if _, exists := ports[ide.Port]; exists {
+			return xerrors.Errorf("IDE config %s is invalid: port %d is already in use", ide.Name, ide.Port)
+		}
+		ports[ide.Port] = struct{}{}
+	}
 	if err := c.WorkspaceConfig.Validate(); err != nil {
 		return xerrors.Errorf("Workspace config is invalid: %w", err)
 	}
@@ -77,6 +107,10 @@ type StaticConfig struct {
 	// DesktopIDEConfigLocation is a path in the filesystem where to find the desktop IDE configuration
 	DesktopIDEConfigLocation string `json:"desktopIdeConfigLocation"`
 
+	// AdditionalIDEConfigPattern is a glob pattern matching the configurations of IDE backends
+	// which run next to the IDE and desktop IDE, e.g. "/ide-*/supervisor-ide-config.json".
+	AdditionalIDEConfigPattern string `json:"additionalIdeConfigPattern"`
+
 	// FrontendLocation is a path in the filesystem where to find supervisor's frontend assets
 	FrontendLocation string `json:"frontendLocation"`
 
@@ -92,6 +126,11 @@ func (c StaticConfig) Validate() error {
 	if c.IDEConfigLocation == "" {
 		return xerrors.Errorf("ideConfigLocation is required")
 	}
+	if c.AdditionalIDEConfigPattern != "" {
+		if _, err := filepath.Match(c.AdditionalIDEConfigPattern, ""); err != nil {
+			return xerrors.Errorf("additionalIdeConfigPattern is invalid: %w", err)
+		}
+	}
 	if c.FrontendLocation == "" {
 		return xerrors.Errorf("frontendLocation is required")
 	}
@@ -118,6 +157,15 @@ const (
 
 // IDEConfig is the IDE specific configuration.
 type IDEConfig struct {
+	// Name identifies an additional IDE backend. It is used in its status and to
+	// serve it at /_ide/<name>/. Ignored for the IDE and desktop IDE.
+	Name string `json:"name"`
+
+	// Port is the port an additional IDE backend listens on. It replaces {DESKTOPIDEPORT}
+	// in the entrypoint args, s.t. desktop IDE configs can be run as additional IDE backends.
+	// Defaults to the first free port after the desktop IDE port. Ignored for the IDE and desktop IDE.
+	Port int `json:"port"`
+
 	// Entrypoint is the command that gets executed by supervisor to start
 	// the IDE process. If this command exits, supervisor will start it again.
 	// If this command exits right after it was started with a non-zero exit
@@ -146,7 +194,7 @@ type IDEConfig struct {
 			// Host is the host to make requests to. Default to "localhost".
 			Host string `json:"host"`
 
-			// Port is the port to make requests to. Default it the IDE port in the supervisor config.
+			// Port is the port to make requests to. Default it the port of the IDE.
 			Port int `json:"port"`
 
 			// Path is the path to make requests to. Defaults to "/".
@@ -264,6 +312,9 @@ type WorkspaceConfig struct {
 	// DebugEnabled controls whether the supervisor debugging facilities (pprof, grpc tracing) should be enabled
 	DebugEnable bool `env:"SUPERVISOR_DEBUG_ENABLE"`
 
+	// TerminalHistoryEnable controls whether the output of terminals is persisted on disk, s.t. it can be read after reconnects
+	TerminalHistoryEnable bool `env:"SUPERVISOR_TERMINAL_HISTORY_ENABLE"`
+
 	// WorkspaceContext is a context for this workspace
 	WorkspaceContext string `env:"GITPOD_WORKSPACE_CONTEXT"`
 
@@ -292,14 +343,33 @@ type WorkspaceGitpodToken struct {
 
 // TaskConfig defines gitpod task shape.
 type TaskConfig struct {
-	Name     *string                 `json:"name,omitempty"`
-	Before   *string                 `json:"before,omitempty"`
-	Init     *string                 `json:"init,omitempty"`
-	Prebuild *string                 `json:"prebuild,omitempty"`
-	Command  *string                 `json:"command,omitempty"`
-	Env      *map[string]interface{} `json:"env,omitempty"`
-	OpenIn   *string                 `json:"openIn,omitempty"`
-	OpenMode *string                 `json:"openMode,omitempty"`
+	Name        *string                 `json:"name,omitempty"`
+	Before      *string                 `json:"before,omitempty"`
+	Init        *string                 `json:"init,omitempty"`
+	Prebuild    *string                 `json:"prebuild,omitempty"`
+	Command     *string                 `json:"command,omitempty"`
+	Env         *map[string]interface{} `json:"env,omitempty"`
+	EnvFile     *[]string               `json:"envFile,omitempty"`
+	DependsOn   *[]string               `json:"dependsOn,omitempty"`
+	Readiness   *TaskReadinessConfig    `json:"readiness,omitempty"`
+	Restart     *string                 `json:"restart,omitempty"`
+	MaxRestarts *int                    `json:"maxRestarts,omitempty"`
+	OpenIn      *string                 `json:"openIn,omitempty"`
+	OpenMode    *string                 `json:"openMode,omitempty"`
+}
+
+// TaskReadinessConfig defines the readiness probe of a task.
+type TaskReadinessConfig struct {
+	Port       *int                     `json:"port,omitempty"`
+	HTTP       *TaskReadinessHTTPConfig `json:"http,omitempty"`
+	LogPattern *string                  `json:"logPattern,omitempty"`
+	Timeout    *int                     `json:"timeout,omitempty"`
+}
+
+// TaskReadinessHTTPConfig defines an HTTP readiness check of a task.
+type TaskReadinessHTTPConfig struct {
+	Port int    `json:"port"`
+	Path string `json:"path,omitempty"`
 }
 
 // Validate validates this configuration.
@@ -443,6 +513,14 @@ func GetConfig() (*Config, error) {
 		}
 	}
 
+	var additionalIdes []*IDEConfig
+	if static.AdditionalIDEConfigPattern != "" {
+		additionalIdes, err = loadAdditionalIDEConfigs(static.AdditionalIDEConfigPattern, static.IDEConfigLocation, static.DesktopIDEConfigLocation)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	workspace, err := loadWorkspaceConfigFromEnv()
 	if err != nil {
 		return nil, err
@@ -452,6 +530,7 @@ func GetConfig() (*Config, error) {
 		StaticConfig:    *static,
 		IDE:             *ide,
 		DesktopIDE:      desktopIde,
+		AdditionalIDEs:  additionalIdes,
 		WorkspaceConfig: *workspace,
 	}, nil
 }
@@ -497,6 +576,52 @@ func loadIDEConfigFromFile(fn string) (*IDEConfig, error) {
 	return &res, nil
 }
 
+// loadAdditionalIDEConfigs loads the IDE configurations matching a glob pattern in lexical order,
+// skipping the IDE and desktop IDE configurations. IDE backends without a port get the first
+// port after the desktop IDE port which is not used by any other IDE backend.
+func loadAdditionalIDEConfigs(pattern string, skip ...string) ([]*IDEConfig, error) {
+	fns, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot find additional IDE configs: %w", err)
+	}
+
+	var (
+		res   []*IDEConfig
+		ports = make(map[int]struct{})
+	)
+nextConfig:
+	for _, fn := range fns {
+		for _, s := range skip {
+			if s != "" && filepath.Clean(s) == filepath.Clean(fn) {
+				continue nextConfig
+			}
+		}
+		ide, err := loadIDEConfigFromFile(fn)
+		if err != nil {
+			return nil, err
+		}
+		if ide.Port != 0 {
+			ports[ide.Port] = struct{}{}
+		}
+		res = append(res, ide)
+	}
+
+	port := desktopIDEPort
+	for _, ide := range res {
+		if ide.Port != 0 {
+			continue
+		}
+		for {
+			port++
+			if _, used := ports[port]; !used {
+				break
+			}
+		}
+		ide.Port = port
+	}
+	return res, nil
+}
+
 // loadWorkspaceConfigFromEnv loads the workspace configuration from environment variables.
 func loadWorkspaceConfigFromEnv() (*WorkspaceConfig, error) {
 	var res WorkspaceConfig
@@ -0,0 +1,84 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadAdditionalIDEConfigs(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Configs     map[string]string
+		Skip        []string
+		Expectation map[string]int
+	}{
+		{
+			Desc:        "no configs",
+			Expectation: map[string]int{},
+		},
+		{
+			Desc: "assigns ports in lexical order",
+			Configs: map[string]string{
+				"ide-jetbrains": `{"name": "intellij"}`,
+				"ide-code":      `{"name": "code"}`,
+			},
+			Expectation: map[string]int{"code": desktopIDEPort + 1, "intellij": desktopIDEPort + 2},
+		},
+		{
+			Desc: "keeps configured ports",
+			Configs: map[string]string{
+				"ide-a": `{"name": "a"}`,
+				"ide-b": `{"name": "b", "port": 24001}`,
+				"ide-c": `{"name": "c", "port": 25000}`,
+			},
+			Expectation: map[string]int{"a": desktopIDEPort + 2, "b": 24001, "c": 25000},
+		},
+		{
+			Desc: "skips IDE and desktop IDE configs",
+			Configs: map[string]string{
+				"ide-desktop": `{"name": "desktop"}`,
+				"ide-rider":   `{"name": "rider"}`,
+			},
+			Skip:        []string{"ide-desktop"},
+			Expectation: map[string]int{"rider": desktopIDEPort + 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			dir := t.TempDir()
+			for d, content := range test.Configs {
+				err := os.MkdirAll(filepath.Join(dir, d), 0755)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = os.WriteFile(filepath.Join(dir, d, "supervisor-ide-config.json"), []byte(content), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			var skip []string
+			for _, s := range test.Skip {
+				skip = append(skip, filepath.Join(dir, s, "supervisor-ide-config.json"))
+			}
+
+			ides, err := loadAdditionalIDEConfigs(filepath.Join(dir, "ide-*", "supervisor-ide-config.json"), skip...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			act := make(map[string]int)
+			for _, ide := range ides {
+				act[ide.Name] = ide.Port
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected ports (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
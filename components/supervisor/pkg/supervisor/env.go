@@ -0,0 +1,321 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/config"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/serverapi"
+)
+
+// Sources of the environment variables of child processes.
+const (
+	// envvarSourceWorkspace marks variables of the workspace container environment
+	envvarSourceWorkspace = "workspace"
+	// envvarSourceUser marks the user-defined Gitpod environment variables
+	envvarSourceUser = "user"
+	// envvarSourceSupervisor marks variables set by supervisor itself
+	envvarSourceSupervisor = "supervisor"
+	// envvarSourceTask marks variables of the env of a task
+	envvarSourceTask = "task"
+	// envvarSourceEnvFile prefixes the path of the envFile a variable is loaded from
+	envvarSourceEnvFile = "envFile:"
+)
+
+// envvar is an environment variable of a child process along with the origin of its value.
+type envvar struct {
+	Name   string
+	Value  string
+	Source string
+	// Secrets are the names of the secrets referenced by the value
+	Secrets []string
+}
+
+// environ renders environment variables in the form of os.Environ.
+func environ(envvars []envvar) []string {
+	res := make([]string, 0, len(envvars))
+	for _, e := range envvars {
+		res = append(res, e.Name+"="+e.Value)
+	}
+	return res
+}
+
+// mergeEnvvars returns base extended by overlay sorted by name. Variables of overlay take precedence.
+func mergeEnvvars(base, overlay []envvar) []envvar {
+	envs := make(map[string]envvar, len(base)+len(overlay))
+	for _, e := range base {
+		envs[e.Name] = e
+	}
+	for _, e := range overlay {
+		envs[e.Name] = e
+	}
+	res := make([]envvar, 0, len(envs))
+	for _, e := range envs {
+		res = append(res, e)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// envvarNamePattern matches valid environment variable names.
+var envvarNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// parseEnvFile parses the dotenv syntax, i.e. lines of NAME=VALUE optionally prefixed with export.
+// Values can be single quoted (literal) or double quoted (supporting escape sequences).
+// Lines starting with # and unquoted text following ' #' are comments.
+func parseEnvFile(r io.Reader, source string) ([]envvar, error) {
+	var (
+		res    []envvar
+		lineNo int
+		scan   = bufio.NewScanner(r)
+	)
+	for scan.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scan.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !envvarNamePattern.MatchString(name) {
+			return nil, xerrors.Errorf("%s:%d: invalid line, expected NAME=VALUE", source, lineNo)
+		}
+		value, err := parseEnvFileValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, xerrors.Errorf("%s:%d: %w", source, lineNo, err)
+		}
+		res = append(res, envvar{Name: name, Value: value, Source: envvarSourceEnvFile + source})
+	}
+	if err := scan.Err(); err != nil {
+		return nil, xerrors.Errorf("cannot read %s: %w", source, err)
+	}
+	return res, nil
+}
+
+func parseEnvFileValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	switch quote := value[0]; quote {
+	case '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", xerrors.New("unterminated single quoted value")
+		}
+		return value[1 : end+1], nil
+	case '"':
+		var (
+			res     strings.Builder
+			escaped bool
+		)
+		for _, c := range value[1:] {
+			if escaped {
+				switch c {
+				case 'n':
+					res.WriteRune('\n')
+				case 't':
+					res.WriteRune('\t')
+				case 'r':
+					res.WriteRune('\r')
+				default:
+					res.WriteRune(c)
+				}
+				escaped = false
+				continue
+			}
+			switch c {
+			case '\\':
+				escaped = true
+			case '"':
+				return res.String(), nil
+			default:
+				res.WriteRune(c)
+			}
+		}
+		return "", xerrors.New("unterminated double quoted value")
+	default:
+		if idx := strings.Index(value, " #"); idx >= 0 {
+			value = value[:idx]
+		}
+		return strings.TrimSpace(value), nil
+	}
+}
+
+// loadEnvFiles loads the given envFiles relative to dir. Files which cannot be loaded are skipped.
+func loadEnvFiles(dir string, files []string) []envvar {
+	var res []envvar
+	for _, fn := range files {
+		path := fn
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			log.WithError(err).WithField("envFile", fn).Warn("cannot load envFile")
+			continue
+		}
+		envvars, err := parseEnvFile(f, fn)
+		f.Close()
+		if err != nil {
+			log.WithError(err).WithField("envFile", fn).Warn("cannot load envFile")
+			continue
+		}
+		res = append(res, envvars...)
+	}
+	return res
+}
+
+// secretRefPattern matches references to secrets in environment variable values, e.g. ${secret:NPM_TOKEN}.
+var secretRefPattern = regexp.MustCompile(`\$\{secret:([^}]*)\}`)
+
+// secretResolver resolves secret references against the user-defined Gitpod environment variables.
+type secretResolver struct {
+	gitpodService serverapi.APIInterface
+
+	mu sync.Mutex
+	// secrets are fetched on first use
+	secrets map[string]string
+}
+
+func (r *secretResolver) lookup(ctx context.Context, name string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.secrets == nil {
+		if r.gitpodService == nil {
+			return "", xerrors.New("not connected to Gitpod API")
+		}
+		vars, err := r.gitpodService.GetEnvVars(ctx)
+		if err != nil {
+			return "", xerrors.Errorf("cannot fetch user environment variables: %w", err)
+		}
+		// a user can define the same variable for different repository patterns, in which case the most specific one wins
+		sort.SliceStable(vars, func(i, j int) bool {
+			return strings.Count(vars[i].RepositoryPattern, "*") < strings.Count(vars[j].RepositoryPattern, "*")
+		})
+		secrets := make(map[string]string, len(vars))
+		for _, v := range vars {
+			if _, exists := secrets[v.Name]; !exists {
+				secrets[v.Name] = v.Value
+			}
+		}
+		r.secrets = secrets
+	}
+
+	value, ok := r.secrets[name]
+	if !ok {
+		return "", xerrors.Errorf("secret %s does not exist", name)
+	}
+	return value, nil
+}
+
+// resolve replaces the secret references in the values of envvars.
+// Variables referencing secrets which cannot be resolved are left out.
+func (r *secretResolver) resolve(ctx context.Context, envvars []envvar) []envvar {
+	res := make([]envvar, 0, len(envvars))
+	for _, e := range envvars {
+		if !secretRefPattern.MatchString(e.Value) {
+			res = append(res, e)
+			continue
+		}
+
+		var err error
+		e.Value = secretRefPattern.ReplaceAllStringFunc(e.Value, func(ref string) string {
+			name := secretRefPattern.FindStringSubmatch(ref)[1]
+			e.Secrets = append(e.Secrets, name)
+			if err != nil {
+				return ""
+			}
+			var value string
+			if r == nil {
+				err = xerrors.New("secrets are not available")
+			} else {
+				value, err = r.lookup(ctx, name)
+			}
+			return value
+		})
+		if err != nil {
+			log.WithError(err).WithField("envvar", e.Name).WithField("source", e.Source).Warn("cannot resolve secret, not setting environment variable")
+			continue
+		}
+		res = append(res, e)
+	}
+	return res
+}
+
+// childProcEnv is the environment of the IDEs, tasks and SSH sessions, i.e. the environment computed by
+// buildChildProcEnv extended by the envFiles of the .gitpod.yml. It is available once the workspace content is ready.
+type childProcEnv struct {
+	cfg     *Config
+	secrets *secretResolver
+
+	ready   chan struct{}
+	envvars []envvar
+}
+
+func newChildProcEnv(cfg *Config, secrets *secretResolver) *childProcEnv {
+	return &childProcEnv{
+		cfg:     cfg,
+		secrets: secrets,
+		ready:   make(chan struct{}),
+	}
+}
+
+// init loads the envFiles of the .gitpod.yml once the content is ready.
+func (e *childProcEnv) init(ctx context.Context, contentState ContentState, gitpodConfigService config.ConfigInterface) {
+	defer close(e.ready)
+
+	var overlay []envvar
+	defer func() {
+		e.envvars = resolveChildProcEnv(e.cfg, nil, overlay)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-contentState.ContentReady():
+	}
+
+	// the config service never becomes ready if the .gitpod.yml is invalid, hence we don't wait forever
+	cfgCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var gitpodConfig *gitpod.GitpodConfig
+	select {
+	case gitpodConfig = <-gitpodConfigService.Observe(cfgCtx):
+	case <-cfgCtx.Done():
+		log.Warn("cannot read .gitpod.yml, not loading envFiles")
+	}
+	if gitpodConfig == nil || len(gitpodConfig.EnvFile) == 0 {
+		return
+	}
+	overlay = e.secrets.resolve(ctx, loadEnvFiles(e.cfg.RepoRoot, gitpodConfig.EnvFile))
+}
+
+// Envvars returns the environment variables along with their sources. It blocks until the environment is available.
+func (e *childProcEnv) Envvars() []envvar {
+	<-e.ready
+	return e.envvars
+}
+
+// Environ returns the environment in the form of os.Environ. It blocks until the environment is available.
+func (e *childProcEnv) Environ() []string {
+	return environ(e.Envvars())
+}
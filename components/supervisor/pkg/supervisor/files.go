@@ -0,0 +1,223 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/gitpod-io/gitpod/common-go/filesync"
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
+)
+
+// filesListBatchSize is the number of entries sent per ListFiles response.
+const filesListBatchSize = 100
+
+// FileService synchronises directory trees of the workspace with another machine.
+type FileService struct {
+	// Root is the directory relative paths are resolved against
+	Root string
+	// UID and GID are the credentials files are read and written with
+	UID, GID int
+
+	api.UnimplementedFileServiceServer
+}
+
+// RegisterGRPC registers the gRPC file service.
+func (s *FileService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterFileServiceServer(srv, s)
+}
+
+// resolve returns the root of the tree a request refers to. Paths are relative to Root and cannot escape it.
+func (s *FileService) resolve(p string) (string, error) {
+	if p == "" || p == "." {
+		return s.Root, nil
+	}
+	fn, err := filesync.Resolve(s.Root, p)
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+	info, err := os.Lstat(fn)
+	if err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		return "", status.Errorf(codes.InvalidArgument, "invalid path %q: must not be a symlink", p)
+	}
+	return fn, nil
+}
+
+// asUser runs op with the file system credentials of the workspace user, so that it can only access what the
+// user can access.
+func (s *FileService) asUser(op func() error) error {
+	return userfs.User{UID: s.UID, GID: s.GID}.Do(op)
+}
+
+// ListFiles lists the entries of a directory tree including the digests of the content chunks of its files.
+func (s *FileService) ListFiles(req *api.ListFilesRequest, srv api.FileService_ListFilesServer) error {
+	filter := filesync.Filter{Include: req.Include, Exclude: req.Exclude}
+	if err := filter.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	root, err := s.resolve(req.Path)
+	if err != nil {
+		return err
+	}
+	var entries []*filesync.Entry
+	err = s.asUser(func() (err error) {
+		entries, err = filesync.List(root, filter, filesync.DefaultChunkSize)
+		return err
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &api.ListFilesResponse{ChunkSize: filesync.DefaultChunkSize}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, toFileEntry(entry))
+		if len(resp.Entries) < filesListBatchSize {
+			continue
+		}
+		err = srv.Send(resp)
+		if err != nil {
+			return err
+		}
+		resp = &api.ListFilesResponse{ChunkSize: filesync.DefaultChunkSize}
+	}
+	return srv.Send(resp)
+}
+
+// ReadChunks streams the content of file chunks.
+func (s *FileService) ReadChunks(req *api.ReadChunksRequest, srv api.FileService_ReadChunksServer) error {
+	root, err := s.resolve(req.Path)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range req.Chunks {
+		if chunk.Length <= 0 || chunk.Length > filesync.DefaultChunkSize {
+			return status.Errorf(codes.InvalidArgument, "invalid length of chunk of %s at %d", chunk.Path, chunk.Offset)
+		}
+		var data []byte
+		err := s.asUser(func() (err error) {
+			data, err = filesync.ReadChunk(root, chunk.Path, chunk.Offset, chunk.Length)
+			return err
+		})
+		if err != nil {
+			return status.Errorf(codes.NotFound, "cannot read chunk of %s at %d: %v", chunk.Path, chunk.Offset, err)
+		}
+		err = srv.Send(&api.ReadChunksResponse{Chunk: &api.FileChunk{
+			Path:   chunk.Path,
+			Offset: chunk.Offset,
+			Length: int64(len(data)),
+			Digest: filesync.Digest(data),
+			Data:   data,
+		}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFiles creates, updates or removes entries of a directory tree.
+func (s *FileService) WriteFiles(srv api.FileService_WriteFilesServer) error {
+	var (
+		w       *filesync.Writer
+		written int64
+	)
+	for {
+		req, err := srv.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if w == nil {
+			// files are owned by the workspace user as they are written with its credentials
+			root, err := s.resolve(req.Path)
+			if err != nil {
+				return err
+			}
+			w = filesync.NewWriter(root)
+		}
+
+		err = s.asUser(func() error {
+			switch change := req.Change.(type) {
+			case *api.WriteFilesRequest_Entry:
+				return w.Apply(fromFileEntry(change.Entry))
+			case *api.WriteFilesRequest_Chunk:
+				written += int64(len(change.Chunk.Data))
+				return w.WriteChunk(change.Chunk.Path, change.Chunk.Offset, change.Chunk.Data, change.Chunk.Digest)
+			case *api.WriteFilesRequest_Remove:
+				return w.Remove(change.Remove)
+			default:
+				return fmt.Errorf("unknown change %T", change)
+			}
+		})
+		if err != nil {
+			// apply the metadata of the entries which have been written so far
+			if cerr := s.asUser(w.Close); cerr != nil {
+				log.WithError(cerr).Warn("cannot apply file metadata")
+			}
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	if w != nil {
+		err := s.asUser(w.Close)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	return srv.SendAndClose(&api.WriteFilesResponse{Written: written})
+}
+
+func toFileEntry(entry *filesync.Entry) *api.FileEntry {
+	res := &api.FileEntry{
+		Path:       entry.Path,
+		Mode:       uint32(entry.Mode),
+		Size:       entry.Size,
+		Modified:   timestamppb.New(entry.ModTime),
+		LinkTarget: entry.LinkTarget,
+		Chunks:     entry.Chunks,
+	}
+	switch entry.Type {
+	case filesync.TypeDirectory:
+		res.Type = api.FileType_directory
+	case filesync.TypeSymlink:
+		res.Type = api.FileType_symlink
+	default:
+		res.Type = api.FileType_regular
+	}
+	return res
+}
+
+func fromFileEntry(entry *api.FileEntry) *filesync.Entry {
+	res := &filesync.Entry{
+		Path:       entry.Path,
+		Mode:       fs.FileMode(entry.Mode).Perm(),
+		Size:       entry.Size,
+		LinkTarget: entry.LinkTarget,
+		Chunks:     entry.Chunks,
+	}
+	if entry.Modified != nil {
+		res.ModTime = entry.Modified.AsTime()
+	}
+	switch entry.Type {
+	case api.FileType_directory:
+		res.Type = filesync.TypeDirectory
+	case api.FileType_symlink:
+		res.Type = filesync.TypeSymlink
+	default:
+		res.Type = filesync.TypeRegular
+	}
+	return res
+}
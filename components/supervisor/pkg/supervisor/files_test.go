@@ -0,0 +1,132 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/common-go/filesync"
+)
+
+func TestFileEntryConversion(t *testing.T) {
+	modified := time.Date(2022, 5, 3, 10, 20, 30, 0, time.UTC)
+	tests := []struct {
+		Name  string
+		Entry *filesync.Entry
+	}{
+		{
+			Name: "regular file",
+			Entry: &filesync.Entry{
+				Path:    "src/main.go",
+				Type:    filesync.TypeRegular,
+				Mode:    0644,
+				Size:    12,
+				ModTime: modified,
+				Chunks:  []string{filesync.Digest([]byte("hello world\n"))},
+			},
+		},
+		{
+			Name: "directory",
+			Entry: &filesync.Entry{
+				Path:    "src",
+				Type:    filesync.TypeDirectory,
+				Mode:    0755,
+				ModTime: modified,
+			},
+		},
+		{
+			Name: "symlink",
+			Entry: &filesync.Entry{
+				Path:       "link",
+				Type:       filesync.TypeSymlink,
+				Mode:       fs.ModePerm,
+				ModTime:    modified,
+				LinkTarget: "src/main.go",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := fromFileEntry(toFileEntry(test.Entry))
+			if diff := cmp.Diff(test.Entry, act); diff != "" {
+				t.Errorf("unexpected entry (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFileServiceAsUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing the file system credentials requires root")
+	}
+
+	root := t.TempDir()
+	// the workspace user must be able to traverse the parent created by the test framework
+	err := os.Chmod(filepath.Dir(root), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chmod(root, 0777)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "secret"), []byte("secret"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &FileService{Root: root, UID: 33333, GID: 33333}
+	err = srv.asUser(func() error {
+		_, err := filesync.ReadChunk(root, "secret", 0, 6)
+		return err
+	})
+	if err == nil {
+		t.Error("expected reading a file of root to fail")
+	}
+
+	err = srv.asUser(func() error {
+		return os.WriteFile(filepath.Join(root, "file"), []byte("hello"), 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(root, "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat := info.Sys().(*syscall.Stat_t); stat.Uid != 33333 || stat.Gid != 33333 {
+		t.Errorf("unexpected owner of written file: %d:%d", stat.Uid, stat.Gid)
+	}
+
+	// the credentials are restored afterwards
+	_, err = os.ReadFile(filepath.Join(root, "secret"))
+	if err != nil {
+		t.Errorf("cannot read file after restoring the credentials: %v", err)
+	}
+}
+
+func TestFileServiceResolve(t *testing.T) {
+	root := t.TempDir()
+	err := os.Symlink("/etc", filepath.Join(root, "etc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &FileService{Root: root}
+	for _, p := range []string{"/etc", "../etc", "etc", "etc/passwd"} {
+		if _, err := srv.resolve(p); err == nil {
+			t.Errorf("expected %s to be rejected", p)
+		}
+	}
+	if fn, err := srv.resolve(""); err != nil || fn != root {
+		t.Errorf("expected an empty path to resolve to the root, got %s (%v)", fn, err)
+	}
+}
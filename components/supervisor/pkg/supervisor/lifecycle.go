@@ -0,0 +1,296 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/content-service/pkg/logs"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+)
+
+const (
+	lifecycleOnContentReady = "onContentReady"
+	lifecyclePostStart      = "postStart"
+	lifecyclePreStop        = "preStop"
+	lifecycleOnIdle         = "onIdle"
+
+	defaultLifecycleHookTimeout = 5 * time.Minute
+	defaultPreStopTimeout       = 30 * time.Second
+	defaultIdleAfter            = 15 * time.Minute
+	idleCheckInterval           = 30 * time.Second
+
+	// lifecycleHookMaxOutput is the number of bytes of a hook's output recorded in the supervisor logs
+	lifecycleHookMaxOutput = 4096
+)
+
+// lifecycleHooks runs the lifecycle hooks configured in .gitpod.yml.
+type lifecycleHooks struct {
+	// newCommand creates the command of a hook
+	newCommand func(command string) *exec.Cmd
+	// headlessLog is the file the output of hooks is appended to, none if empty
+	headlessLog string
+	// lastActivity returns the time the workspace has last been used
+	lastActivity func() time.Time
+	now          func() time.Time
+
+	mu     sync.Mutex
+	config *gitpod.Lifecycle
+	// configured is closed once the configuration has been received
+	configured chan struct{}
+	once       sync.Once
+}
+
+func newLifecycleHooks(cfg *Config, env func() []string, activity func() time.Time) *lifecycleHooks {
+	hooks := &lifecycleHooks{
+		newCommand: func(command string) *exec.Cmd {
+			cmd := runAsGitpodUser(exec.Command("/bin/bash", "-c", command))
+			cmd.Dir = cfg.RepoRoot
+			cmd.Env = env()
+			return cmd
+		},
+		lastActivity: activity,
+		now:          time.Now,
+		configured:   make(chan struct{}),
+	}
+	if cfg.isHeadless() {
+		hooks.headlessLog = logs.PrebuildLogFileName(logs.TerminalStoreLocation, "lifecycle")
+	}
+	return hooks
+}
+
+// Observe keeps track of the lifecycle configuration.
+func (h *lifecycleHooks) Observe(configs <-chan *gitpod.GitpodConfig) {
+	for config := range configs {
+		var lifecycle *gitpod.Lifecycle
+		if config != nil {
+			lifecycle = config.Lifecycle
+		}
+		h.mu.Lock()
+		h.config = lifecycle
+		h.mu.Unlock()
+		h.once.Do(func() { close(h.configured) })
+	}
+}
+
+// hook returns the configured hook of an event once the configuration is available.
+func (h *lifecycleHooks) hook(ctx context.Context, event string) *gitpod.LifecycleHook {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-h.configured:
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.config == nil {
+		return nil
+	}
+	switch event {
+	case lifecycleOnContentReady:
+		return h.config.OnContentReady
+	case lifecyclePostStart:
+		return h.config.PostStart
+	case lifecyclePreStop:
+		return h.config.PreStop
+	case lifecycleOnIdle:
+		return h.config.OnIdle
+	}
+	return nil
+}
+
+// RunOn runs the hook of an event once ready is closed.
+func (h *lifecycleHooks) RunOn(ctx context.Context, event string, ready <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-ready:
+	}
+	h.Run(ctx, event)
+}
+
+// Run runs the hook of an event if it is configured. The hook is killed once the context is done.
+func (h *lifecycleHooks) Run(ctx context.Context, event string) {
+	hook := h.hook(ctx, event)
+	if hook == nil || hook.Command == "" {
+		return
+	}
+
+	timeout := defaultLifecycleHookTimeout
+	if event == lifecyclePreStop {
+		timeout = defaultPreStopTimeout
+	}
+	if hook.Timeout != "" {
+		t, err := time.ParseDuration(hook.Timeout)
+		if err != nil {
+			log.WithError(err).WithField("hook", event).Warn("lifecycle: invalid timeout, using the default")
+		} else {
+			timeout = t
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Sub(h.now()) < timeout {
+		// e.g. the preStop hook must leave the IDE and terminals half of the termination grace period
+		log.WithField("hook", event).WithField("timeout", timeout.String()).WithField("cappedTimeout", deadline.Sub(h.now()).String()).Warn("lifecycle: timeout exceeds the time available, the hook is killed earlier")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	h.runHook(ctx, event, hook.Command)
+}
+
+func (h *lifecycleHooks) runHook(ctx context.Context, event string, command string) {
+	var (
+		hookLog = log.WithField("hook", event).WithField("command", command)
+		output  bytes.Buffer
+		out     io.Writer = &output
+	)
+	if h.headlessLog != "" {
+		f, err := os.OpenFile(h.headlessLog, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			hookLog.WithError(err).Warn("lifecycle: cannot open headless log")
+		} else {
+			defer f.Close()
+			fmt.Fprintf(f, "\r\n🪝 Running %s hook: %s\r\n", event, command)
+			out = io.MultiWriter(out, f)
+			defer func() {
+				fmt.Fprintf(f, "🪝 %s hook finished\r\n", event)
+			}()
+		}
+	}
+
+	cmd := h.newCommand(command)
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// run the hook in its own process group, s.t. we can kill all of its processes on timeout
+	cmd.SysProcAttr.Setpgid = true
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	start := h.now()
+	hookLog.Info("lifecycle: running hook")
+	err := cmd.Start()
+	if err != nil {
+		hookLog.WithError(err).Error("lifecycle: cannot start hook")
+		return
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		<-done
+		err = ctx.Err()
+	}
+
+	res := output.Bytes()
+	if len(res) > lifecycleHookMaxOutput {
+		res = res[len(res)-lifecycleHookMaxOutput:]
+	}
+	hookLog = hookLog.WithField("duration", h.now().Sub(start).String()).WithField("output", string(res))
+	if err != nil {
+		hookLog.WithError(err).Error("lifecycle: hook failed")
+		return
+	}
+	hookLog.Info("lifecycle: hook succeeded")
+}
+
+// WatchIdle runs the onIdle hook whenever the workspace has not been used for the configured duration.
+func (h *lifecycleHooks) WatchIdle(ctx context.Context) {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+
+	var (
+		since = h.now()
+		fired bool
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		since, fired = h.checkIdle(ctx, since, fired)
+	}
+}
+
+// checkIdle runs the onIdle hook if the workspace has been idle since the last activity, but at most once per idle period.
+func (h *lifecycleHooks) checkIdle(ctx context.Context, since time.Time, fired bool) (time.Time, bool) {
+	if activity := h.lastActivity(); activity.After(since) {
+		return activity, false
+	}
+	if fired {
+		return since, fired
+	}
+
+	h.mu.Lock()
+	config := h.config
+	h.mu.Unlock()
+	if config == nil || config.OnIdle == nil {
+		return since, fired
+	}
+	idleAfter := defaultIdleAfter
+	if config.IdleAfter != "" {
+		d, err := time.ParseDuration(config.IdleAfter)
+		if err != nil {
+			log.WithError(err).Warn("lifecycle: invalid idleAfter, using the default")
+		} else {
+			idleAfter = d
+		}
+	}
+	if h.now().Sub(since) < idleAfter {
+		return since, fired
+	}
+	h.Run(ctx, lifecycleOnIdle)
+	return since, true
+}
+
+// ideActivity keeps track of the time IDEs have last reported that the workspace is used.
+type ideActivity struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+// MarkActive records that the workspace is used through an IDE.
+func (a *ideActivity) MarkActive() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.last = time.Now()
+}
+
+// LastActive returns the time an IDE has last marked the workspace as used.
+func (a *ideActivity) LastActive() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.last
+}
+
+// workspaceActivity returns the time a terminal has last received input or an IDE has last marked the workspace as used,
+// or now while SSH connections are established.
+func workspaceActivity(terminals *terminal.MuxTerminalService, connections *sshConnections, ides *ideActivity) func() time.Time {
+	return func() time.Time {
+		if len(connections.List()) > 0 {
+			return time.Now()
+		}
+		last := terminals.LastInput()
+		if ide := ides.LastActive(); ide.After(last) {
+			last = ide
+		}
+		return last
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+)
+
+func newTestLifecycleHooks(t *testing.T, config *gitpod.Lifecycle) *lifecycleHooks {
+	dir := t.TempDir()
+	hooks := &lifecycleHooks{
+		newCommand: func(command string) *exec.Cmd {
+			cmd := exec.Command("/bin/bash", "-c", command)
+			cmd.Dir = dir
+			return cmd
+		},
+		headlessLog:  filepath.Join(dir, "headless.log"),
+		lastActivity: func() time.Time { return time.Time{} },
+		now:          time.Now,
+		configured:   make(chan struct{}),
+	}
+	configs := make(chan *gitpod.GitpodConfig, 1)
+	configs <- &gitpod.GitpodConfig{Lifecycle: config}
+	close(configs)
+	hooks.Observe(configs)
+	return hooks
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Config      *gitpod.Lifecycle
+		Event       string
+		Expectation string
+		MaxDuration time.Duration
+	}{
+		{
+			Desc:        "runs configured hook",
+			Config:      &gitpod.Lifecycle{PostStart: &gitpod.LifecycleHook{Command: "echo started"}},
+			Event:       lifecyclePostStart,
+			Expectation: "started",
+		},
+		{
+			Desc:   "ignores unconfigured hook",
+			Config: &gitpod.Lifecycle{PostStart: &gitpod.LifecycleHook{Command: "echo started"}},
+			Event:  lifecyclePreStop,
+		},
+		{
+			Desc:   "ignores missing lifecycle",
+			Config: nil,
+			Event:  lifecycleOnContentReady,
+		},
+		{
+			Desc:        "kills hook on timeout",
+			Config:      &gitpod.Lifecycle{PreStop: &gitpod.LifecycleHook{Command: "echo flushing; sleep 10 & wait", Timeout: "200ms"}},
+			Event:       lifecyclePreStop,
+			Expectation: "flushing",
+			MaxDuration: 5 * time.Second,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			hooks := newTestLifecycleHooks(t, test.Config)
+
+			start := time.Now()
+			hooks.Run(context.Background(), test.Event)
+			if test.MaxDuration > 0 && time.Since(start) > test.MaxDuration {
+				t.Errorf("hook was not killed after its timeout, took %s", time.Since(start))
+			}
+
+			content, err := os.ReadFile(hooks.headlessLog)
+			if test.Expectation == "" {
+				if err == nil {
+					t.Errorf("expected no output, got %q", content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(content), test.Expectation) || !strings.Contains(string(content), test.Event+" hook finished") {
+				t.Errorf("expected headless log to contain the output %q, got %q", test.Expectation, content)
+			}
+		})
+	}
+}
+
+func TestLifecycleIdle(t *testing.T) {
+	var (
+		start    = time.Date(2022, 5, 3, 10, 0, 0, 0, time.UTC)
+		now      = start
+		activity time.Time
+	)
+	hooks := newTestLifecycleHooks(t, &gitpod.Lifecycle{
+		OnIdle:    &gitpod.LifecycleHook{Command: "echo idle"},
+		IdleAfter: "10m",
+	})
+	hooks.now = func() time.Time { return now }
+	hooks.lastActivity = func() time.Time { return activity }
+	runs := func() int {
+		content, _ := os.ReadFile(hooks.headlessLog)
+		return strings.Count(string(content), "onIdle hook finished")
+	}
+
+	since, fired := start, false
+	steps := []struct {
+		Desc     string
+		Advance  time.Duration
+		Activity bool
+		Runs     int
+	}{
+		{Desc: "not idle long enough", Advance: 5 * time.Minute, Runs: 0},
+		{Desc: "idle", Advance: 6 * time.Minute, Runs: 1},
+		{Desc: "still idle", Advance: 20 * time.Minute, Runs: 1},
+		{Desc: "used again", Advance: time.Minute, Activity: true, Runs: 1},
+		{Desc: "idle again", Advance: 11 * time.Minute, Runs: 2},
+	}
+	for _, step := range steps {
+		now = now.Add(step.Advance)
+		if step.Activity {
+			activity = now
+		}
+		since, fired = hooks.checkIdle(context.Background(), since, fired)
+		if act := runs(); act != step.Runs {
+			t.Errorf("%s: expected %d runs of the onIdle hook, got %d", step.Desc, step.Runs, act)
+		}
+	}
+}
+
+func TestWorkspaceActivity(t *testing.T) {
+	ides := &ideActivity{}
+	activity := workspaceActivity(terminal.NewMuxTerminalService(terminal.NewMux()), newSSHConnections(), ides)
+	if last := activity(); !last.IsZero() {
+		t.Fatalf("expected no activity, got %s", last)
+	}
+
+	ides.MarkActive()
+	if last := activity(); last.IsZero() || last != ides.LastActive() {
+		t.Errorf("expected the activity of the IDE, got %s", last)
+	}
+}
@@ -5,22 +5,35 @@
 package supervisor
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
 )
 
 const (
 	NotifierMaxPendingNotifications   = 120
 	SubscriberMaxPendingNotifications = 100
+	// NotificationLogSize is the maximum number of notifications kept in the notification log
+	NotificationLogSize = 200
+	// NotificationLogFile is the file the notification log is persisted to, s.t. it survives workspace restarts
+	NotificationLogFile = "/workspace/.gitpod/notifications.jsonl"
 )
 
 // NewNotificationService creates a new notification service.
@@ -38,6 +51,12 @@ type NotificationService struct {
 	subscriptions        map[uint64]*subscription
 	nextNotificationID   uint64
 	pendingNotifications map[uint64]*pendingNotification
+	// notificationLog holds the recent notifications, oldest first
+	notificationLog []*api.Notification
+	// logFile is the file the notification log is persisted to, empty until the log was loaded
+	logFile string
+	// logUser is the user the log file is accessed as
+	logUser userfs.User
 
 	api.UnimplementedNotificationServiceServer
 }
@@ -57,11 +76,12 @@ func (pending *pendingNotification) close() {
 }
 
 type subscription struct {
-	id      uint64
-	channel chan *api.SubscribeResponse
-	once    sync.Once
-	closed  bool
-	cancel  context.CancelFunc
+	id             uint64
+	channel        chan *api.SubscribeResponse
+	once           sync.Once
+	closed         bool
+	cancel         context.CancelFunc
+	nonInteractive bool
 }
 
 func (subscription *subscription) close() {
@@ -106,6 +126,10 @@ func (srv *NotificationService) Notify(ctx context.Context, req *api.NotifyReque
 		if ok {
 			delete(srv.pendingNotifications, pending.message.RequestId)
 			pending.close()
+			if entry := srv.findLogEntry(pending.message.RequestId); entry != nil {
+				entry.Pending = false
+				srv.persistLog()
+			}
 		}
 		return nil, ctx.Err()
 	}
@@ -122,10 +146,20 @@ func (srv *NotificationService) notifySubscribers(req *api.NotifyRequest) *pendi
 		}
 	)
 	srv.nextNotificationID++
+	entry := &api.Notification{
+		RequestId: requestID,
+		Request:   req,
+		Timestamp: timestamppb.Now(),
+		Pending:   len(req.Actions) > 0,
+	}
+	srv.notificationLog = trimNotificationLog(append(srv.notificationLog, entry))
+	defer srv.persistLog()
 	for _, subscription := range srv.subscriptions {
 		select {
 		case subscription.channel <- message:
-			// all good
+			if !subscription.nonInteractive && len(req.Actions) == 0 {
+				entry.Read = true
+			}
 		default:
 			// subscriber doesn't consume messages fast enough
 			log.WithField("subscription", req).Info("Cancelling unresponsive subscriber")
@@ -179,20 +213,27 @@ func (srv *NotificationService) subscribeLocked(req *api.SubscribeRequest, resp
 		capacity = SubscriberMaxPendingNotifications
 	}
 	channel := make(chan *api.SubscribeResponse, capacity)
-	log.WithField("pending", len(srv.pendingNotifications)).Info("sending pending notifications")
-	for id, pending := range srv.pendingNotifications {
-		channel <- pending.message
-		if len(pending.message.Request.Actions) == 0 {
-			delete(srv.pendingNotifications, id)
+	if !req.NonInteractive {
+		log.WithField("pending", len(srv.pendingNotifications)).Info("sending pending notifications")
+		for id, pending := range srv.pendingNotifications {
+			channel <- pending.message
+			if len(pending.message.Request.Actions) == 0 {
+				delete(srv.pendingNotifications, id)
+				if entry := srv.findLogEntry(id); entry != nil {
+					entry.Read = true
+				}
+			}
 		}
+		srv.persistLog()
 	}
 	id := srv.nextSubscriptionID
 	srv.nextSubscriptionID++
 	_, cancel := context.WithCancel(resp.Context())
 	subscription := &subscription{
-		channel: channel,
-		id:      id,
-		cancel:  cancel,
+		channel:        channel,
+		id:             id,
+		cancel:         cancel,
+		nonInteractive: req.NonInteractive,
 	}
 	srv.subscriptions[id] = subscription
 	return subscription
@@ -234,9 +275,139 @@ func (srv *NotificationService) Respond(ctx context.Context, req *api.RespondReq
 		pending.close()
 	}
 	delete(srv.pendingNotifications, pending.message.RequestId)
+	if entry := srv.findLogEntry(pending.message.RequestId); entry != nil {
+		entry.Read = true
+		entry.Pending = false
+		entry.Action = req.Response.Action
+		srv.persistLog()
+	}
 	return &api.RespondResponse{}, nil
 }
 
+// ListNotifications lists the notifications of the notification log.
+func (srv *NotificationService) ListNotifications(ctx context.Context, req *api.ListNotificationsRequest) (*api.ListNotificationsResponse, error) {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+	resp := &api.ListNotificationsResponse{}
+	for _, entry := range srv.notificationLog {
+		if req.Unread && entry.Read {
+			continue
+		}
+		resp.Notifications = append(resp.Notifications, proto.Clone(entry).(*api.Notification))
+		if req.MarkRead {
+			entry.Read = true
+		}
+	}
+	if req.MarkRead {
+		srv.persistLog()
+	}
+	return resp, nil
+}
+
+// LoadLog restores the notification log persisted to fn and persists the log there from now on. The file is accessed as user.
+// Restored notifications get new request IDs and are not pending anymore, as nobody waits for their response.
+func (srv *NotificationService) LoadLog(user userfs.User, fn string) error {
+	srv.mutex.Lock()
+	defer srv.mutex.Unlock()
+
+	restored, err := readNotificationLog(user, fn)
+	if err != nil {
+		return err
+	}
+	for _, entry := range restored {
+		entry.RequestId = srv.nextNotificationID
+		srv.nextNotificationID++
+		entry.Pending = false
+	}
+	srv.notificationLog = trimNotificationLog(append(restored, srv.notificationLog...))
+	srv.logFile = fn
+	srv.logUser = user
+	return writeNotificationLog(user, fn, srv.notificationLog)
+}
+
+// persistLog writes the notification log to the log file if there is one, the caller has to hold the mutex.
+func (srv *NotificationService) persistLog() {
+	if srv.logFile == "" {
+		return
+	}
+	err := writeNotificationLog(srv.logUser, srv.logFile, srv.notificationLog)
+	if err != nil {
+		log.WithError(err).Warn("cannot persist notification log")
+	}
+}
+
+func trimNotificationLog(entries []*api.Notification) []*api.Notification {
+	if len(entries) > NotificationLogSize {
+		return entries[len(entries)-NotificationLogSize:]
+	}
+	return entries
+}
+
+// readNotificationLog reads a notification log of one JSON encoded notification per line as user. A missing file contains no notifications.
+func readNotificationLog(user userfs.User, fn string) ([]*api.Notification, error) {
+	content, err := user.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read notification log: %w", err)
+	}
+
+	var res []*api.Notification
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry api.Notification
+		err := protojson.Unmarshal(line, &entry)
+		if err != nil {
+			log.WithError(err).Warn("skipping invalid entry of the notification log")
+			continue
+		}
+		res = append(res, &entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, xerrors.Errorf("cannot read notification log: %w", err)
+	}
+	return res, nil
+}
+
+// writeNotificationLog replaces the notification log in fn with entries as user, one JSON encoded notification per line.
+func writeNotificationLog(user userfs.User, fn string, entries []*api.Notification) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := protojson.Marshal(entry)
+		if err != nil {
+			return xerrors.Errorf("cannot marshal notification: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteString("\n")
+	}
+
+	err := user.MkdirAll(filepath.Dir(fn), 0o755)
+	if err != nil {
+		return xerrors.Errorf("cannot write notification log: %w", err)
+	}
+	err = user.WriteFile(fn, buf.Bytes(), 0o600)
+	if err != nil {
+		return xerrors.Errorf("cannot write notification log: %w", err)
+	}
+	return nil
+}
+
+// findLogEntry returns the notification log entry of a request, the caller has to hold the mutex.
+func (srv *NotificationService) findLogEntry(requestID uint64) *api.Notification {
+	for i := len(srv.notificationLog) - 1; i >= 0; i-- {
+		if srv.notificationLog[i].RequestId == requestID {
+			return srv.notificationLog[i]
+		}
+	}
+	return nil
+}
+
 func isActionAllowed(action string, req *api.NotifyRequest) bool {
 	if action == "" {
 		// user cancelled, which is always allowed
@@ -7,13 +7,17 @@ package supervisor
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"google.golang.org/grpc"
 
 	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
 )
 
 type TestNotificationService_SubscribeServer struct {
@@ -263,3 +267,187 @@ func Test(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func TestNotificationLog(t *testing.T) {
+	notificationService := NewNotificationService()
+	listMessages := func(req *api.ListNotificationsRequest) []string {
+		resp, err := notificationService.ListNotifications(context.Background(), req)
+		if err != nil {
+			t.Fatalf("error listing notifications: %s", err)
+		}
+		var res []string
+		for _, n := range resp.Notifications {
+			res = append(res, n.Request.Message)
+		}
+		return res
+	}
+
+	// fire notification without any subscribers
+	_, err := notificationService.Notify(context.Background(), &api.NotifyRequest{Message: "first"})
+	if err != nil {
+		t.Fatalf("error on notification %s", err)
+	}
+	if diff := cmp.Diff([]string{"first"}, listMessages(&api.ListNotificationsRequest{Unread: true})); diff != "" {
+		t.Errorf("unexpected unread notifications (-want +got):\n%s", diff)
+	}
+
+	// non-interactive subscribers neither consume pending notifications nor mark them as read
+	watcher := NewSubscribeServer()
+	defer watcher.cancel()
+	go func() {
+		_ = notificationService.Subscribe(&api.SubscribeRequest{NonInteractive: true}, watcher)
+	}()
+	for {
+		notificationService.mutex.Lock()
+		subscribed := len(notificationService.subscriptions) > 0
+		notificationService.mutex.Unlock()
+		if subscribed {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, err = notificationService.Notify(context.Background(), &api.NotifyRequest{Message: "second"})
+	if err != nil {
+		t.Fatalf("error on notification %s", err)
+	}
+	if resp := <-watcher.resps; resp.Request.Message != "second" {
+		t.Errorf("expected watcher to receive the new notification only, got %s", resp.Request.Message)
+	}
+	if diff := cmp.Diff([]string{"first", "second"}, listMessages(&api.ListNotificationsRequest{Unread: true, MarkRead: true})); diff != "" {
+		t.Errorf("unexpected unread notifications (-want +got):\n%s", diff)
+	}
+	if unread := listMessages(&api.ListNotificationsRequest{Unread: true}); len(unread) != 0 {
+		t.Errorf("expected notifications to be marked as read, got %v", unread)
+	}
+
+	// responses are recorded
+	responded := make(chan *api.NotifyResponse)
+	go func() {
+		resp, err := notificationService.Notify(context.Background(), &api.NotifyRequest{Message: "third", Actions: []string{"ok"}})
+		if err != nil {
+			t.Errorf("error on notification %s", err)
+		}
+		responded <- resp
+	}()
+	notification := <-watcher.resps
+	resp, err := notificationService.ListNotifications(context.Background(), &api.ListNotificationsRequest{Unread: true})
+	if err != nil {
+		t.Fatalf("error listing notifications: %s", err)
+	}
+	if len(resp.Notifications) != 1 || !resp.Notifications[0].Pending {
+		t.Errorf("expected a pending notification, got %v", resp.Notifications)
+	}
+	_, err = notificationService.Respond(context.Background(), &api.RespondRequest{
+		RequestId: notification.RequestId,
+		Response:  &api.NotifyResponse{Action: "ok"},
+	})
+	if err != nil {
+		t.Fatalf("error on response %s", err)
+	}
+	<-responded
+	resp, err = notificationService.ListNotifications(context.Background(), &api.ListNotificationsRequest{})
+	if err != nil {
+		t.Fatalf("error listing notifications: %s", err)
+	}
+	last := resp.Notifications[len(resp.Notifications)-1]
+	if last.Pending || !last.Read || last.Action != "ok" || last.Timestamp == nil {
+		t.Errorf("expected a read notification with the chosen action, got %v", last)
+	}
+
+	// the log is bounded
+	watcher.cancel()
+	for i := 0; i < NotificationLogSize; i++ {
+		notificationService.notifySubscribers(&api.NotifyRequest{Message: fmt.Sprintf("bulk %d", i)})
+	}
+	messages := listMessages(&api.ListNotificationsRequest{})
+	if len(messages) != NotificationLogSize || messages[0] != "bulk 0" {
+		t.Errorf("expected the log to keep the latest %d notifications, got %d starting with %s", NotificationLogSize, len(messages), messages[0])
+	}
+}
+
+func TestNotificationLogPersistence(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), ".gitpod", "notifications.jsonl")
+	err := os.MkdirAll(filepath.Dir(fn), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a temporary file planted in the workspace must not redirect the log
+	other := filepath.Join(t.TempDir(), "other")
+	err = os.WriteFile(other, []byte("other\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink(other, fn+".tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := NewNotificationService()
+	err = previous.LoadLog(userfs.Current(), fn)
+	if err != nil {
+		t.Fatalf("error loading missing notification log: %s", err)
+	}
+	_, err = previous.Notify(context.Background(), &api.NotifyRequest{Message: "first"})
+	if err != nil {
+		t.Fatalf("error on notification %s", err)
+	}
+	// a notification nobody responded to before the restart
+	previous.notifySubscribers(&api.NotifyRequest{Message: "second", Actions: []string{"ok"}})
+	_, err = previous.ListNotifications(context.Background(), &api.ListNotificationsRequest{MarkRead: true})
+	if err != nil {
+		t.Fatalf("error listing notifications: %s", err)
+	}
+
+	notificationService := NewNotificationService()
+	// notifications sent before the log is loaded are kept after the restored ones
+	_, err = notificationService.Notify(context.Background(), &api.NotifyRequest{Message: "third"})
+	if err != nil {
+		t.Fatalf("error on notification %s", err)
+	}
+	err = notificationService.LoadLog(userfs.Current(), fn)
+	if err != nil {
+		t.Fatalf("error loading notification log: %s", err)
+	}
+	resp, err := notificationService.ListNotifications(context.Background(), &api.ListNotificationsRequest{})
+	if err != nil {
+		t.Fatalf("error listing notifications: %s", err)
+	}
+	type entry struct {
+		Message string
+		Read    bool
+		Pending bool
+	}
+	var entries []entry
+	ids := make(map[uint64]struct{})
+	for _, n := range resp.Notifications {
+		entries = append(entries, entry{Message: n.Request.Message, Read: n.Read, Pending: n.Pending})
+		ids[n.RequestId] = struct{}{}
+	}
+	expectation := []entry{
+		{Message: "first", Read: true},
+		{Message: "second", Read: true},
+		{Message: "third"},
+	}
+	if diff := cmp.Diff(expectation, entries); diff != "" {
+		t.Errorf("unexpected notifications (-want +got):\n%s", diff)
+	}
+	if len(ids) != len(entries) {
+		t.Errorf("expected restored notifications to get new request IDs, got %v", resp.Notifications)
+	}
+
+	// changes are persisted
+	_, err = notificationService.ListNotifications(context.Background(), &api.ListNotificationsRequest{MarkRead: true})
+	if err != nil {
+		t.Fatalf("error listing notifications: %s", err)
+	}
+	persisted, err := readNotificationLog(userfs.Current(), fn)
+	if err != nil {
+		t.Fatalf("error reading notification log: %s", err)
+	}
+	if len(persisted) != 3 || !persisted[2].Read {
+		t.Errorf("expected the notification log to be persisted, got %v", persisted)
+	}
+	if content, err := os.ReadFile(other); err != nil || string(content) != "other\n" {
+		t.Errorf("file linked from the workspace was modified: %q (%v)", content, err)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+)
+
+const (
+	readinessProbeInterval = 1 * time.Second
+	readinessCheckTimeout  = 2 * time.Second
+
+	// maxLogPatternWindow is the amount of terminal output kept around to match log patterns
+	// which span multiple reads.
+	maxLogPatternWindow = 4096
+)
+
+var ansiEscapeSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// readinessProbe determines whether a task is ready based on its readiness config.
+type readinessProbe struct {
+	port       int
+	httpPort   int
+	httpPath   string
+	logPattern *regexp.Regexp
+	logMatched int32
+	timeout    time.Duration
+}
+
+func newReadinessProbe(config *TaskReadinessConfig) (*readinessProbe, error) {
+	probe := &readinessProbe{}
+	if config.Port != nil {
+		probe.port = *config.Port
+	}
+	if config.HTTP != nil {
+		probe.httpPort = config.HTTP.Port
+		probe.httpPath = config.HTTP.Path
+		if !strings.HasPrefix(probe.httpPath, "/") {
+			probe.httpPath = "/" + probe.httpPath
+		}
+	}
+	if config.LogPattern != nil && *config.LogPattern != "" {
+		logPattern, err := regexp.Compile(*config.LogPattern)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid readiness log pattern: %w", err)
+		}
+		probe.logPattern = logPattern
+	}
+	if config.Timeout != nil {
+		probe.timeout = time.Duration(*config.Timeout) * time.Second
+	}
+	return probe, nil
+}
+
+// watchLogs matches the terminal output against the log pattern until it matched once.
+func (p *readinessProbe) watchLogs(term *terminal.Term) {
+	if p.logPattern == nil {
+		return
+	}
+
+	stdout := term.Stdout.ListenWithOptions(terminal.TermListenOptions{
+		ReadTimeout: terminal.NoTimeout,
+	})
+	go func() {
+		defer stdout.Close()
+
+		var (
+			window string
+			buf    = make([]byte, 4096)
+		)
+		for {
+			n, err := stdout.Read(buf)
+			if n > 0 {
+				window += string(buf[:n])
+				if p.logPattern.MatchString(ansiEscapeSequence.ReplaceAllString(window, "")) {
+					atomic.StoreInt32(&p.logMatched, 1)
+					return
+				}
+				if len(window) > maxLogPatternWindow {
+					window = window[len(window)-maxLogPatternWindow:]
+				}
+			}
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				log.WithError(err).Debug("cannot read terminal output for readiness probe")
+				return
+			}
+		}
+	}()
+}
+
+// check runs all configured checks and returns nil if all of them succeeded.
+func (p *readinessProbe) check(ctx context.Context) error {
+	if p.port != 0 {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", p.port), readinessCheckTimeout)
+		if err != nil {
+			return xerrors.Errorf("port %d is not open", p.port)
+		}
+		conn.Close()
+	}
+	if p.httpPort != 0 {
+		ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+		defer cancel()
+		url := fmt.Sprintf("http://localhost:%d%s", p.httpPort, p.httpPath)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return xerrors.Errorf("GET %s failed: %w", url, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return xerrors.Errorf("GET %s responded with %d", url, resp.StatusCode)
+		}
+	}
+	if p.logPattern != nil && atomic.LoadInt32(&p.logMatched) == 0 {
+		return xerrors.Errorf("output did not match %s yet", p.logPattern)
+	}
+	return nil
+}
+
+// probeReadiness starts to periodically check the readiness of a running task and updates its state accordingly.
+// Tasks which depend on the task are started once it became ready for the first time. If the task
+// does not become ready within the configured timeout, it is considered unhealthy and its dependents fail.
+func (tm *tasksManager) probeReadiness(ctx context.Context, t *task, term *terminal.Term) {
+	probe, err := newReadinessProbe(t.config.Readiness)
+	if err != nil {
+		log.WithField("task", t.Id).WithError(err).Error("cannot probe task readiness")
+		tm.setTaskReadiness(t, api.TaskState_unhealthy)
+		t.complete(taskFailed(err.Error()))
+		return
+	}
+	// listen before any command is written to the terminal to not miss any output
+	probe.watchLogs(term)
+	go tm.runReadinessProbe(ctx, t, probe)
+}
+
+func (tm *tasksManager) runReadinessProbe(ctx context.Context, t *task, probe *readinessProbe) {
+	var (
+		taskLog = log.WithField("task", t.Id)
+		start   = time.Now()
+		ticker  = time.NewTicker(readinessProbeInterval)
+		ready   bool
+	)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		tm.mu.RLock()
+		closed := t.State == api.TaskState_closed
+		tm.mu.RUnlock()
+		if closed {
+			return
+		}
+
+		err := probe.check(ctx)
+		if err == nil {
+			if !ready {
+				taskLog.Info("task is ready")
+			}
+			ready = true
+			tm.setTaskReadiness(t, api.TaskState_ready)
+			t.complete(taskSuccessful)
+			continue
+		}
+
+		if ready {
+			tm.setTaskReadiness(t, api.TaskState_unhealthy)
+			continue
+		}
+		if probe.timeout > 0 && time.Since(start) > probe.timeout {
+			tm.setTaskReadiness(t, api.TaskState_unhealthy)
+			t.complete(taskFailed(fmt.Sprintf("task did not become ready within %s: %s", probe.timeout, err)))
+		}
+	}
+}
+
+// setTaskReadiness updates the state of a task unless it has been closed in the meantime.
+func (tm *tasksManager) setTaskReadiness(t *task, newState api.TaskState) {
+	tm.updateState(func() bool {
+		if t.State == newState || t.State == api.TaskState_closed {
+			return false
+		}
+
+		t.State = newState
+		return true
+	})
+}
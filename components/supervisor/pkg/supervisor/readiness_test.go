@@ -0,0 +1,97 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadinessProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	srvPort := srv.Listener.Addr().(*net.TCPAddr).Port
+
+	closedListener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := closedListener.Addr().(*net.TCPAddr).Port
+	closedListener.Close()
+
+	p := func(v int) *int { return &v }
+	s := func(v string) *string { return &v }
+	tests := []struct {
+		Name       string
+		Config     TaskReadinessConfig
+		LogMatched bool
+		Ready      bool
+	}{
+		{
+			Name:   "open port",
+			Config: TaskReadinessConfig{Port: p(srvPort)},
+			Ready:  true,
+		},
+		{
+			Name:   "closed port",
+			Config: TaskReadinessConfig{Port: p(closedPort)},
+		},
+		{
+			Name:   "http 2xx",
+			Config: TaskReadinessConfig{HTTP: &TaskReadinessHTTPConfig{Port: srvPort, Path: "healthz"}},
+			Ready:  true,
+		},
+		{
+			Name:   "http 5xx",
+			Config: TaskReadinessConfig{HTTP: &TaskReadinessHTTPConfig{Port: srvPort, Path: "/"}},
+		},
+		{
+			Name:   "log pattern not matched",
+			Config: TaskReadinessConfig{LogPattern: s("listening on")},
+		},
+		{
+			Name:       "log pattern matched",
+			Config:     TaskReadinessConfig{LogPattern: s("listening on")},
+			LogMatched: true,
+			Ready:      true,
+		},
+		{
+			Name:       "all checks have to succeed",
+			Config:     TaskReadinessConfig{Port: p(closedPort), LogPattern: s("listening on")},
+			LogMatched: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			probe, err := newReadinessProbe(&test.Config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.LogMatched {
+				probe.logMatched = 1
+			}
+			err = probe.check(context.Background())
+			if ready := err == nil; ready != test.Ready {
+				t.Errorf("unexpected readiness: want %v, got %v (%v)", test.Ready, ready, err)
+			}
+		})
+	}
+}
+
+func TestReadinessProbeInvalidLogPattern(t *testing.T) {
+	pattern := "listening on ("
+	_, err := newReadinessProbe(&TaskReadinessConfig{LogPattern: &pattern})
+	if err == nil {
+		t.Error("expected an error for an invalid log pattern")
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+)
+
+const (
+	// resourceAlertDebounce is the time a threshold has to be exceeded before an alert is raised.
+	resourceAlertDebounce = 30 * time.Second
+	// resourceAlertInterval is the time after which an alert of the same severity is raised again.
+	resourceAlertInterval = 10 * time.Minute
+
+	resourceAlertActionShowProcesses = "Show Top Processes"
+	resourceAlertActionRestartTask   = "Restart Task"
+	resourceAlertActionSnooze        = "Snooze"
+)
+
+var defaultPressureThresholds = resourceThresholds{warning: 10, danger: 40}
+
+// pressureResources are the resources for which pressure stall information is available.
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// resourceAlerts notifies the user when the workspace resources are under pressure.
+type resourceAlerts struct {
+	notifications *NotificationService
+	top           *TopService
+	tasks         *tasksManager
+	terminals     *terminal.MuxTerminalService
+
+	readPressure func() (map[string]float64, error)
+	now          func() time.Time
+
+	mu       sync.Mutex
+	config   *gitpod.ResourceAlerts
+	pressure resourceThresholds
+	alerts   map[string]*resourceAlert
+}
+
+type resourceAlert struct {
+	// since is the time the resource has exceeded a threshold first, zero if the resource is not under pressure
+	since         time.Time
+	notifiedLevel api.ResourceStatusSeverity
+	notifiedAt    time.Time
+	// pending is true while a notification awaits the user's response
+	pending bool
+	// snoozed alerts are not raised again for the rest of the session
+	snoozed bool
+}
+
+func newResourceAlerts(notifications *NotificationService, top *TopService, tasks *tasksManager, terminals *terminal.MuxTerminalService) *resourceAlerts {
+	return &resourceAlerts{
+		notifications: notifications,
+		top:           top,
+		tasks:         tasks,
+		terminals:     terminals,
+		readPressure:  readPressure,
+		now:           time.Now,
+		pressure:      defaultPressureThresholds,
+		alerts:        make(map[string]*resourceAlert),
+	}
+}
+
+// Run raises alerts for new resources samples until the context is canceled.
+func (a *resourceAlerts) Run(ctx context.Context, configs <-chan *gitpod.GitpodConfig) {
+	var sub *resourcesSubscription
+	defer func() {
+		if sub != nil {
+			sub.Close()
+		}
+	}()
+	for {
+		if sub == nil {
+			sub = a.top.Subscribe(false)
+			if sub == nil {
+				log.Error("resource alerts: cannot subscribe to resources status")
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case config, ok := <-configs:
+			if !ok {
+				configs = nil
+				continue
+			}
+			a.updateConfig(config)
+		case sample, ok := <-sub.Updates():
+			if !ok {
+				// the subscription has been dropped because we did not keep up
+				sub = nil
+				continue
+			}
+			a.check(ctx, sample)
+		}
+	}
+}
+
+func (a *resourceAlerts) updateConfig(config *gitpod.GitpodConfig) {
+	var alerts *gitpod.ResourceAlerts
+	if config != nil {
+		alerts = config.ResourceAlerts
+	}
+
+	var cpu, memory, pressure *gitpod.ResourceThresholds
+	if alerts != nil {
+		cpu, memory, pressure = alerts.Cpu, alerts.Memory, alerts.Pressure
+	}
+	a.top.SetThresholds(
+		newResourceThresholds(cpu, defaultResourceThresholds),
+		newResourceThresholds(memory, defaultResourceThresholds),
+	)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.config = alerts
+	a.pressure = newResourceThresholds(pressure, defaultPressureThresholds)
+}
+
+func (a *resourceAlerts) check(ctx context.Context, sample *api.ResourcesStatusResponse) {
+	a.mu.Lock()
+	config := a.config
+	pressureThresholds := a.pressure
+	a.mu.Unlock()
+	if config != nil && config.Disabled {
+		return
+	}
+
+	if sample.Cpu != nil {
+		a.evaluate(ctx, "cpu", sample.Cpu.Severity, func() string {
+			return fmt.Sprintf("Workspace CPU usage is at %.0f%% (%dm of %dm).", usagePercentage(sample.Cpu), sample.Cpu.Used, sample.Cpu.Limit)
+		}, topProcessByCPU(sample))
+	}
+	if sample.Memory != nil {
+		a.evaluate(ctx, "memory", sample.Memory.Severity, func() string {
+			return fmt.Sprintf("Workspace memory usage is at %.0f%% (%dMi of %dMi).", usagePercentage(sample.Memory), sample.Memory.Used/(1024*1024), sample.Memory.Limit/(1024*1024))
+		}, topProcessByMemory(sample))
+	}
+
+	if config == nil || config.Pressure == nil {
+		return
+	}
+	pressure, err := a.readPressure()
+	if err != nil {
+		log.WithError(err).Debug("resource alerts: cannot read pressure stall information")
+		return
+	}
+	for _, resource := range pressureResources {
+		value, ok := pressure[resource]
+		if !ok {
+			continue
+		}
+		top := topProcessByCPU(sample)
+		if resource == "memory" {
+			top = topProcessByMemory(sample)
+		}
+		a.evaluate(ctx, resource+" pressure", pressureThresholds.severity(value), func() string {
+			return fmt.Sprintf("Processes in the workspace were stalled on %s %.0f%% of the time during the last 10 seconds.", resource, value)
+		}, top)
+	}
+}
+
+// evaluate raises an alert for a resource once it exceeded a threshold for longer than resourceAlertDebounce.
+// An alert is raised again only if the severity increased or resourceAlertInterval passed.
+func (a *resourceAlerts) evaluate(ctx context.Context, resource string, level api.ResourceStatusSeverity, message func() string, top *api.ProcessResourcesStatus) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	alert, ok := a.alerts[resource]
+	if !ok {
+		alert = &resourceAlert{}
+		a.alerts[resource] = alert
+	}
+	if level == api.ResourceStatusSeverity_normal {
+		alert.since = time.Time{}
+		return
+	}
+
+	now := a.now()
+	if alert.since.IsZero() {
+		alert.since = now
+	}
+	if alert.snoozed || alert.pending || now.Sub(alert.since) < resourceAlertDebounce {
+		return
+	}
+	if level <= alert.notifiedLevel && now.Sub(alert.notifiedAt) < resourceAlertInterval {
+		return
+	}
+	alert.notifiedLevel = level
+	alert.notifiedAt = now
+	alert.pending = true
+
+	req := &api.NotifyRequest{
+		Level:   api.NotifyRequest_WARNING,
+		Message: message(),
+		Actions: []string{resourceAlertActionShowProcesses},
+	}
+	if level == api.ResourceStatusSeverity_danger {
+		req.Level = api.NotifyRequest_ERROR
+	}
+	if top != nil {
+		req.Message += " " + describeProcess(top)
+		if top.TaskId != "" {
+			req.Actions = append(req.Actions, resourceAlertActionRestartTask)
+		}
+	}
+	req.Actions = append(req.Actions, resourceAlertActionSnooze)
+
+	go a.notify(ctx, alert, req, top)
+}
+
+func (a *resourceAlerts) notify(ctx context.Context, alert *resourceAlert, req *api.NotifyRequest, top *api.ProcessResourcesStatus) {
+	resp, err := a.notifications.Notify(ctx, req)
+
+	a.mu.Lock()
+	alert.pending = false
+	if err == nil && resp.Action == resourceAlertActionSnooze {
+		alert.snoozed = true
+	}
+	a.mu.Unlock()
+
+	if err != nil {
+		if ctx.Err() == nil {
+			log.WithError(err).Error("resource alerts: cannot notify about resource pressure")
+		}
+		return
+	}
+
+	switch resp.Action {
+	case resourceAlertActionShowProcesses:
+		err = a.showTopProcesses(ctx)
+	case resourceAlertActionRestartTask:
+		err = a.tasks.Restart(ctx, top.TaskId)
+	}
+	if err != nil {
+		log.WithError(err).WithField("action", resp.Action).Error("resource alerts: cannot run action")
+	}
+}
+
+// showTopProcesses opens a terminal which shows the resources usage per task.
+func (a *resourceAlerts) showTopProcesses(ctx context.Context) error {
+	resp, err := a.terminals.OpenWithOptions(ctx, &api.OpenTerminalRequest{}, terminal.TermOptions{
+		ReadTimeout: 5 * time.Second,
+		Title:       "Workspace Resources",
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot open terminal: %w", err)
+	}
+	term, ok := a.terminals.Mux.Get(resp.Terminal.Alias)
+	if !ok {
+		return xerrors.Errorf("cannot find terminal %s", resp.Terminal.Alias)
+	}
+	_, err = term.PTY.Write([]byte("gp top --watch --by-task\n"))
+	return err
+}
+
+func describeProcess(p *api.ProcessResourcesStatus) string {
+	name := p.Command
+	if p.TaskId != "" {
+		name = p.TaskName
+		if name == "" {
+			name = p.TaskId
+		}
+		name = "task " + name
+	}
+	return fmt.Sprintf("The top consumer is %s using %dm CPU and %dMi memory.", name, p.Cpu, p.Memory/(1024*1024))
+}
+
+func topProcessByCPU(sample *api.ResourcesStatusResponse) *api.ProcessResourcesStatus {
+	// processes are sorted by CPU usage
+	if len(sample.Processes) == 0 {
+		return nil
+	}
+	return sample.Processes[0]
+}
+
+func topProcessByMemory(sample *api.ResourcesStatusResponse) *api.ProcessResourcesStatus {
+	var res *api.ProcessResourcesStatus
+	for _, p := range sample.Processes {
+		if res == nil || p.Memory > res.Memory {
+			res = p
+		}
+	}
+	return res
+}
+
+// readPressure reads the "some" avg10 pressure stall information of the workspace cgroup,
+// falling back to the system wide information.
+func readPressure() (map[string]float64, error) {
+	res := make(map[string]float64, len(pressureResources))
+	for _, resource := range pressureResources {
+		content, err := os.ReadFile("/sys/fs/cgroup/" + resource + ".pressure")
+		if err != nil {
+			content, err = os.ReadFile("/proc/pressure/" + resource)
+		}
+		if err != nil {
+			continue
+		}
+		value, err := parsePressure(string(content))
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse %s pressure: %w", resource, err)
+		}
+		res[resource] = value
+	}
+	if len(res) == 0 {
+		return nil, xerrors.Errorf("pressure stall information is not available")
+	}
+	return res, nil
+}
+
+// parsePressure returns the avg10 value of the "some" line of a PSI file, e.g.
+//
+//	some avg10=1.53 avg60=0.87 avg300=0.28 total=3289
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(content string) (float64, error) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "some" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			value := strings.TrimPrefix(field, "avg10=")
+			if value == field {
+				continue
+			}
+			return strconv.ParseFloat(value, 64)
+		}
+	}
+	return 0, xerrors.Errorf("missing some avg10")
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestParsePressure(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Content     string
+		Expectation float64
+		Error       bool
+	}{
+		{
+			Desc:        "cgroup pressure",
+			Content:     "some avg10=12.53 avg60=0.87 avg300=0.28 total=3289\nfull avg10=3.00 avg60=0.00 avg300=0.00 total=0\n",
+			Expectation: 12.53,
+		},
+		{
+			Desc:        "cpu pressure without full line",
+			Content:     "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+			Expectation: 0,
+		},
+		{
+			Desc:    "malformed",
+			Content: "full avg10=3.00\n",
+			Error:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			value, err := parsePressure(test.Content)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value != test.Expectation {
+				t.Errorf("expected %v, got %v", test.Expectation, value)
+			}
+		})
+	}
+}
+
+func TestResourceAlerts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	notifications := NewNotificationService()
+	subscriber := NewSubscribeServer()
+	defer subscriber.cancel()
+	go func() {
+		_ = notifications.Subscribe(&api.SubscribeRequest{}, subscriber)
+	}()
+
+	now := time.Unix(0, 0)
+	alerts := newResourceAlerts(notifications, NewTopService(), nil, nil)
+	alerts.now = func() time.Time { return now }
+
+	sample := &api.ResourcesStatusResponse{
+		Memory: &api.ResourceStatus{Used: 3900 * 1024 * 1024, Limit: 4000 * 1024 * 1024, Severity: api.ResourceStatusSeverity_danger},
+		Cpu:    &api.ResourceStatus{Used: 100, Limit: 4000},
+		Processes: []*api.ProcessResourcesStatus{
+			{Pid: 20, Command: "/bin/bash", TaskId: "0", TaskName: "watch", Cpu: 100, Memory: 3000 * 1024 * 1024},
+		},
+	}
+
+	alerts.check(ctx, sample)
+	now = now.Add(resourceAlertDebounce / 2)
+	alerts.check(ctx, sample)
+	select {
+	case notification := <-subscriber.resps:
+		t.Fatalf("unexpected notification before debounce: %v", notification.Request)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	now = now.Add(resourceAlertDebounce)
+	alerts.check(ctx, sample)
+	var notification *api.SubscribeResponse
+	select {
+	case notification = <-subscriber.resps:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a notification")
+	}
+	if diff := cmp.Diff([]string{resourceAlertActionShowProcesses, resourceAlertActionRestartTask, resourceAlertActionSnooze}, notification.Request.Actions); diff != "" {
+		t.Errorf("unexpected actions (-want +got):\n%s", diff)
+	}
+	if notification.Request.Level != api.NotifyRequest_ERROR {
+		t.Errorf("expected an error notification, got %v", notification.Request.Level)
+	}
+
+	// no new notification while the previous one is pending
+	now = now.Add(resourceAlertInterval)
+	alerts.check(ctx, sample)
+
+	_, err := notifications.Respond(ctx, &api.RespondRequest{
+		RequestId: notification.RequestId,
+		Response:  &api.NotifyResponse{Action: resourceAlertActionSnooze},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		alerts.mu.Lock()
+		snoozed := alerts.alerts["memory"].snoozed
+		alerts.mu.Unlock()
+		if snoozed {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	now = now.Add(resourceAlertInterval)
+	alerts.check(ctx, sample)
+	select {
+	case notification := <-subscriber.resps:
+		t.Fatalf("unexpected notification after snooze: %v", notification.Request)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
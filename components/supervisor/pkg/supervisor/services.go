@@ -16,16 +16,19 @@ import (
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"golang.org/x/crypto/ssh"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/supervisor/api"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
 )
 
 // RegisterableService can register a service.
@@ -95,8 +98,10 @@ type statusService struct {
 	ContentState    ContentState
 	Ports           *ports.Manager
 	Tasks           *tasksManager
+	Services        *servicesManager
 	ideReady        *ideReadyState
 	desktopIdeReady *ideReadyState
+	additionalIdes  []*additionalIDE
 	topService      *TopService
 
 	api.UnimplementedStatusServiceServer
@@ -143,6 +148,7 @@ func (s *statusService) IDEStatus(ctx context.Context, req *api.IDEStatusRequest
 				return nil, status.Error(codes.DeadlineExceeded, ctx.Err().Error())
 			}
 		}
+
 	}
 
 	ok, _ := s.ideReady.Get()
@@ -157,7 +163,24 @@ func (s *statusService) IDEStatus(ctx context.Context, req *api.IDEStatusRequest
 		}
 		ok = ok && okR
 	}
-	return &api.IDEStatusResponse{Ok: ok, Desktop: desktopStatus}, nil
+	// additional IDEs are optional, their readiness is reported separately and does not affect the workspace IDE
+	additional := make([]*api.IDEStatusResponse_AdditionalStatus, 0, len(s.additionalIdes))
+	for _, ide := range s.additionalIdes {
+		okA, i := ide.Ready.Get()
+		ideStatus := &api.IDEStatusResponse_AdditionalStatus{
+			Name: ide.Config.Name,
+			Ok:   okA,
+			Port: uint32(ide.Config.Port),
+		}
+		if i != nil {
+			ideStatus.Link = i.Link
+			ideStatus.Label = i.Label
+			ideStatus.ClientID = i.ClientID
+			ideStatus.Kind = i.Kind
+		}
+		additional = append(additional, ideStatus)
+	}
+	return &api.IDEStatusResponse{Ok: ok, Desktop: desktopStatus, Additional: additional}, nil
 }
 
 // ContentStatus provides feedback regarding the workspace content readiness.
@@ -272,6 +295,35 @@ func (s *statusService) TasksStatus(req *api.TasksStatusRequest, srv api.StatusS
 	}
 }
 
+func (s *statusService) ServicesStatus(req *api.ServicesStatusRequest, srv api.StatusService_ServicesStatusServer) error {
+	if !req.Observe {
+		return srv.Send(&api.ServicesStatusResponse{
+			Services: s.Services.Status(),
+		})
+	}
+
+	sub := s.Services.Subscribe()
+	if sub == nil {
+		return status.Error(codes.ResourceExhausted, "too many subscriptions")
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case update := <-sub.Updates():
+			if update == nil {
+				return nil
+			}
+			err := srv.Send(&api.ServicesStatusResponse{Services: update})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // RegistrableTokenService can register the token service.
 type RegistrableTokenService struct {
 	Service api.TokenServiceServer
@@ -625,6 +677,8 @@ func (rt *remoteTokenProvider) GetToken(ctx context.Context, req *api.GetTokenRe
 type InfoService struct {
 	cfg          *Config
 	ContentState ContentState
+	Env          *childProcEnv
+	Tasks        *tasksManager
 
 	api.UnimplementedInfoServiceServer
 }
@@ -694,9 +748,40 @@ func (is *InfoService) WorkspaceInfo(context.Context, *api.WorkspaceInfoRequest)
 	return resp, nil
 }
 
+// Environment provides the environment of the processes started by supervisor.
+func (is *InfoService) Environment(ctx context.Context, req *api.EnvironmentRequest) (*api.EnvironmentResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, status.Error(codes.Unavailable, "environment is not available before the workspace content is ready")
+	case <-is.Env.ready:
+	}
+
+	envvars := is.Env.Envvars()
+	if req.TaskId != "" {
+		taskEnv, ok := is.Tasks.Env(req.TaskId)
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "task %s does not exist", req.TaskId)
+		}
+		envvars = mergeEnvvars(envvars, taskEnv)
+	}
+
+	resp := &api.EnvironmentResponse{}
+	for _, e := range envvars {
+		resp.Variables = append(resp.Variables, &api.EnvironmentVariable{
+			Name:    e.Name,
+			Value:   e.Value,
+			Source:  e.Source,
+			Secrets: e.Secrets,
+		})
+	}
+	return resp, nil
+}
+
 // ControlService implements the supervisor control service.
 type ControlService struct {
 	portsManager *ports.Manager
+	tasks        *tasksManager
+	activity     *ideActivity
 
 	privateKey string
 	publicKey  string
@@ -715,12 +800,31 @@ func (c *ControlService) ExposePort(ctx context.Context, req *api.ExposePortRequ
 	return &api.ExposePortResponse{}, err
 }
 
+// ReloadTasks applies the changes of the tasks in .gitpod.yml to the running tasks.
+func (c *ControlService) ReloadTasks(ctx context.Context, req *api.ReloadTasksRequest) (*api.ReloadTasksResponse, error) {
+	diff, err := c.tasks.Reload(ctx, req.DryRun)
+	if err == errNoTaskConfig {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return diff.toResponse(), nil
+}
+
+// MarkActive records that the workspace is used through an IDE.
+func (c *ControlService) MarkActive(context.Context, *api.MarkActiveRequest) (*api.MarkActiveResponse, error) {
+	c.activity.MarkActive()
+	return &api.MarkActiveResponse{}, nil
+}
+
 // CreateSSHKeyPair create a ssh key pair for the workspace.
 func (ss *ControlService) CreateSSHKeyPair(context.Context, *api.CreateSSHKeyPairRequest) (response *api.CreateSSHKeyPairResponse, err error) {
 	home := "/home/gitpod/"
+	user := userfs.User{UID: gitpodUID, GID: gitpodGID}
 	if ss.privateKey != "" && ss.publicKey != "" {
 		checkKey := func() error {
-			data, err := os.ReadFile(filepath.Join(home, ".ssh/authorized_keys"))
+			data, err := user.ReadFile(filepath.Join(home, ".ssh/authorized_keys"))
 			if err != nil {
 				return xerrors.Errorf("cannot read file ~/.ssh/authorized_keys: %w", err)
 			}
@@ -753,21 +857,13 @@ func (ss *ControlService) CreateSSHKeyPair(context.Context, *api.CreateSSHKeyPai
 	if err != nil {
 		return nil, xerrors.Errorf("cannot read privatekey: %w", err)
 	}
-	err = os.MkdirAll(filepath.Join(home, ".ssh"), 0o700)
-	if err != nil {
-		return nil, xerrors.Errorf("cannot create dir ~/.ssh/: %w", err)
-	}
-	f, err := os.OpenFile(filepath.Join(home, ".ssh/authorized_keys"), os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	publicKey, comment, _, _, err := ssh.ParseAuthorizedKey(bPublic)
 	if err != nil {
-		return nil, xerrors.Errorf("cannot open file ~/.ssh/authorized_keys: %w", err)
+		return nil, xerrors.Errorf("cannot parse publickey: %w", err)
 	}
-	_, err = f.Write(bPublic)
+	_, err = addAuthorizedKey(user, filepath.Join(home, ".ssh/authorized_keys"), publicKey, comment, nil)
 	if err != nil {
-		return nil, xerrors.Errorf("cannot write file ~.ssh/authorized_keys: %w", err)
-	}
-	err = os.Chown(filepath.Join(home, ".ssh/authorized_keys"), gitpodUID, gitpodGID)
-	if err != nil {
-		return nil, xerrors.Errorf("cannot chown SSH authorized_keys file: %w", err)
+		return nil, xerrors.Errorf("cannot write file ~/.ssh/authorized_keys: %w", err)
 	}
 	ss.privateKey = string(bPrivate)
 	ss.publicKey = string(bPublic)
@@ -776,6 +872,128 @@ func (ss *ControlService) CreateSSHKeyPair(context.Context, *api.CreateSSHKeyPai
 	}, err
 }
 
+// SSHService implements the supervisor SSH service.
+type SSHService struct {
+	// authorizedKeys is the path of the gitpod user's authorized_keys file
+	authorizedKeys string
+	// user is the gitpod user, the authorized_keys file is accessed as
+	user        userfs.User
+	connections *sshConnections
+
+	api.UnimplementedSSHServiceServer
+}
+
+// RegisterGRPC registers the gRPC SSH service.
+func (s *SSHService) RegisterGRPC(srv *grpc.Server) {
+	api.RegisterSSHServiceServer(srv, s)
+}
+
+// RegisterREST registers the REST SSH service.
+func (s *SSHService) RegisterREST(mux *runtime.ServeMux, grpcEndpoint string) error {
+	return api.RegisterSSHServiceHandlerFromEndpoint(context.Background(), mux, grpcEndpoint, []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())})
+}
+
+// ListKeys lists the authorized SSH keys.
+func (s *SSHService) ListKeys(ctx context.Context, req *api.ListSSHKeysRequest) (*api.ListSSHKeysResponse, error) {
+	keys, err := readAuthorizedKeys(s.user, s.authorizedKeys)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	now := time.Now()
+	res := &api.ListSSHKeysResponse{}
+	for _, k := range keys {
+		if k.Key == nil {
+			continue
+		}
+		res.Keys = append(res.Keys, toSSHKey(k, now))
+	}
+	return res, nil
+}
+
+// AddKey authorizes an SSH key.
+func (s *SSHService) AddKey(ctx context.Context, req *api.AddSSHKeyRequest) (*api.AddSSHKeyResponse, error) {
+	key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(req.PublicKey))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid public key: %v", err)
+	}
+	var expiryTime *time.Time
+	if req.Ttl != nil {
+		ttl := req.Ttl.AsDuration()
+		if ttl <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "ttl must be positive")
+		}
+		t := time.Now().Add(ttl).Truncate(time.Second)
+		expiryTime = &t
+	}
+
+	entry, err := addAuthorizedKey(s.user, s.authorizedKeys, key, comment, expiryTime)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.WithField("fingerprint", entry.Fingerprint()).WithField("expiryTime", expiryTime).Info("authorized SSH key")
+	return &api.AddSSHKeyResponse{Key: toSSHKey(*entry, time.Now())}, nil
+}
+
+// RevokeKey removes an authorized SSH key.
+func (s *SSHService) RevokeKey(ctx context.Context, req *api.RevokeSSHKeyRequest) (*api.RevokeSSHKeyResponse, error) {
+	found, err := removeAuthorizedKey(s.user, s.authorizedKeys, req.Fingerprint)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !found {
+		return nil, status.Error(codes.NotFound, "key not found")
+	}
+	log.WithField("fingerprint", req.Fingerprint).Info("revoked SSH key")
+	err = s.connections.closeUnauthorized(s.user, s.authorizedKeys)
+	if err != nil {
+		log.WithError(err).Warn("cannot close SSH connections of revoked key")
+	}
+	return &api.RevokeSSHKeyResponse{}, nil
+}
+
+// ListSessions lists the established SSH sessions.
+func (s *SSHService) ListSessions(ctx context.Context, req *api.ListSSHSessionsRequest) (*api.ListSSHSessionsResponse, error) {
+	res := &api.ListSSHSessionsResponse{}
+	for _, c := range s.connections.List() {
+		res.Sessions = append(res.Sessions, &api.SSHSession{
+			Id:             c.id,
+			RemoteAddr:     c.conn.RemoteAddr().String(),
+			User:           c.conn.User(),
+			KeyFingerprint: c.KeyFingerprint(),
+			StartTime:      timestamppb.New(c.startTime),
+			Terminals:      c.Terminals(),
+		})
+	}
+	return res, nil
+}
+
+// KillSession closes an SSH session.
+func (s *SSHService) KillSession(ctx context.Context, req *api.KillSSHSessionRequest) (*api.KillSSHSessionResponse, error) {
+	c, ok := s.connections.Get(req.Id)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "session not found")
+	}
+	log.WithField("session", req.Id).WithField("remoteAddr", c.conn.RemoteAddr().String()).Info("killing SSH session")
+	err := c.conn.Close()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.KillSSHSessionResponse{}, nil
+}
+
+func toSSHKey(k authorizedKey, now time.Time) *api.SSHKey {
+	res := &api.SSHKey{
+		Fingerprint: k.Fingerprint(),
+		Type:        k.Key.Type(),
+		Comment:     k.Comment,
+		Expired:     k.Expired(now),
+	}
+	if k.ExpiryTime != nil {
+		res.ExpiryTime = timestamppb.New(*k.ExpiryTime)
+	}
+	return res
+}
+
 // ContentState signals the workspace content state.
 type ContentState interface {
 	MarkContentReady(src csapi.WorkspaceInitSource)
@@ -938,3 +1156,33 @@ func (s *portService) RetryAutoExpose(ctx context.Context, req *api.RetryAutoExp
 func (s *statusService) ResourcesStatus(ctx context.Context, in *api.ResourcesStatuRequest) (*api.ResourcesStatusResponse, error) {
 	return s.topService.data, nil
 }
+
+// ObserveResourcesStatus streams workspace resources status information including a per process tree breakdown.
+func (s *statusService) ObserveResourcesStatus(req *api.ObserveResourcesStatusRequest, srv api.StatusService_ObserveResourcesStatusServer) error {
+	select {
+	case <-srv.Context().Done():
+		return nil
+	case <-s.topService.ready:
+	}
+
+	sub := s.topService.Subscribe(req.History)
+	if sub == nil {
+		return status.Error(codes.ResourceExhausted, "too many subscriptions")
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case update, ok := <-sub.Updates():
+			if !ok {
+				return nil
+			}
+			err := srv.Send(update)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
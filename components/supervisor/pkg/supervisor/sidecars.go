@@ -0,0 +1,472 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	// serviceContainerPrefix prefixes the names of the containers of services configured in .gitpod.yml
+	serviceContainerPrefix = "gitpod-service-"
+	serviceCheckInterval   = 5 * time.Second
+	serviceStopTimeout     = 10 * time.Second
+
+	defaultServiceHealthInterval = "10s"
+	defaultServiceHealthTimeout  = "5s"
+	defaultServiceHealthRetries  = 3
+)
+
+// containerState is the state of a container as reported by docker inspect.
+type containerState struct {
+	Status   string `json:"Status"`
+	ExitCode int    `json:"ExitCode"`
+	Error    string `json:"Error"`
+	Health   *struct {
+		Status string `json:"Status"`
+	} `json:"Health"`
+}
+
+// containerRuntime manages the containers of services.
+type containerRuntime interface {
+	// Run replaces the container of a service with a new one and returns its ID.
+	Run(ctx context.Context, container string, service *gitpod.ServicesItems) (string, error)
+	// Inspect returns the state of a container.
+	Inspect(ctx context.Context, container string) (*containerState, error)
+	// Remove stops and removes a container.
+	Remove(ctx context.Context, container string) error
+}
+
+// dockerCLI runs service containers using the Docker daemon which is started by socketActivationForDocker.
+type dockerCLI struct {
+	repoRoot string
+	env      func() []string
+}
+
+func (d *dockerCLI) docker(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := runAsGitpodUser(exec.CommandContext(ctx, "docker", args...))
+	cmd.Env = d.env()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, xerrors.Errorf("docker %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func (d *dockerCLI) Run(ctx context.Context, container string, service *gitpod.ServicesItems) (string, error) {
+	// a container of a previous run may still exist, e.g. after a workspace restart
+	_, _ = d.docker(ctx, "rm", "--force", container)
+
+	out, err := d.docker(ctx, append([]string{"run"}, dockerRunArgs(d.repoRoot, container, service)...)...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (d *dockerCLI) Inspect(ctx context.Context, container string) (*containerState, error) {
+	out, err := d.docker(ctx, "inspect", "--format", "{{json .State}}", container)
+	if err != nil {
+		return nil, err
+	}
+	var res containerState
+	err = json.Unmarshal(out, &res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse state of container %s: %w", container, err)
+	}
+	return &res, nil
+}
+
+func (d *dockerCLI) Remove(ctx context.Context, container string) error {
+	_, err := d.docker(ctx, "stop", "--time", strconv.Itoa(int(serviceStopTimeout.Seconds())), container)
+	if err != nil {
+		log.WithError(err).WithField("container", container).Warn("cannot stop service container")
+	}
+	_, err = d.docker(ctx, "rm", "--force", container)
+	return err
+}
+
+// dockerRunArgs returns the arguments of docker run to start the container of a service.
+func dockerRunArgs(repoRoot string, container string, service *gitpod.ServicesItems) []string {
+	args := []string{
+		"--detach",
+		"--name", container,
+		"--label", "gitpod.service=" + service.Name,
+	}
+
+	names := make([]string, 0, len(service.Env))
+	for name := range service.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--env", name+"="+service.Env[name])
+	}
+	for _, port := range service.Ports {
+		args = append(args, "--publish", fmt.Sprintf("%d:%d", int(port), int(port)))
+	}
+	for _, volume := range service.Volumes {
+		if strings.HasPrefix(volume, "./") || strings.HasPrefix(volume, "../") {
+			volume = filepath.Join(repoRoot, volume)
+		}
+		args = append(args, "--volume", volume)
+	}
+	if hc := service.Healthcheck; hc != nil && hc.Command != "" {
+		interval, timeout, retries := hc.Interval, hc.Timeout, int(hc.Retries)
+		if interval == "" {
+			interval = defaultServiceHealthInterval
+		}
+		if timeout == "" {
+			timeout = defaultServiceHealthTimeout
+		}
+		if retries <= 0 {
+			retries = defaultServiceHealthRetries
+		}
+		args = append(args,
+			"--health-cmd", hc.Command,
+			"--health-interval", interval,
+			"--health-timeout", timeout,
+			"--health-retries", strconv.Itoa(retries),
+		)
+	}
+	return append(args, service.Image)
+}
+
+type servicesSubscription struct {
+	updates chan []*api.ServiceStatus
+	Close   func() error
+}
+
+func (sub *servicesSubscription) Updates() <-chan []*api.ServiceStatus {
+	return sub.updates
+}
+
+type service struct {
+	api.ServiceStatus
+	config *gitpod.ServicesItems
+	cancel context.CancelFunc
+	// done is closed once the service is no longer supervised
+	done chan struct{}
+}
+
+// servicesManager runs the service containers configured in .gitpod.yml and restarts them on failure.
+type servicesManager struct {
+	runtime       containerRuntime
+	checkInterval time.Duration
+
+	// reconcileMu serialises changes to the set of services
+	reconcileMu sync.Mutex
+	stopped     bool
+
+	mu            sync.RWMutex
+	services      []*service
+	subscriptions map[*servicesSubscription]struct{}
+}
+
+func newServicesManager(runtime containerRuntime) *servicesManager {
+	return &servicesManager{
+		runtime:       runtime,
+		checkInterval: serviceCheckInterval,
+		subscriptions: make(map[*servicesSubscription]struct{}),
+	}
+}
+
+// Subscribe provides the status of the services whenever it changes.
+func (sm *servicesManager) Subscribe() *servicesSubscription {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if len(sm.subscriptions) > maxSubscriptions {
+		return nil
+	}
+
+	sub := &servicesSubscription{updates: make(chan []*api.ServiceStatus, 5)}
+	sub.Close = func() error {
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+
+		// We can safely close the channel here even though we're not the
+		// producer writing to it, because we're holding mu.
+		close(sub.updates)
+		delete(sm.subscriptions, sub)
+
+		return nil
+	}
+	sm.subscriptions[sub] = struct{}{}
+
+	// makes sure that no updates can happen between clients receiving an initial status and subscribing
+	sub.updates <- sm.getStatus()
+	return sub
+}
+
+// Status returns the status of the services.
+func (sm *servicesManager) Status() []*api.ServiceStatus {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	return sm.getStatus()
+}
+
+// getStatus produces an API compatible service status list.
+// Callers are expected to hold mu.
+func (sm *servicesManager) getStatus() []*api.ServiceStatus {
+	status := make([]*api.ServiceStatus, 0, len(sm.services))
+	for _, s := range sm.services {
+		status = append(status, proto.Clone(&s.ServiceStatus).(*api.ServiceStatus))
+	}
+	return status
+}
+
+func (sm *servicesManager) updateState(doUpdate func() (changed bool)) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	changed := doUpdate()
+	if !changed {
+		return
+	}
+
+	updates := sm.getStatus()
+	for sub := range sm.subscriptions {
+		select {
+		case sub.updates <- updates:
+		case <-time.After(5 * time.Second):
+			log.Error("services subscription droped out")
+			sub.Close()
+		}
+	}
+}
+
+func (sm *servicesManager) setState(s *service, state api.ServiceStatus_State, containerID string, message string) {
+	sm.updateState(func() bool {
+		if s.State == state && s.ContainerId == containerID && s.Message == message {
+			return false
+		}
+		s.State = state
+		s.ContainerId = containerID
+		s.Message = message
+		return true
+	})
+}
+
+// Run starts the configured services once the workspace content is ready and keeps them in line with the configuration.
+func (sm *servicesManager) Run(ctx context.Context, contentReady <-chan struct{}, configs <-chan *gitpod.GitpodConfig) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-contentReady:
+	}
+
+	for config := range configs {
+		var services []*gitpod.ServicesItems
+		if config != nil {
+			services = config.Services
+		}
+		sm.reconcile(ctx, services)
+	}
+}
+
+// reconcile starts new services, stops removed ones and restarts services whose configuration has changed.
+func (sm *servicesManager) reconcile(ctx context.Context, configs []*gitpod.ServicesItems) {
+	sm.reconcileMu.Lock()
+	defer sm.reconcileMu.Unlock()
+	if sm.stopped || ctx.Err() != nil {
+		return
+	}
+
+	desired := make(map[string]*gitpod.ServicesItems, len(configs))
+	for _, config := range configs {
+		if config == nil || config.Name == "" || config.Image == "" {
+			continue
+		}
+		if _, exists := desired[config.Name]; exists {
+			log.WithField("service", config.Name).Warn("ignoring service with duplicate name")
+			continue
+		}
+		desired[config.Name] = config
+	}
+
+	sm.mu.RLock()
+	current := sm.services
+	sm.mu.RUnlock()
+
+	var (
+		kept    []*service
+		stopped []*service
+		running = make(map[string]struct{})
+	)
+	for _, s := range current {
+		config, exists := desired[s.Name]
+		if exists && reflect.DeepEqual(config, s.config) {
+			kept = append(kept, s)
+			running[s.Name] = struct{}{}
+			continue
+		}
+		stopped = append(stopped, s)
+	}
+	for _, s := range stopped {
+		sm.stopService(ctx, s)
+	}
+
+	var started []*service
+	for _, config := range configs {
+		if config == nil || desired[config.Name] != config {
+			continue
+		}
+		if _, exists := running[config.Name]; exists {
+			continue
+		}
+		ports := make([]uint32, 0, len(config.Ports))
+		for _, port := range config.Ports {
+			ports = append(ports, uint32(port))
+		}
+		started = append(started, &service{
+			ServiceStatus: api.ServiceStatus{
+				Name:  config.Name,
+				Image: config.Image,
+				State: api.ServiceStatus_starting,
+				Ports: ports,
+			},
+			config: config,
+			done:   make(chan struct{}),
+		})
+	}
+
+	sm.updateState(func() bool {
+		sm.services = append(kept, started...)
+		return len(stopped) > 0 || len(started) > 0
+	})
+	for _, s := range started {
+		var serviceCtx context.Context
+		serviceCtx, s.cancel = context.WithCancel(ctx)
+		go sm.supervise(serviceCtx, s)
+	}
+}
+
+// supervise runs the container of a service and replaces it whenever it exits or becomes unhealthy.
+func (sm *servicesManager) supervise(ctx context.Context, s *service) {
+	defer close(s.done)
+
+	container := serviceContainerPrefix + s.Name
+	serviceLog := log.WithField("service", s.Name).WithField("image", s.config.Image)
+	for {
+		sm.setState(s, api.ServiceStatus_starting, "", "")
+		serviceLog.Info("starting service container")
+		id, err := sm.runtime.Run(ctx, container, s.config)
+		if err == nil {
+			sm.setState(s, api.ServiceStatus_running, id, "")
+			err = sm.watch(ctx, s, container, id)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		backoff := taskRestartBackoff(s.RestartCount)
+		serviceLog.WithError(err).WithField("backoff", backoff.String()).Warn("restarting service container")
+		sm.setState(s, api.ServiceStatus_unhealthy, id, err.Error())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		sm.updateState(func() bool {
+			s.RestartCount++
+			return true
+		})
+	}
+}
+
+// watch tracks the state of a running container and returns once the service needs to be restarted.
+func (sm *servicesManager) watch(ctx context.Context, s *service, container, id string) error {
+	ticker := time.NewTicker(sm.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		state, err := sm.runtime.Inspect(ctx, container)
+		if err != nil {
+			return err
+		}
+		switch state.Status {
+		case "exited", "dead":
+			if state.Error != "" {
+				return xerrors.Errorf("container exited with code %d: %s", state.ExitCode, state.Error)
+			}
+			return xerrors.Errorf("container exited with code %d", state.ExitCode)
+		case "running":
+			if state.Health == nil {
+				sm.setState(s, api.ServiceStatus_running, id, "")
+				continue
+			}
+			switch state.Health.Status {
+			case "healthy":
+				sm.setState(s, api.ServiceStatus_healthy, id, "")
+			case "unhealthy":
+				return xerrors.Errorf("healthcheck failed")
+			default:
+				sm.setState(s, api.ServiceStatus_running, id, "")
+			}
+		}
+	}
+}
+
+// stopService stops supervising a service and removes its container.
+func (sm *servicesManager) stopService(ctx context.Context, s *service) {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+	}
+	log.WithField("service", s.Name).Info("stopping service container")
+	err := sm.runtime.Remove(ctx, serviceContainerPrefix+s.Name)
+	if err != nil {
+		log.WithError(err).WithField("service", s.Name).Warn("cannot remove service container")
+	}
+	sm.setState(s, api.ServiceStatus_stopped, "", "")
+}
+
+// Stop stops all services, e.g. s.t. databases can flush their state before the workspace content is backed up.
+func (sm *servicesManager) Stop(ctx context.Context) {
+	sm.reconcileMu.Lock()
+	defer sm.reconcileMu.Unlock()
+	sm.stopped = true
+
+	sm.mu.RLock()
+	services := sm.services
+	sm.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, s := range services {
+		wg.Add(1)
+		go func(s *service) {
+			defer wg.Done()
+			sm.stopService(ctx, s)
+		}(s)
+	}
+	wg.Wait()
+}
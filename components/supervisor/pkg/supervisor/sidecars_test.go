@@ -0,0 +1,200 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestDockerRunArgs(t *testing.T) {
+	tests := []struct {
+		Desc        string
+		Service     *gitpod.ServicesItems
+		Expectation []string
+	}{
+		{
+			Desc:    "image only",
+			Service: &gitpod.ServicesItems{Name: "redis", Image: "redis:7"},
+			Expectation: []string{
+				"--detach", "--name", "gitpod-service-redis", "--label", "gitpod.service=redis",
+				"redis:7",
+			},
+		},
+		{
+			Desc: "full config",
+			Service: &gitpod.ServicesItems{
+				Name:    "postgres",
+				Image:   "postgres:14",
+				Env:     map[string]string{"POSTGRES_USER": "gitpod", "POSTGRES_PASSWORD": "gitpod"},
+				Ports:   []float64{5432},
+				Volumes: []string{"./.pgdata:/var/lib/postgresql/data", "cache:/cache", "/tmp:/tmp:ro"},
+				Healthcheck: &gitpod.Healthcheck{
+					Command: "pg_isready -U gitpod",
+					Retries: 5,
+				},
+			},
+			Expectation: []string{
+				"--detach", "--name", "gitpod-service-postgres", "--label", "gitpod.service=postgres",
+				"--env", "POSTGRES_PASSWORD=gitpod",
+				"--env", "POSTGRES_USER=gitpod",
+				"--publish", "5432:5432",
+				"--volume", "/workspace/repo/.pgdata:/var/lib/postgresql/data",
+				"--volume", "cache:/cache",
+				"--volume", "/tmp:/tmp:ro",
+				"--health-cmd", "pg_isready -U gitpod",
+				"--health-interval", "10s",
+				"--health-timeout", "5s",
+				"--health-retries", "5",
+				"postgres:14",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			act := dockerRunArgs("/workspace/repo", serviceContainerPrefix+test.Service.Name, test.Service)
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected docker run args (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type testContainerRuntime struct {
+	mu      sync.Mutex
+	runs    map[string]int
+	removed map[string]int
+	states  map[string]*containerState
+}
+
+func (r *testContainerRuntime) Run(ctx context.Context, container string, service *gitpod.ServicesItems) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[container]++
+	if state, exists := r.states[container]; !exists || state.Status == "exited" {
+		r.states[container] = &containerState{Status: "running"}
+	}
+	return container, nil
+}
+
+func (r *testContainerRuntime) Inspect(ctx context.Context, container string) (*containerState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := *r.states[container]
+	return &state, nil
+}
+
+func (r *testContainerRuntime) Remove(ctx context.Context, container string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removed[container]++
+	return nil
+}
+
+func (r *testContainerRuntime) set(container string, state *containerState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[container] = state
+}
+
+func (r *testContainerRuntime) count(counts map[string]int, container string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return counts[container]
+}
+
+func TestServicesManager(t *testing.T) {
+	runtime := &testContainerRuntime{
+		runs:    make(map[string]int),
+		removed: make(map[string]int),
+		states:  make(map[string]*containerState),
+	}
+	sm := newServicesManager(runtime)
+	sm.checkInterval = 10 * time.Millisecond
+
+	sub := sm.Subscribe()
+	defer sub.Close()
+	waitFor := func(desc string, check func(status []*api.ServiceStatus) bool) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case status := <-sub.Updates():
+				if check(status) {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timed out waiting for %s", desc)
+			}
+		}
+	}
+	stateOf := func(name string) func(status []*api.ServiceStatus) api.ServiceStatus_State {
+		return func(status []*api.ServiceStatus) api.ServiceStatus_State {
+			for _, s := range status {
+				if s.Name == name {
+					return s.State
+				}
+			}
+			return -1
+		}
+	}
+	postgres, redis := stateOf("postgres"), stateOf("redis")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	services := []*gitpod.ServicesItems{
+		{Name: "postgres", Image: "postgres:14", Healthcheck: &gitpod.Healthcheck{Command: "pg_isready"}},
+		{Name: "redis", Image: "redis:7"},
+	}
+	runtime.set("gitpod-service-postgres", &containerState{Status: "running", Health: &struct {
+		Status string `json:"Status"`
+	}{Status: "healthy"}})
+	sm.reconcile(ctx, services)
+	waitFor("postgres to become healthy", func(status []*api.ServiceStatus) bool {
+		return postgres(status) == api.ServiceStatus_healthy && redis(status) == api.ServiceStatus_running
+	})
+
+	runtime.set("gitpod-service-redis", &containerState{Status: "exited", ExitCode: 1})
+	waitFor("redis to be restarted", func(status []*api.ServiceStatus) bool {
+		for _, s := range status {
+			if s.Name == "redis" && s.RestartCount == 1 && s.State == api.ServiceStatus_running {
+				return true
+			}
+		}
+		return false
+	})
+	if runs := runtime.count(runtime.runs, "gitpod-service-redis"); runs != 2 {
+		t.Errorf("expected redis to have been run twice, got %d", runs)
+	}
+
+	// removing a service from the configuration stops it
+	sm.reconcile(ctx, services[:1])
+	waitFor("redis to be removed", func(status []*api.ServiceStatus) bool {
+		return len(status) == 1 && postgres(status) == api.ServiceStatus_healthy
+	})
+	if removed := runtime.count(runtime.removed, "gitpod-service-redis"); removed != 1 {
+		t.Errorf("expected redis to have been removed once, got %d", removed)
+	}
+	if runs := runtime.count(runtime.runs, "gitpod-service-postgres"); runs != 1 {
+		t.Errorf("expected unchanged postgres not to be restarted, got %d runs", runs)
+	}
+
+	sm.Stop(ctx)
+	if removed := runtime.count(runtime.removed, "gitpod-service-postgres"); removed != 1 {
+		t.Errorf("expected postgres to have been removed on stop, got %d", removed)
+	}
+	sm.reconcile(ctx, services)
+	if runs := runtime.count(runtime.runs, "gitpod-service-redis"); runs != 2 {
+		t.Errorf("expected no services to be started once stopped, got %d runs of redis", runs)
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
+)
+
+// authorizedKeysExpiryTimeLayout is the layout of the expiry-time option of authorized keys as understood by OpenSSH.
+const authorizedKeysExpiryTimeLayout = "20060102150405Z"
+
+// authorizedKey is an entry of an authorized_keys file.
+type authorizedKey struct {
+	// Key is nil for comments and entries we do not understand
+	Key     ssh.PublicKey
+	Comment string
+	Options []string
+	// ExpiryTime is the time after which the key is not authorized anymore, nil if it does not expire.
+	ExpiryTime *time.Time
+
+	// line is the entry as read from the file
+	line string
+}
+
+// Expired returns true if the key is not authorized anymore at t.
+func (k authorizedKey) Expired(t time.Time) bool {
+	return k.ExpiryTime != nil && !t.Before(*k.ExpiryTime)
+}
+
+// Fingerprint returns the SHA256 fingerprint of the key.
+func (k authorizedKey) Fingerprint() string {
+	return ssh.FingerprintSHA256(k.Key)
+}
+
+// sshKeyRestrictions are the restrictions the options of an authorized key impose on connections authenticated with it.
+type sshKeyRestrictions struct {
+	NoPTY             bool
+	NoPortForwarding  bool
+	NoAgentForwarding bool
+	// From is the pattern list the client address has to match, empty if any address is allowed
+	From string
+}
+
+// Permission extensions which mark the restrictions of a connection.
+const (
+	sshNoPTYExtension             = "no-pty"
+	sshNoPortForwardingExtension  = "no-port-forwarding"
+	sshNoAgentForwardingExtension = "no-agent-forwarding"
+)
+
+// Restrictions returns the restrictions imposed by the options of the key. Options supervisor cannot enforce, e.g.
+// forced commands, are reported as error, s.t. keys carrying them are not authorized rather than unrestricted.
+func (k authorizedKey) Restrictions() (*sshKeyRestrictions, error) {
+	res := &sshKeyRestrictions{}
+	for _, opt := range k.Options {
+		name := strings.ToLower(opt)
+		if i := strings.Index(name, "="); i != -1 {
+			name = name[:i]
+		}
+		switch name {
+		case "expiry-time":
+			// checked by Expired
+		case "from":
+			res.From, _ = cutOption(opt, "from")
+		case "restrict":
+			res.NoPTY = true
+			res.NoPortForwarding = true
+			res.NoAgentForwarding = true
+		case "no-pty":
+			res.NoPTY = true
+		case "pty":
+			res.NoPTY = false
+		case "no-port-forwarding":
+			res.NoPortForwarding = true
+		case "port-forwarding":
+			res.NoPortForwarding = false
+		case "no-agent-forwarding":
+			res.NoAgentForwarding = true
+		case "agent-forwarding":
+			res.NoAgentForwarding = false
+		case "no-x11-forwarding", "x11-forwarding", "no-user-rc", "user-rc":
+			// neither X11 forwarding nor ~/.ssh/rc are supported
+		default:
+			return nil, xerrors.Errorf("unsupported option %s", name)
+		}
+	}
+	return res, nil
+}
+
+// AllowsAddr returns true if the client address matches the from option, using the semantics of OpenSSH without
+// DNS lookups: patterns may contain wildcards or be CIDR ranges, and a matching negated pattern denies access.
+func (r *sshKeyRestrictions) AllowsAddr(addr net.Addr) bool {
+	if r.From == "" {
+		return true
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	allowed := false
+	for _, pattern := range strings.Split(r.From, ",") {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+
+		var match bool
+		if _, cidr, err := net.ParseCIDR(pattern); err == nil {
+			match = cidr.Contains(ip)
+		} else {
+			match, _ = path.Match(strings.ToLower(pattern), ip.String())
+		}
+		if match && negated {
+			return false
+		}
+		allowed = allowed || match
+	}
+	return allowed
+}
+
+// extensions returns the permission extensions marking the restrictions.
+func (r *sshKeyRestrictions) extensions() map[string]string {
+	res := make(map[string]string)
+	if r.NoPTY {
+		res[sshNoPTYExtension] = ""
+	}
+	if r.NoPortForwarding {
+		res[sshNoPortForwardingExtension] = ""
+	}
+	if r.NoAgentForwarding {
+		res[sshNoAgentForwardingExtension] = ""
+	}
+	return res
+}
+
+// authorizedKeysMu serializes modifications of authorized_keys files by supervisor.
+var authorizedKeysMu sync.Mutex
+
+// readAuthorizedKeys parses an authorized_keys file as user. A missing file contains no keys.
+func readAuthorizedKeys(user userfs.User, fn string) ([]authorizedKey, error) {
+	content, err := user.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read authorized keys: %w", err)
+	}
+	if len(content) == 0 {
+		return nil, nil
+	}
+
+	var res []authorizedKey
+	for _, line := range bytes.Split(bytes.TrimRight(content, "\n"), []byte("\n")) {
+		key, comment, options, _, err := ssh.ParseAuthorizedKey(line)
+		if err != nil {
+			// keep comments and entries we do not understand as they are
+			res = append(res, authorizedKey{line: string(line)})
+			continue
+		}
+		entry := authorizedKey{
+			Key:     key,
+			Comment: comment,
+			Options: options,
+			line:    string(line),
+		}
+		for _, opt := range options {
+			value, ok := cutOption(opt, "expiry-time")
+			if !ok {
+				continue
+			}
+			expiryTime, err := parseAuthorizedKeyExpiryTime(value)
+			if err != nil {
+				// keys with an invalid expiry time are not authorized by OpenSSH either
+				expiryTime = time.Time{}
+			}
+			entry.ExpiryTime = &expiryTime
+		}
+		res = append(res, entry)
+	}
+	return res, nil
+}
+
+// parseAuthorizedKeyExpiryTime parses the expiry-time option of authorized keys, i.e. YYYYMMDD[HHMM[SS]] in local time or UTC if suffixed with Z.
+func parseAuthorizedKeyExpiryTime(value string) (time.Time, error) {
+	loc := time.Local
+	if strings.HasSuffix(value, "Z") {
+		loc = time.UTC
+		value = strings.TrimSuffix(value, "Z")
+	}
+	for _, layout := range []string{"20060102150405", "200601021504", "20060102"} {
+		if len(value) != len(layout) {
+			continue
+		}
+		return time.ParseInLocation(layout, value, loc)
+	}
+	return time.Time{}, xerrors.Errorf("invalid expiry time: %s", value)
+}
+
+// cutOption returns the unquoted value of an authorized key option of the form name="value".
+func cutOption(opt, name string) (value string, ok bool) {
+	if !strings.HasPrefix(strings.ToLower(opt), name+"=") {
+		return "", false
+	}
+	return strings.Trim(opt[len(name)+1:], `"`), true
+}
+
+// addAuthorizedKey adds a key to an authorized_keys file as user, replacing any existing entry of the same key.
+// Expired entries are removed along the way.
+func addAuthorizedKey(user userfs.User, fn string, key ssh.PublicKey, comment string, expiryTime *time.Time) (*authorizedKey, error) {
+	authorizedKeysMu.Lock()
+	defer authorizedKeysMu.Unlock()
+
+	keys, err := readAuthorizedKeys(user, fn)
+	if err != nil {
+		return nil, err
+	}
+	entry := authorizedKey{
+		Key:        key,
+		Comment:    comment,
+		ExpiryTime: expiryTime,
+	}
+	if expiryTime != nil {
+		entry.Options = []string{fmt.Sprintf(`expiry-time="%s"`, expiryTime.UTC().Format(authorizedKeysExpiryTimeLayout))}
+	}
+
+	now := time.Now()
+	res := make([]authorizedKey, 0, len(keys)+1)
+	for _, k := range keys {
+		if k.Key != nil && (k.Expired(now) || bytes.Equal(k.Key.Marshal(), key.Marshal())) {
+			continue
+		}
+		res = append(res, k)
+	}
+	res = append(res, entry)
+
+	err = writeAuthorizedKeys(user, fn, res)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// removeAuthorizedKey removes the key with the given fingerprint from an authorized_keys file as user.
+func removeAuthorizedKey(user userfs.User, fn string, fingerprint string) (found bool, err error) {
+	authorizedKeysMu.Lock()
+	defer authorizedKeysMu.Unlock()
+
+	keys, err := readAuthorizedKeys(user, fn)
+	if err != nil {
+		return false, err
+	}
+	res := make([]authorizedKey, 0, len(keys))
+	for _, k := range keys {
+		if k.Key != nil && k.Fingerprint() == fingerprint {
+			found = true
+			continue
+		}
+		res = append(res, k)
+	}
+	if !found {
+		return false, nil
+	}
+	return true, writeAuthorizedKeys(user, fn, res)
+}
+
+// writeAuthorizedKeys replaces an authorized_keys file as user. The user owns ~/.ssh, hence it is never written with
+// the credentials of supervisor.
+func writeAuthorizedKeys(user userfs.User, fn string, keys []authorizedKey) error {
+	var buf bytes.Buffer
+	for _, k := range keys {
+		if k.line != "" || k.Key == nil {
+			buf.WriteString(k.line)
+			buf.WriteString("\n")
+			continue
+		}
+		if len(k.Options) > 0 {
+			buf.WriteString(strings.Join(k.Options, ","))
+			buf.WriteString(" ")
+		}
+		buf.Write(bytes.TrimSpace(ssh.MarshalAuthorizedKey(k.Key)))
+		if k.Comment != "" {
+			buf.WriteString(" ")
+			buf.WriteString(k.Comment)
+		}
+		buf.WriteString("\n")
+	}
+
+	err := user.MkdirAll(filepath.Dir(fn), 0o700)
+	if err != nil {
+		return xerrors.Errorf("cannot create SSH dir: %w", err)
+	}
+	err = user.WriteFile(fn, buf.Bytes(), 0o600)
+	if err != nil {
+		return xerrors.Errorf("cannot write authorized keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
+)
+
+func TestAuthorizedKeys(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), ".ssh", "authorized_keys")
+	permanent := newTestSSHKey(t).PublicKey()
+	temporary := newTestSSHKey(t).PublicKey()
+	expired := newTestSSHKey(t).PublicKey()
+
+	err := os.MkdirAll(filepath.Dir(fn), 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := "# managed by hand\n" +
+		`expiry-time="20200101" ` + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(expired))) + " old\n" +
+		strings.TrimSpace(string(ssh.MarshalAuthorizedKey(permanent))) + " me@laptop\n"
+	err = os.WriteFile(fn, []byte(original), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiryTime := time.Now().Add(time.Hour).Truncate(time.Second)
+	_, err = addAuthorizedKey(userfs.Current(), fn, temporary, "ci", &expiryTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := readAuthorizedKeys(userfs.Current(), fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type entry struct {
+		Fingerprint string
+		Comment     string
+		Expiry      string
+	}
+	var act []entry
+	for _, k := range keys {
+		if k.Key == nil {
+			act = append(act, entry{Comment: k.line})
+			continue
+		}
+		var expiry string
+		if k.ExpiryTime != nil {
+			expiry = k.ExpiryTime.UTC().Format(time.RFC3339)
+		}
+		act = append(act, entry{Fingerprint: k.Fingerprint(), Comment: k.Comment, Expiry: expiry})
+	}
+	exp := []entry{
+		{Comment: "# managed by hand"},
+		{Fingerprint: ssh.FingerprintSHA256(permanent), Comment: "me@laptop"},
+		{Fingerprint: ssh.FingerprintSHA256(temporary), Comment: "ci", Expiry: expiryTime.UTC().Format(time.RFC3339)},
+	}
+	if diff := cmp.Diff(exp, act); diff != "" {
+		t.Errorf("unexpected keys after add (-want +got):\n%s", diff)
+	}
+	if keys[2].Expired(time.Now()) || !keys[2].Expired(expiryTime) {
+		t.Errorf("unexpected expiry of temporary key")
+	}
+
+	found, err := removeAuthorizedKey(userfs.Current(), fn, ssh.FingerprintSHA256(permanent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Errorf("permanent key was not found")
+	}
+	found, err = removeAuthorizedKey(userfs.Current(), fn, ssh.FingerprintSHA256(permanent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Errorf("permanent key was found after revocation")
+	}
+
+	content, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "# managed by hand\nexpiry-time=") {
+		t.Errorf("unexpected authorized keys content:\n%s", content)
+	}
+}
+
+func TestAuthorizedKeysAsUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing the file system credentials requires root")
+	}
+
+	home := t.TempDir()
+	// the workspace user must be able to traverse the parent created by the test framework
+	err := os.Chmod(filepath.Dir(home), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chown(home, 33333, 33333)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(t.TempDir(), "secret")
+	err = os.WriteFile(secret, []byte("secret\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := userfs.User{UID: 33333, GID: 33333}
+	fn := filepath.Join(home, ".ssh", "authorized_keys")
+	err = user.MkdirAll(filepath.Dir(fn), 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a temporary file planted by the user must not redirect the write
+	err = os.Symlink(secret, fn+".tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = addAuthorizedKey(user, fn, newTestSSHKey(t).PublicKey(), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Lstat(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat := info.Sys().(*syscall.Stat_t); !info.Mode().IsRegular() || stat.Uid != 33333 || stat.Gid != 33333 {
+		t.Errorf("unexpected authorized keys file: %s %d:%d", info.Mode(), stat.Uid, stat.Gid)
+	}
+
+	// an authorized_keys file linked to a file of root must be neither read nor replaced
+	err = os.Remove(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink(secret, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = addAuthorizedKey(user, fn, newTestSSHKey(t).PublicKey(), "", nil)
+	if err == nil {
+		t.Error("expected adding a key to an authorized_keys file linked to a file of root to fail")
+	}
+
+	content, err := os.ReadFile(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "secret\n" {
+		t.Errorf("file of root was modified: %q", content)
+	}
+}
+
+func TestAuthorizedKeyRestrictions(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Options     []string
+		Expectation *sshKeyRestrictions
+	}{
+		{Name: "no options", Expectation: &sshKeyRestrictions{}},
+		{Name: "restrict", Options: []string{"restrict"}, Expectation: &sshKeyRestrictions{NoPTY: true, NoPortForwarding: true, NoAgentForwarding: true}},
+		{Name: "restrict with pty", Options: []string{"restrict", "pty"}, Expectation: &sshKeyRestrictions{NoPortForwarding: true, NoAgentForwarding: true}},
+		{Name: "no-pty", Options: []string{"no-pty", "no-X11-forwarding"}, Expectation: &sshKeyRestrictions{NoPTY: true}},
+		{Name: "no-port-forwarding", Options: []string{"no-port-forwarding"}, Expectation: &sshKeyRestrictions{NoPortForwarding: true}},
+		{Name: "from", Options: []string{`from="10.0.0.*,!10.0.0.1"`, `expiry-time="20300101"`}, Expectation: &sshKeyRestrictions{From: "10.0.0.*,!10.0.0.1"}},
+		{Name: "forced command", Options: []string{`command="echo hello"`}},
+		{Name: "permitopen", Options: []string{`permitopen="localhost:8080"`}},
+		{Name: "environment", Options: []string{`environment="FOO=bar"`}},
+		{Name: "unknown", Options: []string{"foo"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := authorizedKey{Options: test.Options}.Restrictions()
+			if test.Expectation == nil {
+				if err == nil {
+					t.Errorf("expected options %v to be refused", test.Options)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected restrictions (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSSHKeyRestrictionsAllowsAddr(t *testing.T) {
+	tests := []struct {
+		From        string
+		Addr        string
+		Expectation bool
+	}{
+		{From: "", Addr: "192.168.1.1:1234", Expectation: true},
+		{From: "127.0.0.1", Addr: "127.0.0.1:1234", Expectation: true},
+		{From: "127.0.0.1", Addr: "192.168.1.1:1234"},
+		{From: "192.168.1.*", Addr: "192.168.1.1:1234", Expectation: true},
+		{From: "192.168.1.*,!192.168.1.1", Addr: "192.168.1.1:1234"},
+		{From: "!192.168.1.1,192.168.1.*", Addr: "192.168.1.2:1234", Expectation: true},
+		{From: "10.0.0.0/8", Addr: "10.1.2.3:1234", Expectation: true},
+		{From: "10.0.0.0/8", Addr: "11.1.2.3:1234"},
+		{From: "::1", Addr: "[::1]:1234", Expectation: true},
+		{From: "example.com", Addr: "93.184.216.34:1234"},
+	}
+	for _, test := range tests {
+		t.Run(test.From+"/"+test.Addr, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", test.Addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act := (&sshKeyRestrictions{From: test.From}).AllowsAddr(addr); act != test.Expectation {
+				t.Errorf("unexpected AllowsAddr(): want %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,475 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+)
+
+// sshSession is a session channel of an SSH connection.
+// Sessions with a pty run in a terminal of the terminal mux, s.t. they are listed by the terminal service.
+type sshSession struct {
+	conn    *sshConnection
+	channel ssh.Channel
+
+	mu      sync.Mutex
+	env     map[string]string
+	pty     *sshPtyRequest
+	started bool
+	// alias is the terminal of a pty session
+	alias string
+	// cmd is the process of a session without pty
+	cmd *exec.Cmd
+
+	agentListener net.Listener
+}
+
+type sshPtyRequest struct {
+	Term     string
+	Columns  uint32
+	Rows     uint32
+	WidthPx  uint32
+	HeightPx uint32
+	Modes    string
+}
+
+type sshWindowChangeRequest struct {
+	Columns  uint32
+	Rows     uint32
+	WidthPx  uint32
+	HeightPx uint32
+}
+
+type sshEnvRequest struct {
+	Name  string
+	Value string
+}
+
+type sshCommandRequest struct {
+	Command string
+}
+
+type sshSignalRequest struct {
+	Signal string
+}
+
+var sshSignals = map[string]syscall.Signal{
+	"ABRT": syscall.SIGABRT,
+	"ALRM": syscall.SIGALRM,
+	"FPE":  syscall.SIGFPE,
+	"HUP":  syscall.SIGHUP,
+	"ILL":  syscall.SIGILL,
+	"INT":  syscall.SIGINT,
+	"KILL": syscall.SIGKILL,
+	"PIPE": syscall.SIGPIPE,
+	"QUIT": syscall.SIGQUIT,
+	"SEGV": syscall.SIGSEGV,
+	"TERM": syscall.SIGTERM,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+}
+
+func (c *sshConnection) handleSession(ctx context.Context, newChannel ssh.NewChannel) {
+	channel, reqs, err := newChannel.Accept()
+	if err != nil {
+		log.WithError(err).Debug("cannot accept SSH session")
+		return
+	}
+
+	s := &sshSession{
+		conn:    c,
+		channel: channel,
+		env:     make(map[string]string),
+	}
+	defer s.close()
+
+	for req := range reqs {
+		var ok bool
+		switch req.Type {
+		case "pty-req":
+			var pty sshPtyRequest
+			if !c.restricted(sshNoPTYExtension) && ssh.Unmarshal(req.Payload, &pty) == nil {
+				s.mu.Lock()
+				s.pty = &pty
+				s.mu.Unlock()
+				ok = true
+			}
+		case "env":
+			var env sshEnvRequest
+			if ssh.Unmarshal(req.Payload, &env) == nil && acceptSSHEnv(env.Name) {
+				s.mu.Lock()
+				s.env[env.Name] = env.Value
+				s.mu.Unlock()
+				ok = true
+			}
+		case "window-change":
+			var size sshWindowChangeRequest
+			if ssh.Unmarshal(req.Payload, &size) == nil {
+				ok = s.resize(ctx, size)
+			}
+		case "signal":
+			var sig sshSignalRequest
+			if ssh.Unmarshal(req.Payload, &sig) == nil {
+				ok = s.signal(sig.Signal)
+			}
+		case "auth-agent-req@openssh.com":
+			if c.restricted(sshNoAgentForwardingExtension) {
+				break
+			}
+			err = s.forwardAgent()
+			if err != nil {
+				log.WithError(err).Warn("cannot forward SSH agent")
+			}
+			ok = err == nil
+		case "shell":
+			ok = s.start(ctx, "", true)
+		case "exec":
+			var cmd sshCommandRequest
+			if ssh.Unmarshal(req.Payload, &cmd) == nil {
+				ok = s.start(ctx, cmd.Command, false)
+			}
+		case "subsystem":
+			var subsystem sshCommandRequest
+			if ssh.Unmarshal(req.Payload, &subsystem) == nil && subsystem.Command == "sftp" {
+				ok = s.startSFTP()
+			}
+		}
+		if req.WantReply {
+			_ = req.Reply(ok, nil)
+		}
+	}
+}
+
+// start starts the shell or command of the session. It returns false if the session has been started already or failed to start.
+func (s *sshSession) start(ctx context.Context, command string, login bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return false
+	}
+
+	shell := s.conn.server.terminalService.DefaultShell
+	args := []string{"-l"}
+	if !login {
+		args = []string{"-c", command}
+	}
+
+	var err error
+	if s.pty != nil {
+		err = s.startTerminal(ctx, shell, args, login)
+	} else {
+		cmd := exec.Command(shell, args...)
+		err = s.startProcess(cmd)
+	}
+	if err != nil {
+		log.WithError(err).Error("cannot start SSH session")
+		return false
+	}
+	s.started = true
+	return true
+}
+
+func (s *sshSession) startSFTP() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return false
+	}
+
+	bin, err := os.Executable()
+	if err != nil {
+		log.WithError(err).Error("cannot start SFTP server")
+		return false
+	}
+	// the SFTP server runs in its own process s.t. it has the permissions of the gitpod user
+	err = s.startProcess(exec.Command(bin, "sftp-server"))
+	if err != nil {
+		log.WithError(err).Error("cannot start SFTP server")
+		return false
+	}
+	s.started = true
+	return true
+}
+
+// startTerminal starts a pty session in a new terminal.
+// Callers are expected to hold mu.
+func (s *sshSession) startTerminal(ctx context.Context, shell string, args []string, login bool) error {
+	env := s.sessionEnv()
+	if s.pty.Term != "" {
+		env["TERM"] = s.pty.Term
+	}
+	terminalService := s.conn.server.terminalService
+	resp, err := terminalService.OpenWithOptions(ctx, &api.OpenTerminalRequest{
+		Workdir:   s.conn.server.home,
+		Env:       env,
+		Shell:     shell,
+		ShellArgs: args,
+		Size: &api.TerminalSize{
+			Cols:     s.pty.Columns,
+			Rows:     s.pty.Rows,
+			WidthPx:  s.pty.WidthPx,
+			HeightPx: s.pty.HeightPx,
+		},
+	}, terminal.TermOptions{
+		ReadTimeout: terminal.NoTimeout,
+		Title:       "ssh",
+		Annotations: map[string]string{
+			"ssh-remote-addr": s.conn.conn.RemoteAddr().String(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	s.alias = resp.Terminal.Alias
+	s.conn.addTerminal(s.alias)
+	term, ok := terminalService.Mux.Get(s.alias)
+	if !ok {
+		return xerrors.Errorf("cannot find terminal %s", s.alias)
+	}
+
+	if login {
+		if motd, err := os.ReadFile(sshMessageOfTheDayFile); err == nil {
+			_, _ = s.channel.Write(normalizeNewlines(motd))
+		}
+	}
+
+	stdout := term.Stdout.Listen()
+	go func() {
+		_, _ = io.Copy(term.PTY, s.channel)
+	}()
+	go func() {
+		defer stdout.Close()
+		_, _ = io.Copy(s.channel, stdout)
+
+		exitCode := 255
+		state, err := term.Wait()
+		if err == nil || state != nil {
+			exitCode = state.ExitCode()
+		}
+		s.exit(exitCode)
+	}()
+	return nil
+}
+
+// startProcess starts a session without pty as the gitpod user.
+// Callers are expected to hold mu.
+func (s *sshSession) startProcess(cmd *exec.Cmd) error {
+	cmd = runAsGitpodUser(cmd)
+	cmd.Dir = s.conn.server.home
+	cmd.Env = s.conn.server.envvars()
+	for k, v := range s.sessionEnv() {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+	cmd.Stdout = s.channel
+	cmd.Stderr = s.channel.Stderr()
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	s.cmd = cmd
+
+	go func() {
+		_, _ = io.Copy(stdin, s.channel)
+		stdin.Close()
+	}()
+	go func() {
+		exitCode := 255
+		err := cmd.Wait()
+		if err == nil || cmd.ProcessState != nil {
+			exitCode = cmd.ProcessState.ExitCode()
+		}
+		s.exit(exitCode)
+	}()
+	return nil
+}
+
+// sshAcceptedEnv are the environment variables clients can set, like AcceptEnv of sshd.
+// Others, e.g. PATH or LD_PRELOAD, would change what the session runs.
+var sshAcceptedEnv = map[string]struct{}{
+	"LANG":         {},
+	"LANGUAGE":     {},
+	"TZ":           {},
+	"COLORTERM":    {},
+	"GIT_PROTOCOL": {},
+}
+
+// acceptSSHEnv returns true if clients can set the environment variable name.
+func acceptSSHEnv(name string) bool {
+	if strings.HasPrefix(name, "LC_") {
+		return true
+	}
+	_, ok := sshAcceptedEnv[name]
+	return ok
+}
+
+// sessionEnv returns the environment requested by the client as well as the SSH specific environment.
+// Callers are expected to hold mu.
+func (s *sshSession) sessionEnv() map[string]string {
+	env := make(map[string]string, len(s.env)+3)
+	for k, v := range s.env {
+		env[k] = v
+	}
+	for k, v := range s.conn.sshEnv() {
+		env[k] = v
+	}
+	if s.agentListener != nil {
+		env["SSH_AUTH_SOCK"] = s.agentListener.Addr().String()
+	}
+	return env
+}
+
+func (s *sshSession) exit(exitCode int) {
+	if exitCode < 0 {
+		// the process has been killed by a signal
+		exitCode = 255
+	}
+	_, _ = s.channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(exitCode)}))
+	_ = s.channel.Close()
+}
+
+func (s *sshSession) resize(ctx context.Context, size sshWindowChangeRequest) bool {
+	s.mu.Lock()
+	alias := s.alias
+	if s.pty != nil {
+		s.pty.Columns, s.pty.Rows, s.pty.WidthPx, s.pty.HeightPx = size.Columns, size.Rows, size.WidthPx, size.HeightPx
+	}
+	s.mu.Unlock()
+	if alias == "" {
+		return false
+	}
+
+	_, err := s.conn.server.terminalService.SetSize(ctx, &api.SetTerminalSizeRequest{
+		Alias:    alias,
+		Priority: &api.SetTerminalSizeRequest_Force{Force: true},
+		Size: &api.TerminalSize{
+			Cols:     size.Columns,
+			Rows:     size.Rows,
+			WidthPx:  size.WidthPx,
+			HeightPx: size.HeightPx,
+		},
+	})
+	return err == nil
+}
+
+func (s *sshSession) signal(name string) bool {
+	sig, ok := sshSignals[name]
+	if !ok {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cmd != nil && s.cmd.Process != nil {
+		return s.cmd.Process.Signal(sig) == nil
+	}
+	if s.alias != "" {
+		term, ok := s.conn.server.terminalService.Mux.Get(s.alias)
+		if ok && term.Command.Process != nil {
+			return term.Command.Process.Signal(sig) == nil
+		}
+	}
+	return false
+}
+
+// forwardAgent listens on a unix socket which forwards connections to the client's SSH agent.
+func (s *sshSession) forwardAgent() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.agentListener != nil {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "ssh-agent-")
+	if err != nil {
+		return err
+	}
+	socket := filepath.Join(dir, "agent.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	// the directory is handed to the user last, s.t. the socket cannot be replaced before it is chowned
+	err = os.Chown(socket, gitpodUID, gitpodGID)
+	if err == nil {
+		err = os.Chown(dir, gitpodUID, gitpodGID)
+	}
+	if err != nil {
+		l.Close()
+		os.RemoveAll(dir)
+		return err
+	}
+	s.agentListener = l
+
+	go func() {
+		defer os.RemoveAll(dir)
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				ch, reqs, err := s.conn.conn.OpenChannel("auth-agent@openssh.com", nil)
+				if err != nil {
+					conn.Close()
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				pipeSSHChannel(ch, conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// close ends the session once the client closed the channel.
+func (s *sshSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.agentListener != nil {
+		s.agentListener.Close()
+	}
+	if s.alias != "" {
+		_ = s.conn.server.terminalService.Mux.CloseTerminal(context.Background(), s.alias)
+		s.conn.removeTerminal(s.alias)
+	}
+	if s.cmd != nil && s.cmd.Process != nil {
+		_ = s.cmd.Process.Kill()
+	}
+	_ = s.channel.Close()
+}
+
+// normalizeNewlines converts \n to \r\n for output written to a terminal directly.
+func normalizeNewlines(b []byte) []byte {
+	res := make([]byte, 0, len(b))
+	for i, c := range b {
+		if c == '\n' && (i == 0 || b[i-1] != '\r') {
+			res = append(res, '\r')
+		}
+		res = append(res, c)
+	}
+	return res
+}
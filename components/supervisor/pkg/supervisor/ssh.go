@@ -5,22 +5,47 @@
 package supervisor
 
 import (
-	"bufio"
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
 	"golang.org/x/xerrors"
 
 	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
 )
 
-func newSSHServer(ctx context.Context, cfg *Config, envvars []string) (*sshServer, error) {
+const (
+	// sshLoginGraceTime is the time a client has to authenticate before the connection is closed.
+	sshLoginGraceTime = 20 * time.Second
+	// sshKeyCheckInterval is the interval in which connections are checked for keys which expired or were removed.
+	sshKeyCheckInterval = 30 * time.Second
+
+	sshHomeDir = "/home/gitpod"
+
+	// sshPubKeyFingerprintExtension is the permission extension holding the fingerprint of the key a connection authenticated with.
+	sshPubKeyFingerprintExtension = "pubkey-fp"
+)
+
+func newSSHServer(ctx context.Context, cfg *Config, envvars func() []string, terminalService *terminal.MuxTerminalService, connections *sshConnections) (*sshServer, error) {
 	bin, err := os.Executable()
 	if err != nil {
 		return nil, xerrors.Errorf("cannot find executable path: %w", err)
@@ -33,25 +58,48 @@ func newSSHServer(ctx context.Context, cfg *Config, envvars []string) (*sshServe
 			return nil, xerrors.Errorf("unexpected error creating SSH key: %w", err)
 		}
 	}
+	hostKeyPEM, err := os.ReadFile(sshkey)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read SSH hostkey file: %w", err)
+	}
+	hostKey, err := ssh.ParsePrivateKey(hostKeyPEM)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse SSH hostkey file: %w", err)
+	}
 	err = ensureSSHDir(cfg)
 	if err != nil {
 		return nil, xerrors.Errorf("unexpected error creating SSH dir: %w", err)
 	}
 
-	return &sshServer{
-		ctx:     ctx,
-		cfg:     cfg,
-		sshkey:  sshkey,
-		envvars: envvars,
-	}, nil
+	s := &sshServer{
+		ctx:             ctx,
+		cfg:             cfg,
+		envvars:         envvars,
+		terminalService: terminalService,
+		connections:     connections,
+		home:            sshHomeDir,
+		user:            userfs.User{UID: gitpodUID, GID: gitpodGID},
+	}
+	s.config = &ssh.ServerConfig{
+		PublicKeyCallback: s.authorizePublicKey,
+		ServerVersion:     "SSH-2.0-Gitpod",
+	}
+	s.config.AddHostKey(hostKey)
+	return s, nil
 }
 
 type sshServer struct {
-	ctx     context.Context
-	cfg     *Config
-	envvars []string
-
-	sshkey string
+	ctx             context.Context
+	cfg             *Config
+	envvars         func() []string
+	terminalService *terminal.MuxTerminalService
+	connections     *sshConnections
+	// home is the home directory of the gitpod user
+	home string
+	// user is the gitpod user, its authorized_keys file is read as
+	user userfs.User
+
+	config *ssh.ServerConfig
 }
 
 // ListenAndServe listens on the TCP network address laddr and then handle packets on incoming connections.
@@ -60,6 +108,7 @@ func (s *sshServer) listenAndServe() error {
 	if err != nil {
 		return err
 	}
+	go s.checkAuthorizedConnections()
 
 	for {
 		conn, err := listener.Accept()
@@ -72,123 +121,440 @@ func (s *sshServer) listenAndServe() error {
 	}
 }
 
-func (s *sshServer) handleConn(ctx context.Context, conn net.Conn) {
-	bin, err := os.Executable()
+// authorizePublicKey admits the gitpod user with any key listed in its authorized_keys file, subject to the options of its entry.
+func (s *sshServer) authorizePublicKey(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	if conn.User() != gitpodUserName {
+		return nil, xerrors.Errorf("user %s is not allowed to log in", conn.User())
+	}
+
+	authorizedKeys, err := readAuthorizedKeys(s.user, filepath.Join(s.home, ".ssh", "authorized_keys"))
 	if err != nil {
-		return
+		return nil, err
 	}
+	now := time.Now()
+	for _, authorizedKey := range authorizedKeys {
+		if authorizedKey.Key == nil || authorizedKey.Expired(now) {
+			continue
+		}
+		if !bytes.Equal(authorizedKey.Key.Marshal(), key.Marshal()) {
+			continue
+		}
+		restrictions, err := authorizedKey.Restrictions()
+		if err != nil {
+			log.WithError(err).WithField("pubkey", authorizedKey.Fingerprint()).Warn("ignoring authorized key with options which are not supported")
+			continue
+		}
+		if !restrictions.AllowsAddr(conn.RemoteAddr()) {
+			continue
+		}
+		extensions := restrictions.extensions()
+		extensions[sshPubKeyFingerprintExtension] = ssh.FingerprintSHA256(key)
+		return &ssh.Permissions{Extensions: extensions}, nil
+	}
+	return nil, xerrors.Errorf("unknown public key for %s", conn.User())
+}
+
+// checkAuthorizedConnections periodically closes connections whose key is not authorized anymore, e.g. because it expired
+// or was removed from the authorized_keys file.
+func (s *sshServer) checkAuthorizedConnections() {
+	ticker := time.NewTicker(sshKeyCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.connections.closeUnauthorized(s.user, filepath.Join(s.home, ".ssh", "authorized_keys"))
+			if err != nil {
+				log.WithError(err).Warn("cannot check SSH connections for unauthorized keys")
+			}
+		}
+	}
+}
 
+func (s *sshServer) handleConn(ctx context.Context, conn net.Conn) {
 	defer conn.Close()
 
-	openssh := filepath.Join(filepath.Dir(bin), "ssh", "sshd")
-	if _, err := os.Stat(openssh); err != nil {
+	_ = conn.SetDeadline(time.Now().Add(sshLoginGraceTime))
+	sshConn, chans, reqs, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		log.WithError(err).WithField("remoteAddr", conn.RemoteAddr().String()).Debug("SSH handshake failed")
 		return
 	}
+	_ = conn.SetDeadline(time.Time{})
 
-	args := []string{
-		"-ieD", "-f/dev/null",
-		"-oProtocol 2",
-		"-oAllowUsers gitpod",
-		"-oPasswordAuthentication no",
-		"-oChallengeResponseAuthentication no",
-		"-oPermitRootLogin no",
-		"-oLoginGraceTime 20",
-		"-oPrintLastLog no",
-		"-oPermitUserEnvironment yes",
-		"-oHostKey " + s.sshkey,
-		"-oPidFile /dev/null",
-		"-oUseDNS no", // Disable DNS lookups.
-		"-oSubsystem sftp internal-sftp",
-		"-oStrictModes no", // don't care for home directory and file permissions
-		"-oLogLevel DEBUG", // enabled DEBUG mode by default
-	}
-
-	envs := make([]string, 0)
-	for _, env := range s.envvars {
-		s := strings.SplitN(env, "=", 2)
-		if len(s) != 2 {
-			continue
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		sshConn.Close()
+	}()
+
+	log := log.WithField("remoteAddr", sshConn.RemoteAddr().String())
+	log.WithField("pubkey", sshConn.Permissions.Extensions[sshPubKeyFingerprintExtension]).Info("SSH connection established")
+	defer log.Info("SSH connection closed")
+
+	c := &sshConnection{
+		id:        uuid.New().String()[:8],
+		server:    s,
+		conn:      sshConn,
+		startTime: time.Now(),
+		forwards:  make(map[string]net.Listener),
+		terminals: make(map[string]struct{}),
+	}
+	defer c.closeForwards()
+	s.connections.add(c)
+	defer s.connections.remove(c.id)
+
+	go c.handleGlobalRequests(reqs)
+	for newChannel := range chans {
+		switch newChannel.ChannelType() {
+		case "session":
+			go c.handleSession(ctx, newChannel)
+		case "direct-tcpip":
+			if c.restricted(sshNoPortForwardingExtension) {
+				_ = newChannel.Reject(ssh.Prohibited, "port forwarding is not permitted")
+				continue
+			}
+			go c.handleDirectTCPIP(newChannel)
+		default:
+			_ = newChannel.Reject(ssh.UnknownChannelType, fmt.Sprintf("unsupported channel type: %s", newChannel.ChannelType()))
 		}
-		envs = append(envs, fmt.Sprintf("%s=%s", s[0], fmt.Sprintf("\"%s\"", strings.ReplaceAll(strings.ReplaceAll(s[1], `\`, `\\`), `"`, `\"`))))
 	}
-	if len(envs) > 0 {
-		args = append(args, fmt.Sprintf("-oSetEnv %s", strings.Join(envs, " ")))
+}
+
+// sshConnection is an established SSH connection.
+type sshConnection struct {
+	id        string
+	server    *sshServer
+	conn      *ssh.ServerConn
+	startTime time.Time
+
+	mu        sync.Mutex
+	forwards  map[string]net.Listener
+	terminals map[string]struct{}
+}
+
+func (c *sshConnection) addTerminal(alias string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.terminals[alias] = struct{}{}
+}
+
+func (c *sshConnection) removeTerminal(alias string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.terminals, alias)
+}
+
+// KeyFingerprint returns the fingerprint of the key this connection authenticated with.
+func (c *sshConnection) KeyFingerprint() string {
+	return c.conn.Permissions.Extensions[sshPubKeyFingerprintExtension]
+}
+
+// restricted returns true if the key of the connection carries the restriction marked by the permission extension.
+func (c *sshConnection) restricted(extension string) bool {
+	_, ok := c.conn.Permissions.Extensions[extension]
+	return ok
+}
+
+// Terminals returns the aliases of the terminals opened by pty sessions of this connection.
+func (c *sshConnection) Terminals() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := make([]string, 0, len(c.terminals))
+	for alias := range c.terminals {
+		res = append(res, alias)
 	}
+	sort.Strings(res)
+	return res
+}
 
-	socketFD, err := conn.(*net.TCPConn).File()
+// sshConnections keeps track of the established SSH connections.
+type sshConnections struct {
+	mu    sync.RWMutex
+	conns map[string]*sshConnection
+}
+
+func newSSHConnections() *sshConnections {
+	return &sshConnections{
+		conns: make(map[string]*sshConnection),
+	}
+}
+
+func (r *sshConnections) add(c *sshConnection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conns[c.id] = c
+}
+
+func (r *sshConnections) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.conns, id)
+}
+
+// Get returns the connection with the given ID.
+func (r *sshConnections) Get(id string) (*sshConnection, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.conns[id]
+	return c, ok
+}
+
+// List returns all established connections, oldest first.
+func (r *sshConnections) List() []*sshConnection {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res := make([]*sshConnection, 0, len(r.conns))
+	for _, c := range r.conns {
+		res = append(res, c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].startTime.Before(res[j].startTime)
+	})
+	return res
+}
+
+// closeUnauthorized closes the connections whose key is not authorized by the authorized_keys file fn of user anymore,
+// i.e. whose key was removed or expired.
+func (r *sshConnections) closeUnauthorized(user userfs.User, fn string) error {
+	keys, err := readAuthorizedKeys(user, fn)
 	if err != nil {
-		log.WithError(err).Error("cannot start SSH server")
-		return
+		return err
 	}
-	defer socketFD.Close()
+	now := time.Now()
+	authorized := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		if k.Key == nil || k.Expired(now) {
+			continue
+		}
+		if _, err := k.Restrictions(); err != nil {
+			continue
+		}
+		authorized[k.Fingerprint()] = struct{}{}
+	}
+
+	for _, c := range r.List() {
+		fp := c.KeyFingerprint()
+		if _, ok := authorized[fp]; ok {
+			continue
+		}
+		log.WithField("session", c.id).WithField("pubkey", fp).WithField("remoteAddr", c.conn.RemoteAddr().String()).Info("closing SSH connection as its key is not authorized anymore")
+		_ = c.conn.Close()
+	}
+	return nil
+}
 
-	log.WithField("args", args).Debug("sshd flags")
-	cmd := exec.CommandContext(ctx, openssh, args...)
-	cmd = runAsGitpodUser(cmd)
-	cmd.Env = s.envvars
-	cmd.ExtraFiles = []*os.File{socketFD}
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = bufio.NewReader(socketFD)
-	cmd.Stdout = bufio.NewWriter(socketFD)
+// sshEnv returns the SSH specific environment of processes started for this connection.
+func (c *sshConnection) sshEnv() map[string]string {
+	remoteHost, remotePort, _ := net.SplitHostPort(c.conn.RemoteAddr().String())
+	localHost, localPort, _ := net.SplitHostPort(c.conn.LocalAddr().String())
+	return map[string]string{
+		"SSH_CLIENT":     fmt.Sprintf("%s %s %s", remoteHost, remotePort, localPort),
+		"SSH_CONNECTION": fmt.Sprintf("%s %s %s %s", remoteHost, remotePort, localHost, localPort),
+	}
+}
 
-	err = cmd.Start()
+type sshTCPIPForwardRequest struct {
+	BindAddr string
+	BindPort uint32
+}
+
+type sshForwardedTCPIPPayload struct {
+	Addr       string
+	Port       uint32
+	OriginAddr string
+	OriginPort uint32
+}
+
+func (c *sshConnection) handleGlobalRequests(reqs <-chan *ssh.Request) {
+	for req := range reqs {
+		switch req.Type {
+		case "tcpip-forward":
+			var fwd sshTCPIPForwardRequest
+			err := ssh.Unmarshal(req.Payload, &fwd)
+			if err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			port, err := c.startRemoteForward(fwd)
+			if err != nil {
+				log.WithError(err).WithField("addr", fwd.BindAddr).WithField("port", fwd.BindPort).Warn("cannot forward remote port")
+				_ = req.Reply(false, nil)
+				continue
+			}
+			var payload []byte
+			if fwd.BindPort == 0 {
+				payload = ssh.Marshal(struct{ Port uint32 }{port})
+			}
+			_ = req.Reply(true, payload)
+		case "cancel-tcpip-forward":
+			var fwd sshTCPIPForwardRequest
+			err := ssh.Unmarshal(req.Payload, &fwd)
+			if err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			_ = req.Reply(c.cancelRemoteForward(fwd), nil)
+		default:
+			if req.WantReply {
+				_ = req.Reply(false, nil)
+			}
+		}
+	}
+}
+
+func (c *sshConnection) startRemoteForward(fwd sshTCPIPForwardRequest) (port uint32, err error) {
+	if c.restricted(sshNoPortForwardingExtension) {
+		return 0, xerrors.Errorf("port forwarding is not permitted")
+	}
+	if fwd.BindPort != 0 && fwd.BindPort < 1024 {
+		return 0, xerrors.Errorf("cannot forward privileged port %d", fwd.BindPort)
+	}
+	// like sshd without GatewayPorts, remote forwards are only reachable from within the workspace
+	bindAddr := fwd.BindAddr
+	if bindAddr == "" || bindAddr == "localhost" {
+		bindAddr = "127.0.0.1"
+	}
+	if ip := net.ParseIP(bindAddr); ip == nil || !ip.IsLoopback() {
+		return 0, xerrors.Errorf("cannot forward non-loopback address %s", fwd.BindAddr)
+	}
+	l, err := net.Listen("tcp", net.JoinHostPort(bindAddr, strconv.Itoa(int(fwd.BindPort))))
 	if err != nil {
-		log.WithError(err).Error("cannot start SSH server: %w", err)
-		return
+		return 0, err
 	}
+	port = uint32(l.Addr().(*net.TCPAddr).Port)
+
+	c.mu.Lock()
+	c.forwards[net.JoinHostPort(fwd.BindAddr, strconv.Itoa(int(port)))] = l
+	c.mu.Unlock()
 
-	done := make(chan error, 1)
 	go func() {
-		done <- cmd.Wait()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				origin := conn.RemoteAddr().(*net.TCPAddr)
+				ch, reqs, err := c.conn.OpenChannel("forwarded-tcpip", ssh.Marshal(sshForwardedTCPIPPayload{
+					Addr:       fwd.BindAddr,
+					Port:       port,
+					OriginAddr: origin.IP.String(),
+					OriginPort: uint32(origin.Port),
+				}))
+				if err != nil {
+					conn.Close()
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				pipeSSHChannel(ch, conn)
+			}()
+		}
 	}()
+	return port, nil
+}
 
-	log.Debug("sshd started")
+func (c *sshConnection) cancelRemoteForward(fwd sshTCPIPForwardRequest) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	select {
-	case <-ctx.Done():
-		if cmd.Process != nil {
-			_ = cmd.Process.Kill()
-		}
-		return
-	case err = <-done:
-		if err != nil {
-			log.WithError(err).Error("SSH server stopped")
-		}
+	key := net.JoinHostPort(fwd.BindAddr, strconv.Itoa(int(fwd.BindPort)))
+	l, ok := c.forwards[key]
+	if !ok {
+		return false
 	}
+	l.Close()
+	delete(c.forwards, key)
+	return true
 }
 
-func prepareSSHKey(ctx context.Context, sshkey string) error {
-	bin, err := os.Executable()
-	if err != nil {
-		return xerrors.Errorf("cannot find executable path: %w", err)
+func (c *sshConnection) closeForwards() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, l := range c.forwards {
+		l.Close()
+		delete(c.forwards, key)
 	}
+}
 
-	openssh := filepath.Join(filepath.Dir(bin), "ssh", "sshd")
-	if _, err := os.Stat(openssh); err != nil {
-		return xerrors.Errorf("cannot locate sshd binary in path %v", openssh)
+type sshDirectTCPIPPayload struct {
+	DestAddr   string
+	DestPort   uint32
+	OriginAddr string
+	OriginPort uint32
+}
+
+func (c *sshConnection) handleDirectTCPIP(newChannel ssh.NewChannel) {
+	var payload sshDirectTCPIPPayload
+	err := ssh.Unmarshal(newChannel.ExtraData(), &payload)
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, "invalid payload")
+		return
 	}
 
-	sshkeygen := filepath.Join(filepath.Dir(bin), "ssh", "ssh-keygen")
-	if _, err := os.Stat(sshkeygen); err != nil {
-		return xerrors.Errorf("cannot locate ssh-keygen (path %v)", sshkeygen)
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(payload.DestAddr, strconv.Itoa(int(payload.DestPort))), 10*time.Second)
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
 	}
+	ch, reqs, err := newChannel.Accept()
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	pipeSSHChannel(ch, conn)
+}
 
-	keycmd := exec.Command(sshkeygen, "-t", "ecdsa", "-q", "-N", "", "-f", sshkey)
-	// We need to force HOME because the Gitpod user might not have existed at the start of the container
-	// which makes the container runtime set an invalid HOME value.
-	keycmd.Env = func() []string {
-		env := os.Environ()
-		res := make([]string, 0, len(env))
-		for _, e := range env {
-			if strings.HasPrefix(e, "HOME=") {
-				e = "HOME=/root"
-			}
-			res = append(res, e)
+// pipeSSHChannel copies data between an SSH channel and a connection until both directions are done.
+func pipeSSHChannel(ch ssh.Channel, conn net.Conn) {
+	defer ch.Close()
+	defer conn.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(ch, conn)
+		_ = ch.CloseWrite()
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, ch)
+		if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+			_ = cw.CloseWrite()
 		}
-		return res
+		done <- struct{}{}
 	}()
+	<-done
+	<-done
+}
+
+// prepareSSHKey creates an ECDSA key pair, writing the private key to sshkey and the public key to sshkey.pub.
+func prepareSSHKey(ctx context.Context, sshkey string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return xerrors.Errorf("cannot generate SSH key: %w", err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal SSH key: %w", err)
+	}
+	publicKey, err := ssh.NewPublicKey(&key.PublicKey)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal SSH public key: %w", err)
+	}
 
-	_, err = keycmd.CombinedOutput()
+	err = os.MkdirAll(filepath.Dir(sshkey), 0o755)
+	if err != nil {
+		return xerrors.Errorf("cannot create SSH hostkey dir: %w", err)
+	}
+	err = os.WriteFile(sshkey, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600)
+	if err != nil {
+		return xerrors.Errorf("cannot create SSH hostkey file: %w", err)
+	}
+	err = os.WriteFile(sshkey+".pub", ssh.MarshalAuthorizedKey(publicKey), 0o644)
 	if err != nil {
 		return xerrors.Errorf("cannot create SSH hostkey file: %w", err)
 	}
@@ -202,9 +568,7 @@ func prepareSSHKey(ctx context.Context, sshkey string) error {
 }
 
 func ensureSSHDir(cfg *Config) error {
-	home := "/home/gitpod"
-
-	d := filepath.Join(home, ".ssh")
+	d := filepath.Join(sshHomeDir, ".ssh")
 	err := os.MkdirAll(d, 0o700)
 	if err != nil {
 		return xerrors.Errorf("cannot create $HOME/.ssh: %w", err)
@@ -219,7 +583,7 @@ func configureSSHDefaultDir(cfg *Config) {
 		log.Error("cannot configure ssh default dir with empty repo root")
 		return
 	}
-	file, err := os.OpenFile("/home/gitpod/.bash_profile", os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	file, err := os.OpenFile(filepath.Join(sshHomeDir, ".bash_profile"), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
 	if err != nil {
 		log.WithError(err).Error("cannot write .bash_profile")
 	}
@@ -229,6 +593,8 @@ func configureSSHDefaultDir(cfg *Config) {
 	}
 }
 
+const sshMessageOfTheDayFile = "/etc/motd"
+
 func configureSSHMessageOfTheDay() {
 	msg := []byte(`Welcome to Gitpod: Always ready to code. Try the following commands to get started:
 
@@ -242,7 +608,7 @@ func configureSSHMessageOfTheDay() {
 For more information, see the Gitpod documentation: https://gitpod.io/docs
 `)
 
-	if err := ioutil.WriteFile("/etc/motd", msg, 0o644); err != nil {
+	if err := ioutil.WriteFile(sshMessageOfTheDayFile, msg, 0o644); err != nil {
 		log.WithError(err).Error("write /etc/motd failed")
 	}
 }
@@ -0,0 +1,342 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
+)
+
+func newTestSSHServer(t *testing.T, authorized ...ssh.PublicKey) (s *sshServer, addr string) {
+	home := t.TempDir()
+	err := os.MkdirAll(filepath.Join(home, ".ssh"), 0o700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var authorizedKeys []byte
+	for _, key := range authorized {
+		authorizedKeys = append(authorizedKeys, ssh.MarshalAuthorizedKey(key)...)
+	}
+	err = os.WriteFile(filepath.Join(home, ".ssh", "authorized_keys"), authorizedKeys, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hostKey, err := generateHostKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	s = &sshServer{
+		ctx:         ctx,
+		cfg:         &Config{},
+		connections: newSSHConnections(),
+		home:        home,
+		user:        userfs.Current(),
+	}
+	s.config = &ssh.ServerConfig{PublicKeyCallback: s.authorizePublicKey}
+	s.config.AddHostKey(hostKey)
+
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.handleConn(ctx, conn)
+		}
+	}()
+	return s, l.Addr().String()
+}
+
+func newTestSSHKey(t *testing.T) ssh.Signer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+func dialTestSSHServer(addr, user string, key ssh.Signer) (*ssh.Client, error) {
+	return ssh.Dial("tcp", addr, &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(key)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+}
+
+func TestSSHServerAuthentication(t *testing.T) {
+	authorized := newTestSSHKey(t)
+	_, addr := newTestSSHServer(t, authorized.PublicKey())
+
+	tests := []struct {
+		Name    string
+		User    string
+		Key     ssh.Signer
+		Success bool
+	}{
+		{Name: "authorized key", User: gitpodUserName, Key: authorized, Success: true},
+		{Name: "unknown key", User: gitpodUserName, Key: newTestSSHKey(t)},
+		{Name: "other user", User: "root", Key: authorized},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			client, err := dialTestSSHServer(addr, test.User, test.Key)
+			if err == nil {
+				client.Close()
+			}
+			if success := err == nil; success != test.Success {
+				t.Errorf("unexpected authentication result: want %v, got %v (%v)", test.Success, success, err)
+			}
+		})
+	}
+}
+
+func TestSSHServerPortForwarding(t *testing.T) {
+	key := newTestSSHKey(t)
+	_, addr := newTestSSHServer(t, key.PublicKey())
+	client, err := dialTestSSHServer(addr, gitpodUserName, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello")
+	}))
+	defer srv.Close()
+
+	t.Run("local", func(t *testing.T) {
+		httpClient := &http.Client{Transport: &http.Transport{Dial: client.Dial}}
+		resp, err := httpClient.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != "hello" {
+			t.Errorf("unexpected response: %q", body)
+		}
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		l, err := client.Listen("tcp", "localhost:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+		go func() {
+			_ = http.Serve(l, srv.Config.Handler)
+		}()
+
+		resp, err := http.Get(fmt.Sprintf("http://%s", l.Addr().String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != "hello" {
+			t.Errorf("unexpected response: %q", body)
+		}
+	})
+
+	t.Run("remote non-loopback", func(t *testing.T) {
+		l, err := client.Listen("tcp", "0.0.0.0:0")
+		if err == nil {
+			l.Close()
+			t.Fatal("expected forwarding a non-loopback address to fail")
+		}
+	})
+}
+
+func TestSSHServerKeyOptions(t *testing.T) {
+	key := newTestSSHKey(t)
+	s, addr := newTestSSHServer(t)
+	setOptions := func(options string) {
+		err := os.WriteFile(filepath.Join(s.home, ".ssh", "authorized_keys"), []byte(options+" "+string(ssh.MarshalAuthorizedKey(key.PublicKey()))), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	t.Run("unsupported options", func(t *testing.T) {
+		for _, options := range []string{`command="echo hello"`, `permitopen="localhost:8080"`, "cert-authority"} {
+			setOptions(options)
+			client, err := dialTestSSHServer(addr, gitpodUserName, key)
+			if err == nil {
+				client.Close()
+				t.Errorf("expected key with %s to be refused", options)
+			}
+		}
+	})
+
+	t.Run("from", func(t *testing.T) {
+		setOptions(`from="10.0.0.1"`)
+		client, err := dialTestSSHServer(addr, gitpodUserName, key)
+		if err == nil {
+			client.Close()
+			t.Error("expected connection from other address to be refused")
+		}
+
+		setOptions(`from="127.0.0.*"`)
+		client, err = dialTestSSHServer(addr, gitpodUserName, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.Close()
+	})
+
+	t.Run("restrict", func(t *testing.T) {
+		setOptions("restrict")
+		client, err := dialTestSSHServer(addr, gitpodUserName, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+
+		conn, err := client.Dial("tcp", srv.Listener.Addr().String())
+		if err == nil {
+			conn.Close()
+			t.Error("expected local port forwarding to be refused")
+		}
+		l, err := client.Listen("tcp", "localhost:0")
+		if err == nil {
+			l.Close()
+			t.Error("expected remote port forwarding to be refused")
+		}
+		session, err := client.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer session.Close()
+		err = session.RequestPty("xterm", 24, 80, ssh.TerminalModes{})
+		if err == nil {
+			t.Error("expected pty request to be refused")
+		}
+	})
+
+	t.Run("restrict with port forwarding", func(t *testing.T) {
+		setOptions("restrict,port-forwarding")
+		client, err := dialTestSSHServer(addr, gitpodUserName, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+
+		conn, err := client.Dial("tcp", srv.Listener.Addr().String())
+		if err != nil {
+			t.Fatalf("expected local port forwarding to be permitted: %v", err)
+		}
+		conn.Close()
+	})
+}
+
+func TestSSHServerCloseUnauthorized(t *testing.T) {
+	key := newTestSSHKey(t)
+	other := newTestSSHKey(t)
+	s, addr := newTestSSHServer(t, key.PublicKey(), other.PublicKey())
+	fn := filepath.Join(s.home, ".ssh", "authorized_keys")
+
+	dial := func(key ssh.Signer) <-chan error {
+		client, err := dialTestSSHServer(addr, gitpodUserName, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { client.Close() })
+		closed := make(chan error, 1)
+		go func() { closed <- client.Wait() }()
+		return closed
+	}
+	waitForConnections := func(n int) {
+		for i := 0; len(s.connections.List()) != n; i++ {
+			if i > 100 {
+				t.Fatalf("expected %d connections, got %d", n, len(s.connections.List()))
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	keyClosed := dial(key)
+	otherClosed := dial(other)
+	waitForConnections(2)
+
+	expired := time.Now().Add(-time.Minute)
+	_, err := addAuthorizedKey(userfs.Current(), fn, key.PublicKey(), "", &expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.connections.closeUnauthorized(userfs.Current(), fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-keyClosed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection of expired key was not closed")
+	}
+	waitForConnections(1)
+
+	_, err = removeAuthorizedKey(userfs.Current(), fn, ssh.FingerprintSHA256(other.PublicKey()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.connections.closeUnauthorized(userfs.Current(), fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-otherClosed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection of removed key was not closed")
+	}
+	waitForConnections(0)
+}
+
+func TestAcceptSSHEnv(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Expectation bool
+	}{
+		{Name: "LANG", Expectation: true},
+		{Name: "LC_ALL", Expectation: true},
+		{Name: "GIT_PROTOCOL", Expectation: true},
+		{Name: "PATH", Expectation: false},
+		{Name: "LD_PRELOAD", Expectation: false},
+		{Name: "HOME", Expectation: false},
+		{Name: "BASH_ENV", Expectation: false},
+	}
+	for _, test := range tests {
+		if act := acceptSSHEnv(test.Name); act != test.Expectation {
+			t.Errorf("acceptSSHEnv(%s) = %v, expected %v", test.Name, act, test.Expectation)
+		}
+	}
+}
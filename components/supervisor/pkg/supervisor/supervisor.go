@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -50,7 +51,9 @@ import (
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/executor"
 	"github.com/gitpod-io/gitpod/content-service/pkg/git"
+	"github.com/gitpod-io/gitpod/content-service/pkg/logs"
 	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/gitpod-protocol/validate"
 	"github.com/gitpod-io/gitpod/supervisor/api"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/activation"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/config"
@@ -59,6 +62,7 @@ import (
 	"github.com/gitpod-io/gitpod/supervisor/pkg/ports"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/serverapi"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -136,6 +140,7 @@ type IDEKind int64
 const (
 	WebIDE IDEKind = iota
 	DesktopIDE
+	AdditionalIDE
 )
 
 func (s IDEKind) String() string {
@@ -144,10 +149,37 @@ func (s IDEKind) String() string {
 		return "web"
 	case DesktopIDE:
 		return "desktop"
+	case AdditionalIDE:
+		return "additional"
 	}
 	return "unknown"
 }
 
+// additionalIDE is an IDE backend which runs next to the IDE and desktop IDE.
+type additionalIDE struct {
+	Config *IDEConfig
+	Ready  *ideReadyState
+}
+
+// ideName returns the name of an IDE used in logs.
+func ideName(ideConfig *IDEConfig, ide IDEKind) string {
+	if ide == AdditionalIDE {
+		return ideConfig.Name
+	}
+	return ide.String()
+}
+
+// idePort returns the port an IDE listens on.
+func idePort(cfg *Config, ideConfig *IDEConfig, ide IDEKind) int {
+	switch ide {
+	case DesktopIDE:
+		return desktopIDEPort
+	case AdditionalIDE:
+		return ideConfig.Port
+	}
+	return cfg.IDEPort
+}
+
 // Run serves as main entrypoint to the supervisor.
 func Run(options ...RunOption) {
 	exitCode := 0
@@ -179,8 +211,9 @@ func Run(options ...RunOption) {
 		return
 	}
 
-	// BEWARE: we can only call buildChildProcEnv once, because it might download env vars from a one-time-secret
-	//         URL, which would fail if we tried another time.
+	// BEWARE: buildChildProcEnv downloads env vars from a one-time-secret URL on the first call only.
+	//         childProcEnvvars lacks the envFiles of the .gitpod.yml, use childProcEnv for processes
+	//         started once the content is ready.
 	childProcEnvvars := buildChildProcEnv(cfg, nil)
 
 	err = AddGitpodUserIfNotExists()
@@ -228,6 +261,9 @@ func Run(options ...RunOption) {
 	if cfg.DesktopIDE != nil {
 		internalPorts = append(internalPorts, desktopIDEPort)
 	}
+	for _, ide := range cfg.AdditionalIDEs {
+		internalPorts = append(internalPorts, uint32(ide.Port))
+	}
 
 	endpoint, host, err := cfg.GitpodAPIEndpoint()
 	if err != nil {
@@ -251,14 +287,33 @@ func Run(options ...RunOption) {
 	if cfg.DesktopIDE != nil {
 		desktopIdeReady = &ideReadyState{cond: sync.NewCond(&sync.Mutex{})}
 	}
+	additionalIdes := make([]*additionalIDE, 0, len(cfg.AdditionalIDEs))
+	for _, ide := range cfg.AdditionalIDEs {
+		additionalIdes = append(additionalIdes, &additionalIDE{Config: ide, Ready: &ideReadyState{cond: sync.NewCond(&sync.Mutex{})}})
+	}
 	if !cfg.isHeadless() {
-		go trackReadiness(ctx, gitpodService, cfg, cstate, ideReady, desktopIdeReady)
+		go trackReadiness(ctx, gitpodService, cfg, cstate, ideReady, desktopIdeReady, additionalIdes)
 	}
 	tokenService.provider[KindGit] = []tokenProvider{NewGitTokenProvider(gitpodService, cfg.WorkspaceConfig, notificationService)}
 
 	gitpodConfigService := config.NewConfigService(cfg.RepoRoot+"/.gitpod.yml", cstate.ContentReady(), log.Log)
+	if !cfg.isHeadless() {
+		go func() {
+			// the log of a previous start is only available once the content is restored
+			<-cstate.ContentReady()
+			err := notificationService.LoadLog(userfs.User{UID: gitpodUID, GID: gitpodGID}, NotificationLogFile)
+			if err != nil {
+				log.WithError(err).Warn("cannot load notification log")
+			}
+		}()
+		go notifyInvalidConfig(ctx, notificationService, gitpodConfigService.ObserveInvalid(ctx))
+	}
 	go gitpodConfigService.Watch(ctx)
 
+	secrets := &secretResolver{gitpodService: gitpodService}
+	childProcEnv := newChildProcEnv(cfg, secrets)
+	go childProcEnv.init(ctx, cstate, gitpodConfigService)
+
 	portMgmt := ports.NewManager(
 		createExposedPortsImpl(cfg, gitpodService),
 		&ports.PollingServedPortsObserver{
@@ -270,7 +325,6 @@ func Run(options ...RunOption) {
 	)
 
 	topService := NewTopService()
-	topService.Observe(ctx)
 
 	supervisorMetrics := metrics.NewMetrics()
 	var metricsReporter *metrics.GrpcMetricsReporter
@@ -310,28 +364,60 @@ func Run(options ...RunOption) {
 		}
 	}
 	termMuxSrv.Env = childProcEnvvars
+	termMuxSrv.EnvProvider = childProcEnv.Environ
 	termMuxSrv.DefaultCreds = &syscall.Credential{
 		Uid: gitpodUID,
 		Gid: gitpodGID,
 	}
+	termMuxSrv.RecordingLocation = filepath.Join(logs.TerminalStoreLocation, "recordings")
+	if cfg.TerminalHistoryEnable {
+		termMuxSrv.History = &terminal.HistoryOptions{
+			// the history does not survive restarts, hence it stays out of the workspace content and its backups
+			Location: filepath.Join(os.TempDir(), "gitpod-terminal-history"),
+		}
+	}
 
 	taskManager := newTasksManager(cfg, termMuxSrv, cstate, nil, ideReady, desktopIdeReady)
+	taskManager.secrets = secrets
+	topService.processes = newProcessSampler(termMux.PIDs, taskManager.Status)
+	topService.Observe(ctx)
+	if !cfg.isHeadless() {
+		go newResourceAlerts(notificationService, topService, taskManager, termMuxSrv).Run(ctx, gitpodConfigService.Observe(ctx))
+		go taskManager.watchTaskConfig(ctx, notificationService, gitpodConfigService.Observe(ctx))
+	}
+	sshConnections := newSSHConnections()
+
+	activity := &ideActivity{}
+	lifecycle := newLifecycleHooks(cfg, childProcEnv.Environ, workspaceActivity(termMuxSrv, sshConnections, activity))
+	go lifecycle.Observe(gitpodConfigService.Observe(ctx))
+	go lifecycle.RunOn(ctx, lifecycleOnContentReady, cstate.ContentReady())
+	if !cfg.isHeadless() {
+		go lifecycle.RunOn(ctx, lifecyclePostStart, ideReady.Wait())
+		go lifecycle.WatchIdle(ctx)
+	}
+
+	services := newServicesManager(&dockerCLI{repoRoot: cfg.RepoRoot, env: childProcEnv.Environ})
+	go services.Run(ctx, cstate.ContentReady(), gitpodConfigService.Observe(ctx))
 
 	apiServices := []RegisterableService{
 		&statusService{
 			ContentState:    cstate,
 			Ports:           portMgmt,
 			Tasks:           taskManager,
+			Services:        services,
 			ideReady:        ideReady,
 			desktopIdeReady: desktopIdeReady,
+			additionalIdes:  additionalIdes,
 			topService:      topService,
 		},
 		termMuxSrv,
 		RegistrableTokenService{Service: tokenService},
 		notificationService,
-		&InfoService{cfg: cfg, ContentState: cstate},
-		&ControlService{portsManager: portMgmt},
+		&InfoService{cfg: cfg, ContentState: cstate, Env: childProcEnv, Tasks: taskManager},
+		&ControlService{portsManager: portMgmt, tasks: taskManager, activity: activity},
+		&SSHService{authorizedKeys: filepath.Join(sshHomeDir, ".ssh", "authorized_keys"), user: userfs.User{UID: gitpodUID, GID: gitpodGID}, connections: sshConnections},
 		&portService{portsManager: portMgmt},
+		&FileService{Root: cfg.RepoRoot, UID: gitpodUID, GID: gitpodGID},
 	}
 	apiServices = append(apiServices, additionalServices...)
 
@@ -343,10 +429,14 @@ func Run(options ...RunOption) {
 
 	var ideWG sync.WaitGroup
 	ideWG.Add(1)
-	go startAndWatchIDE(ctx, cfg, &cfg.IDE, childProcEnvvars, &ideWG, cstate, ideReady, WebIDE, supervisorMetrics)
+	go startAndWatchIDE(ctx, cfg, &cfg.IDE, childProcEnv, &ideWG, cstate, ideReady, WebIDE, supervisorMetrics)
 	if cfg.DesktopIDE != nil {
 		ideWG.Add(1)
-		go startAndWatchIDE(ctx, cfg, cfg.DesktopIDE, childProcEnvvars, &ideWG, cstate, desktopIdeReady, DesktopIDE, supervisorMetrics)
+		go startAndWatchIDE(ctx, cfg, cfg.DesktopIDE, childProcEnv, &ideWG, cstate, desktopIdeReady, DesktopIDE, supervisorMetrics)
+	}
+	for _, ide := range additionalIdes {
+		ideWG.Add(1)
+		go startAndWatchIDE(ctx, cfg, ide.Config, childProcEnv, &ideWG, cstate, ide.Ready, AdditionalIDE, supervisorMetrics)
 	}
 
 	var (
@@ -358,7 +448,7 @@ func Run(options ...RunOption) {
 	wg.Add(1)
 	go startAPIEndpoint(ctx, cfg, &wg, apiServices, tunneledPortsService, metricsReporter, apiEndpointOpts...)
 	wg.Add(1)
-	go startSSHServer(ctx, cfg, &wg, childProcEnvvars)
+	go startSSHServer(ctx, cfg, &wg, childProcEnv.Environ, termMuxSrv, sshConnections)
 	wg.Add(1)
 	tasksSuccessChan := make(chan taskSuccess, 1)
 	go taskManager.Run(ctx, &wg, tasksSuccessChan)
@@ -423,6 +513,12 @@ func Run(options ...RunOption) {
 	log.Info("received SIGTERM (or shutdown) - tearing down")
 	terminalShutdownCtx, cancelTermination := context.WithTimeout(context.Background(), cfg.GetTerminationGracePeriod())
 	defer cancelTermination()
+	// run the preStop hook while the IDE and terminals are still up, leaving them at least half of the grace period
+	preStopCtx, cancelPreStop := context.WithTimeout(terminalShutdownCtx, cfg.GetTerminationGracePeriod()/2)
+	lifecycle.Run(preStopCtx, lifecyclePreStop)
+	cancelPreStop()
+	// stop the service containers while the Docker daemon is still running in its terminal
+	services.Stop(terminalShutdownCtx)
 	cancel()
 	ideWG.Wait()
 	// terminate all terminal processes once the IDE is gone
@@ -727,9 +823,9 @@ var (
 	errSignalTerminated = errors.New("signal: terminated")
 )
 
-func startAndWatchIDE(ctx context.Context, cfg *Config, ideConfig *IDEConfig, childProcEnvvars []string, wg *sync.WaitGroup, cstate *InMemoryContentState, ideReady *ideReadyState, ide IDEKind, metrics *metrics.SupervisorMetrics) {
+func startAndWatchIDE(ctx context.Context, cfg *Config, ideConfig *IDEConfig, childProcEnv *childProcEnv, wg *sync.WaitGroup, cstate *InMemoryContentState, ideReady *ideReadyState, ide IDEKind, metrics *metrics.SupervisorMetrics) {
 	defer wg.Done()
-	defer log.WithField("ide", ide.String()).Debug("startAndWatchIDE shutdown")
+	defer log.WithField("ide", ideName(ideConfig, ide)).Debug("startAndWatchIDE shutdown")
 
 	if cfg.isHeadless() {
 		ideReady.Set(true, nil)
@@ -754,7 +850,7 @@ supervisorLoop:
 
 		ideStopped = make(chan struct{}, 1)
 		startTime := time.Now()
-		cmd = prepareIDELaunch(cfg, ideConfig, childProcEnvvars)
+		cmd = prepareIDELaunch(cfg, ideConfig, childProcEnv.Environ(), ide)
 		launchIDE(cfg, ideConfig, cmd, ideStopped, ideReady, &ideStatus, ide)
 
 		if firstStart {
@@ -787,7 +883,7 @@ supervisorLoop:
 			// we've been asked to shut down
 			ideStatus = statusShouldShutdown
 			if cmd == nil || cmd.Process == nil {
-				log.WithField("ide", ide.String()).Error("cmd or cmd.Process is nil, cannot send SIGTERM signal")
+				log.WithField("ide", ideName(ideConfig, ide)).Error("cmd or cmd.Process is nil, cannot send SIGTERM signal")
 			} else {
 				_ = cmd.Process.Signal(syscall.SIGTERM)
 			}
@@ -795,15 +891,15 @@ supervisorLoop:
 		}
 	}
 
-	log.WithField("ide", ide.String()).WithField("budget", timeBudgetIDEShutdown.String()).Info("IDE supervisor loop ended - waiting for IDE to come down")
+	log.WithField("ide", ideName(ideConfig, ide)).WithField("budget", timeBudgetIDEShutdown.String()).Info("IDE supervisor loop ended - waiting for IDE to come down")
 	select {
 	case <-ideStopped:
-		log.WithField("ide", ide.String()).WithField("budget", timeBudgetIDEShutdown.String()).Info("IDE has been stopped in time")
+		log.WithField("ide", ideName(ideConfig, ide)).WithField("budget", timeBudgetIDEShutdown.String()).Info("IDE has been stopped in time")
 		return
 	case <-time.After(timeBudgetIDEShutdown):
-		log.WithField("ide", ide.String()).WithField("timeBudgetIDEShutdown", timeBudgetIDEShutdown.String()).Error("IDE did not stop in time - sending SIGKILL")
+		log.WithField("ide", ideName(ideConfig, ide)).WithField("timeBudgetIDEShutdown", timeBudgetIDEShutdown.String()).Error("IDE did not stop in time - sending SIGKILL")
 		if cmd == nil || cmd.Process == nil {
-			log.WithField("ide", ide.String()).Error("cmd or cmd.Process is nil, cannot send SIGKILL")
+			log.WithField("ide", ideName(ideConfig, ide)).Error("cmd or cmd.Process is nil, cannot send SIGKILL")
 		} else {
 			_ = cmd.Process.Signal(syscall.SIGKILL)
 		}
@@ -825,7 +921,7 @@ func launchIDE(cfg *Config, ideConfig *IDEConfig, cmd *exec.Cmd, ideStopped chan
 		err := cmd.Start()
 		if err != nil {
 			if s == func() *ideStatus { i := statusNeverRan; return &i }() {
-				log.WithField("ide", ide.String()).WithError(err).Fatal("IDE failed to start")
+				log.WithField("ide", ideName(ideConfig, ide)).WithError(err).Fatal("IDE failed to start")
 			}
 
 			return
@@ -840,12 +936,18 @@ func launchIDE(cfg *Config, ideConfig *IDEConfig, cmd *exec.Cmd, ideStopped chan
 		err = cmd.Wait()
 		if err != nil {
 			if errSignalTerminated.Error() != err.Error() {
-				log.WithField("ide", ide.String()).WithError(err).Warn("IDE was stopped")
+				log.WithField("ide", ideName(ideConfig, ide)).WithError(err).Warn("IDE was stopped")
 			}
 
 			ideWasReady, _ := ideReady.Get()
 			if !ideWasReady {
-				log.WithField("ide", ide.String()).WithError(err).Fatal("IDE failed to start")
+				if ide == AdditionalIDE {
+					// additional IDEs are optional - the workspace stays usable without them, hence we neither
+					// fail the workspace nor restart them
+					log.WithField("ide", ideName(ideConfig, ide)).WithError(err).Error("additional IDE failed to start")
+					return
+				}
+				log.WithField("ide", ideName(ideConfig, ide)).WithError(err).Fatal("IDE failed to start")
 				return
 			}
 		}
@@ -855,11 +957,15 @@ func launchIDE(cfg *Config, ideConfig *IDEConfig, cmd *exec.Cmd, ideStopped chan
 	}()
 }
 
-func prepareIDELaunch(cfg *Config, ideConfig *IDEConfig, childProcEnvvars []string) *exec.Cmd {
+func prepareIDELaunch(cfg *Config, ideConfig *IDEConfig, childProcEnvvars []string, ide IDEKind) *exec.Cmd {
+	desktopPort := desktopIDEPort
+	if ide == AdditionalIDE {
+		desktopPort = ideConfig.Port
+	}
 	args := ideConfig.EntrypointArgs
 	for i := range args {
 		args[i] = strings.ReplaceAll(args[i], "{IDEPORT}", strconv.Itoa(cfg.IDEPort))
-		args[i] = strings.ReplaceAll(args[i], "{DESKTOPIDEPORT}", strconv.Itoa(desktopIDEPort))
+		args[i] = strings.ReplaceAll(args[i], "{DESKTOPIDEPORT}", strconv.Itoa(desktopPort))
 	}
 	log.WithField("args", args).WithField("entrypoint", ideConfig.Entrypoint).Info("preparing IDE launch")
 
@@ -894,15 +1000,21 @@ func prepareIDELaunch(cfg *Config, ideConfig *IDEConfig, childProcEnvvars []stri
 }
 
 // buildChildProcEnv computes the environment variables passed to a child process, based on the total list
-// of envvars. If envvars is nil, os.Environ() is used.
+// of envvars. If envvars is nil, os.Environ() is used. The overlays, e.g. loaded from envFiles, are applied
+// on top of the user-defined environment variables in order.
 //
-// Beware: if config contains an OTS URL the results may differ on subsequent calls.
-func buildChildProcEnv(cfg *Config, envvars []string) []string {
+// Beware: if config contains an OTS URL the variables are downloaded on the first call only.
+func buildChildProcEnv(cfg *Config, envvars []string, overlays ...[]envvar) []string {
+	return environ(resolveChildProcEnv(cfg, envvars, overlays...))
+}
+
+// resolveChildProcEnv computes the environment like buildChildProcEnv but retains the source of each variable.
+func resolveChildProcEnv(cfg *Config, envvars []string, overlays ...[]envvar) []envvar {
 	if envvars == nil {
 		envvars = os.Environ()
 	}
 
-	envs := make(map[string]string)
+	envs := make(map[string]envvar)
 	for _, e := range envvars {
 		segs := strings.SplitN(e, "=", 2)
 		if len(segs) < 2 {
@@ -915,9 +1027,9 @@ func buildChildProcEnv(cfg *Config, envvars []string) []string {
 			continue
 		}
 
-		envs[nme] = val
+		envs[nme] = envvar{Name: nme, Value: val, Source: envvarSourceWorkspace}
 	}
-	envs["SUPERVISOR_ADDR"] = fmt.Sprintf("localhost:%d", cfg.APIEndpointPort)
+	envs["SUPERVISOR_ADDR"] = envvar{Name: "SUPERVISOR_ADDR", Value: fmt.Sprintf("localhost:%d", cfg.APIEndpointPort), Source: envvarSourceSupervisor}
 
 	if cfg.EnvvarOTS != "" {
 		es, err := downloadEnvvarOTS(cfg.EnvvarOTS)
@@ -929,7 +1041,17 @@ func buildChildProcEnv(cfg *Config, envvars []string) []string {
 				continue
 			}
 
-			envs[k] = v
+			envs[k] = envvar{Name: k, Value: v, Source: envvarSourceUser}
+		}
+	}
+
+	for _, overlay := range overlays {
+		for _, e := range overlay {
+			if isBlacklistedEnvvar(e.Name) {
+				continue
+			}
+
+			envs[e.Name] = e
 		}
 	}
 
@@ -948,27 +1070,48 @@ func buildChildProcEnv(cfg *Config, envvars []string) []string {
 	//     - https://github.com/mirror/busybox/blob/24198f652f10dca5603df7c704263358ca21f5ce/libbb/setup_environment.c#L32
 	//     - https://github.com/mirror/busybox/blob/24198f652f10dca5603df7c704263358ca21f5ce/libbb/login.c#L140-L170
 	//
-	envs["HOME"] = "/home/gitpod"
-	envs["USER"] = "gitpod"
+	envs["HOME"] = envvar{Name: "HOME", Value: "/home/gitpod", Source: envvarSourceSupervisor}
+	envs["USER"] = envvar{Name: "USER", Value: "gitpod", Source: envvarSourceSupervisor}
 
 	// Particular Java optimisation: Java pre v10 did not gauge it's available memory correctly, and needed explicitly setting "-Xmx" for all Hotspot/openJDK VMs
 	if mem, ok := envs["GITPOD_MEMORY"]; ok {
-		envs["JAVA_TOOL_OPTIONS"] += fmt.Sprintf(" -Xmx%sm", mem)
+		opts := envs["JAVA_TOOL_OPTIONS"]
+		envs["JAVA_TOOL_OPTIONS"] = envvar{Name: "JAVA_TOOL_OPTIONS", Value: opts.Value + fmt.Sprintf(" -Xmx%sm", mem.Value), Source: envvarSourceSupervisor}
 	}
 
-	var env, envn []string
+	var (
+		env  = make([]envvar, 0, len(envs))
+		envn []string
+	)
 	for nme, val := range envs {
 		log.WithField("envvar", nme).Debug("passing environment variable to IDE")
-		env = append(env, fmt.Sprintf("%s=%s", nme, val))
+		env = append(env, val)
 		envn = append(envn, nme)
 	}
+	sort.Slice(env, func(i, j int) bool { return env[i].Name < env[j].Name })
 
 	log.WithField("envvar", envn).Debug("passing environment variables to IDE")
 
 	return env
 }
 
+// envvarOTS caches the environment variables downloaded from one-time-secret URLs, because each can be downloaded only once.
+var envvarOTS = struct {
+	sync.Mutex
+	downloaded map[string]map[string]string
+}{downloaded: make(map[string]map[string]string)}
+
 func downloadEnvvarOTS(url string) (res map[string]string, err error) {
+	envvarOTS.Lock()
+	defer envvarOTS.Unlock()
+	if res, ok := envvarOTS.downloaded[url]; ok {
+		return res, nil
+	}
+	// a failed download consumes the secret as well
+	defer func() {
+		envvarOTS.downloaded[url] = res
+	}()
+
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Get(url)
 	if err != nil {
@@ -994,7 +1137,7 @@ func downloadEnvvarOTS(url string) (res map[string]string, err error) {
 }
 
 func runIDEReadinessProbe(cfg *Config, ideConfig *IDEConfig, ide IDEKind) (desktopIDEStatus *DesktopIDEStatus) {
-	defer log.WithField("ide", ide.String()).Info("IDE is ready")
+	defer log.WithField("ide", ideName(ideConfig, ide)).Info("IDE is ready")
 
 	defaultIfEmpty := func(value, defaultValue string) string {
 		if len(value) == 0 {
@@ -1010,10 +1153,7 @@ func runIDEReadinessProbe(cfg *Config, ideConfig *IDEConfig, ide IDEKind) (deskt
 		return value
 	}
 
-	defaultProbePort := cfg.IDEPort
-	if ide == DesktopIDE {
-		defaultProbePort = desktopIDEPort
-	}
+	defaultProbePort := idePort(cfg, ideConfig, ide)
 
 	switch ideConfig.ReadinessProbe.Type {
 	case ReadinessProcessProbe:
@@ -1034,7 +1174,7 @@ func runIDEReadinessProbe(cfg *Config, ideConfig *IDEConfig, ide IDEKind) (deskt
 			var err error
 			body, err = ideStatusRequest(url)
 			if err != nil {
-				log.WithField("ide", ide.String()).WithError(err).Debug("Error running IDE readiness probe")
+				log.WithField("ide", ideName(ideConfig, ide)).WithError(err).Debug("Error running IDE readiness probe")
 				continue
 			}
 
@@ -1042,19 +1182,19 @@ func runIDEReadinessProbe(cfg *Config, ideConfig *IDEConfig, ide IDEKind) (deskt
 		}
 
 		duration := time.Since(t0).Seconds()
-		log.WithField("ide", ide.String()).WithField("duration", duration).Infof("IDE readiness took %.3f seconds", duration)
+		log.WithField("ide", ideName(ideConfig, ide)).WithField("duration", duration).Infof("IDE readiness took %.3f seconds", duration)
 
-		if ide != DesktopIDE {
+		if ide == WebIDE {
 			return
 		}
 
 		err := json.Unmarshal(body, &desktopIDEStatus)
 		if err != nil {
-			log.WithField("ide", ide.String()).WithError(err).WithField("body", body).Debugf("Error parsing JSON body from IDE status probe.")
+			log.WithField("ide", ideName(ideConfig, ide)).WithError(err).WithField("body", body).Debugf("Error parsing JSON body from IDE status probe.")
 			return
 		}
 
-		log.WithField("ide", ide.String()).Infof("Desktop IDE status: %s", desktopIDEStatus)
+		log.WithField("ide", ideName(ideConfig, ide)).Infof("Desktop IDE status: %s", desktopIDEStatus)
 		return
 	}
 
@@ -1181,6 +1321,11 @@ func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, serv
 
 	ideURL, _ := url.Parse(fmt.Sprintf("http://localhost:%d", cfg.IDEPort))
 	routes.Handle("/", httputil.NewSingleHostReverseProxy(ideURL))
+	for _, ide := range cfg.AdditionalIDEs {
+		prefix := additionalIDEPathPrefix(ide)
+		ideURL, _ := url.Parse(fmt.Sprintf("http://localhost:%d", ide.Port))
+		routes.Handle(prefix, http.StripPrefix(strings.TrimSuffix(prefix, "/"), httputil.NewSingleHostReverseProxy(ideURL)))
+	}
 	routes.Handle("/_supervisor/frontend/", http.StripPrefix("/_supervisor/frontend", http.FileServer(http.Dir(cfg.StaticConfig.FrontendLocation))))
 
 	routes.Handle("/_supervisor/v1/", http.StripPrefix("/_supervisor", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1225,6 +1370,11 @@ func startAPIEndpoint(ctx context.Context, cfg *Config, wg *sync.WaitGroup, serv
 	l.Close()
 }
 
+// additionalIDEPathPrefix returns the path an additional IDE backend is served at by the API endpoint.
+func additionalIDEPathPrefix(ide *IDEConfig) string {
+	return "/_ide/" + ide.Name + "/"
+}
+
 func tunnelOverWebSocket(tunneled *ports.TunneledPortsService, conn *gitpod.WebsocketConnection) {
 	hostKey, err := generateHostKey()
 	if err != nil {
@@ -1319,7 +1469,7 @@ func stopWhenTasksAreDone(ctx context.Context, wg *sync.WaitGroup, shutdown chan
 	shutdown <- ShutdownReasonSuccess
 }
 
-func startSSHServer(ctx context.Context, cfg *Config, wg *sync.WaitGroup, childProcEnvvars []string) {
+func startSSHServer(ctx context.Context, cfg *Config, wg *sync.WaitGroup, childProcEnvvars func() []string, terminalService *terminal.MuxTerminalService, connections *sshConnections) {
 	defer wg.Done()
 
 	if cfg.isHeadless() {
@@ -1327,7 +1477,7 @@ func startSSHServer(ctx context.Context, cfg *Config, wg *sync.WaitGroup, childP
 	}
 
 	go func() {
-		ssh, err := newSSHServer(ctx, cfg, childProcEnvvars)
+		ssh, err := newSSHServer(ctx, cfg, childProcEnvvars, terminalService, connections)
 		if err != nil {
 			log.WithError(err).Error("err creating SSH server")
 			return
@@ -1621,7 +1771,34 @@ func analyseConfigChanges(ctx context.Context, wscfg *Config, w analytics.Writer
 	}
 }
 
-func trackReadiness(ctx context.Context, gitpodService serverapi.APIInterface, cfg *Config, cstate *InMemoryContentState, ideReady *ideReadyState, desktopIdeReady *ideReadyState) {
+// notifyInvalidConfig shows a notification whenever the .gitpod.yml is changed and it is invalid.
+func notifyInvalidConfig(ctx context.Context, notifications *NotificationService, invalid <-chan *config.InvalidConfig) {
+	for cfg := range invalid {
+		var problems []string
+		for _, d := range cfg.Diagnostics {
+			if d.Severity == validate.SeverityError {
+				problems = append(problems, d.String())
+			}
+		}
+		if len(problems) == 0 {
+			continue
+		}
+		message := fmt.Sprintf("%s is invalid: %s", filepath.Base(cfg.Location), problems[0])
+		if len(problems) > 1 {
+			message += fmt.Sprintf(" (and %d more problems)", len(problems)-1)
+		}
+		message += ". Run 'gp validate' for details."
+		_, err := notifications.Notify(ctx, &api.NotifyRequest{
+			Level:   api.NotifyRequest_WARNING,
+			Message: message,
+		})
+		if err != nil && ctx.Err() == nil {
+			log.WithError(err).Warn("cannot notify about invalid .gitpod.yml")
+		}
+	}
+}
+
+func trackReadiness(ctx context.Context, gitpodService serverapi.APIInterface, cfg *Config, cstate *InMemoryContentState, ideReady *ideReadyState, desktopIdeReady *ideReadyState, additionalIdes []*additionalIDE) {
 	type SupervisorReadiness struct {
 		Kind                string `json:"kind,omitempty"`
 		WorkspaceId         string `json:"workspaceId,omitempty"`
@@ -1661,6 +1838,13 @@ func trackReadiness(ctx context.Context, gitpodService serverapi.APIInterface, c
 			trackFn(ctx, gitpodService, cfg, readinessKindDesktopIDE)
 		}()
 	}
+	for _, ide := range additionalIdes {
+		ide := ide
+		go func() {
+			<-ide.Ready.Wait()
+			trackFn(ctx, gitpodService, cfg, readinessKindIDE+"-"+ide.Config.Name)
+		}()
+	}
 }
 
 func runAsGitpodUser(cmd *exec.Cmd) *exec.Cmd {
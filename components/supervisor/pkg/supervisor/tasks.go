@@ -13,14 +13,18 @@ import (
 	"math"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/xerrors"
+
 	"github.com/gitpod-io/gitpod/common-go/log"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/logs"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
 	"github.com/gitpod-io/gitpod/supervisor/api"
 	"github.com/gitpod-io/gitpod/supervisor/pkg/terminal"
 )
@@ -70,6 +74,29 @@ type task struct {
 	successChan chan taskSuccess
 	title       string
 	lastOutput  string
+	// env is the environment the task terminal has been started with in addition to the workspace environment
+	env []envvar
+
+	// dependencies are the tasks which have to complete before this task is started
+	dependencies []*task
+	// hasDependents is true if other tasks wait for this task to complete
+	hasDependents bool
+	// completed is closed once the commands of this task have finished, result holds their outcome
+	completed    chan struct{}
+	completeOnce sync.Once
+	result       taskSuccess
+	// restartRequested is true if the task terminal has been closed in order to restart the task
+	restartRequested bool
+	// removed is true if the task has been removed from the config on reload
+	removed bool
+}
+
+// complete marks the task as completed. Only the first call has an effect.
+func (t *task) complete(result taskSuccess) {
+	t.completeOnce.Do(func() {
+		t.result = result
+		close(t.completed)
+	})
 }
 
 type headlessTaskProgressReporter interface {
@@ -109,6 +136,15 @@ type tasksManager struct {
 	reporter        headlessTaskProgressReporter
 	ideReady        *ideReadyState
 	desktopIdeReady *ideReadyState
+	secrets         *secretResolver
+
+	// runCtx is the context tasks started on reload run in
+	runCtx context.Context
+	// desired are the tasks of the current .gitpod.yml, which the running tasks are reloaded to
+	desired                   []TaskConfig
+	nextTaskID                int
+	reloadMu                  sync.Mutex
+	reloadNotificationPending bool
 }
 
 func newTasksManager(config *Config, terminalService *terminal.MuxTerminalService, contentState ContentState, reporter headlessTaskProgressReporter, ideReady *ideReadyState, desktopIdeReady *ideReadyState) *tasksManager {
@@ -201,36 +237,106 @@ func (tm *tasksManager) init(ctx context.Context) {
 	tm.waitForIde(ctx, 1*time.Second)
 
 	for i, config := range *tasks {
-		id := strconv.Itoa(i)
-		presentation := &api.TaskPresentation{}
-		if config.Name != nil {
-			presentation.Name = *config.Name
-		} else {
-			presentation.Name = "Gitpod Task " + strconv.Itoa(i+1)
-		}
-		if config.OpenIn != nil {
-			presentation.OpenIn = *config.OpenIn
-		}
-		if config.OpenMode != nil {
-			presentation.OpenMode = *config.OpenMode
-		}
-		task := &task{
-			TaskStatus: api.TaskStatus{
-				Id:           id,
-				State:        api.TaskState_opening,
-				Presentation: presentation,
-			},
-			config:      config,
-			successChan: make(chan taskSuccess, 1),
-			title:       presentation.Name,
-		}
+		task := tm.newTask(strconv.Itoa(i), config, taskConfigName(config, i))
 		task.command = getCommand(task, tm.config.isHeadless(), tm.contentSource, tm.storeLocation)
 		if tm.config.isHeadless() && task.command == "exit" {
 			task.State = api.TaskState_closed
 			task.successChan <- taskSuccessful
+			task.complete(taskSuccessful)
 		}
 		tm.tasks = append(tm.tasks, task)
 	}
+	tm.nextTaskID = len(*tasks)
+
+	tm.resolveDependencies(*tasks)
+}
+
+func (tm *tasksManager) newTask(id string, config TaskConfig, name string) *task {
+	presentation := &api.TaskPresentation{Name: name}
+	if config.OpenIn != nil {
+		presentation.OpenIn = *config.OpenIn
+	}
+	if config.OpenMode != nil {
+		presentation.OpenMode = *config.OpenMode
+	}
+	return &task{
+		TaskStatus: api.TaskStatus{
+			Id:                id,
+			State:             api.TaskState_opening,
+			Presentation:      presentation,
+			HasReadinessProbe: config.Readiness != nil && !tm.config.isHeadless(),
+		},
+		config:      config,
+		successChan: make(chan taskSuccess, 1),
+		title:       presentation.Name,
+		completed:   make(chan struct{}),
+	}
+}
+
+// resolveDependencies wires up the tasks with the tasks they depend on. Tasks with dependencies
+// are held back in the waiting state. If the dependencies cannot be resolved, e.g. because they
+// form a cycle, all tasks with dependencies fail without being started.
+func (tm *tasksManager) resolveDependencies(configs []TaskConfig) {
+	var (
+		items           = make([]gitpod.TasksItems, len(configs))
+		hasDependencies bool
+	)
+	for i, config := range configs {
+		if config.Name != nil {
+			items[i].Name = *config.Name
+		}
+		if config.DependsOn != nil && len(*config.DependsOn) > 0 {
+			items[i].DependsOn = *config.DependsOn
+			hasDependencies = true
+		}
+	}
+	if !hasDependencies {
+		return
+	}
+
+	deps, err := gitpod.ResolveTaskDependencies(items)
+	if err != nil {
+		log.WithError(err).Error("cannot resolve task dependencies")
+		for i, t := range tm.tasks {
+			if len(items[i].DependsOn) == 0 || t.State == api.TaskState_closed {
+				continue
+			}
+			t.State = api.TaskState_closed
+			t.successChan <- taskFailed(err.Error())
+			t.complete(taskFailed(err.Error()))
+		}
+		return
+	}
+
+	for i, t := range tm.tasks {
+		for _, idx := range deps[i] {
+			dep := tm.tasks[idx]
+			dep.hasDependents = true
+			t.dependencies = append(t.dependencies, dep)
+			t.DependsOn = append(t.DependsOn, dep.Id)
+		}
+		if len(t.dependencies) > 0 && t.State != api.TaskState_closed {
+			t.State = api.TaskState_waiting
+		}
+	}
+
+	if tm.config.isHeadless() {
+		// headless tasks exit once their commands are done
+		return
+	}
+	for _, t := range tm.tasks {
+		if !t.hasDependents || t.config.restartPolicy() != taskRestartNever {
+			// the terminals of restarting tasks exit with their commands
+			continue
+		}
+		_ = os.Remove(taskExitCodeFileName(t, tm.storeLocation))
+		// now that the task has dependents, its command writes its exit code
+		t.command = getCommand(t, false, tm.contentSource, tm.storeLocation)
+	}
+}
+
+func taskExitCodeFileName(task *task, storeLocation string) string {
+	return storeLocation + "/exit-code-" + task.Id
 }
 
 func (tm *tasksManager) waitForIde(parent context.Context, timeout time.Duration) {
@@ -259,126 +365,428 @@ func (tm *tasksManager) Run(ctx context.Context, wg *sync.WaitGroup, successChan
 	defer wg.Done()
 	defer log.Debug("tasksManager shutdown")
 
+	tm.mu.Lock()
+	tm.runCtx = ctx
+	tm.mu.Unlock()
 	tm.init(ctx)
 
-	for _, t := range tm.tasks {
-		if t.State == api.TaskState_closed {
+	// tasks added on reload are not waited for
+	tm.mu.RLock()
+	tasks := make([]*task, len(tm.tasks))
+	copy(tasks, tm.tasks)
+	tm.mu.RUnlock()
+
+	for _, t := range tasks {
+		switch t.State {
+		case api.TaskState_closed:
+			continue
+		case api.TaskState_waiting:
+			go tm.startTaskAfterDependencies(ctx, t)
 			continue
 		}
-		taskLog := log.WithField("command", t.command)
-		taskLog.Info("starting a task terminal...")
-		openRequest := &api.OpenTerminalRequest{}
-		if t.config.Env != nil {
-			openRequest.Env = make(map[string]string, len(*t.config.Env))
-			for key, value := range *t.config.Env {
-				// Required check because a string is considered valid JSON (e.g. "hello")
-				// We don't want to marshall basic strings otherwise we get a double quoted environment variable
-				// See: https://github.com/gitpod-io/gitpod/issues/5887
-				if val, ok := value.(string); ok {
-					openRequest.Env[key] = val
+		tm.startTask(ctx, t)
+	}
+
+	var success taskSuccess
+	for _, task := range tasks {
+		select {
+		case <-ctx.Done():
+			success = taskFailed(ctx.Err().Error())
+		case taskResult := <-task.successChan:
+			if taskResult.Failed() {
+				success = success.Fail(string(taskResult))
+			}
+		}
+	}
+
+	if tm.config.isHeadless() && tm.reporter != nil {
+		tm.reporter.done(success)
+	}
+	successChan <- success
+}
+
+// taskEnv computes the environment of a task from its envFiles and env, resolving secret references.
+func (tm *tasksManager) taskEnv(ctx context.Context, t *task) []envvar {
+	var env []envvar
+	if t.config.EnvFile != nil {
+		env = append(env, loadEnvFiles(tm.config.RepoRoot, *t.config.EnvFile)...)
+	}
+	if t.config.Env != nil {
+		keys := make([]string, 0, len(*t.config.Env))
+		for key := range *t.config.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := (*t.config.Env)[key]
+			// Required check because a string is considered valid JSON (e.g. "hello")
+			// We don't want to marshall basic strings otherwise we get a double quoted environment variable
+			// See: https://github.com/gitpod-io/gitpod/issues/5887
+			if val, ok := value.(string); ok {
+				env = append(env, envvar{Name: key, Value: val, Source: envvarSourceTask})
+			} else {
+				v, err := json.Marshal(value)
+				if err != nil {
+					log.WithError(err).WithField("key", key).Error("cannot marshal env var")
 				} else {
-					v, err := json.Marshal(value)
-					if err != nil {
-						taskLog.WithError(err).WithField("key", key).Error("cannot marshal env var")
-					} else {
-						openRequest.Env[key] = string(v)
-					}
+					env = append(env, envvar{Name: key, Value: string(v), Source: envvarSourceTask})
 				}
 			}
 		}
-		resp, err := tm.terminalService.OpenWithOptions(ctx, openRequest, terminal.TermOptions{
-			ReadTimeout: 5 * time.Second,
-			Title:       t.title,
-		})
-		if err != nil {
-			taskLog.WithError(err).Error("cannot open new task terminal")
-			t.successChan <- taskFailed("cannot open new task terminal")
-			tm.setTaskState(t, api.TaskState_closed)
-			continue
+	}
+	env = tm.secrets.resolve(ctx, env)
+
+	// the env takes precedence over envFiles, and later envFiles over earlier ones
+	return mergeEnvvars(nil, env)
+}
+
+// Env returns the environment a task has been started with in addition to the workspace environment.
+func (tm *tasksManager) Env(id string) (env []envvar, ok bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	for _, t := range tm.tasks {
+		if t.Id == id || t.Terminal == id {
+			return t.env, true
 		}
+	}
+	return nil, false
+}
 
-		taskLog = taskLog.WithField("terminal", resp.Terminal.Alias)
-		term, ok := tm.terminalService.Mux.Get(resp.Terminal.Alias)
-		if !ok {
-			taskLog.Error("cannot find a task terminal")
-			t.successChan <- taskFailed("cannot find a task terminal")
-			tm.setTaskState(t, api.TaskState_closed)
-			continue
+func (tm *tasksManager) startTask(ctx context.Context, t *task) {
+	taskLog := log.WithField("command", t.command)
+	taskLog.Info("starting a task terminal...")
+	env := tm.taskEnv(ctx, t)
+	tm.mu.Lock()
+	t.env = env
+	tm.mu.Unlock()
+	openRequest := &api.OpenTerminalRequest{}
+	if len(env) > 0 {
+		openRequest.Env = make(map[string]string, len(env))
+		for _, e := range env {
+			openRequest.Env[e.Name] = e.Value
 		}
+	}
+	resp, err := tm.terminalService.OpenWithOptions(ctx, openRequest, terminal.TermOptions{
+		ReadTimeout: 5 * time.Second,
+		Title:       t.title,
+	})
+	if err != nil {
+		taskLog.WithError(err).Error("cannot open new task terminal")
+		t.successChan <- taskFailed("cannot open new task terminal")
+		t.complete(taskFailed("cannot open new task terminal"))
+		tm.setTaskState(t, api.TaskState_closed)
+		return
+	}
+
+	taskLog = taskLog.WithField("terminal", resp.Terminal.Alias)
+	term, ok := tm.terminalService.Mux.Get(resp.Terminal.Alias)
+	if !ok {
+		taskLog.Error("cannot find a task terminal")
+		t.successChan <- taskFailed("cannot find a task terminal")
+		t.complete(taskFailed("cannot find a task terminal"))
+		tm.setTaskState(t, api.TaskState_closed)
+		return
+	}
 
-		taskLog = taskLog.WithField("pid", term.Command.Process.Pid)
-		taskLog.Info("task terminal has been started")
+	taskLog = taskLog.WithField("pid", term.Command.Process.Pid)
+	taskLog.Info("task terminal has been started")
+	tm.updateState(func() bool {
+		t.Terminal = resp.Terminal.Alias
+		t.State = api.TaskState_running
+		return true
+	})
+
+	// termCtx is canceled once the terminal has been closed
+	termCtx, cancelTerm := context.WithCancel(ctx)
+	go func(t *task, term *terminal.Term) {
+		state, err := term.Wait()
+		cancelTerm()
+
+		var exitCode *int32
+		if state != nil {
+			code := int32(state.ExitCode())
+			exitCode = &code
+		}
+
+		var result taskSuccess
+		if state != nil {
+			if state.Success() {
+				result = taskSuccessful
+			} else {
+				result = taskFailed(state.String())
+			}
+		} else if err != nil {
+			result = taskSuccessful
+		} else {
+			msg := "cannot wait for task"
+			if err != nil {
+				msg = err.Error()
+			}
+
+			result = taskFailed(fmt.Sprintf("%s: %s", msg, t.lastOutput))
+		}
+
+		tm.mu.Lock()
+		restartRequested := t.restartRequested
+		t.restartRequested = false
+		removed := t.removed
+		tm.mu.Unlock()
+		if !removed && restartRequested {
+			tm.restartTask(ctx, t, exitCode)
+			return
+		}
+
+		select {
+		case t.successChan <- result:
+		default:
+			// the task has been started again on reload or restart, its first result counts
+		}
+		if !removed && shouldRestartTask(t, tm.config.isHeadless(), state) {
+			// dependents settle on the first exit rather than waiting for all restarts
+			t.complete(result)
+			tm.restartTask(ctx, t, exitCode)
+			return
+		}
+
+		t.complete(result)
+		taskLog.Info("task terminal has been closed")
 		tm.updateState(func() bool {
-			t.Terminal = resp.Terminal.Alias
-			t.State = api.TaskState_running
+			if exitCode != nil {
+				t.LastExitCode = exitCode
+			}
+			t.State = api.TaskState_closed
 			return true
 		})
+	}(t, term)
 
-		go func(t *task, term *terminal.Term) {
-			state, err := term.Wait()
-			if state != nil {
-				if state.Success() {
-					t.successChan <- taskSuccessful
-				} else {
-					t.successChan <- taskFailed(state.String())
-				}
-			} else if err != nil {
-				t.successChan <- taskSuccessful
-			} else {
-				msg := "cannot wait for task"
-				if err != nil {
-					msg = err.Error()
-				}
+	tm.watch(t, term)
 
-				t.successChan <- taskFailed(fmt.Sprintf("%s: %s", msg, t.lastOutput))
-			}
-			taskLog.Info("task terminal has been closed")
-			tm.setTaskState(t, api.TaskState_closed)
-		}(t, term)
+	if t.HasReadinessProbe {
+		tm.probeReadiness(termCtx, t, term)
+	}
 
-		tm.watch(t, term)
+	if t.command != "" {
+		term.PTY.Write([]byte(t.command + "\n"))
+	}
+
+	if t.hasDependents && !tm.config.isHeadless() {
+		go tm.watchExitCode(termCtx, t)
+	}
+}
 
-		if t.command != "" {
-			term.PTY.Write([]byte(t.command + "\n"))
+// startTaskAfterDependencies starts the task once all its dependencies have completed successfully.
+// If a dependency fails, the task fails without being started.
+func (tm *tasksManager) startTaskAfterDependencies(ctx context.Context, t *task) {
+	for _, dep := range t.dependencies {
+		select {
+		case <-ctx.Done():
+			return
+		case <-dep.completed:
+		}
+		if dep.result.Failed() {
+			result := taskFailed(fmt.Sprintf("dependency %s failed: %s", dep.title, dep.result))
+			t.successChan <- result
+			t.complete(result)
+			tm.setTaskState(t, api.TaskState_closed)
+			return
 		}
 	}
 
-	var success taskSuccess
-	for _, task := range tm.tasks {
+	tm.mu.RLock()
+	removed := t.removed
+	tm.mu.RUnlock()
+	if removed {
+		return
+	}
+
+	tm.setTaskState(t, api.TaskState_opening)
+	tm.startTask(ctx, t)
+}
+
+// watchExitCode completes the task once its commands have written their exit code.
+// Interactive task terminals stay open after the commands have finished, hence the
+// terminal closing cannot be used to detect the completion of a task.
+func (tm *tasksManager) watchExitCode(ctx context.Context, t *task) {
+	exitCodeFile := taskExitCodeFileName(t, tm.storeLocation)
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+	for {
 		select {
 		case <-ctx.Done():
-			success = taskFailed(ctx.Err().Error())
-		case taskResult := <-task.successChan:
-			if taskResult.Failed() {
-				success = success.Fail(string(taskResult))
-			}
+			return
+		case <-t.completed:
+			return
+		case <-ticker.C:
+		}
+
+		content, err := os.ReadFile(exitCodeFile)
+		if err != nil {
+			continue
 		}
+		exitCode := strings.TrimSpace(string(content))
+		if exitCode == "" {
+			continue
+		}
+		if code, err := strconv.ParseInt(exitCode, 10, 32); err == nil {
+			tm.updateState(func() bool {
+				lastExitCode := int32(code)
+				t.LastExitCode = &lastExitCode
+				return true
+			})
+		}
+		if exitCode == "0" {
+			t.complete(taskSuccessful)
+		} else {
+			t.complete(taskFailed("exit code " + exitCode))
+		}
+		return
 	}
+}
 
-	if tm.config.isHeadless() && tm.reporter != nil {
-		tm.reporter.done(success)
+type taskRestartPolicy string
+
+const (
+	taskRestartNever     taskRestartPolicy = "never"
+	taskRestartOnFailure taskRestartPolicy = "on-failure"
+	taskRestartAlways    taskRestartPolicy = "always"
+
+	defaultMaxTaskRestarts = 10
+	maxTaskRestartBackoff  = 1 * time.Minute
+)
+
+func (c TaskConfig) restartPolicy() taskRestartPolicy {
+	if c.Restart == nil {
+		return taskRestartNever
+	}
+	switch policy := taskRestartPolicy(*c.Restart); policy {
+	case taskRestartOnFailure, taskRestartAlways:
+		return policy
+	default:
+		return taskRestartNever
 	}
-	successChan <- success
 }
 
-func getCommand(task *task, isHeadless bool, contentSource csapi.WorkspaceInitSource, storeLocation string) string {
-	commands := getCommands(task, isHeadless, contentSource, storeLocation)
-	command := composeCommand(composeCommandOptions{
-		commands: commands,
-		format:   "{\n%s\n}",
-		sep:      " && ",
+// shouldRestartTask decides based on the task's restart policy whether its terminal should be restarted.
+// Terminals which have been killed, e.g. by gp tasks stop, are never restarted, and neither are tasks of
+// headless workspaces, as prebuilds finish once all tasks have exited.
+func shouldRestartTask(t *task, headless bool, state *os.ProcessState) bool {
+	if headless || state == nil || !state.Exited() {
+		return false
+	}
+
+	maxRestarts := defaultMaxTaskRestarts
+	if t.config.MaxRestarts != nil {
+		maxRestarts = *t.config.MaxRestarts
+	}
+	if int(t.RestartCount) >= maxRestarts {
+		return false
+	}
+
+	switch t.config.restartPolicy() {
+	case taskRestartAlways:
+		return true
+	case taskRestartOnFailure:
+		return !state.Success()
+	default:
+		return false
+	}
+}
+
+// taskRestartBackoff returns the delay before the next restart, doubling with every restart.
+func taskRestartBackoff(restartCount uint32) time.Duration {
+	if restartCount >= 6 {
+		return maxTaskRestartBackoff
+	}
+	backoff := time.Second << restartCount
+	if backoff > maxTaskRestartBackoff {
+		return maxTaskRestartBackoff
+	}
+	return backoff
+}
+
+// restartTask opens a new terminal for the task after an exponential backoff.
+// Like on workspace restarts, only the before and the main command are run again.
+func (tm *tasksManager) restartTask(ctx context.Context, t *task, exitCode *int32) {
+	backoff := taskRestartBackoff(t.RestartCount)
+	taskLog := log.WithField("task", t.Id).WithField("backoff", backoff.String())
+	if exitCode != nil {
+		taskLog = taskLog.WithField("exitCode", *exitCode)
+	}
+	taskLog.Info("restarting task terminal")
+	tm.updateState(func() bool {
+		t.RestartCount++
+		t.LastExitCode = exitCode
+		t.State = api.TaskState_opening
+		return true
 	})
 
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(backoff):
+	}
+
+	t.command = getCommand(t, false, csapi.WorkspaceInitFromBackup, tm.storeLocation)
+	tm.startTask(ctx, t)
+}
+
+// Restart closes the terminal of a running task and opens a new one once it has been closed.
+func (tm *tasksManager) Restart(ctx context.Context, id string) error {
+	tm.mu.Lock()
+	var t *task
+	for _, candidate := range tm.tasks {
+		if candidate.Id == id {
+			t = candidate
+			break
+		}
+	}
+	if t == nil {
+		tm.mu.Unlock()
+		return xerrors.Errorf("task %s not found", id)
+	}
+	switch t.State {
+	case api.TaskState_running, api.TaskState_ready, api.TaskState_unhealthy:
+	default:
+		tm.mu.Unlock()
+		return xerrors.Errorf("task %s is not running", id)
+	}
+	t.restartRequested = true
+	alias := t.Terminal
+	tm.mu.Unlock()
+
+	err := tm.terminalService.Mux.CloseTerminal(ctx, alias)
+	if err != nil {
+		tm.mu.Lock()
+		t.restartRequested = false
+		tm.mu.Unlock()
+		return xerrors.Errorf("cannot close terminal of task %s: %w", id, err)
+	}
+	return nil
+}
+
+func getCommand(task *task, isHeadless bool, contentSource csapi.WorkspaceInitSource, storeLocation string) string {
+	commands := taskCommands(task, storeLocation)
+	phase := taskPhase(isHeadless, contentSource)
+	options := gitpod.TaskCommandOptions{
+		// it's important that prebuild tasks exit eventually,
+		// and the terminal has to exit with the commands for the task to be restarted
+		Exit: isHeadless || task.config.restartPolicy() != taskRestartNever,
+	}
+	if task.hasDependents && !isHeadless && task.config.restartPolicy() == taskRestartNever {
+		options.ExitCodeFile = taskExitCodeFileName(task, storeLocation)
+	}
+	command := commands.Compose(phase, options)
+
 	if isHeadless {
-		// it's important that prebuild tasks exit eventually
 		// also, we need to save the log output in the workspace
 		if strings.TrimSpace(command) == "" {
 			return "exit"
 		}
-		return command + "; exit"
+		return command
 	}
 
-	histfileCommand := getHistfileCommand(task, commands, contentSource, storeLocation)
+	histfileCommand := getHistfileCommand(task, commands.Phase(phase), contentSource, storeLocation)
 	if strings.TrimSpace(command) == "" {
 		return histfileCommand
 	}
@@ -393,9 +801,9 @@ func getHistfileCommand(task *task, commands []*string, contentSource csapi.Work
 	if contentSource == csapi.WorkspaceInitFromPrebuild {
 		histfileCommands = []*string{task.config.Before, task.config.Init, task.config.Prebuild, task.config.Command}
 	}
-	histfileContent := composeCommand(composeCommandOptions{
-		commands: histfileCommands,
-		format:   "%s\r\n",
+	histfileContent := gitpod.ComposeCommand(gitpod.ComposeCommandOptions{
+		Commands: histfileCommands,
+		Format:   "%s\r\n",
 	})
 	if strings.TrimSpace(histfileContent) == "" {
 		return ""
@@ -413,24 +821,32 @@ func getHistfileCommand(task *task, commands []*string, contentSource csapi.Work
 	return " HISTFILE=" + histfile + " history -r"
 }
 
-func getCommands(task *task, isHeadless bool, contentSource csapi.WorkspaceInitSource, storeLocation string) []*string {
-	if isHeadless {
-		// prebuild
-		return []*string{task.config.Before, task.config.Init, task.config.Prebuild}
+// taskCommands returns the commands of a task for the composition shared with gp validate.
+func taskCommands(task *task, storeLocation string) gitpod.TaskCommands {
+	prebuildLogFileName := prebuildLogFileName(task, storeLocation)
+	legacyPrebuildLogFileName := logs.LegacyPrebuildLogFileName(task.Id)
+	printlogs := "[ -r " + legacyPrebuildLogFileName + " ] && cat " + legacyPrebuildLogFileName + "; [ -r " + prebuildLogFileName + " ] && cat " + prebuildLogFileName + "; true"
+	return gitpod.TaskCommands{
+		Before:      task.config.Before,
+		Init:        task.config.Init,
+		Prebuild:    task.config.Prebuild,
+		Command:     task.config.Command,
+		PrebuildLog: &printlogs,
 	}
-	if contentSource == csapi.WorkspaceInitFromPrebuild {
-		// prebuilt
-		prebuildLogFileName := prebuildLogFileName(task, storeLocation)
-		legacyPrebuildLogFileName := logs.LegacyPrebuildLogFileName(task.Id)
-		printlogs := "[ -r " + legacyPrebuildLogFileName + " ] && cat " + legacyPrebuildLogFileName + "; [ -r " + prebuildLogFileName + " ] && cat " + prebuildLogFileName + "; true"
-		return []*string{task.config.Before, &printlogs, task.config.Command}
+}
+
+func taskPhase(isHeadless bool, contentSource csapi.WorkspaceInitSource) gitpod.TaskPhase {
+	if isHeadless {
+		return gitpod.TaskPhasePrebuild
 	}
-	if contentSource == csapi.WorkspaceInitFromBackup {
-		// restart
-		return []*string{task.config.Before, task.config.Command}
+	switch contentSource {
+	case csapi.WorkspaceInitFromPrebuild:
+		return gitpod.TaskPhaseStartFromPrebuild
+	case csapi.WorkspaceInitFromBackup:
+		return gitpod.TaskPhaseRestart
+	default:
+		return gitpod.TaskPhaseStart
 	}
-	// init
-	return []*string{task.config.Before, task.config.Init, task.config.Command}
 }
 
 func prebuildLogFileName(task *task, storeLocation string) string {
@@ -553,19 +969,3 @@ func importParentLogAndGetDuration(fn string, out io.Writer) time.Duration {
 	}
 	return time.Duration(elapsedInMinutes) * time.Minute
 }
-
-type composeCommandOptions struct {
-	commands []*string
-	format   string
-	sep      string
-}
-
-func composeCommand(options composeCommandOptions) string {
-	var commands []string
-	for _, command := range options.commands {
-		if command != nil && strings.TrimSpace(*command) != "" {
-			commands = append(commands, fmt.Sprintf(options.format, *command))
-		}
-	}
-	return strings.Join(commands, options.sep)
-}
@@ -0,0 +1,386 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+const (
+	tasksReloadActionApply   = "Apply"
+	tasksReloadActionDismiss = "Dismiss"
+)
+
+// errNoTaskConfig is returned when tasks are reloaded before a .gitpod.yml has been read.
+var errNoTaskConfig = xerrors.New("no .gitpod.yml found")
+
+// tasksDiff describes how the running tasks differ from the tasks of the current config.
+type tasksDiff struct {
+	Added   []addedTask
+	Removed []*task
+	Changed []taskChange
+}
+
+type addedTask struct {
+	config TaskConfig
+	title  string
+}
+
+type taskChange struct {
+	task   *task
+	config TaskConfig
+}
+
+func (d *tasksDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d *tasksDiff) toResponse() *api.ReloadTasksResponse {
+	res := &api.ReloadTasksResponse{}
+	for _, a := range d.Added {
+		res.Added = append(res.Added, a.title)
+	}
+	for _, t := range d.Removed {
+		res.Removed = append(res.Removed, t.title)
+	}
+	for _, c := range d.Changed {
+		res.Changed = append(res.Changed, c.task.title)
+	}
+	return res
+}
+
+func (d *tasksDiff) String() string {
+	resp := d.toResponse()
+	var changes []string
+	if len(resp.Added) > 0 {
+		changes = append(changes, "start "+strings.Join(resp.Added, ", "))
+	}
+	if len(resp.Removed) > 0 {
+		changes = append(changes, "stop "+strings.Join(resp.Removed, ", "))
+	}
+	if len(resp.Changed) > 0 {
+		changes = append(changes, "restart "+strings.Join(resp.Changed, ", "))
+	}
+	return strings.Join(changes, "; ")
+}
+
+func taskConfigName(config TaskConfig, i int) string {
+	if config.Name != nil {
+		return *config.Name
+	}
+	return "Gitpod Task " + strconv.Itoa(i+1)
+}
+
+// taskKeys identifies tasks across config changes: named tasks by their name, unnamed ones by their order.
+func taskKeys(configs []TaskConfig) []string {
+	keys := make([]string, len(configs))
+	unnamed := 0
+	for i, config := range configs {
+		if config.Name != nil && *config.Name != "" {
+			keys[i] = "name:" + *config.Name
+			continue
+		}
+		keys[i] = "#" + strconv.Itoa(unnamed)
+		unnamed++
+	}
+	return keys
+}
+
+// taskCommandsChanged returns true if a task has to be restarted to apply the new config.
+func taskCommandsChanged(old, new TaskConfig) bool {
+	type commands struct {
+		Before  *string
+		Command *string
+		Env     *map[string]interface{}
+		EnvFile *[]string
+	}
+	normalize := func(c TaskConfig) commands {
+		res := commands{Before: c.Before, Command: c.Command, Env: c.Env, EnvFile: c.EnvFile}
+		if res.Before != nil && *res.Before == "" {
+			res.Before = nil
+		}
+		if res.Command != nil && *res.Command == "" {
+			res.Command = nil
+		}
+		if res.Env != nil && len(*res.Env) == 0 {
+			res.Env = nil
+		}
+		if res.EnvFile != nil && len(*res.EnvFile) == 0 {
+			res.EnvFile = nil
+		}
+		return res
+	}
+	return !reflect.DeepEqual(normalize(old), normalize(new))
+}
+
+// hasCommands returns false for tasks which only open a terminal.
+func (c TaskConfig) hasCommands() bool {
+	for _, command := range []*string{c.Before, c.Init, c.Prebuild, c.Command} {
+		if command != nil && strings.TrimSpace(*command) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// diffTasks compares the running tasks with the tasks of a config. Tasks without commands are
+// plain terminals, which are not stopped if they are missing from the config.
+func diffTasks(tasks []*task, configs []TaskConfig) *tasksDiff {
+	current := make([]TaskConfig, len(tasks))
+	for i, t := range tasks {
+		current[i] = t.config
+	}
+	currentKeys := taskKeys(current)
+	byKey := make(map[string]*task, len(tasks))
+	for i, t := range tasks {
+		byKey[currentKeys[i]] = t
+	}
+
+	diff := &tasksDiff{}
+	seen := make(map[string]struct{}, len(configs))
+	for i, key := range taskKeys(configs) {
+		seen[key] = struct{}{}
+		t, exists := byKey[key]
+		if !exists {
+			diff.Added = append(diff.Added, addedTask{config: configs[i], title: taskConfigName(configs[i], i)})
+			continue
+		}
+		if taskCommandsChanged(t.config, configs[i]) {
+			diff.Changed = append(diff.Changed, taskChange{task: t, config: configs[i]})
+		}
+	}
+	for i, t := range tasks {
+		if _, exists := seen[currentKeys[i]]; exists || !t.config.hasCommands() {
+			continue
+		}
+		diff.Removed = append(diff.Removed, t)
+	}
+	return diff
+}
+
+// tasksFromConfig converts the tasks of a .gitpod.yml the way the server does when it passes them to supervisor.
+func tasksFromConfig(config *gitpod.GitpodConfig) ([]TaskConfig, error) {
+	res := []TaskConfig{}
+	if config == nil || len(config.Tasks) == 0 {
+		return res, nil
+	}
+	tasks, err := json.Marshal(config.Tasks)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(tasks, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// setTaskConfig updates the tasks the running tasks are reloaded to. The running tasks keep the config they
+// have been started with, i.e. GITPOD_TASKS or the config of the last reload, which Reload diffs against.
+func (tm *tasksManager) setTaskConfig(configs []TaskConfig) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.desired = configs
+}
+
+// Reload applies the changes of the configured tasks: added tasks are started, removed ones are stopped and
+// tasks whose commands or environment changed are restarted. Unchanged tasks keep running.
+func (tm *tasksManager) Reload(ctx context.Context, dryRun bool) (*tasksDiff, error) {
+	select {
+	case <-tm.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	tm.reloadMu.Lock()
+	defer tm.reloadMu.Unlock()
+
+	var (
+		stop  []string
+		start []*task
+		wait  []*task
+	)
+	var diff *tasksDiff
+	var err error
+	tm.updateState(func() bool {
+		if tm.desired == nil {
+			err = errNoTaskConfig
+			return false
+		}
+		diff = diffTasks(tm.tasks, tm.desired)
+		if dryRun || diff.empty() {
+			return false
+		}
+
+		removed := make(map[*task]struct{}, len(diff.Removed))
+		for _, t := range diff.Removed {
+			removed[t] = struct{}{}
+			t.removed = true
+			if t.State == api.TaskState_closed {
+				continue
+			}
+			if t.Terminal != "" {
+				stop = append(stop, t.Terminal)
+			}
+			// like unknown dependencies of added tasks, removed tasks do not hold back their dependents
+			t.State = api.TaskState_closed
+			select {
+			case t.successChan <- taskSuccessful:
+			default:
+			}
+			t.complete(taskSuccessful)
+		}
+		tasks := make([]*task, 0, len(tm.tasks)+len(diff.Added))
+		for _, t := range tm.tasks {
+			if _, ok := removed[t]; !ok {
+				tasks = append(tasks, t)
+			}
+		}
+		tm.tasks = tasks
+
+		for _, c := range diff.Changed {
+			t := c.task
+			t.config = c.config
+			switch t.State {
+			case api.TaskState_running, api.TaskState_ready, api.TaskState_unhealthy:
+				t.restartRequested = true
+				stop = append(stop, t.Terminal)
+			case api.TaskState_closed:
+				t.State = api.TaskState_opening
+				t.command = getCommand(t, false, csapi.WorkspaceInitFromBackup, tm.storeLocation)
+				start = append(start, t)
+			default:
+				// the task has not been started yet
+				t.command = getCommand(t, false, tm.contentSource, tm.storeLocation)
+			}
+		}
+
+		byName := make(map[string]*task, len(tm.tasks))
+		for _, t := range tm.tasks {
+			if t.config.Name != nil {
+				byName[*t.config.Name] = t
+			}
+		}
+		for _, a := range diff.Added {
+			config := a.config
+			t := tm.newTask(strconv.Itoa(tm.nextTaskID), config, a.title)
+			tm.nextTaskID++
+			// added tasks have never run, so their init command is run as well
+			t.command = getCommand(t, false, csapi.WorkspaceInitFromOther, tm.storeLocation)
+			if config.DependsOn != nil {
+				for _, name := range *config.DependsOn {
+					if dep, ok := byName[name]; ok {
+						t.dependencies = append(t.dependencies, dep)
+						t.DependsOn = append(t.DependsOn, dep.Id)
+					} else {
+						log.WithField("task", a.title).WithField("dependency", name).Warn("ignoring unknown dependency of reloaded task")
+					}
+				}
+			}
+			// dependencies which are still running complete once their terminal has been closed
+			if len(t.dependencies) > 0 {
+				t.State = api.TaskState_waiting
+				wait = append(wait, t)
+			} else {
+				start = append(start, t)
+			}
+			if config.Name != nil {
+				byName[*config.Name] = t
+			}
+			tm.tasks = append(tm.tasks, t)
+		}
+		return true
+	})
+	if err != nil || dryRun || diff.empty() {
+		return diff, err
+	}
+
+	log.WithField("changes", diff.String()).Info("reloading tasks")
+	for _, alias := range stop {
+		err := tm.terminalService.Mux.CloseTerminal(ctx, alias)
+		if err != nil {
+			log.WithError(err).WithField("terminal", alias).Warn("cannot close task terminal on reload")
+		}
+	}
+	for _, t := range start {
+		go tm.startTask(tm.runCtx, t)
+	}
+	for _, t := range wait {
+		go tm.startTaskAfterDependencies(tm.runCtx, t)
+	}
+	return diff, nil
+}
+
+// watchTaskConfig keeps track of the tasks in .gitpod.yml and offers to reload the running tasks whenever they differ
+// from the tasks the workspace has been started with, e.g. because .gitpod.yml has been changed before a restart.
+func (tm *tasksManager) watchTaskConfig(ctx context.Context, notifications *NotificationService, configs <-chan *gitpod.GitpodConfig) {
+	select {
+	case <-tm.ready:
+	case <-ctx.Done():
+		return
+	}
+
+	for config := range configs {
+		if config == nil {
+			// the config has been removed or cannot be parsed, keep the running tasks
+			continue
+		}
+		tasks, err := tasksFromConfig(config)
+		if err != nil {
+			log.WithError(err).Error("cannot read tasks of .gitpod.yml")
+			continue
+		}
+		tm.setTaskConfig(tasks)
+
+		diff, err := tm.Reload(ctx, true)
+		if err != nil || diff.empty() {
+			continue
+		}
+		tm.mu.Lock()
+		pending := tm.reloadNotificationPending
+		tm.reloadNotificationPending = true
+		tm.mu.Unlock()
+		if pending {
+			// the pending notification applies the latest config
+			continue
+		}
+		go tm.offerReload(ctx, notifications, diff)
+	}
+}
+
+func (tm *tasksManager) offerReload(ctx context.Context, notifications *NotificationService, diff *tasksDiff) {
+	resp, err := notifications.Notify(ctx, &api.NotifyRequest{
+		Level:   api.NotifyRequest_INFO,
+		Message: fmt.Sprintf("The tasks in .gitpod.yml have changed. Apply the changes (%s)?", diff),
+		Actions: []string{tasksReloadActionApply, tasksReloadActionDismiss},
+	})
+	tm.mu.Lock()
+	tm.reloadNotificationPending = false
+	tm.mu.Unlock()
+	if err != nil {
+		if ctx.Err() == nil {
+			log.WithError(err).Error("cannot notify about changed tasks")
+		}
+		return
+	}
+	if resp.Action != tasksReloadActionApply {
+		return
+	}
+	_, err = tm.Reload(ctx, false)
+	if err != nil {
+		log.WithError(err).Error("cannot reload tasks")
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"os/exec"
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sirupsen/logrus"
@@ -24,6 +26,9 @@ import (
 var (
 	skipCommand = "echo \"skip\""
 	failCommand = "exit 1"
+
+	taskNameA = "a"
+	taskNameB = "b"
 )
 
 var exampleEnvVarInputs = &map[string]interface{}{
@@ -120,6 +125,48 @@ func TestTaskManager(t *testing.T) {
 				Success: false,
 			},
 		},
+		{
+			Desc:     "headless prebuild should finish with dependent init tasks",
+			Headless: true,
+			Source:   csapi.WorkspaceInitFromOther,
+			GitpodTasks: &[]TaskConfig{
+				{Name: &taskNameB, Init: &skipCommand, DependsOn: &[]string{taskNameA}},
+				{Name: &taskNameA, Init: &skipCommand},
+			},
+
+			ExpectedReporter: testHeadlessTaskProgressReporter{
+				Done:    true,
+				Success: true,
+			},
+		},
+		{
+			Desc:     "headless prebuild should fail if a dependency fails",
+			Headless: true,
+			Source:   csapi.WorkspaceInitFromOther,
+			GitpodTasks: &[]TaskConfig{
+				{Name: &taskNameA, Init: &failCommand},
+				{Name: &taskNameB, Init: &skipCommand, DependsOn: &[]string{taskNameA}},
+			},
+
+			ExpectedReporter: testHeadlessTaskProgressReporter{
+				Done:    true,
+				Success: false,
+			},
+		},
+		{
+			Desc:     "headless prebuild should fail with cyclic task dependencies",
+			Headless: true,
+			Source:   csapi.WorkspaceInitFromOther,
+			GitpodTasks: &[]TaskConfig{
+				{Name: &taskNameA, Init: &skipCommand, DependsOn: &[]string{taskNameB}},
+				{Name: &taskNameB, Init: &skipCommand, DependsOn: &[]string{taskNameA}},
+			},
+
+			ExpectedReporter: testHeadlessTaskProgressReporter{
+				Done:    true,
+				Success: false,
+			},
+		},
 		{
 			Desc:        "JSON object converted to plain text object",
 			Headless:    true,
@@ -266,6 +313,7 @@ func TestGetTask(t *testing.T) {
 		Name          string
 		Task          TaskConfig
 		IsHeadless    bool
+		HasDependents bool
 		ContentSource csapi.WorkspaceInitSource
 		Expectation   string
 	}{
@@ -294,11 +342,29 @@ func TestGetTask(t *testing.T) {
 			ContentSource: csapi.WorkspaceInitFromOther,
 			Expectation:   "{\nbefore\n} && {\ninit\n} && {\ncommand\n}",
 		},
+		{
+			Name: "restart",
+			Task: TaskConfig{
+				Before:  p("before"),
+				Init:    p("init"),
+				Command: p("command"),
+				Restart: p("on-failure"),
+			},
+			ContentSource: csapi.WorkspaceInitFromBackup,
+			Expectation:   "{\nbefore\n} && {\ncommand\n}; exit",
+		},
+		{
+			Name:          "with dependents",
+			Task:          allTasks,
+			HasDependents: true,
+			ContentSource: csapi.WorkspaceInitFromOther,
+			Expectation:   "{\nbefore\n} && {\ninit\n} && {\ncommand\n}; echo $? > //exit-code-0",
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
-			command := getCommand(&task{config: test.Task, TaskStatus: api.TaskStatus{Id: "0"}}, test.IsHeadless, test.ContentSource, "/")
+			command := getCommand(&task{config: test.Task, TaskStatus: api.TaskStatus{Id: "0"}, hasDependents: test.HasDependents}, test.IsHeadless, test.ContentSource, "/")
 			if diff := cmp.Diff(test.Expectation, command); diff != "" {
 				t.Errorf("unexpected getCommand() (-want +got):\n%s", diff)
 			}
@@ -306,6 +372,298 @@ func TestGetTask(t *testing.T) {
 	}
 }
 
+func TestTaskDependencies(t *testing.T) {
+	p := func(v string) *string { return &v }
+	type Expectation struct {
+		State     api.TaskState
+		DependsOn []string
+		Failed    bool
+	}
+	tests := []struct {
+		Name        string
+		Tasks       []TaskConfig
+		Expectation []Expectation
+	}{
+		{
+			Name: "no dependencies",
+			Tasks: []TaskConfig{
+				{Name: p("a"), Command: &skipCommand},
+				{Name: p("b"), Command: &skipCommand},
+			},
+			Expectation: []Expectation{
+				{State: api.TaskState_opening},
+				{State: api.TaskState_opening},
+			},
+		},
+		{
+			Name: "dependency chain",
+			Tasks: []TaskConfig{
+				{Name: p("a"), Command: &skipCommand, DependsOn: &[]string{"b"}},
+				{Name: p("b"), Command: &skipCommand, DependsOn: &[]string{"c"}},
+				{Name: p("c"), Command: &skipCommand},
+			},
+			Expectation: []Expectation{
+				{State: api.TaskState_waiting, DependsOn: []string{"1"}},
+				{State: api.TaskState_waiting, DependsOn: []string{"2"}},
+				{State: api.TaskState_opening},
+			},
+		},
+		{
+			Name: "unknown dependency",
+			Tasks: []TaskConfig{
+				{Name: p("a"), Command: &skipCommand, DependsOn: &[]string{"foo"}},
+				{Name: p("b"), Command: &skipCommand},
+			},
+			Expectation: []Expectation{
+				{State: api.TaskState_closed, Failed: true},
+				{State: api.TaskState_opening},
+			},
+		},
+		{
+			Name: "cycle",
+			Tasks: []TaskConfig{
+				{Name: p("a"), Command: &skipCommand, DependsOn: &[]string{"c"}},
+				{Name: p("b"), Command: &skipCommand, DependsOn: &[]string{"a"}},
+				{Name: p("c"), Command: &skipCommand, DependsOn: &[]string{"b"}},
+				{Name: p("d"), Command: &skipCommand},
+			},
+			Expectation: []Expectation{
+				{State: api.TaskState_closed, Failed: true},
+				{State: api.TaskState_closed, Failed: true},
+				{State: api.TaskState_closed, Failed: true},
+				{State: api.TaskState_opening},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gitpodTasks, err := json.Marshal(test.Tasks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var (
+				contentState = NewInMemoryContentState("")
+				taskManager  = newTasksManager(&Config{
+					WorkspaceConfig: WorkspaceConfig{
+						GitpodTasks: string(gitpodTasks),
+					},
+				}, nil, contentState, nil, nil, nil)
+			)
+			taskManager.storeLocation = t.TempDir()
+			contentState.MarkContentReady(csapi.WorkspaceInitFromOther)
+			taskManager.init(context.Background())
+
+			var act []Expectation
+			for _, task := range taskManager.tasks {
+				var failed bool
+				select {
+				case res := <-task.successChan:
+					failed = res.Failed()
+				default:
+				}
+				act = append(act, Expectation{
+					State:     task.State,
+					DependsOn: task.DependsOn,
+					Failed:    failed,
+				})
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected tasks (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffTasks(t *testing.T) {
+	p := func(v string) *string { return &v }
+	type Expectation struct {
+		Added   []string
+		Removed []string
+		Changed []string
+	}
+	tests := []struct {
+		Name        string
+		Current     []TaskConfig
+		Desired     []TaskConfig
+		Expectation Expectation
+	}{
+		{
+			Name: "unchanged",
+			Current: []TaskConfig{
+				{Name: p("a"), Init: p("yarn"), Command: p("yarn start")},
+				{Command: p("yarn watch")},
+			},
+			Desired: []TaskConfig{
+				{Name: p("a"), Init: p("yarn install"), Command: p("yarn start")},
+				{Command: p("yarn watch"), OpenMode: p("split-right")},
+			},
+		},
+		{
+			Name: "changed command and env",
+			Current: []TaskConfig{
+				{Name: p("a"), Command: p("yarn start")},
+				{Name: p("b"), Command: p("yarn watch")},
+				{Name: p("c"), Command: p("go run .")},
+			},
+			Desired: []TaskConfig{
+				{Name: p("a"), Command: p("yarn start:dev")},
+				{Name: p("b"), Before: p("export FOO=bar"), Command: p("yarn watch")},
+				{Name: p("c"), Command: p("go run ."), Env: &map[string]interface{}{"PORT": "8080"}},
+			},
+			Expectation: Expectation{
+				Changed: []string{"a", "b", "c"},
+			},
+		},
+		{
+			Name: "added and removed",
+			Current: []TaskConfig{
+				{Name: p("a"), Command: p("yarn start")},
+				{Name: p("b"), Command: p("yarn watch")},
+			},
+			Desired: []TaskConfig{
+				{Name: p("b"), Command: p("yarn watch")},
+				{Name: p("c"), Command: p("go run .")},
+				{Command: p("echo hello")},
+			},
+			Expectation: Expectation{
+				Added:   []string{"c", "Gitpod Task 3"},
+				Removed: []string{"a"},
+			},
+		},
+		{
+			Name: "plain terminals are kept",
+			Current: []TaskConfig{
+				{},
+			},
+			Desired: []TaskConfig{
+				{Name: p("a"), Command: p("yarn start")},
+			},
+			Expectation: Expectation{
+				Added: []string{"a"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var tasks []*task
+			for i, config := range test.Current {
+				tasks = append(tasks, &task{
+					TaskStatus: api.TaskStatus{Id: strconv.Itoa(i)},
+					config:     config,
+					title:      taskConfigName(config, i),
+				})
+			}
+
+			resp := diffTasks(tasks, test.Desired).toResponse()
+			act := Expectation{
+				Added:   resp.Added,
+				Removed: resp.Removed,
+				Changed: resp.Changed,
+			}
+			if diff := cmp.Diff(test.Expectation, act); diff != "" {
+				t.Errorf("unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReloadRemovedTasks(t *testing.T) {
+	p := func(v string) *string { return &v }
+	tm := newTasksManager(&Config{}, nil, nil, nil, nil, nil)
+	close(tm.ready)
+
+	// a removed task which still waits for its dependencies
+	removed := tm.newTask("0", TaskConfig{Name: p("a"), Command: p("yarn build"), DependsOn: &[]string{"c"}}, "a")
+	removed.State = api.TaskState_waiting
+	dependent := tm.newTask("1", TaskConfig{Name: p("b"), Command: p("yarn start")}, "b")
+	dependent.State = api.TaskState_waiting
+	dependent.dependencies = []*task{removed}
+	tm.tasks = []*task{removed, dependent}
+	tm.setTaskConfig([]TaskConfig{dependent.config})
+
+	diff, err := tm.Reload(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"a"}, diff.toResponse().Removed); diff != "" {
+		t.Errorf("unexpected removed tasks (-want +got):\n%s", diff)
+	}
+	if removed.State != api.TaskState_closed {
+		t.Errorf("expected removed task to be closed, got %s", removed.State)
+	}
+	select {
+	case <-removed.completed:
+	default:
+		t.Fatal("expected removed task to be completed")
+	}
+	if removed.result.Failed() {
+		t.Errorf("expected removed task not to fail its dependents, got %s", removed.result)
+	}
+	select {
+	case <-removed.successChan:
+	default:
+		t.Error("expected removed task to report its result")
+	}
+}
+
+func TestShouldRestartTask(t *testing.T) {
+	p := func(v string) *string { return &v }
+	exited := func(cmd string) *os.ProcessState {
+		c := exec.Command("sh", "-c", cmd)
+		_ = c.Run()
+		return c.ProcessState
+	}
+	maxRestarts := 2
+	tests := []struct {
+		Name         string
+		Config       TaskConfig
+		RestartCount uint32
+		Headless     bool
+		State        *os.ProcessState
+		Expectation  bool
+	}{
+		{Name: "no policy", Config: TaskConfig{}, State: exited("exit 1")},
+		{Name: "never", Config: TaskConfig{Restart: p("never")}, State: exited("exit 1")},
+		{Name: "on-failure with failure", Config: TaskConfig{Restart: p("on-failure")}, State: exited("exit 1"), Expectation: true},
+		{Name: "on-failure with success", Config: TaskConfig{Restart: p("on-failure")}, State: exited("exit 0")},
+		{Name: "always with success", Config: TaskConfig{Restart: p("always")}, State: exited("exit 0"), Expectation: true},
+		{Name: "killed", Config: TaskConfig{Restart: p("always")}, State: exited("kill -9 $$")},
+		{Name: "unknown state", Config: TaskConfig{Restart: p("always")}},
+		{Name: "default max restarts", Config: TaskConfig{Restart: p("always")}, RestartCount: defaultMaxTaskRestarts, State: exited("exit 1")},
+		{Name: "below max restarts", Config: TaskConfig{Restart: p("always"), MaxRestarts: &maxRestarts}, RestartCount: 1, State: exited("exit 1"), Expectation: true},
+		{Name: "max restarts", Config: TaskConfig{Restart: p("always"), MaxRestarts: &maxRestarts}, RestartCount: 2, State: exited("exit 1")},
+		{Name: "headless", Config: TaskConfig{Restart: p("always")}, Headless: true, State: exited("exit 1")},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			task := &task{config: test.Config, TaskStatus: api.TaskStatus{RestartCount: test.RestartCount}}
+			if act := shouldRestartTask(task, test.Headless, test.State); act != test.Expectation {
+				t.Errorf("unexpected shouldRestartTask(): want %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestTaskRestartBackoff(t *testing.T) {
+	tests := []struct {
+		RestartCount uint32
+		Expectation  time.Duration
+	}{
+		{RestartCount: 0, Expectation: 1 * time.Second},
+		{RestartCount: 1, Expectation: 2 * time.Second},
+		{RestartCount: 5, Expectation: 32 * time.Second},
+		{RestartCount: 6, Expectation: maxTaskRestartBackoff},
+		{RestartCount: 100, Expectation: maxTaskRestartBackoff},
+	}
+	for _, test := range tests {
+		if act := taskRestartBackoff(test.RestartCount); act != test.Expectation {
+			t.Errorf("unexpected taskRestartBackoff(%d): want %s, got %s", test.RestartCount, test.Expectation, act)
+		}
+	}
+}
+
 func TestTaskSuccess(t *testing.T) {
 	type Expectation struct {
 		Failed bool
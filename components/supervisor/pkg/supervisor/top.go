@@ -19,21 +19,114 @@ import (
 
 	linuxproc "github.com/c9s/goprocinfo/linux"
 	"github.com/gitpod-io/gitpod/common-go/log"
+	gitpod "github.com/gitpod-io/gitpod/gitpod-protocol"
 	"github.com/gitpod-io/gitpod/supervisor/api"
 	daemonapi "github.com/gitpod-io/gitpod/ws-daemon/api"
 )
 
+// resourcesHistorySize is the number of samples kept in the rolling resources history.
+const resourcesHistorySize = 300
+
 type TopService struct {
 	data      *api.ResourcesStatusResponse
 	ready     chan struct{}
 	readyOnce sync.Once
 	top       func(ctx context.Context) (*api.ResourcesStatusResponse, error)
+
+	// processes samples the per process tree usage, if nil no breakdown is provided
+	processes *processSampler
+
+	mu               sync.RWMutex
+	history          []*api.ResourcesStatusResponse
+	subscriptions    map[*resourcesSubscription]struct{}
+	cpuThresholds    resourceThresholds
+	memoryThresholds resourceThresholds
+}
+
+type resourcesSubscription struct {
+	updates chan *api.ResourcesStatusResponse
+	Close   func() error
+}
+
+func (sub *resourcesSubscription) Updates() <-chan *api.ResourcesStatusResponse {
+	return sub.updates
 }
 
 func NewTopService() *TopService {
 	log.Debug("gitpod top service: initialized")
 	return &TopService{
-		top: Top,
+		top:              Top,
+		ready:            make(chan struct{}),
+		subscriptions:    make(map[*resourcesSubscription]struct{}),
+		cpuThresholds:    defaultResourceThresholds,
+		memoryThresholds: defaultResourceThresholds,
+	}
+}
+
+// Subscribe returns a subscription to new resources samples. If history is true,
+// the samples of the rolling history are delivered first.
+func (t *TopService) Subscribe(history bool) *resourcesSubscription {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.subscriptions) > maxSubscriptions {
+		return nil
+	}
+
+	sub := &resourcesSubscription{updates: make(chan *api.ResourcesStatusResponse, resourcesHistorySize+5)}
+	sub.Close = func() error {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if _, ok := t.subscriptions[sub]; !ok {
+			return nil
+		}
+		// We can safely close the channel here even though we're not the
+		// producer writing to it, because we're holding mu.
+		close(sub.updates)
+		delete(t.subscriptions, sub)
+
+		return nil
+	}
+	t.subscriptions[sub] = struct{}{}
+
+	if history {
+		for _, data := range t.history {
+			sub.updates <- data
+		}
+	} else if len(t.history) > 0 {
+		sub.updates <- t.history[len(t.history)-1]
+	}
+	return sub
+}
+
+// History returns the samples of the rolling resources history, oldest first.
+func (t *TopService) History() []*api.ResourcesStatusResponse {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	res := make([]*api.ResourcesStatusResponse, len(t.history))
+	copy(res, t.history)
+	return res
+}
+
+func (t *TopService) publish(data *api.ResourcesStatusResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.history = append(t.history, data)
+	if len(t.history) > resourcesHistorySize {
+		t.history = t.history[len(t.history)-resourcesHistorySize:]
+	}
+
+	for sub := range t.subscriptions {
+		select {
+		case sub.updates <- data:
+		default:
+			log.Error("resources subscription droped out")
+			close(sub.updates)
+			delete(t.subscriptions, sub)
+		}
 	}
 }
 
@@ -46,8 +139,6 @@ func (t *TopService) Observe(ctx context.Context) {
 		maxReconnectionDelay        = 30 * time.Second
 	)
 
-	t.ready = make(chan struct{})
-
 	go func() {
 		for {
 			data, err := t.top(ctx)
@@ -55,7 +146,20 @@ func (t *TopService) Observe(ctx context.Context) {
 				log.WithField("error", err).Errorf("failed to retrieve resource status from upstream, trying again in %d seconds...", uint32(delay.Seconds()))
 			} else {
 				delay = minReconnectionDelay
+				if t.processes != nil {
+					sample, err := t.processes.Sample()
+					if err != nil {
+						log.WithError(err).Warn("failed to sample process resources")
+					} else {
+						data.Processes = sample.processes
+						data.DiskIo = sample.diskIO
+						data.Pids = resolvePIDsStatus(sample.pids)
+					}
+				}
+				t.applyThresholds(data)
+				data.Timestamp = time.Now().UnixMilli()
 				t.data = data
+				t.publish(data)
 
 				t.readyOnce.Do(func() {
 					close(t.ready)
@@ -75,17 +179,73 @@ func (t *TopService) Observe(ctx context.Context) {
 	}()
 }
 
-func calcSeverity(value int64) api.ResourceStatusSeverity {
+// resourceThresholds are the usage in percent at which a resource is considered under pressure.
+type resourceThresholds struct {
+	warning float64
+	danger  float64
+}
+
+var defaultResourceThresholds = resourceThresholds{warning: 80, danger: 95}
+
+// newResourceThresholds returns the configured thresholds, falling back to defaults for the ones which are not configured.
+func newResourceThresholds(config *gitpod.ResourceThresholds, defaults resourceThresholds) resourceThresholds {
+	res := defaults
+	if config == nil {
+		return res
+	}
+	if config.Warning > 0 {
+		res.warning = config.Warning
+	}
+	if config.Danger > 0 {
+		res.danger = config.Danger
+	}
+	return res
+}
+
+func (t resourceThresholds) severity(value float64) api.ResourceStatusSeverity {
 	switch {
-	case value >= 95:
+	case value >= t.danger:
 		return api.ResourceStatusSeverity_danger
-	case value >= 80:
+	case value >= t.warning:
 		return api.ResourceStatusSeverity_warning
 	default:
 		return api.ResourceStatusSeverity_normal
 	}
 }
 
+func calcSeverity(value int64) api.ResourceStatusSeverity {
+	return defaultResourceThresholds.severity(float64(value))
+}
+
+// usagePercentage returns the usage of a resource in percent of its limit.
+func usagePercentage(status *api.ResourceStatus) float64 {
+	if status == nil || status.Limit <= 0 {
+		return 0
+	}
+	return float64(status.Used) / float64(status.Limit) * 100
+}
+
+// SetThresholds configures the thresholds used to compute the severity of the CPU and memory usage.
+func (t *TopService) SetThresholds(cpu, memory resourceThresholds) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cpuThresholds = cpu
+	t.memoryThresholds = memory
+}
+
+func (t *TopService) applyThresholds(data *api.ResourcesStatusResponse) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if data.Cpu != nil && data.Cpu.Limit > 0 {
+		data.Cpu.Severity = t.cpuThresholds.severity(usagePercentage(data.Cpu))
+	}
+	if data.Memory != nil && data.Memory.Limit > 0 {
+		data.Memory.Severity = t.memoryThresholds.severity(usagePercentage(data.Memory))
+	}
+}
+
 // Top provides workspace resources status information.
 func Top(ctx context.Context) (*api.ResourcesStatusResponse, error) {
 	const socketFN = "/.supervisor/info.sock"
@@ -0,0 +1,297 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	linuxproc "github.com/c9s/goprocinfo/linux"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+// userHZ is the number of clock ticks per second used in /proc/<pid>/stat.
+const userHZ = 100
+
+// processSampler samples the resource usage of the process trees started by supervisor,
+// i.e. of each direct child of supervisor and all of its descendants.
+type processSampler struct {
+	procfs   string
+	rootPID  int
+	pageSize int64
+	now      func() time.Time
+
+	// terminals returns the PIDs of the terminal processes mapped to their alias
+	terminals func() map[int]string
+	// tasks returns the status of the workspace tasks
+	tasks func() []*api.TaskStatus
+
+	prev     map[int]processCounters
+	prevTime time.Time
+}
+
+type processCounters struct {
+	cpuTicks   uint64
+	readBytes  uint64
+	writeBytes uint64
+}
+
+type processInfo struct {
+	pid      int
+	ppid     int
+	comm     string
+	rss      int64
+	counters processCounters
+}
+
+type processesSample struct {
+	processes []*api.ProcessResourcesStatus
+	diskIO    *api.DiskIOStatus
+	pids      int64
+}
+
+func newProcessSampler(terminals func() map[int]string, tasks func() []*api.TaskStatus) *processSampler {
+	return &processSampler{
+		procfs:    "/proc",
+		rootPID:   os.Getpid(),
+		pageSize:  int64(os.Getpagesize()),
+		now:       time.Now,
+		terminals: terminals,
+		tasks:     tasks,
+	}
+}
+
+// Sample reads the current usage of all processes and attributes it to the process trees.
+// CPU and disk I/O are rates computed against the previous sample and are zero on the first sample.
+func (s *processSampler) Sample() (*processesSample, error) {
+	procs, err := s.readProcesses()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	elapsed := now.Sub(s.prevTime).Seconds()
+	if s.prev == nil || elapsed <= 0 {
+		elapsed = 0
+	}
+
+	var (
+		terminals = make(map[int]string)
+		tasks     = make(map[string]*api.TaskStatus)
+	)
+	if s.terminals != nil {
+		terminals = s.terminals()
+	}
+	if s.tasks != nil {
+		for _, task := range s.tasks() {
+			if task.Terminal != "" {
+				tasks[task.Terminal] = task
+			}
+		}
+	}
+
+	var (
+		res    = &processesSample{diskIO: &api.DiskIOStatus{}, pids: int64(len(procs))}
+		trees  = make(map[int]*api.ProcessResourcesStatus)
+		roots  = make(map[int]int, len(procs))
+		counts = make(map[int]processCounters, len(procs))
+	)
+	for pid, proc := range procs {
+		counts[pid] = proc.counters
+
+		var delta processCounters
+		if prev, ok := s.prev[pid]; ok && elapsed > 0 {
+			delta = processCounters{
+				cpuTicks:   saturatingSub(proc.counters.cpuTicks, prev.cpuTicks),
+				readBytes:  saturatingSub(proc.counters.readBytes, prev.readBytes),
+				writeBytes: saturatingSub(proc.counters.writeBytes, prev.writeBytes),
+			}
+		}
+		var (
+			cpu   int64
+			read  int64
+			write int64
+		)
+		if elapsed > 0 {
+			cpu = int64(float64(delta.cpuTicks) / userHZ / elapsed * 1000)
+			read = int64(float64(delta.readBytes) / elapsed)
+			write = int64(float64(delta.writeBytes) / elapsed)
+		}
+		res.diskIO.Read += read
+		res.diskIO.Write += write
+
+		root := s.findRoot(pid, procs, roots)
+		if root == 0 {
+			continue
+		}
+		tree, ok := trees[root]
+		if !ok {
+			tree = &api.ProcessResourcesStatus{
+				Pid:     int64(root),
+				Command: s.command(procs[root]),
+				DiskIo:  &api.DiskIOStatus{},
+			}
+			if alias, ok := terminals[root]; ok {
+				tree.Terminal = alias
+				if task, ok := tasks[alias]; ok {
+					tree.TaskId = task.Id
+					if task.Presentation != nil {
+						tree.TaskName = task.Presentation.Name
+					}
+				}
+			}
+			trees[root] = tree
+		}
+		tree.Cpu += cpu
+		tree.Memory += proc.rss
+		tree.DiskIo.Read += read
+		tree.DiskIo.Write += write
+		tree.Pids++
+	}
+	s.prev = counts
+	s.prevTime = now
+
+	res.processes = make([]*api.ProcessResourcesStatus, 0, len(trees))
+	for _, tree := range trees {
+		res.processes = append(res.processes, tree)
+	}
+	sort.Slice(res.processes, func(i, j int) bool {
+		a, b := res.processes[i], res.processes[j]
+		if a.Cpu != b.Cpu {
+			return a.Cpu > b.Cpu
+		}
+		if a.Memory != b.Memory {
+			return a.Memory > b.Memory
+		}
+		return a.Pid < b.Pid
+	})
+	return res, nil
+}
+
+// findRoot returns the PID of the direct child of supervisor the process descends from,
+// or 0 if the process is not a descendant of supervisor.
+func (s *processSampler) findRoot(pid int, procs map[int]*processInfo, roots map[int]int) int {
+	if root, ok := roots[pid]; ok {
+		return root
+	}
+	var (
+		path []int
+		root int
+		cur  = pid
+	)
+	for {
+		if r, ok := roots[cur]; ok {
+			root = r
+			break
+		}
+		proc, ok := procs[cur]
+		if !ok || cur == s.rootPID {
+			break
+		}
+		path = append(path, cur)
+		if proc.ppid == s.rootPID {
+			root = cur
+			break
+		}
+		if len(path) > len(procs) {
+			// guards against cycles caused by PIDs being reused while reading procfs
+			break
+		}
+		cur = proc.ppid
+	}
+	for _, p := range path {
+		roots[p] = root
+	}
+	return root
+}
+
+func (s *processSampler) command(proc *processInfo) string {
+	if proc == nil {
+		return ""
+	}
+	cmdline, err := linuxproc.ReadProcessCmdline(filepath.Join(s.procfs, strconv.Itoa(proc.pid), "cmdline"))
+	if err == nil && cmdline != "" {
+		return cmdline
+	}
+	return proc.comm
+}
+
+func (s *processSampler) readProcesses() (map[int]*processInfo, error) {
+	entries, err := ioutil.ReadDir(s.procfs)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read %s: %w", s.procfs, err)
+	}
+	res := make(map[int]*processInfo, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.procfs, entry.Name())
+		// processes can exit at any time while we read procfs, hence we skip them if we fail to read their stats
+		stat, err := readProcessStat(filepath.Join(dir, "stat"))
+		if err != nil {
+			continue
+		}
+		proc := &processInfo{
+			pid:  pid,
+			ppid: int(stat.Ppid),
+			comm: strings.TrimSuffix(strings.TrimPrefix(stat.Comm, "("), ")"),
+			rss:  stat.Rss * s.pageSize,
+			counters: processCounters{
+				cpuTicks: stat.Utime + stat.Stime,
+			},
+		}
+		if io, err := linuxproc.ReadProcessIO(filepath.Join(dir, "io")); err == nil {
+			proc.counters.readBytes = io.ReadBytes
+			proc.counters.writeBytes = io.WriteBytes
+		}
+		res[pid] = proc
+	}
+	return res, nil
+}
+
+func readProcessStat(fn string) (stat *linuxproc.ProcessStat, err error) {
+	defer func() {
+		// ReadProcessStat panics if the stat file is malformed, e.g. because it was truncated
+		if r := recover(); r != nil {
+			err = xerrors.Errorf("failed to parse %s: %v", fn, r)
+		}
+	}()
+	return linuxproc.ReadProcessStat(fn)
+}
+
+func saturatingSub(a, b uint64) uint64 {
+	if a < b {
+		return 0
+	}
+	return a - b
+}
+
+// resolvePIDsStatus returns the number of processes and the limit of the pids cgroup.
+// If the cgroup is not available the number of processes visible in procfs is used.
+func resolvePIDsStatus(visible int64) *api.ResourceStatus {
+	res := &api.ResourceStatus{Used: visible}
+	if content, err := ioutil.ReadFile("/sys/fs/cgroup/pids/pids.current"); err == nil {
+		if current, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64); err == nil {
+			res.Used = current
+		}
+	}
+	if content, err := ioutil.ReadFile("/sys/fs/cgroup/pids/pids.max"); err == nil {
+		if limit, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64); err == nil {
+			res.Limit = limit
+		}
+	}
+	if res.Limit > 0 {
+		res.Severity = calcSeverity(int64(float64(res.Used) / float64(res.Limit) * 100))
+	}
+	return res
+}
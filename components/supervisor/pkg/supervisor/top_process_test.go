@@ -0,0 +1,128 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+type fakeProcess struct {
+	pid     int
+	ppid    int
+	comm    string
+	cmdline string
+	ticks   uint64
+	rss     int64
+	read    uint64
+	write   uint64
+}
+
+func writeFakeProcfs(t *testing.T, procfs string, procs []fakeProcess) {
+	if err := os.RemoveAll(procfs); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range procs {
+		dir := filepath.Join(procfs, fmt.Sprint(p.pid))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		stat := fmt.Sprintf("%d (%s) S %d 0 0 0 -1 0 0 0 0 0 %d 0 0 0 20 0 1 0 0 0 %d 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n", p.pid, p.comm, p.ppid, p.ticks, p.rss)
+		io := fmt.Sprintf("rchar: 0\nwchar: 0\nsyscr: 0\nsyscw: 0\nread_bytes: %d\nwrite_bytes: %d\ncancelled_write_bytes: 0\n", p.read, p.write)
+		files := map[string]string{
+			"stat":    stat,
+			"io":      io,
+			"cmdline": p.cmdline + "\x00",
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestProcessSampler(t *testing.T) {
+	procfs := filepath.Join(t.TempDir(), "proc")
+	now := time.Unix(0, 0)
+	sampler := &processSampler{
+		procfs:   procfs,
+		rootPID:  10,
+		pageSize: 4096,
+		now:      func() time.Time { return now },
+		terminals: func() map[int]string {
+			return map[int]string{20: "term-1"}
+		},
+		tasks: func() []*api.TaskStatus {
+			return []*api.TaskStatus{{Id: "0", Terminal: "term-1", Presentation: &api.TaskPresentation{Name: "watch"}}}
+		},
+	}
+
+	procs := []fakeProcess{
+		{pid: 1, ppid: 0, comm: "supervisor", cmdline: "supervisor init"},
+		{pid: 10, ppid: 1, comm: "supervisor", cmdline: "supervisor run"},
+		{pid: 20, ppid: 10, comm: "bash", cmdline: "/bin/bash", rss: 1},
+		{pid: 21, ppid: 20, comm: "node", cmdline: "node watch.js", ticks: 100, rss: 2, read: 1000, write: 2000},
+		{pid: 30, ppid: 10, comm: "code", cmdline: "code-server --port 23000", ticks: 50, rss: 4},
+	}
+	writeFakeProcfs(t, procfs, procs)
+	first, err := sampler.Sample()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range first.processes {
+		if p.Cpu != 0 || p.DiskIo.Read != 0 || p.DiskIo.Write != 0 {
+			t.Errorf("first sample should not report rates: %v", p)
+		}
+	}
+
+	now = now.Add(2 * time.Second)
+	procs[3].ticks += 100
+	procs[3].read += 4000
+	procs[3].write += 8000
+	procs[4].ticks += 20
+	writeFakeProcfs(t, procfs, procs)
+	second, err := sampler.Sample()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectation := &processesSample{
+		processes: []*api.ProcessResourcesStatus{
+			{
+				Pid:      20,
+				Command:  "/bin/bash",
+				Terminal: "term-1",
+				TaskId:   "0",
+				TaskName: "watch",
+				Cpu:      500,
+				Memory:   3 * 4096,
+				DiskIo:   &api.DiskIOStatus{Read: 2000, Write: 4000},
+				Pids:     2,
+			},
+			{
+				Pid:     30,
+				Command: "code-server --port 23000",
+				Cpu:     100,
+				Memory:  4 * 4096,
+				DiskIo:  &api.DiskIOStatus{},
+				Pids:    1,
+			},
+		},
+		diskIO: &api.DiskIOStatus{Read: 2000, Write: 4000},
+		pids:   5,
+	}
+	if diff := cmp.Diff(expectation, second, cmp.AllowUnexported(processesSample{}), protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected sample (-want +got):\n%s", diff)
+	}
+}
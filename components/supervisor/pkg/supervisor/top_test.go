@@ -86,3 +86,43 @@ func TestTopServiceWithUpstreamFailure(t *testing.T) {
 		t.Errorf("Total Cpu should be 5")
 	}
 }
+
+func TestTopServiceHistory(t *testing.T) {
+	var samples int64
+	topService := NewTopService()
+	topService.top = func(ctx context.Context) (*api.ResourcesStatusResponse, error) {
+		samples++
+		return &api.ResourcesStatusResponse{
+			Memory: &api.ResourceStatus{Used: samples},
+			Cpu:    &api.ResourceStatus{Used: samples},
+		}, nil
+	}
+	for i := 0; i < resourcesHistorySize+2; i++ {
+		data, _ := topService.top(context.Background())
+		topService.publish(data)
+	}
+
+	history := topService.History()
+	if len(history) != resourcesHistorySize {
+		t.Fatalf("history should contain %d samples, got %d", resourcesHistorySize, len(history))
+	}
+	if history[0].Cpu.Used != 3 {
+		t.Errorf("oldest sample should be 3, got %d", history[0].Cpu.Used)
+	}
+
+	sub := topService.Subscribe(true)
+	defer sub.Close()
+	if len(sub.Updates()) != resourcesHistorySize {
+		t.Errorf("subscription should receive the history first, got %d samples", len(sub.Updates()))
+	}
+
+	live := topService.Subscribe(false)
+	defer live.Close()
+	if latest := <-live.Updates(); latest.Cpu.Used != samples {
+		t.Errorf("subscription should receive the latest sample first, got %d", latest.Cpu.Used)
+	}
+	topService.publish(&api.ResourcesStatusResponse{Cpu: &api.ResourceStatus{Used: 42}})
+	if update := <-live.Updates(); update.Cpu.Used != 42 {
+		t.Errorf("subscription should receive new samples, got %d", update.Cpu.Used)
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+const (
+	// DefaultHistoryMaxFileSize is the size in bytes after which a history file is compressed and rotated.
+	DefaultHistoryMaxFileSize = 1 << 20
+	// DefaultHistoryMaxFiles is the number of rotated history files kept per terminal.
+	DefaultHistoryMaxFiles = 16
+	// DefaultHistoryMaxClosedTerminals is the number of closed terminals whose history is kept.
+	DefaultHistoryMaxClosedTerminals = 16
+
+	// historyIndexInterval is the minimal time between two entries of the time index of a history.
+	historyIndexInterval = 1 * time.Second
+)
+
+// HistoryOptions configures the persistent output history of terminals.
+type HistoryOptions struct {
+	// Location is the directory in which the history of each terminal is stored in a sub directory named after its alias.
+	Location string
+
+	// MaxFileSize is the size in bytes after which a history file is compressed and rotated.
+	// Use 0 for DefaultHistoryMaxFileSize.
+	MaxFileSize int64
+
+	// MaxFiles is the number of rotated history files kept per terminal. Older output is dropped.
+	// Use 0 for DefaultHistoryMaxFiles.
+	MaxFiles int
+
+	// MaxClosedTerminals is the number of closed terminals whose history is kept. The history of terminals
+	// closed before is removed. Use 0 for DefaultHistoryMaxClosedTerminals.
+	MaxClosedTerminals int
+}
+
+// History persists the output of a terminal in rotating, compressed files.
+// Positions in the output are addressed by their offset from the very first byte the terminal wrote.
+type History struct {
+	dir         string
+	maxFileSize int64
+	maxFiles    int
+
+	mu      sync.RWMutex
+	current *os.File
+	// start is the offset of the first byte of the current file
+	start int64
+	// size is the number of bytes written to the current file
+	size int64
+	// segments are the rotated files, oldest first
+	segments []historySegment
+	index    []historyIndexEntry
+	changed  chan struct{}
+	closed   bool
+	failed   bool
+
+	// compressions are the rotated files which are being compressed
+	compressions sync.WaitGroup
+}
+
+type historySegment struct {
+	start      int64
+	size       int64
+	compressed bool
+}
+
+type historyIndexEntry struct {
+	time   time.Time
+	offset int64
+}
+
+// newHistory creates a new history in dir, removing any previous content.
+func newHistory(dir string, options HistoryOptions) (*History, error) {
+	h := &History{
+		dir:         dir,
+		maxFileSize: options.MaxFileSize,
+		maxFiles:    options.MaxFiles,
+		changed:     make(chan struct{}),
+	}
+	if h.maxFileSize <= 0 {
+		h.maxFileSize = DefaultHistoryMaxFileSize
+	}
+	if h.maxFiles <= 0 {
+		h.maxFiles = DefaultHistoryMaxFiles
+	}
+
+	err := os.RemoveAll(dir)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot clear terminal history: %w", err)
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create terminal history: %w", err)
+	}
+	h.current, err = os.Create(h.segmentFile(0, false))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create terminal history: %w", err)
+	}
+	return h, nil
+}
+
+func (h *History) segmentFile(start int64, compressed bool) string {
+	fn := filepath.Join(h.dir, fmt.Sprintf("%020d.log", start))
+	if compressed {
+		fn += ".gz"
+	}
+	return fn
+}
+
+// Write appends p to the history. Failures are logged and stop the history, but never fail the write
+// s.t. a broken history does not affect the terminal.
+func (h *History) Write(p []byte) (n int, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed || h.failed {
+		return len(p), nil
+	}
+
+	now := time.Now()
+	if len(h.index) == 0 || now.Sub(h.index[len(h.index)-1].time) >= historyIndexInterval {
+		h.index = append(h.index, historyIndexEntry{time: now, offset: h.start + h.size})
+	}
+
+	n, err = h.current.Write(p)
+	h.size += int64(n)
+	if err == nil && h.size >= h.maxFileSize {
+		err = h.rotate()
+	}
+	if err != nil {
+		log.WithError(err).WithField("dir", h.dir).Warn("cannot write terminal history, stopping it")
+		h.failed = true
+	}
+
+	close(h.changed)
+	h.changed = make(chan struct{})
+	return len(p), nil
+}
+
+// rotate starts a new file and compresses the current one in the background, s.t. writes do not wait for it.
+// Callers are expected to hold mu.
+func (h *History) rotate() error {
+	err := h.current.Close()
+	if err != nil {
+		return err
+	}
+	seg := historySegment{start: h.start, size: h.size}
+	h.segments = append(h.segments, seg)
+
+	for len(h.segments) > h.maxFiles {
+		_ = os.Remove(h.segmentFile(h.segments[0].start, false))
+		_ = os.Remove(h.segmentFile(h.segments[0].start, true))
+		h.segments = h.segments[1:]
+	}
+	first := h.segments[0].start
+	for len(h.index) > 0 && h.index[0].offset < first {
+		h.index = h.index[1:]
+	}
+
+	h.start += h.size
+	h.size = 0
+	h.current, err = os.Create(h.segmentFile(h.start, false))
+	if err != nil {
+		return err
+	}
+
+	h.compressions.Add(1)
+	go func() {
+		defer h.compressions.Done()
+		h.compressSegment(seg.start)
+	}()
+	return nil
+}
+
+// compressSegment compresses a rotated file. Until it is compressed, readers use the uncompressed file.
+func (h *History) compressSegment(start int64) {
+	src, dst := h.segmentFile(start, false), h.segmentFile(start, true)
+	err := compressFile(src, dst)
+	if err != nil {
+		log.WithError(err).WithField("dir", h.dir).Warn("cannot compress terminal history, keeping it uncompressed")
+		_ = os.Remove(dst)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range h.segments {
+		if h.segments[i].start == start {
+			h.segments[i].compressed = true
+			_ = os.Remove(src)
+			return
+		}
+	}
+	// the segment has been dropped while it was compressed
+	_ = os.Remove(dst)
+}
+
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer out.Close()
+
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if err != nil {
+		return err
+	}
+	err = zw.Close()
+	if err != nil {
+		return err
+	}
+	err = out.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// Close stops the history once all rotated files are compressed. The persisted output remains readable.
+func (h *History) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	close(h.changed)
+	err := h.current.Close()
+	h.mu.Unlock()
+
+	h.compressions.Wait()
+	return err
+}
+
+// Remove closes the history and removes the persisted output.
+func (h *History) Remove() error {
+	_ = h.Close()
+	return os.RemoveAll(h.dir)
+}
+
+// Bounds returns the offsets of the first available and the next to be written byte.
+func (h *History) Bounds() (start, end int64) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	start = h.start
+	if len(h.segments) > 0 {
+		start = h.segments[0].start
+	}
+	return start, h.start + h.size
+}
+
+// OffsetAt returns the offset of the first output which has been written at or after t.
+func (h *History) OffsetAt(t time.Time) int64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	idx := sort.Search(len(h.index), func(i int) bool {
+		return !h.index[i].time.Before(t)
+	})
+	if idx < len(h.index) {
+		return h.index[idx].offset
+	}
+	return h.start + h.size
+}
+
+// Changed returns a channel which is closed once new output has been written or the history has been closed.
+func (h *History) Changed() (changed <-chan struct{}, closed bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.changed, h.closed
+}
+
+// ReadRange copies the output in [offset, offset+limit) to w, or up to the current end if limit is 0.
+// Offsets before the first available byte are moved to the first available byte.
+// It returns the offset following the last copied byte.
+func (h *History) ReadRange(offset, limit int64, w io.Writer) (next int64, err error) {
+	h.mu.RLock()
+	segments := append([]historySegment{}, h.segments...)
+	segments = append(segments, historySegment{start: h.start, size: h.size})
+	h.mu.RUnlock()
+
+	if offset < segments[0].start {
+		offset = segments[0].start
+	}
+	end := segments[len(segments)-1].start + segments[len(segments)-1].size
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	for _, seg := range segments {
+		if offset >= end {
+			break
+		}
+		if offset >= seg.start+seg.size {
+			continue
+		}
+
+		var n int64
+		n, err = h.readSegment(seg, offset, end, w)
+		offset += n
+		if err != nil {
+			return offset, err
+		}
+	}
+	return offset, nil
+}
+
+func (h *History) readSegment(seg historySegment, offset, end int64, w io.Writer) (int64, error) {
+	compressed := seg.compressed
+	f, err := os.Open(h.segmentFile(seg.start, compressed))
+	if os.IsNotExist(err) && !compressed {
+		// the segment has been compressed in the meantime
+		compressed = true
+		f, err = os.Open(h.segmentFile(seg.start, compressed))
+	}
+	if os.IsNotExist(err) {
+		// the segment has been dropped in the meantime
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if compressed {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	_, err = io.CopyN(io.Discard, r, offset-seg.start)
+	if err != nil {
+		return 0, err
+	}
+	remaining := seg.start + seg.size - offset
+	if end-offset < remaining {
+		remaining = end - offset
+	}
+	return io.CopyN(w, r, remaining)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/gitpod-io/gitpod/supervisor/api"
+)
+
+func TestHistory(t *testing.T) {
+	dir := t.TempDir()
+	history, err := newHistory(dir, HistoryOptions{MaxFileSize: 10, MaxFiles: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var written bytes.Buffer
+	for i := 0; i < 10; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		written.WriteString(line)
+		_, _ = history.Write([]byte(line))
+	}
+	err = history.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	// every second line fills a file, only the last two rotated files are kept
+	expectedNames := []string{
+		"00000000000000000042.log.gz",
+		"00000000000000000056.log.gz",
+		"00000000000000000070.log",
+	}
+	if diff := cmp.Diff(expectedNames, names); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+
+	start, end := history.Bounds()
+	if start != 42 || end != 70 {
+		t.Errorf("unexpected bounds: want [42, 70), got [%d, %d)", start, end)
+	}
+
+	tests := []struct {
+		Desc        string
+		Offset      int64
+		Limit       int64
+		Expectation string
+	}{
+		{Desc: "all", Expectation: written.String()[42:]},
+		{Desc: "within file", Offset: 49, Limit: 6, Expectation: "line 7"},
+		{Desc: "across files", Offset: 49, Limit: 14, Expectation: "line 7\nline 8\n"},
+		{Desc: "dropped offset", Offset: 7, Limit: 7, Expectation: "line 6\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			var out bytes.Buffer
+			_, err := history.ReadRange(test.Offset, test.Limit, &out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.Expectation, out.String()); diff != "" {
+				t.Errorf("unexpected output (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHistoryResponseWriterGrep(t *testing.T) {
+	var (
+		data    []string
+		offsets []int64
+	)
+	w := &historyResponseWriter{
+		offset: 100,
+		grep:   []byte("error"),
+		send: func(resp *api.ReadTerminalHistoryResponse) error {
+			data = append(data, string(resp.Data))
+			offsets = append(offsets, resp.Offset)
+			return nil
+		},
+	}
+	for _, chunk := range []string{"ok\nan err", "or here\nfine\n", "last error"} {
+		_, err := w.Write([]byte(chunk))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(data) != 1 {
+		t.Fatalf("expected partial lines to be buffered, got %q", data)
+	}
+	err := w.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"an error here\n", "last error"}, data); diff != "" {
+		t.Errorf("unexpected lines (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{103, 122}, offsets); diff != "" {
+		t.Errorf("unexpected offsets (-want +got):\n%s", diff)
+	}
+}
+
+func TestHistoryPruning(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		mux     = NewMux()
+		options = &HistoryOptions{Location: dir, MaxClosedTerminals: 1}
+		aliases []string
+	)
+	for i := 0; i < 3; i++ {
+		alias, err := mux.Start(exec.Command("/bin/true"), TermOptions{History: options})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if term, ok := mux.Get(alias); ok {
+			<-term.waitDone
+		}
+		err = mux.CloseTerminal(context.Background(), alias)
+		if err != nil && err != ErrNotFound {
+			t.Fatal(err)
+		}
+		aliases = append(aliases, alias)
+	}
+	running, err := mux.Start(exec.Command("/bin/sleep", "10"), TermOptions{History: options})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = mux.CloseTerminal(context.Background(), running)
+	}()
+
+	for i, alias := range aliases {
+		_, ok := mux.History(alias)
+		if expected := i == len(aliases)-1; ok != expected {
+			t.Errorf("history of terminal %d: expected available=%v, got %v", i, expected, ok)
+		}
+	}
+}
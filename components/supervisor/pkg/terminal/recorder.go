@@ -0,0 +1,110 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/creack/pty"
+)
+
+// asciicastHeader is the first line of an asciicast v2 file,
+// see https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// asciicastRecorder writes a terminal session as asciicast v2.
+type asciicastRecorder struct {
+	w     io.WriteCloser
+	start time.Time
+	// pending holds an incomplete UTF-8 sequence at the end of the last output
+	pending []byte
+}
+
+func newAsciicastRecorder(w io.WriteCloser, size *pty.Winsize, title string, env map[string]string) (*asciicastRecorder, error) {
+	rec := &asciicastRecorder{
+		w:     w,
+		start: time.Now(),
+	}
+	header := asciicastHeader{
+		Version:   2,
+		Timestamp: rec.start.Unix(),
+		Title:     title,
+		Env:       env,
+	}
+	if size != nil {
+		header.Width = size.Cols
+		header.Height = size.Rows
+	}
+	line, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", line)
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Output records output written to the terminal.
+func (rec *asciicastRecorder) Output(p []byte) error {
+	data := append(rec.pending, p...)
+	rec.pending = nil
+
+	// asciicast events are JSON strings, hence we must not split multi-byte characters across events
+	end := len(data)
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				end = len(data) - i
+			}
+			break
+		}
+	}
+	rec.pending = append(rec.pending, data[end:]...)
+	if end == 0 {
+		return nil
+	}
+	return rec.event("o", string(data[:end]))
+}
+
+// Resize records a change of the terminal size.
+func (rec *asciicastRecorder) Resize(size *pty.Winsize) error {
+	return rec.event("r", fmt.Sprintf("%dx%d", size.Cols, size.Rows))
+}
+
+func (rec *asciicastRecorder) event(code, data string) error {
+	line, err := json.Marshal([]interface{}{time.Since(rec.start).Seconds(), code, data})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(rec.w, "%s\n", line)
+	return err
+}
+
+// Close writes any pending output and closes the underlying writer.
+func (rec *asciicastRecorder) Close() error {
+	var err error
+	if len(rec.pending) > 0 {
+		err = rec.event("o", string(rec.pending))
+		rec.pending = nil
+	}
+	cerr := rec.w.Close()
+	if err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package terminal
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/creack/pty"
+	"github.com/google/go-cmp/cmp"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestAsciicastRecorder(t *testing.T) {
+	var out bytes.Buffer
+	rec, err := newAsciicastRecorder(nopWriteCloser{&out}, &pty.Winsize{Cols: 80, Rows: 24}, "task", map[string]string{"TERM": "xterm-256color"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	euro := []byte("€")
+	for _, p := range [][]byte{
+		[]byte("hello "),
+		// a multi-byte character split across two writes
+		euro[:1],
+		append(euro[1:], '\n'),
+	} {
+		err = rec.Output(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = rec.Resize(&pty.Winsize{Cols: 120, Rows: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rec.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and three events, got %q", lines)
+	}
+
+	var header asciicastHeader
+	err = json.Unmarshal([]byte(lines[0]), &header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header.Timestamp = 0
+	if diff := cmp.Diff(asciicastHeader{Version: 2, Width: 80, Height: 24, Title: "task", Env: map[string]string{"TERM": "xterm-256color"}}, header); diff != "" {
+		t.Errorf("unexpected header (-want +got):\n%s", diff)
+	}
+
+	var events [][]string
+	for _, line := range lines[1:] {
+		var event []interface{}
+		err = json.Unmarshal([]byte(line), &event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := event[0].(float64); !ok {
+			t.Errorf("expected the event time to be a number: %s", line)
+		}
+		events = append(events, []string{event[1].(string), event[2].(string)})
+	}
+	expectedEvents := [][]string{
+		{"o", "hello "},
+		{"o", "€\n"},
+		{"r", "120x40"},
+	}
+	if diff := cmp.Diff(expectedEvents, events); diff != "" {
+		t.Errorf("unexpected events (-want +got):\n%s", diff)
+	}
+}
+
+func TestRecordingPath(t *testing.T) {
+	srv := &MuxTerminalService{RecordingLocation: "/workspace/.gitpod/recordings"}
+	tests := []struct {
+		Name        string
+		Path        string
+		Expectation string
+	}{
+		{Name: "file name", Path: "demo.cast", Expectation: "/workspace/.gitpod/recordings/demo.cast"},
+		{Name: "absolute path in recording location", Path: "/workspace/.gitpod/recordings/demo.cast", Expectation: "/workspace/.gitpod/recordings/demo.cast"},
+		{Name: "absolute path outside of recording location", Path: "/etc/shadow"},
+		{Name: "nested absolute path", Path: "/workspace/.gitpod/recordings/sub/demo.cast"},
+		{Name: "escaping absolute path", Path: "/workspace/.gitpod/recordings/../../../etc/shadow"},
+		{Name: "relative path", Path: "../../etc/shadow"},
+		{Name: "parent", Path: ".."},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := srv.recordingPath("alias", test.Path)
+			if test.Expectation == "" {
+				if err == nil {
+					t.Errorf("expected %s to be rejected, got %s", test.Path, act)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act != test.Expectation {
+				t.Errorf("unexpected path: want %s, got %s", test.Expectation, act)
+			}
+		})
+	}
+
+	if _, err := (&MuxTerminalService{}).recordingPath("alias", ""); err == nil {
+		t.Error("expected recordings to be rejected without a recording location")
+	}
+}
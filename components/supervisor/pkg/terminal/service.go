@@ -5,12 +5,17 @@
 package terminal
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -50,11 +55,31 @@ type MuxTerminalService struct {
 
 	DefaultShell string
 	Env          []string
+	// EnvProvider allows dynamically to compute the environment
+	// if nil then Env is used
+	EnvProvider  func() []string
 	DefaultCreds *syscall.Credential
 
+	// History persists the output of all terminals opened by this service if not nil
+	History *HistoryOptions
+	// RecordingLocation is the directory recordings are written to, no recordings can be made if empty
+	RecordingLocation string
+
+	// lastInput is the time of the last write to a terminal in unix nanoseconds
+	lastInput int64
+
 	api.UnimplementedTerminalServiceServer
 }
 
+// LastInput returns the time of the last write to a terminal, zero if there was none.
+func (srv *MuxTerminalService) LastInput() time.Time {
+	t := atomic.LoadInt64(&srv.lastInput)
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, t)
+}
+
 // RegisterGRPC registers a gRPC service.
 func (srv *MuxTerminalService) RegisterGRPC(s *grpc.Server) {
 	api.RegisterTerminalServiceServer(s, srv)
@@ -94,13 +119,20 @@ func (srv *MuxTerminalService) OpenWithOptions(ctx context.Context, req *api.Ope
 	if cmd.Dir == "" {
 		cmd.Dir = srv.DefaultWorkdir
 	}
-	cmd.Env = append(srv.Env, "TERM=xterm-256color")
+	env := srv.Env
+	if srv.EnvProvider != nil {
+		env = srv.EnvProvider()
+	}
+	cmd.Env = append(env, "TERM=xterm-256color")
 	for key, value := range req.Env {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%v=%v", key, value))
 	}
 	for k, v := range req.Annotations {
 		options.Annotations[k] = v
 	}
+	if options.History == nil {
+		options.History = srv.History
+	}
 	if req.Size != nil {
 		options.Size = &pty.Winsize{
 			Cols: uint16(req.Size.Cols),
@@ -298,6 +330,7 @@ func (srv *MuxTerminalService) Write(ctx context.Context, req *api.WriteTerminal
 		return nil, status.Error(codes.NotFound, "terminal not found")
 	}
 
+	atomic.StoreInt64(&srv.lastInput, time.Now().UnixNano())
 	n, err := term.PTY.Write(req.Stdin)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
@@ -329,6 +362,10 @@ func (srv *MuxTerminalService) SetSize(ctx context.Context, req *api.SetTerminal
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	term.recordResize(&pty.Winsize{
+		Cols: uint16(req.Size.Cols),
+		Rows: uint16(req.Size.Rows),
+	})
 
 	return &api.SetTerminalSizeResponse{}, nil
 }
@@ -356,3 +393,206 @@ func (srv *MuxTerminalService) UpdateAnnotations(ctx context.Context, req *api.U
 	term.UpdateAnnotations(req.Changed, req.Deleted)
 	return &api.UpdateTerminalAnnotationsResponse{}, nil
 }
+
+// StartRecording starts recording a terminal session.
+func (srv *MuxTerminalService) StartRecording(ctx context.Context, req *api.StartTerminalRecordingRequest) (*api.StartTerminalRecordingResponse, error) {
+	srv.Mux.mu.RLock()
+	term, ok := srv.Mux.terms[req.Alias]
+	srv.Mux.mu.RUnlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "terminal not found")
+	}
+
+	path, err := srv.recordingPath(req.Alias, req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = term.StartRecording(path, srv.DefaultCreds)
+	if err == ErrNotFound {
+		return nil, status.Error(codes.NotFound, "terminal not found")
+	}
+	if err == ErrAlreadyRecording {
+		return nil, status.Error(codes.FailedPrecondition, "terminal is already being recorded")
+	}
+	if errors.Is(err, fs.ErrExist) {
+		return nil, status.Errorf(codes.AlreadyExists, "recording %s exists already", path)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.WithField("alias", req.Alias).WithField("path", path).Info("started terminal recording")
+	return &api.StartTerminalRecordingResponse{Path: path}, nil
+}
+
+// recordingPath returns the path of a recording in the recording location. Recordings are confined to that
+// location, as they are created by supervisor: name is either a file name or a path directly in the location.
+func (srv *MuxTerminalService) recordingPath(alias, name string) (string, error) {
+	if srv.RecordingLocation == "" {
+		return "", status.Error(codes.FailedPrecondition, "recordings are not supported")
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s-%d.cast", alias, time.Now().Unix())
+	}
+	if filepath.IsAbs(name) {
+		if filepath.Dir(name) != filepath.Clean(srv.RecordingLocation) {
+			return "", status.Errorf(codes.InvalidArgument, "recordings must be located in %s", srv.RecordingLocation)
+		}
+		name = filepath.Base(name)
+	}
+	if name == "." || name == ".." || strings.ContainsRune(name, os.PathSeparator) {
+		return "", status.Errorf(codes.InvalidArgument, "invalid recording name %q: must be a file name", name)
+	}
+	return filepath.Join(srv.RecordingLocation, name), nil
+}
+
+// StopRecording stops recording a terminal session.
+func (srv *MuxTerminalService) StopRecording(ctx context.Context, req *api.StopTerminalRecordingRequest) (*api.StopTerminalRecordingResponse, error) {
+	srv.Mux.mu.RLock()
+	term, ok := srv.Mux.terms[req.Alias]
+	srv.Mux.mu.RUnlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "terminal not found")
+	}
+
+	path, err := term.StopRecording()
+	if err == ErrNotRecording {
+		return nil, status.Error(codes.FailedPrecondition, "terminal is not being recorded")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.WithField("alias", req.Alias).WithField("path", path).Info("stopped terminal recording")
+	return &api.StopTerminalRecordingResponse{Path: path}, nil
+}
+
+// ReadHistory reads the persisted output history of a terminal.
+func (srv *MuxTerminalService) ReadHistory(req *api.ReadTerminalHistoryRequest, resp api.TerminalService_ReadHistoryServer) error {
+	history, ok := srv.Mux.History(req.Alias)
+	if !ok {
+		return status.Error(codes.NotFound, "terminal history not found")
+	}
+
+	start, end := history.Bounds()
+	offset := req.Offset
+	if req.Since != nil {
+		offset = history.OffsetAt(req.Since.AsTime())
+	} else if offset < 0 {
+		offset += end
+	}
+	if offset < start {
+		offset = start
+	}
+
+	w := &historyResponseWriter{
+		offset: offset,
+		send:   resp.Send,
+	}
+	if req.Grep != "" {
+		w.grep = []byte(req.Grep)
+	}
+
+	limit := req.Limit
+	for {
+		changed, closed := history.Changed()
+
+		if start, _ := history.Bounds(); offset < start {
+			// the output we were about to read has been rotated away in the meantime
+			offset = start
+			w.Reset(offset)
+		}
+		next, err := history.ReadRange(offset, limit, w)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if limit > 0 {
+			limit -= next - offset
+			if limit <= 0 {
+				break
+			}
+		}
+		offset = next
+
+		if !req.Follow || closed {
+			break
+		}
+		select {
+		case <-changed:
+		case <-resp.Context().Done():
+			return status.Error(codes.DeadlineExceeded, resp.Context().Err().Error())
+		}
+	}
+
+	err := w.Flush()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// historyResponseWriter sends terminal history as ReadHistory responses,
+// optionally filtered to the lines containing grep.
+type historyResponseWriter struct {
+	offset int64
+	send   func(*api.ReadTerminalHistoryResponse) error
+
+	grep       []byte
+	line       []byte
+	lineOffset int64
+}
+
+func (w *historyResponseWriter) Write(p []byte) (n int, err error) {
+	if w.grep == nil {
+		err = w.send(&api.ReadTerminalHistoryResponse{
+			Data:   append([]byte{}, p...),
+			Offset: w.offset,
+		})
+		if err != nil {
+			return 0, err
+		}
+		w.offset += int64(len(p))
+		return len(p), nil
+	}
+
+	for len(p) > 0 {
+		if len(w.line) == 0 {
+			w.lineOffset = w.offset
+		}
+		idx := bytes.IndexByte(p, '\n')
+		if idx == -1 {
+			w.line = append(w.line, p...)
+			w.offset += int64(len(p))
+			n += len(p)
+			return n, nil
+		}
+		w.line = append(w.line, p[:idx+1]...)
+		w.offset += int64(idx + 1)
+		n += idx + 1
+		p = p[idx+1:]
+
+		err = w.Flush()
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Flush sends the buffered line if it matches grep.
+func (w *historyResponseWriter) Flush() error {
+	line := w.line
+	w.line = nil
+	if len(line) == 0 || !bytes.Contains(line, w.grep) {
+		return nil
+	}
+	return w.send(&api.ReadTerminalHistoryResponse{
+		Data:   line,
+		Offset: w.lineOffset,
+	})
+}
+
+// Reset drops any buffered line and continues at offset.
+func (w *historyResponseWriter) Reset(offset int64) {
+	w.line = nil
+	w.offset = offset
+}
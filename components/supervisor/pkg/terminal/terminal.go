@@ -12,7 +12,10 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/creack/pty"
@@ -25,20 +28,25 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/log"
 	"github.com/gitpod-io/gitpod/common-go/process"
 	"github.com/gitpod-io/gitpod/supervisor/api"
+	"github.com/gitpod-io/gitpod/supervisor/pkg/userfs"
 )
 
 // NewMux creates a new terminal mux.
 func NewMux() *Mux {
 	return &Mux{
-		terms: make(map[string]*Term),
+		terms:     make(map[string]*Term),
+		histories: make(map[string]*History),
 	}
 }
 
 // Mux can mux pseudo-terminals.
 type Mux struct {
-	aliases []string
-	terms   map[string]*Term
-	mu      sync.RWMutex
+	aliases   []string
+	terms     map[string]*Term
+	histories map[string]*History
+	// historyAliases are the aliases of the histories, oldest first
+	historyAliases []string
+	mu             sync.RWMutex
 }
 
 // Get returns a terminal for the given alias.
@@ -49,6 +57,63 @@ func (m *Mux) Get(alias string) (*Term, bool) {
 	return term, ok
 }
 
+// PIDs returns the process IDs of the commands running in the terminals mapped to their alias.
+func (m *Mux) PIDs() map[int]string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	res := make(map[int]string, len(m.terms))
+	for alias, term := range m.terms {
+		if term.Command == nil || term.Command.Process == nil {
+			continue
+		}
+		res[term.Command.Process.Pid] = alias
+	}
+	return res
+}
+
+// History returns the output history for the given alias. The history remains available after the terminal has been closed,
+// until the histories of newer closed terminals replace it.
+func (m *Mux) History(alias string) (*History, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	history, ok := m.histories[alias]
+	return history, ok
+}
+
+// pruneHistories removes the histories of the oldest closed terminals, s.t. at most maxClosed remain.
+// Callers are expected to hold mu.
+func (m *Mux) pruneHistories(maxClosed int) {
+	if maxClosed <= 0 {
+		maxClosed = DefaultHistoryMaxClosedTerminals
+	}
+	var closed int
+	for _, alias := range m.historyAliases {
+		if _, running := m.terms[alias]; !running {
+			closed++
+		}
+	}
+
+	aliases := make([]string, 0, len(m.historyAliases))
+	for _, alias := range m.historyAliases {
+		if _, running := m.terms[alias]; running || closed <= maxClosed {
+			aliases = append(aliases, alias)
+			continue
+		}
+		closed--
+		alias := alias
+		history := m.histories[alias]
+		delete(m.histories, alias)
+		// removing waits for pending compressions, which must not block starting terminals
+		go func() {
+			err := history.Remove()
+			if err != nil {
+				log.WithError(err).WithField("alias", alias).Warn("cannot remove terminal history")
+			}
+		}()
+	}
+	m.historyAliases = aliases
+}
+
 // Start starts a new command in its own pseudo-terminal and returns an alias
 // for that pseudo terminal.
 func (m *Mux) Start(cmd *exec.Cmd, options TermOptions) (alias string, err error) {
@@ -73,6 +138,11 @@ func (m *Mux) Start(cmd *exec.Cmd, options TermOptions) (alias string, err error
 	}
 	m.aliases = append(m.aliases, alias)
 	m.terms[alias] = term
+	if term.Stdout.history != nil {
+		m.histories[alias] = term.Stdout.history
+		m.historyAliases = append(m.historyAliases, alias)
+		m.pruneHistories(options.History.MaxClosedTerminals)
+	}
 
 	log.WithField("alias", alias).WithField("cmd", cmd.Path).Info("started new terminal")
 
@@ -170,6 +240,15 @@ func newTerm(alias string, pty *os.File, cmd *exec.Cmd, options TermOptions) (*T
 		return nil, err
 	}
 
+	var history *History
+	if options.History != nil {
+		history, err = newHistory(filepath.Join(options.History.Location, alias), *options.History)
+		if err != nil {
+			log.WithError(err).WithField("alias", alias).Warn("cannot persist terminal history")
+			history = nil
+		}
+	}
+
 	timeout := options.ReadTimeout
 	if timeout == 0 {
 		timeout = NoTimeout
@@ -181,6 +260,7 @@ func newTerm(alias string, pty *os.File, cmd *exec.Cmd, options TermOptions) (*T
 			timeout:   timeout,
 			listener:  make(map[*multiWriterListener]struct{}),
 			recorder:  recorder,
+			history:   history,
 			logStdout: options.LogToStdout,
 			logLabel:  alias,
 		},
@@ -231,6 +311,9 @@ type TermOptions struct {
 
 	// LogToStdout forwards the terminal's stdout to supervisor's stdout
 	LogToStdout bool
+
+	// History persists the terminal's output if not nil
+	History *HistoryOptions
 }
 
 // Term is a pseudo-terminal.
@@ -317,6 +400,79 @@ func (term *Term) resolveForegroundCommand() (string, error) {
 	return string(content), nil
 }
 
+// StartRecording starts recording the terminal session as asciicast v2 to the new file at path.
+// If creds is not nil the file is created as that user.
+// The recording ends when StopRecording is called or the terminal is closed.
+func (term *Term) StartRecording(path string, creds *syscall.Credential) error {
+	size, err := pty.GetsizeFull(term.PTY)
+	if err != nil {
+		return err
+	}
+	env := make(map[string]string)
+	for _, e := range term.Command.Env {
+		if strings.HasPrefix(e, "TERM=") {
+			env["TERM"] = strings.TrimPrefix(e, "TERM=")
+		}
+	}
+	env["SHELL"] = term.Command.Path
+
+	term.Stdout.mu.Lock()
+	defer term.Stdout.mu.Unlock()
+	if term.Stdout.closed {
+		return ErrNotFound
+	}
+	if term.Stdout.recording != nil {
+		return ErrAlreadyRecording
+	}
+
+	// the recording is created as the user, who controls the directory it is created in
+	user := userfs.Current()
+	if creds != nil {
+		user = userfs.User{UID: int(creds.Uid), GID: int(creds.Gid)}
+	}
+	err = user.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return xerrors.Errorf("cannot create recording directory: %w", err)
+	}
+	f, err := user.Create(path, 0644)
+	if err != nil {
+		return xerrors.Errorf("cannot create recording: %w", err)
+	}
+	rec, err := newAsciicastRecorder(f, size, term.defaultTitle, env)
+	if err != nil {
+		f.Close()
+		return xerrors.Errorf("cannot write recording: %w", err)
+	}
+	term.Stdout.recording = rec
+	term.Stdout.recordingPath = path
+	return nil
+}
+
+// StopRecording stops the active recording of the terminal session and returns the path of the recording.
+func (term *Term) StopRecording() (path string, err error) {
+	term.Stdout.mu.Lock()
+	defer term.Stdout.mu.Unlock()
+	if term.Stdout.recording == nil {
+		return "", ErrNotRecording
+	}
+	err = term.Stdout.recording.Close()
+	term.Stdout.recording = nil
+	return term.Stdout.recordingPath, err
+}
+
+// recordResize records a size change of the terminal if a recording is active.
+func (term *Term) recordResize(size *pty.Winsize) {
+	term.Stdout.mu.Lock()
+	defer term.Stdout.mu.Unlock()
+	if term.Stdout.recording == nil {
+		return
+	}
+	err := term.Stdout.recording.Resize(size)
+	if err != nil {
+		log.WithError(err).WithField("label", term.Stdout.logLabel).Warn("cannot record terminal resize")
+	}
+}
+
 // Wait waits for the terminal to exit and returns the resulted process state.
 func (term *Term) Wait() (*os.ProcessState, error) {
 	<-term.waitDone
@@ -332,6 +488,11 @@ type multiWriter struct {
 	// ring buffer to record last 256kb of pty output
 	// new listener is initialized with the latest recodring first
 	recorder *RingBuffer
+	// history persists the pty output if enabled
+	history *History
+	// recording records the pty output as asciicast to recordingPath while a recording is active
+	recording     *asciicastRecorder
+	recordingPath string
 
 	logStdout bool
 	logLabel  string
@@ -342,6 +503,10 @@ var (
 	ErrNotFound = errors.New("not found")
 	// ErrReadTimeout happens when a listener takes too long to read.
 	ErrReadTimeout = errors.New("read timeout")
+	// ErrAlreadyRecording means a recording of the terminal is active already.
+	ErrAlreadyRecording = errors.New("already recording")
+	// ErrNotRecording means no recording of the terminal is active.
+	ErrNotRecording = errors.New("not recording")
 )
 
 type multiWriterListener struct {
@@ -465,6 +630,17 @@ func (mw *multiWriter) Write(p []byte) (n int, err error) {
 	defer mw.mu.Unlock()
 
 	mw.recorder.Write(p)
+	if mw.history != nil {
+		_, _ = mw.history.Write(p)
+	}
+	if mw.recording != nil {
+		err := mw.recording.Output(p)
+		if err != nil {
+			log.WithError(err).WithField("label", mw.logLabel).Warn("cannot record terminal output, stopping the recording")
+			_ = mw.recording.Close()
+			mw.recording = nil
+		}
+	}
 	if mw.logStdout {
 		log.WithFields(logrus.Fields{
 			"terminalOutput": true,
@@ -505,6 +681,19 @@ func (mw *multiWriter) Close() error {
 			err = cerr
 		}
 	}
+	if mw.history != nil {
+		cerr := mw.history.Close()
+		if cerr != nil {
+			err = cerr
+		}
+	}
+	if mw.recording != nil {
+		cerr := mw.recording.Close()
+		if cerr != nil {
+			err = cerr
+		}
+		mw.recording = nil
+	}
 	return err
 }
 
@@ -0,0 +1,109 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+// Package userfs accesses the file system with the credentials of the workspace user. Supervisor runs as root,
+// hence files in locations the user controls must not be accessed with its own credentials: the user could
+// replace them with symlinks to files only root has access to.
+package userfs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+)
+
+// User holds the file system credentials files are accessed with.
+type User struct {
+	UID, GID int
+}
+
+// Current returns the credentials of the current process.
+func Current() User {
+	return User{UID: os.Getuid(), GID: os.Getgid()}
+}
+
+// Do runs op with the file system credentials of the user, so that it can only access what the user can access.
+// The credentials are changed for the current thread only, hence op must not access the file system from other
+// goroutines.
+func (u User) Do(op func() error) (err error) {
+	runtime.LockOSThread()
+	prevGID, _ := unix.SetfsgidRetGid(u.GID)
+	prevUID, _ := unix.SetfsuidRetUid(u.UID)
+	defer func() {
+		_ = unix.Setfsuid(prevUID)
+		_ = unix.Setfsgid(prevGID)
+		// setfsuid and setfsgid do not report failures, but return the current IDs when called with -1
+		if uid, _ := unix.SetfsuidRetUid(-1); uid != prevUID {
+			// never hand this thread back to the runtime, it exits with the goroutine
+			log.WithField("uid", uid).Error("cannot restore file system credentials")
+			return
+		}
+		if gid, _ := unix.SetfsgidRetGid(-1); gid != prevGID {
+			log.WithField("gid", gid).Error("cannot restore file system credentials")
+			return
+		}
+		runtime.UnlockOSThread()
+	}()
+
+	if uid, _ := unix.SetfsuidRetUid(-1); uid != u.UID {
+		return xerrors.Errorf("cannot change file system credentials to uid %d", u.UID)
+	}
+	if gid, _ := unix.SetfsgidRetGid(-1); gid != u.GID {
+		return xerrors.Errorf("cannot change file system credentials to gid %d", u.GID)
+	}
+	return op()
+}
+
+// ReadFile reads the file fn as the user.
+func (u User) ReadFile(fn string) (content []byte, err error) {
+	err = u.Do(func() error {
+		content, err = os.ReadFile(fn)
+		return err
+	})
+	return content, err
+}
+
+// MkdirAll creates the directory dir and its missing parents as the user.
+func (u User) MkdirAll(dir string, perm os.FileMode) error {
+	return u.Do(func() error {
+		return os.MkdirAll(dir, perm)
+	})
+}
+
+// Create creates the new file fn as the user. It fails if fn exists already, even as a symlink.
+func (u User) Create(fn string, perm os.FileMode) (f *os.File, err error) {
+	err = u.Do(func() (err error) {
+		f, err = os.OpenFile(fn, os.O_CREATE|os.O_EXCL|os.O_WRONLY|unix.O_NOFOLLOW, perm)
+		return err
+	})
+	return f, err
+}
+
+// WriteFile replaces the file fn with content as the user. The content is written to a temporary file next to fn
+// first, s.t. readers never see a partially written file.
+func (u User) WriteFile(fn string, content []byte, perm os.FileMode) error {
+	return u.Do(func() error {
+		tmp, err := os.CreateTemp(filepath.Dir(fn), filepath.Base(fn)+".*.tmp")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		_, err = tmp.Write(content)
+		if err == nil {
+			err = tmp.Chmod(perm)
+		}
+		if cerr := tmp.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		return os.Rename(tmp.Name(), fn)
+	})
+}
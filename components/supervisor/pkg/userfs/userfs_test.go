@@ -0,0 +1,82 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package userfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	err := os.WriteFile(target, []byte("secret"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Symlink(target, filepath.Join(dir, "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"link", "target"} {
+		f, err := Current().Create(filepath.Join(dir, name), 0o644)
+		if err == nil {
+			f.Close()
+			t.Errorf("expected creating existing %s to fail", name)
+		}
+	}
+	if content, _ := os.ReadFile(target); string(content) != "secret" {
+		t.Errorf("expected target to be untouched, got %q", content)
+	}
+
+	f, err := Current().Create(filepath.Join(dir, "new"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	err := os.WriteFile(target, []byte("secret"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := filepath.Join(dir, "file")
+	err = os.Symlink(target, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a temporary file planted under the name used by earlier versions must not be followed either
+	err = os.Symlink(target, fn+".tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Current().WriteFile(fn, []byte("hello"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := os.ReadFile(target); string(content) != "secret" {
+		t.Errorf("expected the symlink target to be untouched, got %q", content)
+	}
+	info, err := os.Lstat(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected a regular file with mode 0600, got %s", info.Mode())
+	}
+	if content, _ := os.ReadFile(fn); string(content) != "hello" {
+		t.Errorf("unexpected content: %q", content)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 3 {
+		t.Errorf("expected no temporary files to be left behind, got %d entries", len(entries))
+	}
+}
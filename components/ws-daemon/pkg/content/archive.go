@@ -21,16 +21,27 @@ import (
 
 // BuildTarbal creates an OCI compatible tar file dst from the folder src, expecting the overlay whiteout format
 func BuildTarbal(ctx context.Context, src string, dst string, fullWorkspaceBackup bool, opts ...carchive.TarOption) (err error) {
-	var cfg carchive.TarConfig
-	for _, opt := range opts {
-		opt(&cfg)
-	}
-
 	//nolint:staticcheck,ineffassign
 	span, ctx := opentracing.StartSpanFromContext(ctx, "buildTarbal")
 	span.LogKV("src", src, "dst", dst)
 	defer tracing.FinishSpan(span, &err)
 
+	tarFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY, 0755)
+	if err != nil {
+		return xerrors.Errorf("Unable to create tar file: %v", err.Error())
+	}
+	defer tarFile.Close()
+
+	return WriteTarbal(ctx, src, tarFile, fullWorkspaceBackup, opts...)
+}
+
+// WriteTarbal writes an OCI compatible tar stream of the folder src to dst, expecting the overlay whiteout format
+func WriteTarbal(ctx context.Context, src string, dst io.Writer, fullWorkspaceBackup bool, opts ...carchive.TarOption) (err error) {
+	var cfg carchive.TarConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	// ensure the src actually exists before trying to tar it
 	if _, err := os.Stat(src); err != nil {
 		return xerrors.Errorf("Unable to tar files: %v", err.Error())
@@ -67,12 +78,7 @@ func BuildTarbal(ctx context.Context, src string, dst string, fullWorkspaceBacku
 	}
 	defer tarReader.Close()
 
-	tarFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY, 0755)
-	if err != nil {
-		return xerrors.Errorf("Unable to create tar file: %v", err.Error())
-	}
-
-	_, err = io.Copy(tarFile, tarReader)
+	_, err = io.Copy(dst, tarReader)
 	if err != nil {
 		return xerrors.Errorf("Unable create tar file: %v", err.Error())
 	}
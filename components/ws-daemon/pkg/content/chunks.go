@@ -0,0 +1,153 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	csapi "github.com/gitpod-io/gitpod/content-service/api"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/chunks"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
+)
+
+// chunkUploadConcurrency is the number of chunks which are uploaded at the same time
+const chunkUploadConcurrency = 8
+
+// uploadChunkedBackup splits a tarbal of loc into content-defined chunks while it is written and uploads the chunks
+// which are not present in the remote storage yet. The chunk index is uploaded last under backupName, s.t. the previous
+// backup stays intact until all chunks of the new one are present. Chunks which are no longer referenced are deleted
+// afterwards.
+func (s *WorkspaceService) uploadChunkedBackup(ctx context.Context, sess *session.Workspace, rs storage.DirectAccess, loc string, backupName string, tarOpts []archive.TarOption) (err error) {
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "uploadChunkedBackup")
+	defer tracing.FinishSpan(span, &err)
+
+	err = rs.EnsureExists(ctx)
+	if err != nil {
+		return err
+	}
+	existing, err := rs.ListObjects(ctx, rs.BackupObject(chunks.Prefix))
+	if err != nil {
+		return xerrors.Errorf("cannot list existing chunks: %w", err)
+	}
+	known := make(map[string]struct{}, len(existing))
+	for _, obj := range existing {
+		known[obj] = struct{}{}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(WriteTarbal(ctx, loc, pw, false, tarOpts...))
+	}()
+	// unblocks the tarbal writer if building the chunks fails
+	defer pr.Close()
+
+	res, err := chunks.Build(ctx, pr, chunks.DefaultOptions, chunkUploadConcurrency,
+		func(dgst digest.Digest) bool {
+			_, ok := known[rs.BackupObject(chunks.ObjectName(dgst))]
+			return ok
+		},
+		func(ctx context.Context, dgst digest.Digest, chunk []byte) error {
+			return retryIfErr(ctx, s.config.Backup.Attempts, log.WithFields(sess.OWI()).WithField("op", "upload chunk"), func(ctx context.Context) error {
+				return s.uploadChunk(ctx, sess, rs, dgst, chunk)
+			})
+		},
+	)
+	if err != nil {
+		return err
+	}
+	span.LogKV("chunks", len(res.Index.Chunks), "uploadedChunks", res.UploadedChunks, "uploadedBytes", res.UploadedBytes)
+	log.WithFields(sess.OWI()).WithField("chunks", len(res.Index.Chunks)).WithField("uploadedChunks", res.UploadedChunks).WithField("uploadedBytes", res.UploadedBytes).Debug("uploaded backup chunks")
+
+	index, err := json.Marshal(res.Index)
+	if err != nil {
+		return err
+	}
+	tmpidx, err := os.CreateTemp(s.config.TmpDir, fmt.Sprintf("chunks-%s-*.json", sess.InstanceID))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpidx.Name())
+	_, err = tmpidx.Write(index)
+	tmpidx.Close()
+	if err != nil {
+		return err
+	}
+
+	err = retryIfErr(ctx, s.config.Backup.Attempts, log.WithFields(sess.OWI()).WithField("op", "upload chunk index"), func(ctx context.Context) error {
+		// The content type tells restores that this is a chunk index rather than a tarball.
+		_, _, err := rs.Upload(ctx, tmpidx.Name(), backupName, storage.WithContentType(csapi.ContentTypeChunkIndex))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.deleteUnreferencedChunks(ctx, sess, rs, existing, res.Index)
+	return nil
+}
+
+// deleteUnreferencedChunks deletes the chunks which the new index no longer references. This happens as part of the
+// backup, s.t. no other backup of the workspace can reuse a chunk while it is being deleted. Failures are only logged,
+// as unreferenced chunks do not affect restores.
+func (s *WorkspaceService) deleteUnreferencedChunks(ctx context.Context, sess *session.Workspace, rs storage.DirectAccess, existing []string, index *csapi.WorkspaceChunkIndex) {
+	// chunks are stored next to the backup
+	prefix := path.Dir(rs.BackupObject(storage.DefaultBackup)) + "/"
+	unreferenced := chunks.Unreferenced(existing, prefix, index)
+	if len(unreferenced) == 0 {
+		return
+	}
+
+	ps, err := storage.NewPresignedAccess(&s.config.Storage)
+	if err != nil {
+		log.WithError(err).WithFields(sess.OWI()).Warn("cannot delete unreferenced backup chunks")
+		return
+	}
+	bucket := rs.Bucket(sess.Owner)
+	for _, obj := range unreferenced {
+		err = ps.DeleteObject(ctx, bucket, &storage.DeleteObjectQuery{Name: obj})
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.WithError(err).WithFields(sess.OWI()).WithField("object", obj).Warn("cannot delete unreferenced backup chunk")
+			return
+		}
+	}
+	log.WithFields(sess.OWI()).WithField("chunks", len(unreferenced)).Debug("deleted unreferenced backup chunks")
+}
+
+// uploadChunk uploads a chunk from memory if the remote storage supports it and through a temporary file otherwise
+func (s *WorkspaceService) uploadChunk(ctx context.Context, sess *session.Workspace, rs storage.DirectAccess, dgst digest.Digest, chunk []byte) error {
+	if bu, ok := rs.(storage.BytesUploader); ok {
+		_, _, err := bu.UploadBytes(ctx, chunk, chunks.ObjectName(dgst))
+		return err
+	}
+
+	tmpf, err := os.CreateTemp(s.config.TmpDir, fmt.Sprintf("chunk-%s-*", sess.InstanceID))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpf.Name())
+	_, err = tmpf.Write(chunk)
+	tmpf.Close()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = rs.Upload(ctx, tmpf.Name(), chunks.ObjectName(dgst))
+	return err
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/opencontainers/go-digest"
+
+	cntntcfg "github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/content-service/pkg/chunks"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
+)
+
+func TestUploadChunkedBackup(t *testing.T) {
+	ctx := context.Background()
+	cfg := cntntcfg.StorageConfig{
+		Stage: cntntcfg.StageDevStaging,
+		Kind:  cntntcfg.LocalStorage,
+		LocalConfig: &cntntcfg.LocalConfig{
+			Path:   t.TempDir(),
+			URL:    "http://localhost:8080",
+			Secret: "test-secret",
+		},
+	}
+	rs, err := storage.NewDirectAccess(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := &session.Workspace{Owner: "owner", WorkspaceID: "workspace", InstanceID: "instance"}
+	err = rs.Init(ctx, sess.Owner, sess.WorkspaceID, sess.InstanceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &WorkspaceService{config: Config{
+		TmpDir:  t.TempDir(),
+		Storage: cfg,
+		Backup:  BackupConfig{Attempts: 1},
+	}}
+
+	loc := t.TempDir()
+	content := make([]byte, 8*1024*1024)
+	_, _ = rand.New(rand.NewSource(42)).Read(content)
+	for i, c := range [][]byte{content, append([]byte("changed"), content[1024:]...)} {
+		err = os.WriteFile(filepath.Join(loc, "data"), c, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = srv.uploadChunkedBackup(ctx, sess, rs, loc, storage.DefaultBackup, nil)
+		if err != nil {
+			t.Fatalf("backup %d: %v", i, err)
+		}
+	}
+
+	// only the chunks of the last backup remain
+	var tarbal bytes.Buffer
+	err = WriteTarbal(ctx, loc, &tarbal, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected []string
+	_, err = chunks.Build(ctx, &tarbal, chunks.DefaultOptions, 1,
+		func(dgst digest.Digest) bool {
+			expected = append(expected, rs.BackupObject(chunks.ObjectName(dgst)))
+			return true
+		},
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	act, err := rs.ListObjects(ctx, rs.BackupObject(chunks.Prefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(expected)
+	sort.Strings(act)
+	if diff := cmp.Diff(expected, act); diff != "" {
+		t.Errorf("unexpected chunks (-want +got):\n%s", diff)
+	}
+}
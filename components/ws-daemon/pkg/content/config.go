@@ -52,6 +52,15 @@ type BackupConfig struct {
 
 	// Period is the time between regular workspace backups
 	Period util.Duration `json:"period"`
+
+	// Chunked enables content-defined chunking of regular backups. Only the chunks which are not
+	// part of the remote storage yet are uploaded, next to a chunk index which replaces the tarball.
+	Chunked bool `json:"chunked,omitempty"`
+
+	// Encryption enables envelope encryption of regular backups and snapshots using data keys wrapped
+	// per workspace owner. Full workspace backups are not encrypted and chunked backups are disabled while
+	// encryption is configured. The KMS must remain available for as long as encrypted backups exist.
+	Encryption *cntntcfg.EncryptionConfig `json:"encryption,omitempty"`
 }
 
 type UserNamespacesConfig struct {
@@ -0,0 +1,68 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/xerrors"
+
+	carchive "github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/encryption"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+// writeEncryptedTarbal writes a tarbal of src to dst, encrypted using the data key
+func writeEncryptedTarbal(ctx context.Context, src string, dst io.Writer, key []byte, opts ...carchive.TarOption) error {
+	w, err := encryption.NewWriter(dst, key)
+	if err != nil {
+		return err
+	}
+	err = WriteTarbal(ctx, src, w, false, opts...)
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// unwrapDataKeys returns the plain data keys of all encrypted remote content, indexed by the name of the content.
+// The keys are unwrapped here rather than in the initializer, s.t. the content initializer never gets access to the KMS.
+// Every data key must belong to the owner of the location the content is stored at, s.t. content copied into the
+// workspace owner's bucket cannot be decrypted on their behalf.
+func (s *WorkspaceService) unwrapDataKeys(ctx context.Context, ps storage.PresignedAccess, owner string, rc map[string]storage.DownloadInfo) (map[string][]byte, error) {
+	var res map[string][]byte
+	for name, info := range rc {
+		if !encryption.IsEncrypted(&info.Meta) {
+			continue
+		}
+		if s.kms == nil {
+			return nil, xerrors.Errorf("%s is encrypted but backup encryption is not configured", name)
+		}
+
+		scope := owner
+		if name != storage.DefaultBackup {
+			// snapshots and prebuilds may belong to someone else
+			bkt, obj, err := storage.ParseSnapshotName(name)
+			if err != nil {
+				return nil, err
+			}
+			scope, err = storage.BackupOwner(ps, bkt, obj)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		key, err := encryption.UnwrapDataKey(ctx, s.kms, scope, &info.Meta)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot unwrap data key of %s: %w", name, err)
+		}
+		if res == nil {
+			res = make(map[string][]byte)
+		}
+		res[name] = key
+	}
+	return res, nil
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cntntcfg "github.com/gitpod-io/gitpod/content-service/api/config"
+	"github.com/gitpod-io/gitpod/content-service/pkg/encryption"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+)
+
+func TestWriteEncryptedTarbal(t *testing.T) {
+	src := t.TempDir()
+	err := os.WriteFile(filepath.Join(src, "hello.txt"), []byte("hello world"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := make([]byte, encryption.DataKeySize)
+	_, _ = rand.Read(key)
+
+	var buf bytes.Buffer
+	err = writeEncryptedTarbal(context.Background(), src, &buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("hello world")) {
+		t.Fatal("encrypted tarbal contains the plain content")
+	}
+
+	r, err := encryption.NewReader(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			t.Fatal("hello.txt is not part of the tarbal")
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Name != "hello.txt" {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "hello world" {
+			t.Errorf("unexpected content: %q", content)
+		}
+		return
+	}
+}
+
+func TestUnwrapDataKeys(t *testing.T) {
+	ctx := context.Background()
+	master := make([]byte, encryption.DataKeySize)
+	_, _ = rand.Read(master)
+	fn := filepath.Join(t.TempDir(), "keyfile.json")
+	err := os.WriteFile(fn, []byte(fmt.Sprintf(`{"primary":"v1","keys":{"v1":%q}}`, base64.StdEncoding.EncodeToString(master))), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kms, err := encryption.NewKeyfileKMS(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps, err := storage.NewPresignedLocalAccess(&cntntcfg.LocalConfig{Path: t.TempDir(), URL: "http://localhost", Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryptedFor := func(scope string) (*encryption.DataKey, storage.DownloadInfo) {
+		dk, err := encryption.NewDataKey(ctx, kms, scope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		annotations := dk.Annotations()
+		return dk, storage.DownloadInfo{Meta: storage.ObjectMeta{
+			EncryptionKeyID:  annotations[storage.ObjectAnnotationEncryptionKeyID],
+			EncryptedDataKey: annotations[storage.ObjectAnnotationEncryptedDataKey],
+		}}
+	}
+	snapshotName := func(owner string) string {
+		return ps.BackupObject(owner, "workspace", "snapshot-1.tar") + "@" + ps.Bucket(owner)
+	}
+
+	backupKey, backup := encryptedFor("owner")
+	snapshotKey, snapshot := encryptedFor("other")
+	rc := map[string]storage.DownloadInfo{
+		storage.DefaultBackup: backup,
+		snapshotName("other"): snapshot,
+		"prebuild":            {},
+	}
+	keys, err := (&WorkspaceService{kms: kms}).unwrapDataKeys(ctx, ps, "owner", rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || !bytes.Equal(keys[storage.DefaultBackup], backupKey.Key()) || !bytes.Equal(keys[snapshotName("other")], snapshotKey.Key()) {
+		t.Errorf("unexpected data keys: %v", keys)
+	}
+
+	// content of another owner which was copied to the owner's backup location
+	for name, info := range map[string]storage.DownloadInfo{
+		storage.DefaultBackup: snapshot,
+		snapshotName("owner"): snapshot,
+	} {
+		_, err = (&WorkspaceService{kms: kms}).unwrapDataKeys(ctx, ps, "owner", map[string]storage.DownloadInfo{name: info})
+		if !errors.Is(err, encryption.ErrScopeMismatch) {
+			t.Errorf("expected content of another owner at %s to be rejected, got %v", name, err)
+		}
+	}
+
+	_, err = (&WorkspaceService{}).unwrapDataKeys(ctx, ps, "owner", rc)
+	if err == nil {
+		t.Error("expected encrypted content to fail without a KMS")
+	}
+
+	keys, err = (&WorkspaceService{}).unwrapDataKeys(ctx, ps, "owner", map[string]storage.DownloadInfo{"prebuild": {}})
+	if err != nil || keys != nil {
+		t.Errorf("expected no data keys for plain content, got %v (%v)", keys, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -29,6 +30,7 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/tracing"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/encryption"
 	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
 )
@@ -46,6 +48,9 @@ type RunInitializerOpts struct {
 	GID uint32
 
 	OWI OWI
+
+	// DataKeys are the plain data keys of encrypted remote content, indexed by the name of the content
+	DataKeys map[string][]byte
 }
 
 type OWI struct {
@@ -75,6 +80,15 @@ func collectRemoteContent(ctx context.Context, rs storage.DirectAccess, ps stora
 	} else {
 		rc[storage.DefaultBackup] = *backup
 	}
+	if storage.IsChunkedBackup(backup) {
+		chunks, err := storage.SignChunkedBackup(ctx, ps, rs.Bucket(workspaceOwner), rs.BackupObject(storage.DefaultBackup), backup)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot sign backup chunks: %w", err)
+		}
+		for name, info := range chunks {
+			rc[name] = info
+		}
+	}
 
 	si := initializer.GetSnapshot()
 	pi := initializer.GetPrebuild()
@@ -161,6 +175,7 @@ func RunInitializer(ctx context.Context, destination string, initializer *csapi.
 		Destination:   "/dst",
 		Initializer:   init,
 		RemoteContent: remoteContent,
+		DataKeys:      opts.DataKeys,
 		TraceInfo:     tracing.GetTraceID(span),
 		IDMappings:    opts.IdMappings,
 		GID:           int(opts.GID),
@@ -326,7 +341,7 @@ func RunInitializerChild() (err error) {
 		return err
 	}
 
-	rs := &remoteContentStorage{RemoteContent: initmsg.RemoteContent}
+	rs := &remoteContentStorage{RemoteContent: initmsg.RemoteContent, DataKeys: initmsg.DataKeys}
 
 	dst := initmsg.Destination
 	initializer, err := wsinit.NewFromRequest(ctx, dst, rs, &req, wsinit.NewFromRequestOpts{ForceGitpodUserForGit: false})
@@ -364,6 +379,7 @@ var _ storage.DirectAccess = &remoteContentStorage{}
 
 type remoteContentStorage struct {
 	RemoteContent map[string]storage.DownloadInfo
+	DataKeys      map[string][]byte
 }
 
 // Init does nothing
@@ -390,6 +406,11 @@ func (rs *remoteContentStorage) Download(ctx context.Context, destination string
 
 	span.SetTag("URL", info.URL)
 
+	if storage.IsChunkedBackup(&info) {
+		span.SetTag("chunked", true)
+		return true, rs.downloadChunked(ctx, destination, info, mappings)
+	}
+
 	// create a temporal file to download the content
 	tempFile, err := os.CreateTemp("", "remote-content-*")
 	if err != nil {
@@ -421,7 +442,20 @@ func (rs *remoteContentStorage) Download(ctx context.Context, destination string
 	defer os.Remove(tempFile.Name())
 	defer tempFile.Close()
 
-	err = archive.ExtractTarbal(ctx, tempFile, destination, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
+	var src io.Reader = tempFile
+	if encryption.IsEncrypted(&info.Meta) {
+		span.SetTag("encrypted", true)
+		key, ok := rs.DataKeys[name]
+		if !ok {
+			return true, xerrors.Errorf("%s is encrypted but its data key is missing", name)
+		}
+		src, err = encryption.NewReader(tempFile, key)
+		if err != nil {
+			return true, xerrors.Errorf("cannot decrypt %s: %w", name, err)
+		}
+	}
+
+	err = archive.ExtractTarbal(ctx, src, destination, archive.WithUIDMapping(mappings), archive.WithGIDMapping(mappings))
 	if err != nil {
 		return true, xerrors.Errorf("tar %s: %s", destination, err.Error())
 	}
@@ -429,6 +463,20 @@ func (rs *remoteContentStorage) Download(ctx context.Context, destination string
 	return true, nil
 }
 
+// downloadChunked reassembles a chunked backup from the chunks which are part of the remote content
+func (rs *remoteContentStorage) downloadChunked(ctx context.Context, destination string, info storage.DownloadInfo, mappings []archive.IDMapping) error {
+	index, err := storage.DownloadChunkIndex(ctx, info.URL)
+	if err != nil {
+		return err
+	}
+
+	urls := make(map[string]string, len(rs.RemoteContent))
+	for name, info := range rs.RemoteContent {
+		urls[name] = info.URL
+	}
+	return storage.ExtractChunkedBackup(ctx, destination, index, urls, mappings)
+}
+
 // DownloadSnapshot always returns false and does nothing
 func (rs *remoteContentStorage) DownloadSnapshot(ctx context.Context, destination string, name string, mappings []archive.IDMapping) (bool, error) {
 	return rs.Download(ctx, destination, name, mappings)
@@ -477,6 +525,7 @@ func (rs *remoteContentStorage) SnapshotObject(name string) string {
 type msgInitContent struct {
 	Destination   string
 	RemoteContent map[string]storage.DownloadInfo
+	DataKeys      map[string][]byte
 	Initializer   []byte
 	UID, GID      int
 	IDMappings    []archive.IDMapping
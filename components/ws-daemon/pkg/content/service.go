@@ -30,6 +30,7 @@ import (
 	"github.com/gitpod-io/gitpod/common-go/tracing"
 	csapi "github.com/gitpod-io/gitpod/content-service/api"
 	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/encryption"
 	wsinit "github.com/gitpod-io/gitpod/content-service/pkg/initializer"
 	"github.com/gitpod-io/gitpod/content-service/pkg/logs"
 	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
@@ -56,6 +57,9 @@ type WorkspaceService struct {
 	stopService context.CancelFunc
 	runtime     container.Runtime
 
+	// kms wraps the data keys of encrypted backups. Nil if backups are not encrypted.
+	kms encryption.KMS
+
 	metrics *metrics
 
 	// channel to limit the number of concurrent backups and uploads.
@@ -85,6 +89,14 @@ func NewWorkspaceService(ctx context.Context, cfg Config, kubernetesNamespace st
 		return nil, err
 	}
 
+	var kms encryption.KMS
+	if cfg.Backup.Encryption != nil {
+		kms, err = encryption.NewKMS(cfg.Backup.Encryption)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create KMS for backup encryption: %w", err)
+		}
+	}
+
 	// read all session json files
 	store, err := session.NewStore(ctx, cfg.WorkingArea, workspaceLifecycleHooks(cfg, kubernetesNamespace, wec, uidmapper, xfs, cgroupMountPoint))
 	if err != nil {
@@ -126,6 +138,7 @@ func NewWorkspaceService(ctx context.Context, cfg Config, kubernetesNamespace st
 		ctx:         ctx,
 		stopService: stopService,
 		runtime:     runtime,
+		kms:         kms,
 
 		metrics: &metrics{
 			BackupWaitingTimeHist:       waitingTimeHist,
@@ -237,6 +250,7 @@ func (s *WorkspaceService) InitWorkspace(ctx context.Context, req *api.InitWorks
 
 	if !req.FullWorkspaceBackup && !req.PersistentVolumeClaim {
 		var remoteContent map[string]storage.DownloadInfo
+		var dataKeys map[string][]byte
 
 		// some workspaces don't have remote storage enabled. For those workspaces we clearly
 		// cannot collect remote content (i.e. the backup or prebuilds) and hence must not try.
@@ -261,6 +275,12 @@ func (s *WorkspaceService) InitWorkspace(ctx context.Context, req *api.InitWorks
 				log.WithError(err).Error("cannot collect remote content")
 				return nil, status.Error(codes.Internal, "remote content error")
 			}
+
+			dataKeys, err = s.unwrapDataKeys(ctx, ps, workspace.Owner, remoteContent)
+			if err != nil {
+				log.WithError(err).Error("cannot unwrap data keys of remote content")
+				return nil, status.Error(codes.Internal, "remote content error")
+			}
 		}
 
 		// This task/call cannot be canceled. Once it's started it's brought to a conclusion, independent of the caller disconnecting
@@ -288,6 +308,7 @@ func (s *WorkspaceService) InitWorkspace(ctx context.Context, req *api.InitWorks
 				WorkspaceID: req.Metadata.MetaId,
 				InstanceID:  req.Id,
 			},
+			DataKeys: dataKeys,
 		}
 
 		err = RunInitializer(ctx, workspace.Location, req.Initializer, remoteContent, opts)
@@ -545,6 +566,63 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 		return xerrors.Errorf("no remote storage configured")
 	}
 
+	var tarOpts []archive.TarOption
+	if !sess.FullWorkspaceBackup {
+		mappings := []archive.IDMapping{
+			{ContainerID: 0, HostID: wsinit.GitpodUID, Size: 1},
+			{ContainerID: 1, HostID: 100000, Size: 65534},
+		}
+		tarOpts = append(tarOpts,
+			archive.WithUIDMapping(mappings),
+			archive.WithGIDMapping(mappings),
+		)
+	}
+
+	// Full workspace backups are served by registry-facade and hence cannot be encrypted
+	var dataKey *encryption.DataKey
+	if s.kms != nil && !sess.FullWorkspaceBackup {
+		dataKey, err = encryption.NewDataKey(ctx, s.kms, sess.Owner)
+		if err != nil {
+			return xerrors.Errorf("cannot create data key: %w", err)
+		}
+		opts = append(opts, storage.WithAnnotations(dataKey.Annotations()))
+	}
+
+	// chunks are deduplicated across backups and hence cannot be encrypted with a per-backup data key
+	chunked := s.config.Backup.Chunked && dataKey == nil && !sess.FullWorkspaceBackup && backupName == storage.DefaultBackup
+	if chunked {
+		// snapshots remain self-contained tarballs as they outlive the workspace's chunks
+		err = retryIfErr(ctx, s.config.Backup.Attempts, log.WithFields(sess.OWI()).WithField("op", "upload chunked backup"), func(ctx context.Context) error {
+			return s.uploadChunkedBackup(ctx, sess, rs, loc, backupName, tarOpts)
+		})
+		if err != nil {
+			return xerrors.Errorf("cannot upload workspace content: %w", err)
+		}
+		return nil
+	}
+
+	if !sess.FullWorkspaceBackup {
+		// Stream the backup into the remote storage to avoid doubling the disk usage on the node.
+		// Full workspace backups need their digest upfront, hence they go through a temporary file.
+		var streamed bool
+		err = retryIfErr(ctx, s.config.Backup.Attempts, log.WithFields(sess.OWI()).WithField("op", "stream backup"), func(ctx context.Context) error {
+			err := s.streamBackup(ctx, sess, rs, loc, backupName, dataKey, tarOpts, opts...)
+			if errors.Is(err, errStreamingUnsupported) {
+				// no point in retrying
+				return nil
+			}
+			streamed = err == nil
+			return err
+		})
+		if err != nil {
+			return xerrors.Errorf("cannot upload workspace content: %w", err)
+		}
+		if streamed {
+			return nil
+		}
+		log.WithFields(sess.OWI()).Debug("remote storage cannot stream backups - falling back to temporary file")
+	}
+
 	var (
 		tmpf       *os.File
 		tmpfSize   int64
@@ -557,20 +635,11 @@ func (s *WorkspaceService) uploadWorkspaceContent(ctx context.Context, sess *ses
 		}
 		defer tmpf.Close()
 
-		var opts []archive.TarOption
-		opts = append(opts)
-		if !sess.FullWorkspaceBackup {
-			mappings := []archive.IDMapping{
-				{ContainerID: 0, HostID: wsinit.GitpodUID, Size: 1},
-				{ContainerID: 1, HostID: 100000, Size: 65534},
-			}
-			opts = append(opts,
-				archive.WithUIDMapping(mappings),
-				archive.WithGIDMapping(mappings),
-			)
+		if dataKey != nil {
+			err = writeEncryptedTarbal(ctx, loc, tmpf, dataKey.Key(), tarOpts...)
+		} else {
+			err = BuildTarbal(ctx, loc, tmpf.Name(), sess.FullWorkspaceBackup, tarOpts...)
 		}
-
-		err = BuildTarbal(ctx, loc, tmpf.Name(), sess.FullWorkspaceBackup, opts...)
 		if err != nil {
 			return
 		}
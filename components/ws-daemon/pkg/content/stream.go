@@ -0,0 +1,111 @@
+// Copyright (c) 2022 Gitpod GmbH. All rights reserved.
+// Licensed under the GNU Affero General Public License (AGPL).
+// See License.AGPL.txt in the project root for license information.
+
+package content
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/opencontainers/go-digest"
+	"github.com/opentracing/opentracing-go"
+	"golang.org/x/xerrors"
+
+	"github.com/gitpod-io/gitpod/common-go/log"
+	"github.com/gitpod-io/gitpod/common-go/tracing"
+	"github.com/gitpod-io/gitpod/content-service/pkg/archive"
+	"github.com/gitpod-io/gitpod/content-service/pkg/encryption"
+	"github.com/gitpod-io/gitpod/content-service/pkg/storage"
+	"github.com/gitpod-io/gitpod/ws-daemon/pkg/internal/session"
+)
+
+// errStreamingUnsupported is returned by streamBackup if the remote storage cannot upload in parts
+var errStreamingUnsupported = errors.New("remote storage does not support streaming backups")
+
+// streamBackup builds a zstd compressed tarbal of loc and streams it directly into a multipart upload,
+// s.t. the backup does not have to be written to disk first. Failed parts are retried on their own.
+// If dataKey is not nil, the compressed tarbal is encrypted using it.
+// Returns errStreamingUnsupported if the remote storage cannot upload in parts.
+func (s *WorkspaceService) streamBackup(ctx context.Context, sess *session.Workspace, rs storage.DirectAccess, loc string, backupName string, dataKey *encryption.DataKey, tarOpts []archive.TarOption, opts ...storage.UploadOption) (err error) {
+	mpu, ok := rs.(storage.MultipartUploader)
+	if !ok {
+		return errStreamingUnsupported
+	}
+
+	//nolint:ineffassign
+	span, ctx := opentracing.StartSpanFromContext(ctx, "streamBackup")
+	defer tracing.FinishSpan(span, &err)
+
+	upload, err := mpu.NewMultipartUpload(ctx, backupName, opts...)
+	if errors.Is(err, storage.ErrMultipartUnsupported) {
+		return errStreamingUnsupported
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot start multipart upload: %w", err)
+	}
+
+	w := storage.NewMultipartWriter(ctx, upload, storage.DefaultPartSize, func(ctx context.Context, op func(ctx context.Context) error) error {
+		return retryIfErr(ctx, s.config.Backup.Attempts, log.WithFields(sess.OWI()).WithField("op", "upload part"), op)
+	})
+
+	var (
+		compressedDigester = digest.Canonical.Digester()
+		digester           = digest.Canonical.Digester()
+		size               = &countingWriter{}
+	)
+	var (
+		dst       io.Writer = w
+		encrypter io.WriteCloser
+	)
+	if dataKey != nil {
+		encrypter, err = encryption.NewWriter(w, dataKey.Key())
+		if err != nil {
+			w.Abort()
+			return err
+		}
+		dst = encrypter
+	}
+	enc, err := zstd.NewWriter(io.MultiWriter(compressedDigester.Hash(), dst))
+	if err != nil {
+		w.Abort()
+		return err
+	}
+
+	err = WriteTarbal(ctx, loc, io.MultiWriter(digester.Hash(), size, enc), false, tarOpts...)
+	if err == nil {
+		err = enc.Close()
+	} else {
+		enc.Close()
+	}
+	if err == nil && encrypter != nil {
+		err = encrypter.Close()
+	}
+	if err != nil {
+		w.Abort()
+		return xerrors.Errorf("cannot build archive: %w", err)
+	}
+
+	_, _, err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	span.LogKV("digest", digester.Digest().String(), "size", size.n, "compressedDigest", compressedDigester.Digest().String(), "compressedSize", w.Size())
+	log.WithFields(sess.OWI()).
+		WithField("digest", digester.Digest()).WithField("size", size.n).
+		WithField("compressedDigest", compressedDigester.Digest()).WithField("compressedSize", w.Size()).
+		Debug("streamed workspace backup")
+	return nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -804,6 +804,11 @@ export class PortSpec extends jspb.Message {
     getUrl(): string;
     setUrl(value: string): PortSpec;
 
+    hasAccessPolicy(): boolean;
+    clearAccessPolicy(): void;
+    getAccessPolicy(): PortAccessPolicy | undefined;
+    setAccessPolicy(value?: PortAccessPolicy): PortSpec;
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): PortSpec.AsObject;
     static toObject(includeInstance: boolean, msg: PortSpec): PortSpec.AsObject;
@@ -819,6 +824,34 @@ export namespace PortSpec {
         port: number;
         visibility: PortVisibility;
         url: string;
+        accessPolicy?: PortAccessPolicy.AsObject;
+    };
+}
+
+export class PortAccessPolicy extends jspb.Message {
+    clearBasicAuthList(): void;
+    getBasicAuthList(): Array<string>;
+    setBasicAuthList(value: Array<string>): PortAccessPolicy;
+    addBasicAuth(value: string, index?: number): string;
+    clearAllowedCidrsList(): void;
+    getAllowedCidrsList(): Array<string>;
+    setAllowedCidrsList(value: Array<string>): PortAccessPolicy;
+    addAllowedCidrs(value: string, index?: number): string;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): PortAccessPolicy.AsObject;
+    static toObject(includeInstance: boolean, msg: PortAccessPolicy): PortAccessPolicy.AsObject;
+    static extensions: { [key: number]: jspb.ExtensionFieldInfo<jspb.Message> };
+    static extensionsBinary: { [key: number]: jspb.ExtensionFieldBinaryInfo<jspb.Message> };
+    static serializeBinaryToWriter(message: PortAccessPolicy, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): PortAccessPolicy;
+    static deserializeBinaryFromReader(message: PortAccessPolicy, reader: jspb.BinaryReader): PortAccessPolicy;
+}
+
+export namespace PortAccessPolicy {
+    export type AsObject = {
+        basicAuthList: Array<string>;
+        allowedCidrsList: Array<string>;
     };
 }
 
@@ -1290,6 +1323,7 @@ export enum AdmissionLevel {
 export enum PortVisibility {
     PORT_VISIBILITY_PRIVATE = 0,
     PORT_VISIBILITY_PUBLIC = 1,
+    PORT_VISIBILITY_PROTECTED = 2,
 }
 
 export enum WorkspaceConditionBool {
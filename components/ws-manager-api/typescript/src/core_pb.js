@@ -50,6 +50,7 @@ goog.exportSymbol("proto.wsman.IDEImage", null, global);
 goog.exportSymbol("proto.wsman.MarkActiveRequest", null, global);
 goog.exportSymbol("proto.wsman.MarkActiveResponse", null, global);
 goog.exportSymbol("proto.wsman.MetadataFilter", null, global);
+goog.exportSymbol("proto.wsman.PortAccessPolicy", null, global);
 goog.exportSymbol("proto.wsman.PortSpec", null, global);
 goog.exportSymbol("proto.wsman.PortVisibility", null, global);
 goog.exportSymbol("proto.wsman.SSHPublicKeys", null, global);
@@ -730,6 +731,27 @@ if (goog.DEBUG && !COMPILED) {
      */
     proto.wsman.PortSpec.displayName = "proto.wsman.PortSpec";
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.wsman.PortAccessPolicy = function (opt_data) {
+    jspb.Message.initialize(this, opt_data, 0, -1, proto.wsman.PortAccessPolicy.repeatedFields_, null);
+};
+goog.inherits(proto.wsman.PortAccessPolicy, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+    /**
+     * @public
+     * @override
+     */
+    proto.wsman.PortAccessPolicy.displayName = "proto.wsman.PortAccessPolicy";
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -5850,6 +5872,7 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
                 port: jspb.Message.getFieldWithDefault(msg, 1, 0),
                 visibility: jspb.Message.getFieldWithDefault(msg, 3, 0),
                 url: jspb.Message.getFieldWithDefault(msg, 4, ""),
+                accessPolicy: (f = msg.getAccessPolicy()) && proto.wsman.PortAccessPolicy.toObject(includeInstance, f),
             };
 
         if (includeInstance) {
@@ -5896,6 +5919,11 @@ proto.wsman.PortSpec.deserializeBinaryFromReader = function (msg, reader) {
                 var value = /** @type {string} */ (reader.readString());
                 msg.setUrl(value);
                 break;
+            case 5:
+                var value = new proto.wsman.PortAccessPolicy();
+                reader.readMessage(value, proto.wsman.PortAccessPolicy.deserializeBinaryFromReader);
+                msg.setAccessPolicy(value);
+                break;
             default:
                 reader.skipField();
                 break;
@@ -5935,6 +5963,10 @@ proto.wsman.PortSpec.serializeBinaryToWriter = function (message, writer) {
     if (f.length > 0) {
         writer.writeString(4, f);
     }
+    f = message.getAccessPolicy();
+    if (f != null) {
+        writer.writeMessage(5, f, proto.wsman.PortAccessPolicy.serializeBinaryToWriter);
+    }
 };
 
 /**
@@ -5985,6 +6017,223 @@ proto.wsman.PortSpec.prototype.setUrl = function (value) {
     return jspb.Message.setProto3StringField(this, 4, value);
 };
 
+/**
+ * optional PortAccessPolicy access_policy = 5;
+ * @return {?proto.wsman.PortAccessPolicy}
+ */
+proto.wsman.PortSpec.prototype.getAccessPolicy = function () {
+    return /** @type{?proto.wsman.PortAccessPolicy} */ (
+        jspb.Message.getWrapperField(this, proto.wsman.PortAccessPolicy, 5)
+    );
+};
+
+/**
+ * @param {?proto.wsman.PortAccessPolicy|undefined} value
+ * @return {!proto.wsman.PortSpec} returns this
+ */
+proto.wsman.PortSpec.prototype.setAccessPolicy = function (value) {
+    return jspb.Message.setWrapperField(this, 5, value);
+};
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.wsman.PortSpec} returns this
+ */
+proto.wsman.PortSpec.prototype.clearAccessPolicy = function () {
+    return this.setAccessPolicy(undefined);
+};
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.wsman.PortSpec.prototype.hasAccessPolicy = function () {
+    return jspb.Message.getField(this, 5) != null;
+};
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.wsman.PortAccessPolicy.repeatedFields_ = [1, 2];
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+    /**
+     * Creates an object representation of this proto.
+     * Field names that are reserved in JavaScript and will be renamed to pb_name.
+     * Optional fields that are not set will be set to undefined.
+     * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+     * For the list of reserved names please see:
+     *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+     * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+     *     JSPB instance for transitional soy proto support:
+     *     http://goto/soy-param-migration
+     * @return {!Object}
+     */
+    proto.wsman.PortAccessPolicy.prototype.toObject = function (opt_includeInstance) {
+        return proto.wsman.PortAccessPolicy.toObject(opt_includeInstance, this);
+    };
+
+    /**
+     * Static version of the {@see toObject} method.
+     * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+     *     the JSPB instance for transitional soy proto support:
+     *     http://goto/soy-param-migration
+     * @param {!proto.wsman.PortAccessPolicy} msg The msg instance to transform.
+     * @return {!Object}
+     * @suppress {unusedLocalVariables} f is only used for nested messages
+     */
+    proto.wsman.PortAccessPolicy.toObject = function (includeInstance, msg) {
+        var f,
+            obj = {
+                basicAuthList: (f = jspb.Message.getRepeatedField(msg, 1)) == null ? undefined : f,
+                allowedCidrsList: (f = jspb.Message.getRepeatedField(msg, 2)) == null ? undefined : f,
+            };
+
+        if (includeInstance) {
+            obj.$jspbMessageInstance = msg;
+        }
+        return obj;
+    };
+}
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.wsman.PortAccessPolicy}
+ */
+proto.wsman.PortAccessPolicy.deserializeBinary = function (bytes) {
+    var reader = new jspb.BinaryReader(bytes);
+    var msg = new proto.wsman.PortAccessPolicy();
+    return proto.wsman.PortAccessPolicy.deserializeBinaryFromReader(msg, reader);
+};
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.wsman.PortAccessPolicy} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.wsman.PortAccessPolicy}
+ */
+proto.wsman.PortAccessPolicy.deserializeBinaryFromReader = function (msg, reader) {
+    while (reader.nextField()) {
+        if (reader.isEndGroup()) {
+            break;
+        }
+        var field = reader.getFieldNumber();
+        switch (field) {
+            case 1:
+                var value = /** @type {string} */ (reader.readString());
+                msg.addBasicAuth(value);
+                break;
+            case 2:
+                var value = /** @type {string} */ (reader.readString());
+                msg.addAllowedCidrs(value);
+                break;
+            default:
+                reader.skipField();
+                break;
+        }
+    }
+    return msg;
+};
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.wsman.PortAccessPolicy.prototype.serializeBinary = function () {
+    var writer = new jspb.BinaryWriter();
+    proto.wsman.PortAccessPolicy.serializeBinaryToWriter(this, writer);
+    return writer.getResultBuffer();
+};
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.wsman.PortAccessPolicy} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.wsman.PortAccessPolicy.serializeBinaryToWriter = function (message, writer) {
+    var f = undefined;
+    f = message.getBasicAuthList();
+    if (f.length > 0) {
+        writer.writeRepeatedString(1, f);
+    }
+    f = message.getAllowedCidrsList();
+    if (f.length > 0) {
+        writer.writeRepeatedString(2, f);
+    }
+};
+
+/**
+ * repeated string basic_auth = 1;
+ * @return {!Array<string>}
+ */
+proto.wsman.PortAccessPolicy.prototype.getBasicAuthList = function () {
+    return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 1));
+};
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.wsman.PortAccessPolicy} returns this
+ */
+proto.wsman.PortAccessPolicy.prototype.setBasicAuthList = function (value) {
+    return jspb.Message.setField(this, 1, value || []);
+};
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.PortAccessPolicy} returns this
+ */
+proto.wsman.PortAccessPolicy.prototype.addBasicAuth = function (value, opt_index) {
+    return jspb.Message.addToRepeatedField(this, 1, value, opt_index);
+};
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.PortAccessPolicy} returns this
+ */
+proto.wsman.PortAccessPolicy.prototype.clearBasicAuthList = function () {
+    return this.setBasicAuthList([]);
+};
+
+/**
+ * repeated string allowed_cidrs = 2;
+ * @return {!Array<string>}
+ */
+proto.wsman.PortAccessPolicy.prototype.getAllowedCidrsList = function () {
+    return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 2));
+};
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.wsman.PortAccessPolicy} returns this
+ */
+proto.wsman.PortAccessPolicy.prototype.setAllowedCidrsList = function (value) {
+    return jspb.Message.setField(this, 2, value || []);
+};
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.wsman.PortAccessPolicy} returns this
+ */
+proto.wsman.PortAccessPolicy.prototype.addAllowedCidrs = function (value, opt_index) {
+    return jspb.Message.addToRepeatedField(this, 2, value, opt_index);
+};
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.wsman.PortAccessPolicy} returns this
+ */
+proto.wsman.PortAccessPolicy.prototype.clearAllowedCidrsList = function () {
+    return this.setAllowedCidrsList([]);
+};
+
 if (jspb.Message.GENERATE_TO_OBJECT) {
     /**
      * Creates an object representation of this proto.
@@ -9044,6 +9293,7 @@ proto.wsman.AdmissionLevel = {
 proto.wsman.PortVisibility = {
     PORT_VISIBILITY_PRIVATE: 0,
     PORT_VISIBILITY_PUBLIC: 1,
+    PORT_VISIBILITY_PROTECTED: 2,
 };
 
 /**
@@ -429,6 +429,8 @@ const mapPortVisibility = (visibility: WsManPortVisibility | undefined): PortVis
             return "private";
         case WsManPortVisibility.PORT_VISIBILITY_PUBLIC:
             return "public";
+        case WsManPortVisibility.PORT_VISIBILITY_PROTECTED:
+            return "protected";
     }
 };
 
@@ -1063,14 +1063,16 @@ func (m *Manager) ControlPort(ctx context.Context, req *api.ControlPortRequest)
 			}
 
 			portSpec := &api.PortSpec{
-				Port:       uint32(port),
-				Visibility: req.Spec.Visibility,
-				Url:        url,
+				Port:         uint32(port),
+				Visibility:   req.Spec.Visibility,
+				Url:          url,
+				AccessPolicy: req.Spec.AccessPolicy,
 			}
 
 			exposedPorts.Ports = append(exposedPorts.Ports, portSpec)
 		} else if req.Expose && existingPortSpecIdx >= 0 {
 			exposedPorts.Ports[existingPortSpecIdx].Visibility = req.Spec.Visibility
+			exposedPorts.Ports[existingPortSpecIdx].AccessPolicy = req.Spec.AccessPolicy
 		} else if !req.Expose && existingPortSpecIdx < 0 {
 			// port isn't exposed already - we're done here
 			return nil
@@ -5,12 +5,20 @@
 package proxy
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/gitpod-io/golang-crypto/bcrypt"
 	"github.com/gorilla/mux"
 
 	"github.com/gitpod-io/gitpod/ws-manager/api"
@@ -54,10 +62,12 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 				return
 			}
 
+			var protectedPort *api.PortSpec
 			if port != "" {
-				// this is a workspace port request and ports can be public or private.
-				// For public ports no tokens or cookies matter, private ports are subject
-				// to the same access policies as the workspace itself is.
+				// this is a workspace port request and ports can be public, protected or private.
+				// For public ports no tokens or cookies matter, protected ports are accessible if their
+				// access policy is met, private ports are subject to the same access policies as the
+				// workspace itself is.
 				var isPublic bool
 
 				prt, err := strconv.ParseUint(port, 10, 16)
@@ -67,6 +77,9 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 					for _, p := range ws.Ports {
 						if p.Port == uint32(prt) {
 							isPublic = p.Visibility == api.PortVisibility_PORT_VISIBILITY_PUBLIC
+							if p.Visibility == api.PortVisibility_PORT_VISIBILITY_PROTECTED {
+								protectedPort = p
+							}
 
 							break
 						}
@@ -80,7 +93,26 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 					return
 				}
 
-				// port seems to be private - subject it to the same access policy as the workspace itself
+				if protectedPort != nil && checkPortAccessPolicy(req, protectedPort.AccessPolicy) {
+					// the credentials are meant for us, not for the workspace
+					if len(protectedPort.AccessPolicy.GetBasicAuth()) > 0 {
+						req.Header.Del("Authorization")
+					}
+					h.ServeHTTP(resp, req)
+
+					return
+				}
+
+				// port seems to be private or the access policy is not met - subject it to the same access policy as the workspace itself
+			}
+
+			// unauthorized requests to protected ports are asked for the port credentials rather than the owner token
+			unauthorized := func(status int) {
+				if protectedPort != nil && len(protectedPort.AccessPolicy.GetBasicAuth()) > 0 {
+					resp.Header().Set("WWW-Authenticate", `Basic realm="Gitpod workspace port", charset="UTF-8"`)
+					status = http.StatusUnauthorized
+				}
+				resp.WriteHeader(status)
 			}
 
 			tkn := req.Header.Get("x-gitpod-owner-token")
@@ -89,7 +121,7 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 				c, err := req.Cookie(cn)
 				if err != nil {
 					log.WithField("cookieName", cn).Debug("no owner cookie present")
-					resp.WriteHeader(http.StatusUnauthorized)
+					unauthorized(http.StatusUnauthorized)
 
 					return
 				}
@@ -99,14 +131,14 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 			tkn, err := url.QueryUnescape(tkn)
 			if err != nil {
 				log.WithError(err).Warn("cannot decode owner token")
-				resp.WriteHeader(http.StatusBadRequest)
+				unauthorized(http.StatusBadRequest)
 
 				return
 			}
 
 			if tkn != ws.Auth.OwnerToken {
 				log.Warn("owner token mismatch")
-				resp.WriteHeader(http.StatusForbidden)
+				unauthorized(http.StatusForbidden)
 
 				return
 			}
@@ -115,3 +147,153 @@ func WorkspaceAuthHandler(domain string, info WorkspaceInfoProvider) mux.Middlew
 		})
 	}
 }
+
+// checkPortAccessPolicy returns true if the request meets all requirements of the access policy.
+// An empty policy is never met, i.e. the port is accessible by the workspace owner only.
+func checkPortAccessPolicy(req *http.Request, policy *api.PortAccessPolicy) bool {
+	if len(policy.GetBasicAuth()) == 0 && len(policy.GetAllowedCidrs()) == 0 {
+		return false
+	}
+	if len(policy.GetAllowedCidrs()) > 0 && !isAllowedClientIP(clientIP(req), policy.AllowedCidrs) {
+		return false
+	}
+	if len(policy.GetBasicAuth()) > 0 {
+		user, password, ok := req.BasicAuth()
+		if !ok || !checkBasicAuth(user, password, policy.BasicAuth) {
+			return false
+		}
+	}
+	return true
+}
+
+// clientIP returns the IP of the client sending the request. Requests are routed through the proxy, which sets X-Real-IP.
+func clientIP(req *http.Request) net.IP {
+	if ip := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-IP"))); ip != nil {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+func isAllowedClientIP(ip net.IP, allowedCIDRs []string) bool {
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range allowedCIDRs {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			// a plain IP is allowed as well
+			allowed := net.ParseIP(cidr)
+			if allowed != nil && allowed.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBasicAuth verifies the credentials against htpasswd entries with bcrypt hashes.
+func checkBasicAuth(user, password string, htpasswd []string) bool {
+	for _, entry := range htpasswd {
+		entryUser, hash, ok := strings.Cut(entry, ":")
+		if !ok || subtle.ConstantTimeCompare([]byte(entryUser), []byte(user)) != 1 {
+			continue
+		}
+		if verifiedBasicAuth.Has(entry, password) {
+			return true
+		}
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			verifiedBasicAuth.Add(entry, password)
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// basicAuthCacheTTL is how long a successful verification of basic auth credentials is remembered
+	basicAuthCacheTTL = 5 * time.Minute
+	// basicAuthCacheSize is the max number of remembered verifications
+	basicAuthCacheSize = 10000
+)
+
+// verifiedBasicAuth remembers successful verifications, s.t. not every request to a protected port runs bcrypt
+var verifiedBasicAuth = newBasicAuthCache(basicAuthCacheTTL, basicAuthCacheSize)
+
+// basicAuthCache remembers verified credentials for a short time. Credentials are keyed by an HMAC with a random key,
+// s.t. neither passwords nor cheap to brute-force hashes of them are kept in memory.
+type basicAuthCache struct {
+	ttl  time.Duration
+	size int
+	key  []byte
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+func newBasicAuthCache(ttl time.Duration, size int) *basicAuthCache {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	if err != nil {
+		panic(fmt.Sprintf("cannot generate basic auth cache key: %v", err))
+	}
+	return &basicAuthCache{
+		ttl:     ttl,
+		size:    size,
+		key:     key,
+		now:     time.Now,
+		entries: make(map[string]time.Time),
+	}
+}
+
+func (c *basicAuthCache) cacheKey(entry, password string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(entry))
+	mac.Write([]byte{0})
+	mac.Write([]byte(password))
+	return string(mac.Sum(nil))
+}
+
+// Has returns true if the password has been verified against the htpasswd entry within the TTL.
+func (c *basicAuthCache) Has(entry, password string) bool {
+	key := c.cacheKey(entry, password)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	if c.now().After(expires) {
+		delete(c.entries, key)
+		return false
+	}
+	return true
+}
+
+// Add remembers that the password matches the htpasswd entry.
+func (c *basicAuthCache) Add(entry, password string) {
+	key := c.cacheKey(entry, password)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= c.size {
+		for k, expires := range c.entries {
+			if now.After(expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.size {
+			// all entries are in use, start over rather than growing without bounds
+			c.entries = make(map[string]time.Time)
+		}
+	}
+	c.entries[key] = now.Add(c.ttl)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/gorilla/mux"
@@ -56,6 +57,25 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 				Ports: []*api.PortSpec{{Port: testPort, Visibility: api.PortVisibility_PORT_VISIBILITY_PUBLIC}},
 			},
 		}
+		protectedPortInfos = map[string]*WorkspaceInfo{
+			workspaceID: {
+				WorkspaceID: workspaceID,
+				InstanceID:  instanceID,
+				Auth: &api.WorkspaceAuthentication{
+					Admission:  api.AdmissionLevel_ADMIT_OWNER_ONLY,
+					OwnerToken: ownerToken,
+				},
+				Ports: []*api.PortSpec{{
+					Port:       testPort,
+					Visibility: api.PortVisibility_PORT_VISIBILITY_PROTECTED,
+					AccessPolicy: &api.PortAccessPolicy{
+						// password is "secret"
+						BasicAuth:    []string{"customer:$2a$04$.0DUtDehe7KpSXwZC6Bt3.Joi/qKMCf/SbA7hLS7AfIvXyJbwP062"},
+						AllowedCidrs: []string{"10.0.0.0/8"},
+					},
+				}},
+			},
+		}
 		admitEveryoneInfos = map[string]*WorkspaceInfo{
 			workspaceID: {
 				WorkspaceID: workspaceID,
@@ -68,6 +88,8 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 		Name        string
 		Infos       map[string]*WorkspaceInfo
 		OwnerCookie string
+		BasicAuth   []string
+		ClientIP    string
 		WorkspaceID string
 		Port        string
 		Expected    testResult
@@ -218,6 +240,64 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 				StatusCode:    http.StatusUnauthorized,
 			},
 		},
+		{
+			Name:        "protected port",
+			Infos:       protectedPortInfos,
+			WorkspaceID: workspaceID,
+			Port:        strconv.Itoa(testPort),
+			BasicAuth:   []string{"customer", "secret"},
+			ClientIP:    "10.1.2.3",
+			Expected: testResult{
+				HandlerCalled: true,
+				StatusCode:    http.StatusOK,
+			},
+		},
+		{
+			Name:        "protected port with wrong password",
+			Infos:       protectedPortInfos,
+			WorkspaceID: workspaceID,
+			Port:        strconv.Itoa(testPort),
+			BasicAuth:   []string{"customer", "wrong"},
+			ClientIP:    "10.1.2.3",
+			Expected: testResult{
+				HandlerCalled: false,
+				StatusCode:    http.StatusUnauthorized,
+			},
+		},
+		{
+			Name:        "protected port from disallowed IP",
+			Infos:       protectedPortInfos,
+			WorkspaceID: workspaceID,
+			Port:        strconv.Itoa(testPort),
+			BasicAuth:   []string{"customer", "secret"},
+			ClientIP:    "192.168.1.1",
+			Expected: testResult{
+				HandlerCalled: false,
+				StatusCode:    http.StatusUnauthorized,
+			},
+		},
+		{
+			Name:        "protected port with wrong cookie",
+			Infos:       protectedPortInfos,
+			WorkspaceID: workspaceID,
+			Port:        strconv.Itoa(testPort),
+			OwnerCookie: "this is the wrong value",
+			Expected: testResult{
+				HandlerCalled: false,
+				StatusCode:    http.StatusUnauthorized,
+			},
+		},
+		{
+			Name:        "protected port with owner cookie",
+			Infos:       protectedPortInfos,
+			WorkspaceID: workspaceID,
+			Port:        strconv.Itoa(testPort),
+			OwnerCookie: ownerToken,
+			Expected: testResult{
+				HandlerCalled: true,
+				StatusCode:    http.StatusOK,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -233,6 +313,12 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 			if test.OwnerCookie != "" {
 				setOwnerTokenCookie(req, instanceID, test.OwnerCookie)
 			}
+			if len(test.BasicAuth) == 2 {
+				req.SetBasicAuth(test.BasicAuth[0], test.BasicAuth[1])
+			}
+			if test.ClientIP != "" {
+				req.Header.Set("X-Real-IP", test.ClientIP)
+			}
 			vars := map[string]string{
 				workspaceIDIdentifier: test.WorkspaceID,
 			}
@@ -254,3 +340,38 @@ func TestWorkspaceAuthHandler(t *testing.T) {
 func setOwnerTokenCookie(r *http.Request, instanceID, token string) {
 	r.AddCookie(&http.Cookie{Name: "_test_domain_com_ws_" + instanceID + "_owner_", Value: token})
 }
+
+func TestBasicAuthCache(t *testing.T) {
+	var (
+		now   = time.Date(2022, 5, 3, 10, 0, 0, 0, time.UTC)
+		cache = newBasicAuthCache(time.Minute, 2)
+		entry = "customer:$2a$04$.0DUtDehe7KpSXwZC6Bt3.Joi/qKMCf/SbA7hLS7AfIvXyJbwP062"
+	)
+	cache.now = func() time.Time { return now }
+
+	if cache.Has(entry, "secret") {
+		t.Fatal("expected unverified credentials not to be cached")
+	}
+	cache.Add(entry, "secret")
+	if !cache.Has(entry, "secret") {
+		t.Error("expected verified credentials to be cached")
+	}
+	if cache.Has(entry, "wrong") {
+		t.Error("expected other passwords not to be cached")
+	}
+	if cache.Has("customer:$2a$04$other", "secret") {
+		t.Error("expected the credentials of other entries not to be cached")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if cache.Has(entry, "secret") {
+		t.Error("expected cached credentials to expire")
+	}
+
+	for _, password := range []string{"a", "b", "c"} {
+		cache.Add(entry, password)
+	}
+	if len(cache.entries) > 2 {
+		t.Errorf("expected the cache to hold at most 2 entries, got %d", len(cache.entries))
+	}
+}
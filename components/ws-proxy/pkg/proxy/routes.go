@@ -100,6 +100,8 @@ func installWorkspaceRoutes(r *mux.Router, config *RouteHandlerConfig, ip Worksp
 	routes.HandleDirectSupervisorRoute(r.PathPrefix("/_supervisor/v1/status/ide"), false)
 	routes.HandleDirectSupervisorRoute(r.PathPrefix("/_supervisor/v1"), true)
 	routes.HandleDirectSupervisorRoute(r.PathPrefix("/_supervisor"), true)
+	// additional IDE backends are served by supervisor at /_ide/<name>/
+	routes.HandleDirectSupervisorRoute(r.PathPrefix("/_ide/"), true)
 
 	rootRouter := enableCompression(r)
 	rootRouter.Use(func(h http.Handler) http.Handler {
@@ -351,6 +353,7 @@ func installWorkspacePortRoutes(r *mux.Router, config *RouteHandlerConfig, infoP
 	}
 
 	r.Use(logHandler)
+	// enforces the port visibility, i.e. the access policy of protected ports
 	r.Use(config.WorkspaceAuthHandler)
 	// filter all session cookies
 	r.Use(sensitiveCookieHandler(config.Config.GitpodInstallation.HostName))
@@ -426,6 +426,30 @@ func TestRoutes(t *testing.T) {
 				Body: "supervisor hit: /_supervisor/v1/status/content\n",
 			},
 		},
+		{
+			Desc: "unauthenticated additional IDE backend",
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"_ide/intellij/status", nil),
+				addHostHeader,
+			),
+			Expectation: Expectation{
+				Status: http.StatusUnauthorized,
+			},
+		},
+		{
+			Desc: "authenticated additional IDE backend",
+			Request: modifyRequest(httptest.NewRequest("GET", workspaces[0].URL+"_ide/intellij/status", nil),
+				addHostHeader,
+				addOwnerToken(workspaces[0].InstanceID, workspaces[0].Auth.OwnerToken),
+			),
+			Expectation: Expectation{
+				Status: http.StatusOK,
+				Header: http.Header{
+					"Content-Length": {"38"},
+					"Content-Type":   {"text/plain; charset=utf-8"},
+				},
+				Body: "supervisor hit: /_ide/intellij/status\n",
+			},
+		},
 		{
 			Desc: "non-existent authorized GET /",
 			Request: modifyRequest(httptest.NewRequest("GET", strings.ReplaceAll(workspaces[0].URL, "amaranth", "blabla"), nil),
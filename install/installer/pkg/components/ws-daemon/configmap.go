@@ -49,6 +49,7 @@ func configmap(ctx *common.RenderContext) ([]runtime.Object, error) {
 	var ioLimitConfig daemon.IOLimitConfig
 
 	var procLimit int64
+	var chunkedBackups bool
 	networkLimitConfig := netlimit.Config{
 		Enabled:              false,
 		Enforce:              false,
@@ -99,6 +100,7 @@ func configmap(ctx *common.RenderContext) ([]runtime.Object, error) {
 		}
 
 		procLimit = ucfg.Workspace.ProcLimit
+		chunkedBackups = ucfg.Workspace.WSDaemon.ChunkedBackups
 
 		return nil
 	})
@@ -129,6 +131,7 @@ func configmap(ctx *common.RenderContext) ([]runtime.Object, error) {
 				Backup: content.BackupConfig{
 					Timeout:  util.Duration(time.Minute * 5),
 					Attempts: 3,
+					Chunked:  chunkedBackups,
 				},
 				Initializer: content.InitializerConfig{
 					Command: "/app/content-initializer",
@@ -110,6 +110,8 @@ type WorkspaceConfig struct {
 		Runtime struct {
 			NodeToContainerMapping []NodeToContainerMappingValues `json:"nodeToContainerMapping"`
 		} `json:"runtime"`
+		// ChunkedBackups stores regular workspace backups as content-defined chunks, s.t. only changed chunks are uploaded
+		ChunkedBackups bool `json:"chunkedBackups"`
 	} `json:"wsDaemon"`
 
 	WorkspaceClasses map[string]WorkspaceClass `json:"classes,omitempty"`
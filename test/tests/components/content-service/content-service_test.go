@@ -295,6 +295,7 @@ func uploadBlob(t *testing.T, originalUrl, updatedUrl, content string) {
 	}
 	// Add Host header
 	httpreq.Host = u.Host
+	httpreq.Header.Set("x-ms-blob-type", "BlockBlob")
 	httpresp, err := client.Do(httpreq)
 	if err != nil {
 		t.Fatalf("HTTP PUT request failed: %q", err)